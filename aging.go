@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WIPConfig caps how many PRs a person or team should have open at once.
+type WIPConfig struct {
+	PerAuthor int `yaml:"per_author"` // Default 3
+	PerTeam   int `yaml:"per_team"`   // Off unless set
+	// Overrides sets a different limit for specific logins or team names.
+	Overrides map[string]int `yaml:"overrides"`
+}
+
+func (w WIPConfig) limitFor(name string, team bool) int {
+	for k, v := range w.Overrides {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	if team {
+		return w.PerTeam
+	}
+	if w.PerAuthor == 0 {
+		return 3
+	}
+	return w.PerAuthor
+}
+
+func printOpenAging(prs []PullRequest) {
+	fmt.Println("⏳ OPEN PR AGING & WIP LIMITS")
+	printExplanation("Today's open queue by age, and people or teams with more PRs in flight than their WIP limit.",
+		"Stale detection only catches the extreme tail. A queue full of 5-day-old PRs, or one author juggling eight, is the real drag.")
+
+	now := clock()
+	var ages []time.Duration
+	for _, pr := range prs {
+		ages = append(ages, now.Sub(pr.CreatedAt))
+	}
+	fmt.Printf("   Open PRs: %d   Median age: %s   Oldest: %s\n\n", len(prs), formatDuration(percentile(ages, 50)), formatDuration(percentile(ages, 100)))
+	printDurationHistogram(ages, "%s%% of the queue is younger than %s.")
+
+	type wip struct {
+		Name   string
+		Open   int
+		Drafts int
+		Limit  int
+	}
+	authors := make(map[string]*wip)
+	teams := make(map[string]*wip)
+	count := func(m map[string]*wip, name string, team bool, draft bool) {
+		if m[name] == nil {
+			m[name] = &wip{Name: name, Limit: cfg.WIP.limitFor(name, team)}
+		}
+		m[name].Open++
+		if draft {
+			m[name].Drafts++
+		}
+	}
+	for _, pr := range prs {
+		if pr.Author == "" || isBot(pr.Author) {
+			continue
+		}
+		count(authors, pr.Author, false, pr.IsDraft)
+		if t := teamOf(pr.Author); t != "" {
+			count(teams, t, true, pr.IsDraft)
+		}
+	}
+
+	var over []*wip
+	hidden := 0 // Authors over their limit when names are hidden
+	for _, m := range []map[string]*wip{authors, teams} {
+		for _, w := range m {
+			switch {
+			case w.Limit == 0 || w.Open <= w.Limit:
+			case authors[w.Name] == w && !namesShown():
+				hidden++
+			default:
+				over = append(over, w)
+			}
+		}
+	}
+	sort.Slice(over, func(i, j int) bool {
+		if over[i].Open-over[i].Limit != over[j].Open-over[j].Limit {
+			return over[i].Open-over[i].Limit > over[j].Open-over[j].Limit
+		}
+		return over[i].Name < over[j].Name
+	})
+
+	fmt.Println()
+	if len(over) == 0 && hidden == 0 {
+		fmt.Println("   ✅ Everyone is within their WIP limit.")
+		return
+	}
+	if hidden > 0 {
+		fmt.Printf("   🚧 %d authors over their WIP limit\n", hidden)
+	}
+	for _, w := range over {
+		kind := ""
+		if teams[w.Name] == w {
+			kind = " (team)"
+		}
+		fmt.Printf("   🚧 %s%s: %d open (limit %d, %d drafts)\n", w.Name, kind, w.Open, w.Limit, w.Drafts)
+	}
+	fmt.Println("\n   Action: Finish or close work in flight before opening more. Stop starting, start finishing.")
+}
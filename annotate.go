@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// metricDelta is one row of the week-over-week comparison.
+type metricDelta struct {
+	Label       string
+	Before, Now string
+	Change      string
+	Regressed   bool
+	Improved    bool
+}
+
+// compareDuration describes the change of a duration where lower is better.
+// Changes under 10% don't count as a regression or an improvement.
+func compareDuration(label string, before, now time.Duration) metricDelta {
+	d := metricDelta{Label: label, Before: formatDuration(before), Now: formatDuration(now), Change: "-"}
+	if before > 0 {
+		change := pctChange(float64(before), float64(now))
+		d.Change = fmt.Sprintf("%+.0f%%", change)
+		d.Regressed, d.Improved = change >= 10, change <= -10
+	}
+	return d
+}
+
+// compareNumber describes the change of a number. higher tells whether higher
+// values are better; threshold is the absolute change that counts.
+func compareNumber(label string, before, now float64, decimals int, higher bool, threshold float64) metricDelta {
+	d := metricDelta{Label: label, Before: formatNumber(before, decimals), Now: formatNumber(now, decimals)}
+	change := now - before
+	if change >= 0 {
+		d.Change = "+" + formatNumber(change, decimals)
+	} else {
+		d.Change = "-" + formatNumber(-change, decimals)
+	}
+	if !higher {
+		change = -change
+	}
+	d.Regressed, d.Improved = change <= -threshold, change >= threshold
+	return d
+}
+
+// snapshotDeltas compares the headline numbers of two snapshots.
+func snapshotDeltas(before, now Snapshot) []metricDelta {
+	b, n := before.Metrics, now.Metrics
+	return []metricDelta{
+		compareNumber("Health score", float64(before.Health), float64(now.Health), 0, true, 3),
+		compareDuration("Median merge time", b.MedianCycleTime, n.MedianCycleTime),
+		compareDuration("P90 merge time", b.P90CycleTime, n.P90CycleTime),
+		compareDuration("Median first review", b.MedianFirstReview, n.MedianFirstReview),
+		compareNumber("PRs reviewed (%)", b.ReviewedPct, n.ReviewedPct, 0, true, 5),
+		compareNumber("Review rounds per PR", b.AvgRounds, n.AvgRounds, 1, false, 0.3),
+		compareNumber("Median PR size (lines)", float64(b.MedianSize), float64(n.MedianSize), 0, false, float64(max(b.MedianSize/5, 20))),
+		compareNumber("Open PRs", float64(before.Open), float64(now.Open), 0, false, float64(max(before.Open/5, 3))),
+		compareNumber("Stale PRs", float64(before.Stale), float64(now.Stale), 0, false, 2),
+	}
+}
+
+// areaRegression is an area whose median merge time got worse.
+type areaRegression struct {
+	Area        string
+	Before, Now time.Duration
+	Owners      []string
+}
+
+// regressedAreas lists the areas with at least minPRs PRs in both snapshots
+// whose median merge time rose by threshold percent or more, worst first.
+func regressedAreas(before, now Snapshot, minPRs int, threshold float64) []areaRegression {
+	var out []areaRegression
+	for area, n := range now.Areas {
+		b, ok := before.Areas[area]
+		if !ok || b.PRs < minPRs || n.PRs < minPRs || b.MedianCycleTime <= 0 {
+			continue
+		}
+		if pctChange(float64(b.MedianCycleTime), float64(n.MedianCycleTime)) >= threshold && n.MedianCycleTime-b.MedianCycleTime >= time.Hour {
+			out = append(out, areaRegression{Area: area, Before: b.MedianCycleTime, Now: n.MedianCycleTime})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ri, rj := float64(out[i].Now)/float64(out[i].Before), float64(out[j].Now)/float64(out[j].Before)
+		if ri != rj {
+			return ri > rj
+		}
+		return out[i].Area < out[j].Area
+	})
+	return out
+}
+
+// areaOwners returns the CODEOWNERS owners of each area's files among prs,
+// most files owned first.
+func areaOwners(prs []PullRequest, owners *Codeowners) map[string][]string {
+	counts := make(map[string]map[string]int)
+	for _, pr := range prs {
+		for _, p := range pr.FilePaths {
+			area := groupFor(p)
+			for _, o := range owners.OwnersFor(p) {
+				if counts[area] == nil {
+					counts[area] = make(map[string]int)
+				}
+				counts[area][o]++
+			}
+		}
+	}
+	out := make(map[string][]string, len(counts))
+	for area, c := range counts {
+		var list []string
+		for o := range c {
+			list = append(list, o)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if c[list[i]] != c[list[j]] {
+				return c[list[i]] > c[list[j]]
+			}
+			return list[i] < list[j]
+		})
+		out[area] = list
+	}
+	return out
+}
+
+// baselineSnapshot returns the latest snapshot taken at least age before
+// now, allowing half a day of slack for scheduled runs drifting.
+func baselineSnapshot(history []Snapshot, now time.Time, age time.Duration) (Snapshot, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].Taken.After(now.Add(-age + 12*time.Hour)) {
+			return history[i], true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// annotationBody renders the week-over-week comment in GitHub Markdown.
+// Owners of regressed areas are @-mentioned when names are shown.
+func annotationBody(repo string, before *Snapshot, now Snapshot, regressions []areaRegression) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### 📊 Review metrics for %s, %s\n\n", repo, formatDate(now.Taken))
+	if before == nil {
+		fmt.Fprintf(&b, "No earlier snapshot to compare with yet; this run is the baseline. Health score: **%d/100**.\n", now.Health)
+		return b.String()
+	}
+
+	deltas := snapshotDeltas(*before, now)
+	fmt.Fprintf(&b, "Compared with %s (last %d merged PRs each time).\n\n", formatDate(before.Taken), now.Metrics.Count)
+	b.WriteString("| Metric | Before | Now | Change |\n|---|---:|---:|---:|\n")
+	regressed := 0
+	for _, d := range deltas {
+		mark := ""
+		switch {
+		case d.Regressed:
+			mark = " 🔴"
+			regressed++
+		case d.Improved:
+			mark = " 🟢"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s%s |\n", d.Label, d.Before, d.Now, d.Change, mark)
+	}
+
+	if len(regressions) > 0 {
+		fmt.Fprintf(&b, "\n**Slower areas** (median merge time by %s):\n\n", strings.ToLower(groupLabel()))
+		for _, r := range regressions {
+			line := fmt.Sprintf("- `%s`: %s → %s (%+.0f%%)", r.Area, formatDuration(r.Before), formatDuration(r.Now), pctChange(float64(r.Before), float64(r.Now)))
+			if namesShown() && len(r.Owners) > 0 {
+				var mentions []string
+				for _, o := range r.Owners[:min(len(r.Owners), 3)] {
+					mentions = append(mentions, "@"+o)
+				}
+				line += " cc " + strings.Join(mentions, " ")
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	if regressed == 0 && len(regressions) == 0 {
+		b.WriteString("\n✅ No regressions this week.\n")
+	}
+	b.WriteString("\n<sub>Posted by `bottleneck annotate`. 🔴 worse, 🟢 better; small changes aren't marked.</sub>\n")
+	return b.String()
+}
+
+// postIssueComment comments on an issue or pull request.
+func postIssueComment(ctx context.Context, owner, name string, number int, body string, timeout time.Duration) error {
+	_, err := ghAPI(ctx, timeout, "-X", "POST", fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, name, number), "-f", "body="+body)
+	return err
+}
+
+func runAnnotate(args []string) {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	issue := fs.Int("issue", 0, "Tracking issue or PR number to comment on")
+	limit := fs.Int("limit", 300, "Max number of merged PRs to measure")
+	against := fs.Duration("against", 7*24*time.Hour, "Compare with the latest snapshot at least this old")
+	minPRs := fs.Int("min-area-prs", 5, "PRs an area needs in both snapshots to be compared")
+	threshold := fs.Float64("area-threshold", 25, "Percent rise in an area's median merge time that counts as a regression")
+	dryRun := fs.Bool("dry-run", false, "Print the comment instead of posting it; the snapshot is still saved")
+	storeDir := fs.String("store", defaultStoreDir, "Directory of the local snapshot store (persist it between runs, e.g. with actions/cache)")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	privacyMode := fs.String("privacy", "", "Hide names: team or aggregate (no owners are mentioned). Overrides privacy: in config")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck annotate --issue <number> [flags] [owner/repo]")
+		fmt.Println("Saves a snapshot and comments the week-over-week deltas on a tracking issue, mentioning the code owners of slower areas.")
+		fmt.Println("The repository defaults to $GITHUB_REPOSITORY, as set in GitHub Actions.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if fs.NArg() > 0 {
+		repo = fs.Arg(0)
+	}
+	if repo == "" || (*issue < 1 && !*dryRun) || *limit < 1 || *minPRs < 1 || *against <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if *privacyMode != "" {
+		if err := setPrivacy(*privacyMode); err != nil {
+			slog.Error("invalid --privacy", "err", err)
+			os.Exit(1)
+		}
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+	merged, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+	if err != nil && len(merged) == 0 {
+		slog.Error("fetching merged PRs", "repo", repo, "err", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Warn("continuing with partial data", "repo", repo, "fetched", len(merged), "err", err)
+	}
+	open, err := fetchPRs(ctx, owner, name, 100, "OPEN", *reqTimeout, *reqDelay)
+	if err != nil {
+		slog.Warn("fetching open PRs", "repo", repo, "fetched", len(open), "err", err)
+	}
+	generated, err := loadGeneratedMatcher(ctx, owner, name, *reqTimeout)
+	if err != nil {
+		slog.Warn("could not fetch .gitattributes", "repo", repo, "err", err)
+	}
+	markGenerated(merged, generated, true)
+	markGenerated(open, generated, true)
+
+	store := Store{Dir: *storeDir}
+	history, err := store.Load(repo)
+	if err != nil {
+		slog.Warn("could not read snapshot history", "repo", repo, "err", err)
+	}
+	now := clock()
+	snap := healthSnapshot(repo, merged, open, now)
+	if err := store.Save(snap); err != nil {
+		slog.Error("saving snapshot", "repo", repo, "err", err)
+		os.Exit(1)
+	}
+
+	var before *Snapshot
+	var regressions []areaRegression
+	if b, ok := baselineSnapshot(history, now, *against); ok {
+		before = &b
+		regressions = regressedAreas(b, snap, *minPRs, *threshold)
+		if len(regressions) > 0 && namesShown() {
+			owners, err := fetchCodeowners(ctx, owner, name, *reqTimeout)
+			if err != nil {
+				slog.Warn("could not fetch CODEOWNERS", "repo", repo, "err", err)
+			}
+			byArea := areaOwners(merged, owners)
+			for i := range regressions {
+				regressions[i].Owners = byArea[regressions[i].Area]
+			}
+		}
+	} else {
+		slog.Info("no snapshot old enough to compare with; this run is the baseline", "repo", repo, "against", *against)
+	}
+	body := annotationBody(repo, before, snap, regressions)
+
+	// Actions shows the step summary on the run page
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+		if f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+			fmt.Fprintln(f, body)
+			f.Close()
+		}
+	}
+	if *dryRun {
+		fmt.Print(body)
+		return
+	}
+	if err := postIssueComment(ctx, owner, name, *issue, body, *reqTimeout); err != nil {
+		slog.Error("commenting on the tracking issue", "repo", repo, "issue", *issue, "err", err)
+		os.Exit(1)
+	}
+	slog.Info("commented on the tracking issue", "repo", repo, "issue", *issue, "regressed_areas", len(regressions))
+}
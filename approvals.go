@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// approval is a reviewer's first approval of a PR.
+type approval struct {
+	Reviewer    string
+	At          time.Time
+	Requested   time.Time // When they were asked to review
+	FirstReview time.Time // Their first review of any kind
+}
+
+// approvalOrder returns each approver's first approval before pr merged,
+// earliest first.
+func approvalOrder(pr PullRequest) []approval {
+	byReviewer := make(map[string]*approval)
+	var order []*approval
+	for _, r := range pr.Reviews {
+		if r.Author == "" || r.Author == pr.Author || isBot(r.Author) || r.CreatedAt.After(pr.MergedAt) {
+			continue
+		}
+		a := byReviewer[r.Author]
+		if a == nil {
+			a = &approval{Reviewer: r.Author, FirstReview: r.CreatedAt, Requested: requestedAt(pr, r.Author, r.CreatedAt)}
+			byReviewer[r.Author] = a
+			order = append(order, a)
+		}
+		if r.State == "APPROVED" && a.At.IsZero() {
+			a.At = r.CreatedAt
+		}
+	}
+	var out []approval
+	for _, a := range order {
+		if !a.At.IsZero() {
+			out = append(out, *a)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+func printApprovalOrder(prs []PullRequest) {
+	fmt.Println("✌️  SECOND APPROVAL LATENCY")
+	printExplanation("For PRs with two or more approvals: how long the second approval trails the first, and whether second reviewers wait for the first.",
+		"Each required approval adds a hand-off. This is the counterfactual data for dropping from two required approvals to one.")
+
+	var gaps, saved, responseFirst, responseSecond, actual, counterfactual []time.Duration
+	waited, single := 0, 0
+	for _, pr := range prs {
+		approvals := approvalOrder(pr)
+		if len(approvals) == 1 {
+			single++
+		}
+		if len(approvals) < 2 {
+			continue
+		}
+		first, second := approvals[0], approvals[1]
+		gaps = append(gaps, second.At.Sub(first.At))
+		responseFirst = append(responseFirst, max(first.FirstReview.Sub(first.Requested), 0))
+		responseSecond = append(responseSecond, max(second.FirstReview.Sub(second.Requested), 0))
+		// Asked before the first approval but only looked after it
+		if second.Requested.Before(first.At) && !second.FirstReview.Before(first.At) {
+			waited++
+		}
+
+		// Had one approval been enough, the PR could have merged once the
+		// first approval came in, with the same approval-to-merge delay
+		cycle := pr.MergedAt.Sub(pr.CreatedAt)
+		cut := min(second.At.Sub(first.At), pr.MergedAt.Sub(first.At))
+		actual = append(actual, cycle)
+		counterfactual = append(counterfactual, cycle-max(cut, 0))
+		saved = append(saved, max(cut, 0))
+	}
+	if len(gaps) == 0 {
+		fmt.Println("   No PRs with two or more approvals in this dataset.")
+		return
+	}
+
+	pct := func(n, of int) float64 { return float64(n) / float64(of) * 100 }
+	fmt.Printf("   PRs with 2+ approvals: %d (%.0f%%), with exactly 1: %d\n\n", len(gaps), pct(len(gaps), len(prs)), single)
+	fmt.Printf("   %-38s %-14s %s\n", "", "Median", "P90")
+	for _, row := range []struct {
+		Label string
+		D     []time.Duration
+	}{
+		{"First → second approval", gaps},
+		{"Response time, first approver", responseFirst},
+		{"Response time, second approver", responseSecond},
+	} {
+		fmt.Printf("   %-38s %-14s %s\n", row.Label, formatDuration(percentile(row.D, 50)), formatDuration(percentile(row.D, 90)))
+	}
+	fmt.Printf("\n   Second reviewer waited for the first: %d of %d PRs (%.0f%%)\n", waited, len(gaps), pct(waited, len(gaps)))
+	fmt.Println("   (Requested before the first approval but didn't review until after it.)")
+
+	fmt.Println("\n   If one approval had been enough:")
+	fmt.Printf("   Median merge time  %s → %s\n", formatDuration(percentile(actual, 50)), formatDuration(percentile(counterfactual, 50)))
+	fmt.Printf("   P90 merge time     %s → %s\n", formatDuration(percentile(actual, 90)), formatDuration(percentile(counterfactual, 90)))
+	var total time.Duration
+	for _, d := range saved {
+		total += d
+	}
+	fmt.Printf("   Waiting removed    %s across %d PRs\n", formatDuration(total), len(saved))
+	fmt.Println("   (Assumes the PR would have merged after the first approval with the same approval-to-merge delay. Second reviews also catch defects, which this can't price.)")
+}
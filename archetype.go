@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// archetype is a kind of repository with its own idea of healthy. Thresholds
+// override the Good and Bad bounds of health components by name.
+type archetype struct {
+	Name       string
+	Label      string
+	Thresholds map[string][2]float64
+}
+
+var archetypes = []archetype{
+	{"service", "high-velocity service", map[string][2]float64{
+		"triage": {2, 24},
+		"size":   {150, 600},
+		"stale":  {0, 30},
+	}},
+	{"library", "slow-moving library", map[string][2]float64{
+		"triage": {24, 168},
+		"stale":  {0, 70},
+	}},
+	{"docs", "docs repository", map[string][2]float64{
+		"triage":   {24, 120},
+		"size":     {100, 500},
+		"coverage": {60, 20},
+	}},
+	{"monorepo", "monorepo", map[string][2]float64{
+		"triage": {4, 48},
+		"size":   {300, 1500},
+		"heroes": {10, 40},
+	}},
+	{"general", "general", nil},
+}
+
+// monorepoContainers are the top-level directories monorepos keep their
+// projects in.
+var monorepoContainers = map[string]bool{
+	"packages": true, "services": true, "apps": true, "libs": true, "modules": true,
+	"plugins": true, "crates": true, "projects": true, "components": true,
+}
+
+func archetypeNamed(name string) (archetype, bool) {
+	for _, a := range archetypes {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return archetype{}, false
+}
+
+func validateArchetype(name string) error {
+	if _, ok := archetypeNamed(name); ok || name == "" || name == "auto" {
+		return nil
+	}
+	var names []string
+	for _, a := range archetypes {
+		names = append(names, a.Name)
+	}
+	return fmt.Errorf("archetype: unknown %q (use auto, %s)", name, strings.Join(names, ", "))
+}
+
+// classifyRepo picks the archetype the merged PRs look like, and why:
+//   - docs: 60%+ of PRs only touch documentation
+//   - monorepo: 5+ projects (under packages/, services/... or configured
+//     services) with 3+ PRs each
+//   - service: 10+ merged PRs a week
+//   - library: under 3 merged PRs a week
+func classifyRepo(merged []PullRequest) (archetype, string) {
+	general, _ := archetypeNamed("general")
+	if len(merged) < 10 {
+		return general, "too few merged PRs to tell"
+	}
+
+	docs := 0
+	projects := make(map[string]int)
+	first, last := merged[0].MergedAt, merged[0].MergedAt
+	for _, pr := range merged {
+		if isDocsOnly(pr) {
+			docs++
+		}
+		seen := make(map[string]bool)
+		for _, p := range pr.FilePaths {
+			project := ""
+			if len(cfg.Services) > 0 {
+				project = groupFor(p)
+			} else if monorepoContainers[rootDir(p)] && strings.Count(p, "/") >= 2 {
+				project = groupAt(p, 2)
+			}
+			if project != "" && !seen[project] {
+				seen[project] = true
+				projects[project]++
+			}
+		}
+		if pr.MergedAt.Before(first) {
+			first = pr.MergedAt
+		}
+		if pr.MergedAt.After(last) {
+			last = pr.MergedAt
+		}
+	}
+	weeks := max(last.Sub(first).Hours()/24/7, 1)
+	perWeek := float64(len(merged)) / weeks
+	active := 0
+	for _, n := range projects {
+		if n >= 3 {
+			active++
+		}
+	}
+
+	pick := func(name, why string) (archetype, string) {
+		a, _ := archetypeNamed(name)
+		return a, why
+	}
+	switch docsShare := float64(docs) / float64(len(merged)); {
+	case docsShare >= 0.6:
+		return pick("docs", fmt.Sprintf("%.0f%% of PRs are docs-only", docsShare*100))
+	case active >= 5:
+		return pick("monorepo", fmt.Sprintf("%d projects with 3+ PRs", active))
+	case perWeek >= 10:
+		return pick("service", fmt.Sprintf("%s merged PRs/week", formatNumber(perWeek, 1)))
+	case perWeek < 3:
+		return pick("library", fmt.Sprintf("%s merged PRs/week", formatNumber(perWeek, 1)))
+	}
+	return general, fmt.Sprintf("%s merged PRs/week", formatNumber(perWeek, 1))
+}
+
+// repoArchetype applies the archetype setting in config: auto (the
+// default) classifies the PRs, a name forces that archetype.
+func repoArchetype(merged []PullRequest) (archetype, string) {
+	if a, ok := archetypeNamed(cfg.Archetype); ok {
+		return a, "set in config"
+	}
+	return classifyRepo(merged)
+}
+
+// bounds returns the Good and Bad thresholds of a health component under
+// the archetype.
+func (a archetype) bounds(c healthComponent) (float64, float64) {
+	if t, ok := a.Thresholds[c.Name]; ok {
+		return t[0], t[1]
+	}
+	return c.Good, c.Bad
+}
+
+// describe renders the archetype's thresholds that differ from the defaults.
+func (a archetype) describe() string {
+	var parts []string
+	for _, c := range healthComponents {
+		t, ok := a.Thresholds[c.Name]
+		if !ok {
+			continue
+		}
+		switch c.Name {
+		case "triage":
+			parts = append(parts, fmt.Sprintf("triage %s-%s", formatDuration(time.Duration(t[0]*float64(time.Hour))), formatDuration(time.Duration(t[1]*float64(time.Hour)))))
+		case "size":
+			parts = append(parts, fmt.Sprintf("size %s-%s lines", formatInt(int(t[0])), formatInt(int(t[1]))))
+		default:
+			parts = append(parts, fmt.Sprintf("%s %.0f-%.0f%%", c.Name, t[0], t[1]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
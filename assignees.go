@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// assigned reports whether pr has, or had, an assignee.
+func assigned(pr PullRequest) bool {
+	return pr.AssignedAt != nil || len(pr.Assignees) > 0
+}
+
+// printAssignees reports how assignment drives the PRs: time from opening
+// to the first assignment, the open PRs nobody is assigned to, and whether
+// assigned PRs merge faster than unassigned ones.
+func printAssignees(merged, open []PullRequest, now time.Time) {
+	fmt.Println("📌 ASSIGNEES")
+	printExplanation("Time from opening to the first assignment, open PRs without an assignee, and merge speed with and without one.",
+		"Teams that drive their process through assignees stall on the PRs nobody owns.")
+
+	var toAssign, cycleAssigned, cycleUnassigned, firstAssigned, firstUnassigned []time.Duration
+	for _, pr := range append(append([]PullRequest(nil), merged...), open...) {
+		if pr.AssignedAt != nil {
+			toAssign = append(toAssign, max(0, pr.AssignedAt.Sub(pr.CreatedAt)))
+		}
+	}
+	nAssigned := 0
+	for _, pr := range merged {
+		cycle := pr.MergedAt.Sub(pr.CreatedAt)
+		if assigned(pr) {
+			nAssigned++
+			cycleAssigned = append(cycleAssigned, cycle)
+		} else {
+			cycleUnassigned = append(cycleUnassigned, cycle)
+		}
+		if pr.FirstReviewAt == nil {
+			continue
+		}
+		if wait := max(0, pr.FirstReviewAt.Sub(pr.CreatedAt)); assigned(pr) {
+			firstAssigned = append(firstAssigned, wait)
+		} else {
+			firstUnassigned = append(firstUnassigned, wait)
+		}
+	}
+	var unassigned []PullRequest
+	openAssigned := 0
+	for _, pr := range open {
+		switch {
+		case assigned(pr):
+			openAssigned++
+		case !pr.IsDraft:
+			unassigned = append(unassigned, pr)
+		}
+	}
+	if nAssigned == 0 && openAssigned == 0 {
+		fmt.Println("   No PR has an assignee; this repository doesn't work with assignees.")
+		return
+	}
+
+	if len(merged) > 0 {
+		fmt.Printf("   Merged PRs with an assignee: %d of %d (%.0f%%)\n", nAssigned, len(merged), float64(nAssigned)/float64(len(merged))*100)
+	}
+	if len(toAssign) > 0 {
+		fmt.Printf("   Time to assignment:          median %s, P90 %s (%d PRs)\n",
+			formatDuration(percentile(toAssign, 50)), formatDuration(percentile(toAssign, 90)), len(toAssign))
+	}
+
+	if len(cycleAssigned) > 0 && len(cycleUnassigned) > 0 {
+		fmt.Printf("\n   %-24s %-14s %s\n", "Median", "Assigned", "Unassigned")
+		fmt.Printf("   %-24s %-14s %s\n", "Cycle time", formatDuration(percentile(cycleAssigned, 50)), formatDuration(percentile(cycleUnassigned, 50)))
+		if len(firstAssigned) > 0 && len(firstUnassigned) > 0 {
+			fmt.Printf("   %-24s %-14s %s\n", "Time to first review", formatDuration(percentile(firstAssigned, 50)), formatDuration(percentile(firstUnassigned, 50)))
+		}
+		a, u := percentile(cycleAssigned, 50), percentile(cycleUnassigned, 50)
+		switch {
+		case a < u*9/10:
+			fmt.Printf("   🚀 Assigned PRs merge %.0f%% faster.\n", (1-float64(a)/float64(u))*100)
+		case a > u*11/10:
+			fmt.Printf("   🐢 Assigned PRs merge %.0f%% slower: assignment may mark the hard ones rather than speed them up.\n", (float64(a)/float64(u)-1)*100)
+		default:
+			fmt.Println("   Assigned and unassigned PRs merge about as fast.")
+		}
+	}
+
+	if len(open) == 0 {
+		return
+	}
+	sort.Slice(unassigned, func(i, j int) bool { return unassigned[i].CreatedAt.Before(unassigned[j].CreatedAt) })
+	fmt.Printf("\n   Unassigned open PRs (drafts left out): %d of %d\n", len(unassigned), len(open))
+	for i, pr := range unassigned {
+		if i == 5 {
+			fmt.Printf("      ... and %d more\n", len(unassigned)-5)
+			break
+		}
+		fmt.Printf("      #%-6d %-45s open %s\n", pr.Number, limitString(pr.Title, 45), formatDuration(now.Sub(pr.CreatedAt)))
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AvailabilityConfig lists the sources used to tell "away" apart from "ghosting".
+type AvailabilityConfig struct {
+	// Mode is "annotate" (default) to mark away reviewers, or "exclude" to
+	// drop them from the analyses entirely.
+	Mode string `yaml:"mode"`
+	// PTO holds explicit out-of-office ranges per login (dates are inclusive).
+	PTO map[string][]PTORange `yaml:"pto"`
+	// ICal maps logins to iCal feed URLs of their out-of-office calendar.
+	ICal map[string]string `yaml:"ical"`
+	// GitHubStatus treats a GitHub status marked "busy" as unavailable.
+	GitHubStatus bool `yaml:"github_status"`
+}
+
+type PTORange struct {
+	From   time.Time `yaml:"from"`
+	To     time.Time `yaml:"to"`
+	Reason string    `yaml:"reason"`
+}
+
+type awayPeriod struct {
+	From, To time.Time
+	Reason   string
+}
+
+// Availability answers whether a reviewer was legitimately away.
+type Availability struct {
+	exclude bool
+	away    map[string][]awayPeriod
+	busy    map[string]string // GitHub "busy" status message, by login
+}
+
+// Away reports whether login is unavailable at t, with a short reason.
+func (a *Availability) Away(login string, t time.Time) (bool, string) {
+	if a == nil {
+		return false, ""
+	}
+	for _, p := range a.away[strings.ToLower(canonicalLogin(login))] {
+		if !t.Before(p.From) && t.Before(p.To) {
+			reason := fmt.Sprintf("away until %s", formatDate(p.To.Add(-time.Second)))
+			if p.Reason != "" {
+				reason = fmt.Sprintf("%s, %s", p.Reason, reason)
+			}
+			return true, reason
+		}
+	}
+	if msg, ok := a.busy[strings.ToLower(canonicalLogin(login))]; ok {
+		if msg == "" {
+			return true, "GitHub status: busy"
+		}
+		return true, fmt.Sprintf("GitHub status: %s", msg)
+	}
+	return false, ""
+}
+
+// Exclude reports whether away reviewers should be dropped rather than annotated.
+func (a *Availability) Exclude() bool {
+	return a != nil && a.exclude
+}
+
+// awayFor returns how much of the time from `from` to `to` login spent on
+// leave or out of office by calendar. A GitHub busy status has no dates, so
+// it doesn't count.
+func (a *Availability) awayFor(login string, from, to time.Time) time.Duration {
+	if a == nil {
+		return 0
+	}
+	periods := append([]awayPeriod(nil), a.away[strings.ToLower(canonicalLogin(login))]...)
+	sort.Slice(periods, func(i, j int) bool { return periods[i].From.Before(periods[j].From) })
+	var d time.Duration
+	cur := from // Periods from PTO and calendars may overlap
+	for _, p := range periods {
+		start, end := p.From, p.To
+		if start.Before(cur) {
+			start = cur
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			d += end.Sub(start)
+			cur = end
+		}
+	}
+	return d
+}
+
+// responseWait is the part of the wait from a review request at from until
+// to that login is charged with: the time they were away doesn't count. In
+// exclude mode, waits that overlap an absence are dropped (ok is false).
+func (a *Availability) responseWait(login string, from, to time.Time) (wait time.Duration, ok bool) {
+	away := a.awayFor(login, from, to)
+	if away > 0 && a.Exclude() {
+		return 0, false
+	}
+	return max(0, to.Sub(from)-away), true
+}
+
+// validateAvailability rejects unknown availability modes.
+func validateAvailability(c AvailabilityConfig) error {
+	switch c.Mode {
+	case "", "annotate", "exclude":
+		return nil
+	}
+	return fmt.Errorf("availability: unknown mode %q (use annotate or exclude)", c.Mode)
+}
+
+// loadAvailability gathers every configured source. Logins are the people the
+// caller is about to judge (used for the GitHub status lookup). Failing
+// sources are reported as warnings and skipped.
+func loadAvailability(ctx context.Context, c AvailabilityConfig, logins []string, timeout time.Duration) (*Availability, []error) {
+	a := &Availability{
+		exclude: c.Mode == "exclude",
+		away:    make(map[string][]awayPeriod),
+		busy:    make(map[string]string),
+	}
+	var warnings []error
+
+	for login, ranges := range c.PTO {
+		for _, r := range ranges {
+			// "to" is an inclusive date
+			a.away[strings.ToLower(canonicalLogin(login))] = append(a.away[strings.ToLower(canonicalLogin(login))], awayPeriod{From: r.From, To: r.To.AddDate(0, 0, 1), Reason: r.Reason})
+		}
+	}
+
+	client := &http.Client{Timeout: timeout}
+	for login, url := range c.ICal {
+		periods, err := fetchICal(ctx, client, url)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("availability calendar for %s: %w", login, err))
+			continue
+		}
+		a.away[strings.ToLower(canonicalLogin(login))] = append(a.away[strings.ToLower(canonicalLogin(login))], periods...)
+	}
+
+	if c.GitHubStatus {
+		busy, err := fetchBusyStatuses(ctx, logins, timeout)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("GitHub status lookup: %w", err))
+		}
+		for login, msg := range busy {
+			a.busy[strings.ToLower(canonicalLogin(login))] = msg
+		}
+	}
+
+	return a, warnings
+}
+
+func fetchICal(ctx context.Context, client *http.Client, url string) ([]awayPeriod, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseICal(resp.Body)
+}
+
+// parseICal extracts VEVENT ranges from an iCal feed. All-day events end on
+// their exclusive DTEND, as specified by RFC 5545.
+func parseICal(r io.Reader) ([]awayPeriod, error) {
+	// Unfold continuation lines first
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var periods []awayPeriod
+	var cur *awayPeriod
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			cur = &awayPeriod{}
+		case line == "END:VEVENT":
+			if cur != nil && !cur.From.IsZero() {
+				if cur.To.IsZero() {
+					cur.To = cur.From.AddDate(0, 0, 1)
+				}
+				periods = append(periods, *cur)
+			}
+			cur = nil
+		case cur != nil:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			name, params, _ := strings.Cut(key, ";")
+			switch name {
+			case "DTSTART":
+				cur.From = parseICalTime(value, params)
+			case "DTEND":
+				cur.To = parseICalTime(value, params)
+			case "SUMMARY":
+				cur.Reason = value
+			}
+		}
+	}
+	return periods, nil
+}
+
+var icalTZID = regexp.MustCompile(`TZID=([^;:]+)`)
+
+func parseICalTime(value, params string) time.Time {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t
+	}
+	loc := time.UTC
+	if m := icalTZID.FindStringSubmatch(params); m != nil {
+		if l, err := time.LoadLocation(m[1]); err == nil {
+			loc = l
+		}
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// fetchBusyStatuses returns the status message of every login whose GitHub
+// status indicates limited availability. Teams (org/team) are skipped.
+func fetchBusyStatuses(ctx context.Context, logins []string, timeout time.Duration) (map[string]string, error) {
+	var users []string
+	seen := make(map[string]bool)
+	for _, l := range logins {
+		if l != "" && !strings.Contains(l, "/") && !seen[l] {
+			users = append(users, l)
+			seen[l] = true
+		}
+	}
+	sort.Strings(users)
+	busy := make(map[string]string)
+
+	// Query users in batches using aliases
+	for start := 0; start < len(users); start += 50 {
+		end := start + 50
+		if end > len(users) {
+			end = len(users)
+		}
+		var b strings.Builder
+		b.WriteString("query {\n")
+		for i, u := range users[start:end] {
+			fmt.Fprintf(&b, "  u%d: user(login: %q) { login status { indicatesLimitedAvailability message } }\n", i, u)
+		}
+		b.WriteString("}")
+
+		output, err := ghGraphQL(ctx, b.String(), timeout)
+		if err != nil {
+			return busy, err
+		}
+		var resp struct {
+			Data map[string]*struct {
+				Login  string `json:"login"`
+				Status *struct {
+					IndicatesLimitedAvailability bool   `json:"indicatesLimitedAvailability"`
+					Message                      string `json:"message"`
+				} `json:"status"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return busy, err
+		}
+		for _, u := range resp.Data {
+			if u != nil && u.Status != nil && u.Status.IndicatesLimitedAvailability {
+				busy[u.Login] = u.Status.Message
+			}
+		}
+	}
+	return busy, nil
+}
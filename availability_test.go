@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResponseWait(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	a := &Availability{away: map[string][]awayPeriod{
+		"alice": {{From: day(3), To: day(5)}, {From: day(4), To: day(6)}}, // Overlapping PTO and calendar
+	}}
+	if got, ok := a.responseWait("alice", day(1), day(8)); !ok || got != 4*24*time.Hour {
+		t.Errorf("wait = %s, %v; want 4d without the 3 days away", got, ok)
+	}
+	if got, ok := a.responseWait("bob", day(1), day(8)); !ok || got != 7*24*time.Hour {
+		t.Errorf("wait for bob = %s, %v; want all 7d", got, ok)
+	}
+	a.exclude = true
+	if _, ok := a.responseWait("alice", day(1), day(8)); ok {
+		t.Error("exclude mode kept a wait that overlapped an absence")
+	}
+	if err := validateAvailability(AvailabilityConfig{Mode: "exclued"}); err == nil {
+		t.Error("a misspelled mode was accepted")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+)
+
+// searchCap is the most results GitHub search returns for one query.
+const searchCap = 1000
+
+// completedMonths returns the n calendar months before the one now is in,
+// oldest first.
+func completedMonths(now time.Time, n int) []Period {
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var periods []Period
+	for i := n; i >= 1; i-- {
+		start := current.AddDate(0, -i, 0)
+		periods = append(periods, Period{Label: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)})
+	}
+	return periods
+}
+
+// backfillMonth fetches every merged PR of one month and summarizes it.
+func backfillMonth(ctx context.Context, owner, name string, p Period, timeout, delay time.Duration) (MonthSnapshot, error) {
+	prs, err := searchPRs(ctx, owner, name, "is:merged merged:"+p.searchRange(), searchCap, timeout, delay)
+	if err != nil {
+		return MonthSnapshot{}, err
+	}
+	snap := MonthSnapshot{
+		Month:     p.Label,
+		Count:     len(prs),
+		Metrics:   computeMetrics(prs),
+		Truncated: len(prs) >= searchCap,
+		Taken:     clock(),
+	}
+	for _, pr := range prs {
+		snap.TotalCycle += pr.MergedAt.Sub(pr.CreatedAt)
+		switch workKind(pr) {
+		case workFeature:
+			snap.Features++
+		case workMaintenance:
+			snap.Maintenance++
+		}
+	}
+	return snap, nil
+}
+
+// monthStat is one month of the trend and forecast sections.
+type monthStat struct {
+	Month   string
+	Count   int
+	Total   time.Duration
+	Stored  bool   // From a backfilled snapshot instead of the fetched PRs
+	Partial string // Why the month is incomplete, "" if it is complete
+
+	Features, Maintenance int // PRs by workKind
+}
+
+func (m monthStat) Avg() time.Duration {
+	return m.Total / time.Duration(m.Count)
+}
+
+// monthlyStats groups merged PRs by month. Months materialized by backfill
+// replace the fetched PRs, which only cover part of the oldest month. The
+// month of now is marked partial, and so is the oldest fetched month when
+// truncated reports the fetch stopped at its limit.
+func monthlyStats(prs []PullRequest, stored map[string]MonthSnapshot, now time.Time, truncated bool) []monthStat {
+	byMonth := make(map[string]*monthStat)
+	for _, pr := range prs {
+		key := pr.MergedAt.Format("2006-01")
+		if byMonth[key] == nil {
+			byMonth[key] = &monthStat{Month: key}
+		}
+		byMonth[key].Count++
+		byMonth[key].Total += pr.MergedAt.Sub(pr.CreatedAt)
+		switch workKind(pr) {
+		case workFeature:
+			byMonth[key].Features++
+		case workMaintenance:
+			byMonth[key].Maintenance++
+		}
+	}
+	for key, s := range stored {
+		if s.Count == 0 {
+			continue
+		}
+		byMonth[key] = &monthStat{Month: key, Count: s.Count, Total: s.TotalCycle, Stored: true, Features: s.Features, Maintenance: s.Maintenance}
+	}
+
+	var out []monthStat
+	for _, m := range byMonth {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Month < out[j].Month })
+
+	current := now.UTC().Format("2006-01")
+	for i := range out {
+		switch {
+		case out[i].Month == current:
+			out[i].Partial = "in progress"
+		case i == 0 && truncated && !out[i].Stored:
+			out[i].Partial = "cut off by --limit"
+		}
+	}
+	return out
+}
+
+// completeMonths drops partial months.
+func completeMonths(months []monthStat) []monthStat {
+	var out []monthStat
+	for _, m := range months {
+		if m.Partial == "" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func runBackfill(args []string) {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	months := fs.Int("months", 12, "Number of completed months to materialize")
+	force := fs.Bool("force", false, "Re-fetch months that are already in the store")
+	storeDir := fs.String("store", defaultStoreDir, "Directory of the local snapshot store")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck backfill [flags] <owner/repo>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 || *months < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+
+	store := Store{Dir: *storeDir}
+	existing, err := store.LoadMonths(repo)
+	if err != nil {
+		slog.Error("reading store", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	var done []MonthSnapshot
+	failed := false
+	for _, p := range completedMonths(clock(), *months) {
+		if _, ok := existing[p.Label]; ok && !*force {
+			slog.Debug("month already materialized", "repo", repo, "month", p.Label)
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		slog.Info("backfilling month", "repo", repo, "month", p.Label)
+		snap, err := backfillMonth(ctx, owner, name, p, *reqTimeout, *reqDelay)
+		if err != nil {
+			slog.Error("backfilling month", "repo", repo, "month", p.Label, "err", err)
+			failed = true
+			continue
+		}
+		if snap.Truncated {
+			slog.Warn("month hit the search cap; its numbers cover the first PRs only", "repo", repo, "month", p.Label, "prs", snap.Count)
+		}
+		done = append(done, snap)
+		fmt.Printf("   %s: %4d merged PRs, median %s\n", formatMonthKey(p.Label), snap.Count, formatDuration(snap.Metrics.MedianCycleTime))
+
+		// Save as we go so an interrupted backfill keeps its progress
+		if err := store.SaveMonths(repo, []MonthSnapshot{snap}); err != nil {
+			slog.Error("saving month", "repo", repo, "month", p.Label, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	slog.Info("backfill finished", "repo", repo, "months", len(done), "store", *storeDir)
+	if failed || ctx.Err() != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultReferenceRepos are the public repositories of the bundled
+// reference dataset, and the ones --live fetches: large, active projects
+// that review through GitHub pull requests.
+var defaultReferenceRepos = []string{
+	"kubernetes/kubernetes",
+	"hashicorp/terraform",
+	"grafana/grafana",
+	"prometheus/prometheus",
+	"rust-lang/rust",
+	"facebook/react",
+	"microsoft/vscode",
+	"rails/rails",
+	"django/django",
+	"cli/cli",
+}
+
+// Reference is the key metrics of one reference repository. A list of them,
+// as JSON, is a reusable reference dataset.
+type Reference struct {
+	Repo    string    `json:"repo"`
+	Taken   time.Time `json:"taken"`
+	Metrics Metrics   `json:"metrics"`
+}
+
+// bundledReferences is the reference dataset benchmarks compare against by
+// default, so a run costs one repository's fetch. Refresh it from
+// defaultReferenceRepos with
+//
+//	bottleneck benchmark --live --save-reference benchmark_reference.json <owner/repo>
+//
+//go:embed benchmark_reference.json
+var bundledReferences []byte
+
+// benchmarkMetric is one number compared against the references.
+type benchmarkMetric struct {
+	Label         string
+	LowerIsBetter bool
+	Value         func(m Metrics) float64
+	Format        func(v float64) string
+}
+
+func durationMetric(v float64) string { return formatDuration(time.Duration(v)) }
+
+var benchmarkMetrics = []benchmarkMetric{
+	{"Median cycle time", true, func(m Metrics) float64 { return float64(m.MedianCycleTime) }, durationMetric},
+	{"P90 cycle time", true, func(m Metrics) float64 { return float64(m.P90CycleTime) }, durationMetric},
+	{"Median first review", true, func(m Metrics) float64 { return float64(m.MedianFirstReview) }, durationMetric},
+	{"Reviewed PRs", false, func(m Metrics) float64 { return m.ReviewedPct }, func(v float64) string { return fmt.Sprintf("%.0f%%", v) }},
+	{"Avg review rounds", true, func(m Metrics) float64 { return m.AvgRounds }, func(v float64) string { return formatNumber(v, 1) }},
+	{"Median PR size", true, func(m Metrics) float64 { return float64(m.MedianSize) }, func(v float64) string { return formatInt(int(v)) + " lines" }},
+	{"Top reviewer share", true, func(m Metrics) float64 { return m.TopReviewerPct }, func(v float64) string { return fmt.Sprintf("%.0f%%", v) }},
+}
+
+// quantile returns the p-th quantile (0-100) of sorted values by the
+// nearest-rank method.
+func quantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+// betterThan returns the share of references v beats, counting ties as half.
+func betterThan(v float64, refs []float64, lowerIsBetter bool) float64 {
+	score := 0.0
+	for _, r := range refs {
+		switch {
+		case v == r:
+			score += 0.5
+		case (v < r) == lowerIsBetter:
+			score++
+		}
+	}
+	return score / float64(len(refs)) * 100
+}
+
+func loadReferences(path string) ([]Reference, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseReferences(path, data)
+}
+
+func parseReferences(path string, data []byte) ([]Reference, error) {
+	var refs []Reference
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return refs, nil
+}
+
+func printBenchmark(repo string, m Metrics, refs []Reference) {
+	fmt.Printf("🏁 BENCHMARK: %s vs %d reference repositories\n", repo, len(refs))
+	printExplanation("Your key numbers placed within the spread of the reference repositories (25th, 50th and 75th percentile).",
+		"\"Is a 2.5 day median good?\" depends entirely on what comparable projects achieve.")
+
+	fmt.Printf("   %-20s %-12s %-12s %-12s %-12s %s\n", "Metric", "You", "P25", "Median", "P75", "Standing")
+	for _, bm := range benchmarkMetrics {
+		var values []float64
+		for _, r := range refs {
+			values = append(values, bm.Value(r.Metrics))
+		}
+		sort.Float64s(values)
+		v := bm.Value(m)
+		pct := betterThan(v, values, bm.LowerIsBetter)
+		standing := "🟡"
+		switch {
+		case pct >= 75:
+			standing = "🟢"
+		case pct < 25:
+			standing = "🔴"
+		}
+		fmt.Printf("   %-20s %-12s %-12s %-12s %-12s %s better than %.0f%%\n", bm.Label, bm.Format(v),
+			bm.Format(quantile(values, 25)), bm.Format(quantile(values, 50)), bm.Format(quantile(values, 75)), standing, pct)
+	}
+
+	fmt.Println("\n   References:")
+	for _, r := range refs {
+		fmt.Printf("   - %-32s %4d PRs, median %s (%s)\n", r.Repo, r.Metrics.Count, formatDuration(r.Metrics.MedianCycleTime), formatDate(r.Taken))
+	}
+	fmt.Println("\n   (Reference projects differ in size and process. Use this as context, not as a target.)")
+}
+
+func runBenchmark(args []string) {
+	fs := flag.NewFlagSet("benchmark", flag.ExitOnError)
+	against := fs.String("against", "", "Comma-separated public repos to fetch live and compare against")
+	live := fs.Bool("live", false, "Fetch the bundled list of large open-source projects live instead of using the bundled reference dataset")
+	referencePath := fs.String("reference", "", "Compare against a saved reference dataset (JSON) instead of the bundled one")
+	saveReference := fs.String("save-reference", "", "Write the fetched reference metrics to this file for reuse with --reference")
+	limit := fs.Int("limit", 100, "Max number of merged PRs to fetch per repository")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck benchmark [flags] <owner/repo>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	refRepos := defaultReferenceRepos
+	if *against != "" {
+		refRepos = nil
+		for _, r := range strings.Split(*against, ",") {
+			r = strings.TrimSpace(r)
+			if _, _, err := parseRepo(r); err != nil {
+				slog.Error("invalid --against repository", "repo", r, "err", err)
+				os.Exit(1)
+			}
+			refRepos = append(refRepos, r)
+		}
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+	prs, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+	if err != nil && len(prs) == 0 {
+		slog.Error("fetching merged PRs", "repo", repo, "err", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Warn("continuing with partial data", "repo", repo, "fetched", len(prs), "err", err)
+	}
+
+	var refs []Reference
+	switch {
+	case *referencePath != "":
+		refs, err = loadReferences(*referencePath)
+		if err != nil {
+			slog.Error("loading reference dataset", "err", err)
+			os.Exit(1)
+		}
+	case !*live && *against == "":
+		refs, err = parseReferences("bundled reference dataset", bundledReferences)
+		if err != nil {
+			slog.Error("loading reference dataset", "err", err)
+			os.Exit(1)
+		}
+		if len(refs) == 0 {
+			slog.Error("this build has no bundled reference dataset; pass --live to fetch the reference repositories, --against or --reference")
+			os.Exit(1)
+		}
+		// A repository doesn't benchmark against itself
+		for i, r := range refs {
+			if strings.EqualFold(r.Repo, repo) {
+				refs = append(refs[:i], refs[i+1:]...)
+				break
+			}
+		}
+	default:
+		for _, r := range refRepos {
+			if ctx.Err() != nil {
+				break
+			}
+			if strings.EqualFold(r, repo) {
+				continue
+			}
+			o, n, _ := parseRepo(r)
+			slog.Info("fetching reference repository", "repo", r, "limit", *limit)
+			refPRs, err := fetchPRs(ctx, o, n, *limit, "MERGED", *reqTimeout, *reqDelay)
+			if err != nil || len(refPRs) == 0 {
+				slog.Warn("skipping reference repository", "repo", r, "fetched", len(refPRs), "err", err)
+				continue
+			}
+			refs = append(refs, Reference{Repo: r, Taken: clock(), Metrics: computeMetrics(refPRs)})
+		}
+		if *saveReference != "" && len(refs) > 0 {
+			data, err := json.MarshalIndent(refs, "", "  ")
+			if err == nil {
+				err = os.WriteFile(*saveReference, append(data, '\n'), 0o644)
+			}
+			if err != nil {
+				slog.Error("saving reference dataset", "err", err)
+			} else {
+				slog.Info("saved reference dataset", "path", *saveReference, "repos", len(refs))
+			}
+		}
+	}
+	if len(refs) == 0 {
+		slog.Error("no reference repositories could be analyzed")
+		os.Exit(1)
+	}
+
+	printBenchmark(repo, computeMetrics(prs), refs)
+}
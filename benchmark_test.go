@@ -0,0 +1,21 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBundledReferences(t *testing.T) {
+	refs, err := parseReferences("bundled reference dataset", bundledReferences)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) == 0 {
+		t.Fatal("benchmark_reference.json is empty, so benchmarks have nothing to compare against by default: regenerate it with benchmark --live --save-reference benchmark_reference.json")
+	}
+	for _, r := range refs {
+		if !slices.Contains(defaultReferenceRepos, r.Repo) || r.Taken.IsZero() || r.Metrics.Count == 0 {
+			t.Errorf("reference %s taken %s with %d PRs isn't one of the default reference repositories, measured", r.Repo, r.Taken, r.Metrics.Count)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BranchConfig describes the team's naming convention for head branches.
+type BranchConfig struct {
+	// Types classify branches by the first pattern their name matches.
+	// When set, they replace the built-in types.
+	Types []BranchType `yaml:"types"`
+	// Ticket is a regular expression of the ticket IDs branch names should
+	// carry (default: Jira-style keys such as ABC-123).
+	Ticket string `yaml:"ticket"`
+}
+
+// BranchType names the branches matching a regular expression, e.g.
+// {name: "feature", pattern: "^feat(ure)?/"}.
+type BranchType struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// defaultBranchTypes are the prefixes of common branch naming conventions
+// and of dependency update bots.
+var defaultBranchTypes = []BranchType{
+	{Name: "feature", Pattern: `^(feature|feat)[/-]`},
+	{Name: "fix", Pattern: `^(fix|bugfix|hotfix)[/-]`},
+	{Name: "refactor", Pattern: `^refactor[/-]`},
+	{Name: "chore", Pattern: `^(chore|maint|maintenance)[/-]`},
+	{Name: "docs", Pattern: `^docs?[/-]`},
+	{Name: "test", Pattern: `^tests?[/-]`},
+	{Name: "ci", Pattern: `^(ci|build)[/-]`},
+	{Name: "release", Pattern: `^release[/-]`},
+	{Name: "dependencies", Pattern: `^(dependabot|renovate)/`},
+}
+
+const defaultTicketPattern = `\b[A-Z][A-Z0-9]+-\d+\b`
+
+type compiledBranchType struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+const branchNoMatch = "(none)"
+
+var (
+	branchTypes  []compiledBranchType
+	branchTicket *regexp.Regexp
+)
+
+// compileBranchTypes prepares the configured branch types and ticket
+// pattern, falling back to the built-in ones.
+func compileBranchTypes() error {
+	types := cfg.Branches.Types
+	if len(types) == 0 {
+		types = defaultBranchTypes
+	}
+	branchTypes = nil
+	for _, t := range types {
+		if t.Name == "" {
+			return fmt.Errorf("branch type with pattern %q has no name", t.Pattern)
+		}
+		re, err := regexp.Compile(t.Pattern)
+		if err != nil {
+			return fmt.Errorf("branch type %s: %w", t.Name, err)
+		}
+		branchTypes = append(branchTypes, compiledBranchType{Name: t.Name, Pattern: re})
+	}
+	ticket := cfg.Branches.Ticket
+	if ticket == "" {
+		ticket = defaultTicketPattern
+	}
+	re, err := regexp.Compile(ticket)
+	if err != nil {
+		return fmt.Errorf("ticket: %w", err)
+	}
+	branchTicket = re
+	return nil
+}
+
+// branchType returns the type of a head branch name, or "(none)" when it
+// follows no known convention.
+func branchType(ref string) string {
+	for _, t := range branchTypes {
+		if t.Pattern.MatchString(ref) {
+			return t.Name
+		}
+	}
+	return branchNoMatch
+}
+
+// hasTicket reports whether a branch name carries a ticket ID.
+func hasTicket(ref string) bool {
+	return branchTicket != nil && branchTicket.MatchString(ref)
+}
+
+// branchPrefix is the first segment of a branch name, what a convention
+// would put the type in.
+func branchPrefix(ref string) string {
+	if i := strings.Index(ref, "/"); i > 0 {
+		return ref[:i+1]
+	}
+	return "(no prefix)"
+}
+
+func printBranchNaming(prs []PullRequest) {
+	fmt.Println("🌿 BRANCH NAMING")
+	printExplanation("How many PRs follow the branch naming convention (feature/, fix/, ticket IDs...), and key metrics per branch type.",
+		"A convention nobody follows is noise. Where it is followed, it shows whether fixes really move faster and ticketed work gets reviewed sooner.")
+
+	var named []PullRequest
+	for _, pr := range prs {
+		if pr.HeadRef != "" {
+			named = append(named, pr)
+		}
+	}
+	if len(named) == 0 {
+		fmt.Println("   No branch names available.")
+		return
+	}
+
+	byType := make(map[string][]PullRequest)
+	var ticketed, unticketed []PullRequest
+	prefixes := make(map[string]int)
+	authors := make(map[string]bool)
+	for _, pr := range named {
+		t := branchType(pr.HeadRef)
+		byType[t] = append(byType[t], pr)
+		if hasTicket(pr.HeadRef) {
+			ticketed = append(ticketed, pr)
+		} else {
+			unticketed = append(unticketed, pr)
+		}
+		if t == branchNoMatch {
+			prefixes[branchPrefix(pr.HeadRef)]++
+		}
+		authors[strings.ToLower(pr.Author)] = true
+	}
+
+	conforming := len(named) - len(byType[branchNoMatch])
+	fmt.Printf("   Convention adherence: %.0f%% of %d PRs use a known branch type, %.0f%% name a ticket.\n\n",
+		float64(conforming)/float64(len(named))*100, len(named), float64(len(ticketed))/float64(len(named))*100)
+
+	var types []string
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if len(byType[types[i]]) != len(byType[types[j]]) {
+			return len(byType[types[i]]) > len(byType[types[j]])
+		}
+		return types[i] < types[j]
+	})
+
+	header := "   %-14s %5s   %-14s %-14s %-14s %-7s %s\n"
+	row := func(name string, group []PullRequest) Metrics {
+		m := computeMetrics(group)
+		fmt.Printf(header, name, fmt.Sprint(m.Count), formatDuration(m.MedianCycleTime), formatDuration(m.P90CycleTime),
+			formatDuration(m.MedianFirstReview), formatNumber(m.AvgRounds, 1), formatInt(m.MedianSize))
+		return m
+	}
+	fmt.Printf(header, "Branch Type", "PRs", "Median Merge", "P90 Merge", "1st Review", "Rounds", "Median Size")
+	for _, t := range types {
+		row(t, byType[t])
+	}
+
+	if len(ticketed) > 0 {
+		fmt.Println()
+		fmt.Printf(header, "Ticket ID", "PRs", "Median Merge", "P90 Merge", "1st Review", "Rounds", "Median Size")
+		with := row("with", ticketed)
+		without := Metrics{}
+		if len(unticketed) > 0 {
+			without = row("without", unticketed)
+		}
+		if with.Count >= 5 && without.Count >= 5 && without.MedianCycleTime > 0 {
+			change := pctChange(float64(without.MedianCycleTime), float64(with.MedianCycleTime))
+			word := "faster"
+			if change > 0 {
+				word = "slower"
+			}
+			fmt.Printf("\n   💡 Branches naming a ticket merge %.0f%% %s (median %s vs %s).\n", math.Abs(change), word,
+				formatDuration(with.MedianCycleTime), formatDuration(without.MedianCycleTime))
+		}
+	}
+
+	if len(prefixes) == 0 {
+		return
+	}
+	// Personal branch prefixes are logins: fold them together unless names
+	// are shown
+	if !namesShown() {
+		folded := make(map[string]int)
+		for p, n := range prefixes {
+			if authors[strings.ToLower(strings.TrimSuffix(p, "/"))] {
+				p = "(author logins)"
+			}
+			folded[p] += n
+		}
+		prefixes = folded
+	}
+	var names []string
+	for p := range prefixes {
+		names = append(names, p)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if prefixes[names[i]] != prefixes[names[j]] {
+			return prefixes[names[i]] > prefixes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var top []string
+	for _, p := range names[:min(5, len(names))] {
+		top = append(top, fmt.Sprintf("%s (%d)", p, prefixes[p]))
+	}
+	fmt.Printf("\n   Most common prefixes outside the convention: %s\n", strings.Join(top, ", "))
+	if len(cfg.Branches.Types) == 0 {
+		fmt.Println("   (Define the team's branch types under branches in config.)")
+	}
+}
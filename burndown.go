@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pendingRequest is a review request on an open PR that hasn't been answered.
+type pendingRequest struct {
+	Number   int
+	Title    string
+	Reviewer string
+	Team     bool          // Reviewer is a team (org/team); anyone in it can answer
+	Pending  time.Duration // Less the time the reviewer was away
+	SLO      time.Duration // The PR's first-review SLA policy, else the default
+	Away     string        // Why the reviewer is away now, in annotate mode
+}
+
+// used is the share of its SLO the request has used.
+func (r pendingRequest) used() float64 {
+	return float64(r.Pending) / float64(r.SLO)
+}
+
+// pendingRequests lists every outstanding review request on non-draft PRs,
+// most of their SLO used first. A PR's SLA policy with a first_review
+// target sets its SLO; others get slo. In exclude mode, requests to
+// reviewers who are away now or were away while the request waited are left
+// out.
+func pendingRequests(prs []PullRequest, avail *Availability, slo time.Duration, now time.Time) []pendingRequest {
+	var out []pendingRequest
+	for _, pr := range prs {
+		if pr.IsDraft {
+			continue
+		}
+		prSLO := slo
+		if p := policyFor(pr); p != nil && p.FirstReview > 0 {
+			prSLO = time.Duration(p.FirstReview)
+		}
+		for _, reviewer := range pr.Requested {
+			if strings.Contains(reviewer, "/") {
+				out = append(out, pendingRequest{Number: pr.Number, Title: pr.Title, Reviewer: reviewer, Team: true,
+					Pending: now.Sub(requestedAt(pr, reviewer, now)), SLO: prSLO})
+				continue
+			}
+			away, reason := avail.Away(reviewer, now)
+			pending, ok := avail.responseWait(reviewer, requestedAt(pr, reviewer, now), now)
+			if !ok || (away && avail.Exclude()) {
+				continue
+			}
+			req := pendingRequest{Number: pr.Number, Title: pr.Title, Reviewer: reviewer, Pending: pending, SLO: prSLO}
+			if away {
+				req.Away = reason
+			}
+			out = append(out, req)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].used() != out[j].used() {
+			return out[i].used() > out[j].used()
+		}
+		if out[i].Number != out[j].Number {
+			return out[i].Number < out[j].Number
+		}
+		return out[i].Reviewer < out[j].Reviewer
+	})
+	return out
+}
+
+// sloBar renders how much of the SLO budget a request has used, capped at
+// twice the budget.
+func sloBar(used float64) string {
+	const width = 10
+	filled := int(min(used, 2) * width / 2)
+	bar := ""
+	for i := 0; i < width; i++ {
+		switch {
+		case i < filled && i >= width/2:
+			bar += "▓"
+		case i < filled:
+			bar += "█"
+		default:
+			bar += "░"
+		}
+	}
+	return bar
+}
+
+func printReviewBurnDown(prs []PullRequest, avail *Availability, slo time.Duration) {
+	fmt.Println("🔥 REVIEW REQUEST SLO BURN-DOWN")
+	printExplanation("Every outstanding review request, measured against the first-response SLO and sorted by how far over it is.",
+		"A single ghost cutoff treats a request 1h past it the same as one 3 weeks past it. Burn-down shows near-misses before they breach.")
+
+	requests := pendingRequests(prs, avail, slo, clock())
+	if len(requests) == 0 {
+		fmt.Println("   ✅ No outstanding review requests.")
+		return
+	}
+
+	ok, atRisk, breached, teams, byPolicy := 0, 0, 0, 0, 0
+	for _, r := range requests {
+		if r.Team {
+			teams++
+		}
+		if r.SLO != slo {
+			byPolicy++
+		}
+		switch used := r.used(); {
+		case used >= 1:
+			breached++
+		case used >= 0.75:
+			atRisk++
+		default:
+			ok++
+		}
+	}
+	fmt.Printf("   SLO: first response within %s", formatDuration(slo))
+	if byPolicy > 0 {
+		fmt.Printf(", or the first_review target of the PR's SLA policy (%d requests)", byPolicy)
+	}
+	fmt.Println()
+	fmt.Printf("   Outstanding: %d   ✅ On track: %d   ⚠️  At risk (>75%%): %d   🚨 Breached: %d\n\n", len(requests), ok, atRisk, breached)
+
+	const maxRows = 15
+	fmt.Printf("   %-6s %-18s %-12s %-12s %-10s %s\n", "PR", "Reviewer", "Pending", "Overdue", "Budget", "Title")
+	label := func(r pendingRequest) string {
+		if r.Team {
+			return limitString(r.Reviewer, 11) + " (team)"
+		}
+		return limitString(person(r.Reviewer), 15)
+	}
+	for i, r := range requests {
+		if i == maxRows {
+			fmt.Printf("   ... and %d more\n", len(requests)-maxRows)
+			break
+		}
+		used := r.used()
+		overdue := "-"
+		if r.Pending > r.SLO {
+			overdue = formatDuration(r.Pending - r.SLO)
+		}
+		fmt.Printf("   #%-5d %-18s %-12s %-12s %s %4.0f%%  %s\n", r.Number, label(r), formatDuration(r.Pending), overdue,
+			sloBar(used), used*100, limitString(r.Title, 35))
+		if r.Away != "" {
+			fmt.Printf("          🏖️  %s\n", r.Away)
+		}
+	}
+	fmt.Println("\n   (Budget bar: █ within the SLO, ▓ past it, full at 2x. Drafts are excluded, and time reviewers were away doesn't count.)")
+	if teams > 0 {
+		fmt.Printf("   ((team): %d requests to a team, which anyone in it can answer.)\n", teams)
+	}
+	if breached > 0 {
+		fmt.Println("   Action: Work the list top-down, or reassign requests that are far overdue.")
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// burnoutSignals are one person's (or team's) review load signals over a
+// set of PRs.
+type burnoutSignals struct {
+	Reviews    int
+	Share      float64       // Share of all reviews
+	AfterHours float64       // Share of reviews and own commits outside work hours
+	Latency    time.Duration // Median response to review requests
+	Depth      float64       // Share of reviewed PRs with changes requested or comments, not just an approval
+}
+
+// collectBurnoutSignals computes the signals per person, keyed by person()
+// so team mode adds up per team.
+func collectBurnoutSignals(prs []PullRequest) map[string]*burnoutSignals {
+	out := make(map[string]*burnoutSignals)
+	get := func(login string) *burnoutSignals {
+		key := person(login)
+		if privacy == privacyAggregate {
+			key = login // Counted, not shown
+		}
+		if out[key] == nil {
+			out[key] = &burnoutSignals{}
+		}
+		return out[key]
+	}
+	activity := make(map[*burnoutSignals][2]int) // After hours, total
+	depth := make(map[*burnoutSignals][2]int)    // Engaged PRs, reviewed PRs
+	latencies := make(map[*burnoutSignals][]time.Duration)
+	total := 0
+	tally := func(s *burnoutSignals, login string, t time.Time) {
+		loc := locationOf(login)
+		if loc == nil {
+			loc = primaryLocation
+		}
+		a := activity[s]
+		if !atWork(t, loc) {
+			a[0]++
+		}
+		a[1]++
+		activity[s] = a
+	}
+
+	for _, pr := range prs {
+		engaged := make(map[string]bool)
+		reviewed := make(map[string]bool)
+		for _, r := range pr.Reviews {
+			if r.Author == "" || r.Author == pr.Author || isBot(r.Author) {
+				continue
+			}
+			s := get(r.Author)
+			s.Reviews++
+			total++
+			tally(s, r.Author, r.CreatedAt)
+			if !reviewed[r.Author] {
+				reviewed[r.Author] = true
+				d := r.CreatedAt.Sub(requestedAt(pr, r.Author, r.CreatedAt))
+				latencies[s] = append(latencies[s], max(d, 0))
+			}
+			if r.State == "CHANGES_REQUESTED" || r.State == "COMMENTED" {
+				engaged[r.Author] = true
+			}
+		}
+		for login := range reviewed {
+			s := get(login)
+			d := depth[s]
+			if engaged[login] {
+				d[0]++
+			}
+			d[1]++
+			depth[s] = d
+		}
+		if pr.Author != "" && !isBot(pr.Author) {
+			for _, c := range pr.CommitTimes {
+				tally(get(pr.Author), pr.Author, c)
+			}
+		}
+	}
+
+	for _, s := range out {
+		if total > 0 {
+			s.Share = float64(s.Reviews) / float64(total)
+		}
+		if a := activity[s]; a[1] > 0 {
+			s.AfterHours = float64(a[0]) / float64(a[1])
+		}
+		if d := depth[s]; d[1] > 0 {
+			s.Depth = float64(d[0]) / float64(d[1])
+		}
+		if l := latencies[s]; len(l) > 0 {
+			s.Latency = percentile(l, 50)
+		}
+	}
+	return out
+}
+
+// clamp01 scales v linearly from lo (0) to hi (1).
+func clamp01(v, lo, hi float64) float64 {
+	return math.Max(0, math.Min(1, (v-lo)/(hi-lo)))
+}
+
+// burnoutRisk combines recent load and after-hours work with how response
+// latency and review depth changed since the earlier period, into 0-100.
+type burnoutRisk struct {
+	Name                      string
+	Recent, Earlier           burnoutSignals
+	Load, Hours, Creep, Depth float64 // Component scores, 0-1
+}
+
+// Components are weighted: load 30, after hours 30, latency creep 20,
+// declining depth 20.
+func (r burnoutRisk) Score() float64 {
+	return (0.3*r.Load + 0.3*r.Hours + 0.2*r.Creep + 0.2*r.Depth) * 100
+}
+
+// Change is the movement of the load and after-hours components from the
+// earlier period to the recent one, in score points.
+func (r burnoutRisk) Change() float64 {
+	earlier := 0.3*clamp01(r.Earlier.Share, 0.15, 0.4) + 0.3*clamp01(r.Earlier.AfterHours, 0.15, 0.45)
+	return (0.3*r.Load + 0.3*r.Hours - earlier) * 100
+}
+
+func burnoutLevel(score float64) string {
+	switch {
+	case score >= 60:
+		return "🔴 High"
+	case score >= 35:
+		return "🟠 Elevated"
+	}
+	return "🟢 Low"
+}
+
+// burnoutRisks splits prs at the midpoint of their merge dates and rates
+// everyone with at least minReviews reviews in the recent half.
+func burnoutRisks(prs []PullRequest, minReviews int) []burnoutRisk {
+	if len(prs) == 0 {
+		return nil
+	}
+	sorted := append([]PullRequest(nil), prs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MergedAt.Before(sorted[j].MergedAt) })
+	mid := sorted[0].MergedAt.Add(sorted[len(sorted)-1].MergedAt.Sub(sorted[0].MergedAt) / 2)
+	cut := sort.Search(len(sorted), func(i int) bool { return sorted[i].MergedAt.After(mid) })
+	earlier, recent := collectBurnoutSignals(sorted[:cut]), collectBurnoutSignals(sorted[cut:])
+
+	var out []burnoutRisk
+	for name, s := range recent {
+		if s.Reviews < minReviews {
+			continue
+		}
+		r := burnoutRisk{Name: name, Recent: *s}
+		r.Load = clamp01(s.Share, 0.15, 0.4)
+		r.Hours = clamp01(s.AfterHours, 0.15, 0.45)
+		if e := earlier[name]; e != nil {
+			r.Earlier = *e
+			if e.Latency > 0 {
+				r.Creep = clamp01(float64(s.Latency)/float64(e.Latency), 1, 2)
+			}
+			if e.Reviews >= minReviews {
+				r.Depth = clamp01(e.Depth-s.Depth, 0, 0.3)
+			}
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score() != out[j].Score() {
+			return out[i].Score() > out[j].Score()
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func printBurnoutRisk(prs []PullRequest) {
+	fmt.Println("🔥 MAINTAINER BURNOUT RISK")
+	printExplanation("A composite of four signals over the recent half of the window: share of all reviews (load), reviews and commits outside work hours, response latency creeping up, and reviews getting shallower (approvals without comments).",
+		"Each signal alone is noise. Together they show who is carrying too much before they burn out or leave. A prompt for a conversation, never a performance metric.")
+
+	risks := burnoutRisks(prs, 5)
+	if len(risks) == 0 {
+		fmt.Println("   Not enough reviews in the recent half of the window (5 per person).")
+		return
+	}
+
+	if privacy == privacyAggregate {
+		counts := make(map[string]int)
+		for _, r := range risks {
+			counts[burnoutLevel(r.Score())]++
+		}
+		fmt.Printf("   Reviewers rated: %d   🔴 High: %d   🟠 Elevated: %d   🟢 Low: %d\n", len(risks), counts["🔴 High"], counts["🟠 Elevated"], counts["🟢 Low"])
+		fmt.Println("   (--privacy aggregate: individuals aren't shown.)")
+		return
+	}
+
+	label := "Reviewer"
+	if privacy == privacyTeam {
+		label = "Team"
+	}
+	fmt.Printf("   %-16s %-12s %5s  %-6s %-11s %-15s %-9s %s\n", label, "Risk", "Score", "Load", "After hrs", "Latency", "Depth", "Trend")
+	for i, r := range risks {
+		if i == 10 {
+			break
+		}
+		latency := formatDuration(r.Recent.Latency)
+		if r.Earlier.Latency > 0 {
+			latency = fmt.Sprintf("%s (%+.0f%%)", latency, pctChange(float64(r.Earlier.Latency), float64(r.Recent.Latency)))
+		}
+		depth := fmt.Sprintf("%.0f%%", r.Recent.Depth*100)
+		if r.Earlier.Reviews >= 5 {
+			depth = fmt.Sprintf("%.0f→%.0f%%", r.Earlier.Depth*100, r.Recent.Depth*100)
+		}
+		trend := "→"
+		switch c := r.Change(); {
+		case c >= 10:
+			trend = "↑ rising"
+		case c <= -10:
+			trend = "↓ easing"
+		}
+		fmt.Printf("   %-16s %-12s %5.0f  %5.0f%% %9.0f%%  %-15s %-9s %s\n", limitString(r.Name, 15), burnoutLevel(r.Score()), r.Score(),
+			r.Recent.Share*100, r.Recent.AfterHours*100, latency, depth, trend)
+	}
+	fmt.Println("\n   (Load: share of all reviews. After hrs: outside timezones.work_hours or weekends, in their configured timezone.")
+	fmt.Println("    Depth: reviewed PRs with changes requested or comments. Trend: load and after-hours against the earlier half.)")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CapacityConfig tunes review capacity planning.
+type CapacityConfig struct {
+	// ReviewHoursPerWeek is the time each reviewer spends reviewing in a
+	// week (default 5).
+	ReviewHoursPerWeek float64 `yaml:"review_hours_per_week"`
+	// Reviewers overrides the number of available reviewers, which is
+	// otherwise everyone who reviewed 3+ PRs in the last four weeks.
+	Reviewers int `yaml:"reviewers"`
+}
+
+func (c CapacityConfig) hoursPerWeek() float64 {
+	if c.ReviewHoursPerWeek > 0 {
+		return c.ReviewHoursPerWeek
+	}
+	return 5
+}
+
+func validateCapacity(c CapacityConfig) error {
+	if c.ReviewHoursPerWeek < 0 || c.ReviewHoursPerWeek > 168 {
+		return fmt.Errorf("capacity: review_hours_per_week must be between 0 and 168")
+	}
+	if c.Reviewers < 0 {
+		return fmt.Errorf("capacity: reviewers must not be negative")
+	}
+	return nil
+}
+
+// Review effort model: a fixed cost per review, reading time for each
+// reviewer's first pass, and writing time per conversation comment.
+const (
+	effortPerReview  = 15 * time.Minute
+	effortPerComment = 5 * time.Minute
+	readingSpeed     = 500 // Lines per hour
+	maxReadingTime   = 2 * time.Hour
+)
+
+// reviewEffort estimates the reviewer time a PR took.
+func reviewEffort(pr PullRequest) time.Duration {
+	reading := min(time.Duration(float64(pr.Size)/readingSpeed*float64(time.Hour)), maxReadingTime)
+	var effort time.Duration
+	seen := make(map[string]bool)
+	for _, r := range pr.Reviews {
+		if r.Author == "" || r.Author == pr.Author || isBot(r.Author) {
+			continue
+		}
+		effort += effortPerReview
+		if !seen[r.Author] {
+			seen[r.Author] = true
+			effort += reading
+		}
+	}
+	for _, c := range pr.Comments {
+		if c.Author != "" && c.Author != pr.Author && !isBot(c.Author) {
+			effort += effortPerComment
+		}
+	}
+	return effort
+}
+
+// activeReviewers counts who reviewed at least three PRs in the four weeks
+// before now.
+func activeReviewers(prs []PullRequest, now time.Time) int {
+	since := now.AddDate(0, 0, -28)
+	counts := make(map[string]int)
+	for _, pr := range prs {
+		seen := make(map[string]bool)
+		for _, r := range pr.Reviews {
+			if r.Author == "" || r.Author == pr.Author || isBot(r.Author) || seen[r.Author] || r.CreatedAt.Before(since) {
+				continue
+			}
+			seen[r.Author] = true
+			counts[r.Author]++
+		}
+	}
+	active := 0
+	for _, n := range counts {
+		if n >= 3 {
+			active++
+		}
+	}
+	return active
+}
+
+func printCapacityPlan(merged, open []PullRequest) {
+	fmt.Println("🧮 REVIEW CAPACITY PLAN (Next 30 Days)")
+	printExplanation("Reviewer-hours next month will need (forecast PR volume × estimated review effort per PR) against the hours the available reviewers have.",
+		"Turns \"we're swamped\" into a number for hiring and rotation discussions: how many reviewer-hours short the team is.")
+
+	now := clock()
+	weeks := queueWeeks(merged, open, now)
+	if len(weeks) < 4 {
+		fmt.Println("   Not enough complete weeks in this dataset (need 4).")
+		return
+	}
+	rates := weeklyRates(weeks[len(weeks)-4:])
+
+	var efforts []time.Duration
+	var total time.Duration
+	for _, pr := range merged {
+		e := reviewEffort(pr)
+		efforts = append(efforts, e)
+		total += e
+	}
+	mean := total / time.Duration(len(efforts))
+	sort.Slice(efforts, func(i, j int) bool { return efforts[i] < efforts[j] })
+
+	const perMonth = 30.0 / 7
+	volume := rates.Arrivals * perMonth
+	needed := volume * mean.Hours()
+
+	reviewers, source := cfg.Capacity.Reviewers, "capacity.reviewers in config"
+	if reviewers == 0 {
+		reviewers, source = activeReviewers(merged, now), "reviewed 3+ PRs in the last 4 weeks"
+	}
+	available := float64(reviewers) * cfg.Capacity.hoursPerWeek() * perMonth
+
+	fmt.Printf("   Review effort per PR: mean %s, median %s, P90 %s\n", formatDuration(mean), formatDuration(percentile(efforts, 50)), formatDuration(percentile(efforts, 90)))
+	fmt.Printf("   Forecast volume:      ~%.0f PRs (%s opened/week over the last 4 weeks)\n\n", volume, formatNumber(rates.Arrivals, 1))
+	fmt.Printf("   Needed:    %5.0f reviewer-hours\n", needed)
+	fmt.Printf("   Available: %5.0f reviewer-hours (%d reviewers × %s h/week; %s)\n", available, reviewers, formatNumber(cfg.Capacity.hoursPerWeek(), 1), source)
+
+	perReviewer := cfg.Capacity.hoursPerWeek() * perMonth
+	switch gap := needed - available; {
+	case reviewers == 0:
+		fmt.Println("\n   🚨 No active reviewers in the last 4 weeks.")
+	case gap > 0:
+		more := int(math.Ceil(gap / perReviewer))
+		noun := "reviewers"
+		if more == 1 {
+			noun = "reviewer"
+		}
+		fmt.Printf("\n   🚨 Short by %.0f reviewer-hours (%.0f%% over capacity): about %d more %s at %s h/week, or more review time from the current ones.\n",
+			gap, gap/available*100, more, noun, formatNumber(cfg.Capacity.hoursPerWeek(), 1))
+	case gap > -0.15*available:
+		fmt.Printf("\n   ⚠️  At %.0f%% of capacity. Any absence or busy week puts the team behind.\n", needed/available*100)
+	default:
+		fmt.Printf("\n   ✅ At %.0f%% of capacity. %.0f reviewer-hours to spare.\n", needed/available*100, -gap)
+	}
+	fmt.Println("   (Effort estimate: 15 min per review, reading at 500 lines/hour up to 2h per reviewer, 5 min per comment. Set the review time per reviewer with capacity: in config.)")
+}
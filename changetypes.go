@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var conventionalTitle = regexp.MustCompile(`^\s*([A-Za-z][\w-]*)(?:\([^)]*\))?!?:\s`)
+
+// conventionalTypes are the change types of the Conventional Commits spec
+// and its common Angular extensions.
+var conventionalTypes = []string{"feat", "fix", "perf", "refactor", "docs", "test", "build", "ci", "chore", "style", "revert"}
+
+// changeType returns the conventional-commit type of a PR title. Prefixes
+// listed under change_types in config map to the type they alias; other
+// unknown prefixes are "other", titles without one "untyped".
+func changeType(title string) string {
+	m := conventionalTitle.FindStringSubmatch(title)
+	if m == nil {
+		return "untyped"
+	}
+	prefix := strings.ToLower(m[1])
+	for alias, t := range cfg.ChangeTypes {
+		if strings.EqualFold(alias, prefix) {
+			return strings.ToLower(t)
+		}
+	}
+	for _, t := range conventionalTypes {
+		if t == prefix {
+			return t
+		}
+	}
+	return "other"
+}
+
+func printChangeTypes(prs []PullRequest) {
+	fmt.Println("🏷️  CHANGE TYPES")
+	printExplanation("Key metrics per conventional-commit type, read from PR titles (feat:, fix(api):, chore!: ...).",
+		"Fixes should be fast-tracked and chores cheap to review. This shows whether they actually are.")
+
+	byType := make(map[string][]PullRequest)
+	for _, pr := range prs {
+		t := changeType(pr.Title)
+		byType[t] = append(byType[t], pr)
+	}
+	if len(byType) == 1 && byType["untyped"] != nil {
+		fmt.Println("   No PR titles use conventional-commit prefixes (feat:, fix:, ...).")
+		return
+	}
+
+	var types []string
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if len(byType[types[i]]) != len(byType[types[j]]) {
+			return len(byType[types[i]]) > len(byType[types[j]])
+		}
+		return types[i] < types[j]
+	})
+
+	fmt.Printf("   %-10s %5s   %-14s %-14s %-14s %-7s %s\n", "Type", "PRs", "Median Merge", "P90 Merge", "1st Review", "Rounds", "Median Size")
+	metrics := make(map[string]Metrics)
+	for _, t := range types {
+		m := computeMetrics(byType[t])
+		metrics[t] = m
+		fmt.Printf("   %-10s %5d   %-14s %-14s %-14s %-7s %s\n", t, m.Count, formatDuration(m.MedianCycleTime), formatDuration(m.P90CycleTime),
+			formatDuration(m.MedianFirstReview), formatNumber(m.AvgRounds, 1), formatInt(m.MedianSize))
+	}
+
+	fix, feat := metrics["fix"], metrics["feat"]
+	if fix.Count >= 5 && feat.Count >= 5 && feat.MedianCycleTime > 0 {
+		change := pctChange(float64(feat.MedianCycleTime), float64(fix.MedianCycleTime))
+		fmt.Println()
+		if change < 0 {
+			fmt.Printf("   💡 Fixes merge %.0f%% faster than features (median %s vs %s).\n", -change, formatDuration(fix.MedianCycleTime), formatDuration(feat.MedianCycleTime))
+		} else {
+			fmt.Printf("   ⚠️  Fixes are not fast-tracked: they merge %.0f%% slower than features (median %s vs %s).\n", change, formatDuration(fix.MedianCycleTime), formatDuration(feat.MedianCycleTime))
+		}
+	}
+	if n := len(byType["untyped"]); n > 0 {
+		fmt.Printf("   (%d PRs without a prefix are \"untyped\"; unknown prefixes are \"other\". Map custom prefixes with change_types in config.)\n", n)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chart geometry, in SVG user units.
+const (
+	chartWidth  = 720.0
+	chartHeight = 400.0
+	chartLeft   = 70.0 // Room for the value axis labels
+	chartRight  = 20.0
+	chartTop    = 50.0
+	chartBottom = 70.0 // Room for the category labels
+)
+
+func svgEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// svgChart starts an SVG document with a white background and a title.
+func svgChart(title string) *strings.Builder {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#fff"/>`+"\n")
+	fmt.Fprintf(&b, `<text x="%.0f" y="30" font-size="18" font-weight="bold" fill="#222">%s</text>`+"\n", chartLeft, svgEscape(title))
+	return &b
+}
+
+// svgAxis draws horizontal grid lines with value labels from 0 to top.
+func svgAxis(b *strings.Builder, top float64, label func(float64) string) {
+	plotH := chartHeight - chartTop - chartBottom
+	for i := 0; i <= 4; i++ {
+		v := top * float64(i) / 4
+		y := chartHeight - chartBottom - plotH*float64(i)/4
+		fmt.Fprintf(b, `<line x1="%.0f" y1="%.1f" x2="%.0f" y2="%.1f" stroke="#e5e5e5"/>`+"\n", chartLeft, y, chartWidth-chartRight, y)
+		fmt.Fprintf(b, `<text x="%.0f" y="%.1f" font-size="11" fill="#666" text-anchor="end">%s</text>`+"\n", chartLeft-8, y+4, svgEscape(label(v)))
+	}
+}
+
+// niceTop rounds v up to 1, 2, 4 or 8 times a power of ten, so the four
+// grid steps stay round.
+func niceTop(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 4, 8, 10} {
+		if m*p >= v {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// svgBarChart renders one bar per label. Values are counts.
+func svgBarChart(title string, labels []string, values []float64, label func(float64) string) string {
+	b := svgChart(title)
+	top := 4.0 // Whole grid steps for small counts
+	for _, v := range values {
+		top = max(top, v)
+	}
+	top = niceTop(top)
+	svgAxis(b, top, label)
+
+	plotW, plotH := chartWidth-chartLeft-chartRight, chartHeight-chartTop-chartBottom
+	slot := plotW / float64(max(len(values), 1))
+	for i, v := range values {
+		h := plotH * v / top
+		x := chartLeft + slot*float64(i) + slot*0.15
+		y := chartHeight - chartBottom - h
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#4e79a7"><title>%s: %s</title></rect>`+"\n",
+			x, y, slot*0.7, h, svgEscape(labels[i]), svgEscape(label(v)))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="11" fill="#222" text-anchor="middle">%s</text>`+"\n", x+slot*0.35, y-4, svgEscape(label(v)))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="11" fill="#444" text-anchor="end" transform="rotate(-30 %.1f %.1f)">%s</text>`+"\n",
+			x+slot*0.35, chartHeight-chartBottom+16, x+slot*0.35, chartHeight-chartBottom+16, svgEscape(labels[i]))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// svgLineChart renders values as a line with a point per label.
+func svgLineChart(title string, labels []string, values []float64, label func(float64) string) string {
+	b := svgChart(title)
+	top := 0.0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	top = niceTop(top)
+	svgAxis(b, top, label)
+
+	plotW, plotH := chartWidth-chartLeft-chartRight, chartHeight-chartTop-chartBottom
+	step := plotW / float64(max(len(values), 1))
+	var points []string
+	for i, v := range values {
+		x := chartLeft + step*(float64(i)+0.5)
+		y := chartHeight - chartBottom - plotH*v/top
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="4" fill="#e15759"><title>%s: %s</title></circle>`+"\n", x, y, svgEscape(labels[i]), svgEscape(label(v)))
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="11" fill="#444" text-anchor="middle">%s</text>`+"\n", x, chartHeight-chartBottom+18, svgEscape(labels[i]))
+	}
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="#e15759" stroke-width="2"/>`+"\n", strings.Join(points, " "))
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// writeCharts writes the monthly trend, the merge time histogram and the
+// reviewer load of one repository as SVG files to dir. There is no PNG
+// output: rasterizing text would need a font renderer outside the standard
+// library. It returns the files written.
+func writeCharts(dir, repo string, merged []PullRequest, months []monthStat) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	prefix := strings.ReplaceAll(repo, "/", "_")
+	hours := func(v float64) string { return formatDuration(time.Duration(v * float64(time.Hour))) }
+	count := func(v float64) string { return formatNumber(v, 0) }
+
+	charts := map[string]string{}
+	if len(months) > 0 {
+		var labels []string
+		var values []float64
+		for _, m := range months {
+			label := formatMonthKey(m.Month)
+			if m.Partial != "" {
+				label += " (partial)"
+			}
+			labels = append(labels, label)
+			values = append(values, m.Avg().Hours())
+		}
+		charts["trend"] = svgLineChart(repo+": average merge time per month", labels, values, hours)
+	}
+	if len(merged) > 0 {
+		var durations []time.Duration
+		for _, pr := range merged {
+			durations = append(durations, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+		var labels []string
+		var values []float64
+		for _, bucket := range histogramCounts(durations) {
+			labels = append(labels, bucket.Label)
+			values = append(values, float64(bucket.Count))
+		}
+		charts["merge-histogram"] = svgBarChart(repo+": merge time distribution (PRs)", labels, values, count)
+	}
+	// Reviewer load names people, so aggregate privacy leaves it out
+	if counts, total := countReviews(merged); total > 0 && privacy != privacyAggregate {
+		per := "reviewer"
+		if privacy == privacyTeam {
+			per = "team"
+		}
+		counts = byPerson(counts)
+		type load struct {
+			Name  string
+			Count int
+		}
+		var loads []load
+		for name, c := range counts {
+			loads = append(loads, load{name, c})
+		}
+		sort.Slice(loads, func(i, j int) bool {
+			if loads[i].Count != loads[j].Count {
+				return loads[i].Count > loads[j].Count
+			}
+			return loads[i].Name < loads[j].Name
+		})
+		var labels []string
+		var values []float64
+		for i, l := range loads {
+			if i == 15 {
+				break
+			}
+			labels = append(labels, l.Name)
+			values = append(values, float64(l.Count))
+		}
+		charts["reviewer-load"] = svgBarChart(fmt.Sprintf("%s: reviews per %s (top %d of %d)", repo, per, len(labels), len(loads)), labels, values, count)
+	}
+
+	var names []string
+	for name := range charts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var written []string
+	for _, name := range names {
+		path := filepath.Join(dir, prefix+"-"+name+".svg")
+		if err := os.WriteFile(path, []byte(charts[name]), 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
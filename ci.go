@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ciBatch is how many PRs one check-run query covers.
+const ciBatch = 10
+
+// CheckRun is one attempt of a CI check on a PR's head commit. Re-runs of
+// a check are separate attempts with the same name.
+type CheckRun struct {
+	Name        string
+	Conclusion  string    // SUCCESS, FAILURE, TIMED_OUT, CANCELLED, ...
+	QueuedAt    time.Time // When it could start (see readyAt)
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Duration is how long the attempt ran.
+func (c CheckRun) Duration() time.Duration {
+	if c.StartedAt.IsZero() || c.CompletedAt.Before(c.StartedAt) {
+		return 0
+	}
+	return c.CompletedAt.Sub(c.StartedAt)
+}
+
+func (c CheckRun) failed() bool {
+	return c.Conclusion == "FAILURE" || c.Conclusion == "TIMED_OUT"
+}
+
+// fetchCheckRuns fetches every check-run attempt, re-runs included, on the
+// head commit of each PR, keyed by PR number. On failure it returns the
+// batches fetched so far.
+func fetchCheckRuns(ctx context.Context, owner, name string, prs []PullRequest, timeout, delay time.Duration) (map[int][]CheckRun, error) {
+	runs := make(map[int][]CheckRun)
+	for start := 0; start < len(prs); start += ciBatch {
+		if start > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return runs, err
+			}
+		}
+		batch := prs[start:min(start+ciBatch, len(prs))]
+		var b strings.Builder
+		fmt.Fprintf(&b, "query {\n  repository(owner: %q, name: %q) {\n", owner, name)
+		for _, pr := range batch {
+			fmt.Fprintf(&b, `    pr%d: pullRequest(number: %d) {
+      commits(last: 1) { nodes { commit { checkSuites(first: 10) { nodes {
+        createdAt
+        checkRuns(first: 50, filterBy: {checkType: ALL}) { nodes { name conclusion startedAt completedAt } }
+      } } } } }
+    }
+`, pr.Number, pr.Number)
+		}
+		b.WriteString("  }\n}")
+
+		output, err := ghGraphQL(ctx, b.String(), timeout)
+		if err != nil {
+			return runs, err
+		}
+		var resp struct {
+			Data struct {
+				Repository map[string]*struct {
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								CheckSuites struct {
+									Nodes []struct {
+										CreatedAt time.Time `json:"createdAt"`
+										CheckRuns struct {
+											Nodes []struct {
+												Name        string     `json:"name"`
+												Conclusion  string     `json:"conclusion"`
+												StartedAt   *time.Time `json:"startedAt"`
+												CompletedAt *time.Time `json:"completedAt"`
+											} `json:"nodes"`
+										} `json:"checkRuns"`
+									} `json:"nodes"`
+								} `json:"checkSuites"`
+							} `json:"commit"`
+						} `json:"nodes"`
+					} `json:"commits"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return runs, err
+		}
+		for _, pr := range batch {
+			node := resp.Data.Repository[fmt.Sprintf("pr%d", pr.Number)]
+			if node == nil {
+				continue
+			}
+			for _, c := range node.Commits.Nodes {
+				for _, suite := range c.Commit.CheckSuites.Nodes {
+					var suiteRuns []CheckRun
+					for _, r := range suite.CheckRuns.Nodes {
+						if r.StartedAt == nil || r.CompletedAt == nil {
+							continue // Still running or never started
+						}
+						suiteRuns = append(suiteRuns, CheckRun{
+							Name:        r.Name,
+							Conclusion:  r.Conclusion,
+							StartedAt:   *r.StartedAt,
+							CompletedAt: *r.CompletedAt,
+						})
+					}
+					for i := range suiteRuns {
+						suiteRuns[i].QueuedAt = readyAt(suite.CreatedAt, suiteRuns[i], suiteRuns)
+					}
+					runs[pr.Number] = append(runs[pr.Number], suiteRuns...)
+				}
+			}
+		}
+	}
+	return runs, nil
+}
+
+// readyAt is when run could start: the creation of its check suite, or the
+// latest completion of another run in the suite that finished before it
+// started. A job that `needs:` others waits for them, not for a runner, so
+// its queue time counts from when its prerequisites were done. The API
+// doesn't say which runs a job needs; any earlier run in the suite is
+// treated as one.
+func readyAt(suiteCreated time.Time, run CheckRun, suite []CheckRun) time.Time {
+	ready := suiteCreated
+	for _, r := range suite {
+		if r.Name != run.Name && r.CompletedAt.After(ready) && !r.CompletedAt.After(run.StartedAt) {
+			ready = r.CompletedAt
+		}
+	}
+	return ready
+}
+
+// checkAttempts groups a PR's check runs by check name, oldest attempt
+// first.
+func checkAttempts(runs []CheckRun) map[string][]CheckRun {
+	byName := make(map[string][]CheckRun)
+	for _, r := range runs {
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+	for _, attempts := range byName {
+		sort.Slice(attempts, func(i, j int) bool { return attempts[i].StartedAt.Before(attempts[j].StartedAt) })
+	}
+	return byName
+}
+
+func printFlakyChecks(runs map[int][]CheckRun) {
+	fmt.Println("🎲 FLAKY CHECKS")
+	printExplanation("Checks that failed and then passed on a re-run of the same commit, with the CI time and PR delay those re-runs cost.",
+		"Flaky CI is a silent review-to-merge bottleneck: approved PRs wait on re-runs, and people learn to ignore red builds.")
+
+	type checkStat struct {
+		Name              string
+		PRs, Rerun, Flaky int
+		Reruns            int
+		Wasted            time.Duration // Runner time of superseded attempts
+		Delay             time.Duration // Final attempt finishing later than the first
+	}
+	stats := make(map[string]*checkStat)
+	var totalWasted, totalDelay time.Duration
+	for _, prRuns := range runs {
+		for name, attempts := range checkAttempts(prRuns) {
+			s := stats[name]
+			if s == nil {
+				s = &checkStat{Name: name}
+				stats[name] = s
+			}
+			s.PRs++
+			if len(attempts) < 2 {
+				continue
+			}
+			s.Rerun++
+			s.Reruns += len(attempts) - 1
+			last := attempts[len(attempts)-1]
+			failedBefore := false
+			for _, a := range attempts[:len(attempts)-1] {
+				failedBefore = failedBefore || a.failed()
+				s.Wasted += a.Duration()
+			}
+			if failedBefore && last.Conclusion == "SUCCESS" {
+				s.Flaky++
+			}
+			s.Delay += last.CompletedAt.Sub(attempts[0].CompletedAt)
+		}
+	}
+	if len(stats) == 0 {
+		fmt.Println("   No check runs found on the merged PRs.")
+		return
+	}
+
+	var list []*checkStat
+	for _, s := range stats {
+		totalWasted += s.Wasted
+		totalDelay += s.Delay
+		if s.Rerun > 0 {
+			list = append(list, s)
+		}
+	}
+	fmt.Printf("   PRs with check data: %d, distinct checks: %d\n\n", len(runs), len(stats))
+	if len(list) == 0 {
+		fmt.Println("   ✅ No check was re-run on the same commit.")
+		return
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Flaky != list[j].Flaky {
+			return list[i].Flaky > list[j].Flaky
+		}
+		if list[i].Reruns != list[j].Reruns {
+			return list[i].Reruns > list[j].Reruns
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	fmt.Printf("   %-32s %6s %7s %7s %8s   %s\n", "Check", "PRs", "Re-run", "Flaky", "Flaky %", "CI time re-run")
+	for i, s := range list {
+		if i == 10 {
+			fmt.Printf("   ... and %d more re-run checks\n", len(list)-10)
+			break
+		}
+		fmt.Printf("   %-32s %6d %7d %7d %7.0f%%   %s\n", limitString(s.Name, 32), s.PRs, s.Rerun, s.Flaky,
+			float64(s.Flaky)/float64(s.PRs)*100, formatDuration(s.Wasted))
+	}
+
+	fmt.Printf("\n   Estimated CI time spent on superseded attempts: %s\n", formatNumber(totalWasted.Hours(), 1)+"h")
+	fmt.Printf("   Estimated delay added to PRs by re-runs:        %s\n", formatNumber(totalDelay.Hours(), 1)+"h")
+	if top := list[0]; top.Flaky > 0 {
+		fmt.Printf("\n   Action: Fix or quarantine %q first. It failed and then passed on a re-run in %d PRs.\n", top.Name, top.Flaky)
+	}
+	fmt.Println("   (Only the head commit of each PR is checked. Flaky: failed, then passed on the same commit.)")
+}
+
+func printCheckTiming(runs map[int][]CheckRun) {
+	fmt.Println("⏱️  CI QUEUE VS EXECUTION")
+	printExplanation("Per check, how long it waited for a runner after the push (or after the checks it depends on) versus how long it actually ran.",
+		"Long queues are a runner capacity problem, long runs are a slow test problem. They need different fixes.")
+
+	type checkTiming struct {
+		Name       string
+		Queue, Run []time.Duration
+	}
+	timings := make(map[string]*checkTiming)
+	for _, prRuns := range runs {
+		for name, attempts := range checkAttempts(prRuns) {
+			// Re-runs wait for someone to trigger them, so only the first
+			// attempt has a meaningful queue time
+			first := attempts[0]
+			if first.QueuedAt.IsZero() || first.StartedAt.Before(first.QueuedAt) {
+				continue
+			}
+			if timings[name] == nil {
+				timings[name] = &checkTiming{Name: name}
+			}
+			timings[name].Queue = append(timings[name].Queue, first.StartedAt.Sub(first.QueuedAt))
+			timings[name].Run = append(timings[name].Run, first.Duration())
+		}
+	}
+	if len(timings) == 0 {
+		fmt.Println("   No check timing data on the merged PRs.")
+		return
+	}
+
+	var list []*checkTiming
+	var allQueue, allRun []time.Duration
+	for _, t := range timings {
+		list = append(list, t)
+		allQueue = append(allQueue, t.Queue...)
+		allRun = append(allRun, t.Run...)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		qi, qj := percentile(list[i].Queue, 50)+percentile(list[i].Run, 50), percentile(list[j].Queue, 50)+percentile(list[j].Run, 50)
+		if qi != qj {
+			return qi > qj
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	fmt.Printf("   %-32s %5s   %-12s %-12s %-12s %s\n", "Check", "Runs", "Queue (med)", "Queue (P90)", "Run (med)", "Bottleneck")
+	var capacity, slow []string
+	for i, t := range list {
+		queue, queue90, run := percentile(t.Queue, 50), percentile(t.Queue, 90), percentile(t.Run, 50)
+		verdict := ""
+		switch {
+		case queue90 >= 5*time.Minute && queue > run:
+			verdict = "🚦 runners"
+			capacity = append(capacity, t.Name)
+		case run >= 15*time.Minute:
+			verdict = "🐢 tests"
+			slow = append(slow, t.Name)
+		}
+		if i < 15 {
+			fmt.Printf("   %-32s %5d   %-12s %-12s %-12s %s\n", limitString(t.Name, 32), len(t.Run), formatDuration(queue), formatDuration(queue90), formatDuration(run), verdict)
+		}
+	}
+	if len(list) > 15 {
+		fmt.Printf("   ... and %d more checks\n", len(list)-15)
+	}
+
+	var totalQueue, totalRun time.Duration
+	for _, d := range allQueue {
+		totalQueue += d
+	}
+	for _, d := range allRun {
+		totalRun += d
+	}
+	if totalQueue+totalRun > 0 {
+		fmt.Printf("\n   Share of CI time spent queued: %.0f%%\n", float64(totalQueue)/float64(totalQueue+totalRun)*100)
+	}
+	if len(capacity) > 0 {
+		fmt.Printf("   🚦 Runner capacity: %d checks wait longer for a runner than they run (e.g. %s). Add runners or spread scheduled jobs.\n", len(capacity), capacity[0])
+	}
+	if len(slow) > 0 {
+		fmt.Printf("   🐢 Slow tests: %d checks run 15m or more (e.g. %s). Split, parallelize or cache them.\n", len(slow), slow[0])
+	}
+	fmt.Println("   (Queue: check suite created, or earlier checks in it done → check started, first attempt only. 🚦: P90 queue ≥ 5m and longer than the run.)")
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadyAt(t *testing.T) {
+	push := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return push.Add(time.Duration(m) * time.Minute) }
+	suite := []CheckRun{
+		{Name: "build", StartedAt: at(1), CompletedAt: at(10)},
+		{Name: "lint", StartedAt: at(2), CompletedAt: at(4)},
+		{Name: "deploy", StartedAt: at(12), CompletedAt: at(15)}, // needs: [build, lint]
+	}
+	for i, want := range []time.Time{push, push, at(10)} {
+		if got := readyAt(push, suite[i], suite); !got.Equal(want) {
+			t.Errorf("%s ready at %s, want %s", suite[i].Name, got.Sub(push), want.Sub(push))
+		}
+	}
+}
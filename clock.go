@@ -0,0 +1,7 @@
+package main
+
+import "time"
+
+// clock returns the current time. Reports read "now" through it so golden
+// tests can pin it.
+var clock = time.Now
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Codeowners holds the parsed rules of a repository's CODEOWNERS file.
+type Codeowners struct {
+	Rules []CodeownersRule
+}
+
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string // Logins and org/team slugs, without the leading @
+	re      *regexp.Regexp
+}
+
+// fetchCodeowners looks up CODEOWNERS in the locations GitHub honours
+// (.github/, root, docs/) on the default branch. It returns nil when the
+// repository has none.
+func fetchCodeowners(ctx context.Context, owner, name string, timeout time.Duration) (*Codeowners, error) {
+	locations := []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+	files, err := fetchRepoFiles(ctx, owner, name, locations, timeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, loc := range locations {
+		if text := files[loc]; text != "" {
+			return parseCodeowners(text), nil
+		}
+	}
+	return nil, nil
+}
+
+// fetchRepoFiles reads files from the default branch in a single query.
+// Missing files are absent from the result.
+func fetchRepoFiles(ctx context.Context, owner, name string, paths []string, timeout time.Duration) (map[string]string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "query {\n  repository(owner: %q, name: %q) {\n", owner, name)
+	for i, p := range paths {
+		fmt.Fprintf(&b, "    f%d: object(expression: %q) { ... on Blob { text } }\n", i, "HEAD:"+p)
+	}
+	b.WriteString("  }\n}")
+
+	output, err := ghGraphQL(ctx, b.String(), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Repository map[string]*struct {
+				Text string `json:"text"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	for i, p := range paths {
+		if blob := resp.Data.Repository[fmt.Sprintf("f%d", i)]; blob != nil {
+			files[p] = blob.Text
+		}
+	}
+	return files, nil
+}
+
+func parseCodeowners(text string) *Codeowners {
+	co := &Codeowners{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		rule := CodeownersRule{Pattern: fields[0], re: codeownersPattern(fields[0])}
+		for _, o := range fields[1:] {
+			if strings.HasPrefix(o, "#") {
+				break
+			}
+			// Email owners can't be matched against review requests
+			if strings.HasPrefix(o, "@") {
+				rule.Owners = append(rule.Owners, strings.TrimPrefix(o, "@"))
+			}
+		}
+		co.Rules = append(co.Rules, rule)
+	}
+	return co
+}
+
+// codeownersPattern converts a gitignore-style CODEOWNERS pattern to a regexp.
+func codeownersPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.Trim(pattern, "/"), "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+	if p == "*" {
+		return regexp.MustCompile(".*")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+
+	prefix := "^(.*/)?"
+	if anchored {
+		prefix = "^"
+	}
+	suffix := "(/.*)?$"
+	if dirOnly {
+		suffix = "/.*$"
+	} else if strings.HasSuffix(p, "/*") {
+		// "docs/*" owns files directly in docs/, not in subdirectories
+		suffix = "$"
+	}
+	return regexp.MustCompile(prefix + b.String() + suffix)
+}
+
+// OwnersFor returns the owners of a path. As in GitHub, the last matching
+// rule wins.
+func (c *Codeowners) OwnersFor(path string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].re.MatchString(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// IsRequired reports whether reviewer owns any of the PR's files, which makes
+// their review required for merge rather than a courtesy request.
+func (c *Codeowners) IsRequired(pr PullRequest, reviewer string) bool {
+	for _, path := range pr.FilePaths {
+		for _, o := range c.OwnersFor(path) {
+			if strings.EqualFold(o, reviewer) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Cohort is a group of merged PRs compared as a unit, e.g. a quarter or the
+// PRs that shipped in one release.
+type Cohort struct {
+	Label string
+	PRs   []PullRequest
+}
+
+// Release is a tag and the date of the commit it points to.
+type Release struct {
+	Tag  string
+	Date time.Time
+}
+
+// fetchReleases lists up to limit tags, newest first, with their commit
+// dates. Annotated tags are resolved to their target commit.
+func fetchReleases(ctx context.Context, owner, name string, limit int, timeout, delay time.Duration) ([]Release, error) {
+	var releases []Release
+	var cursor string
+
+	queryTmpl := `
+query {
+  repository(owner: %q, name: %q) {
+    refs(%s) {
+      nodes {
+        name
+        target {
+          ... on Commit { committedDate }
+          ... on Tag { target { ... on Commit { committedDate } } }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	for len(releases) < limit {
+		if cursor != "" {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return releases, err
+			}
+		}
+		args := fmt.Sprintf(`refPrefix: "refs/tags/", first: %d, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}`, min(100, limit-len(releases)))
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, owner, name, args), timeout)
+		if err != nil {
+			return releases, err
+		}
+		var resp struct {
+			Data struct {
+				Repository struct {
+					Refs struct {
+						Nodes []struct {
+							Name   string `json:"name"`
+							Target struct {
+								CommittedDate *time.Time `json:"committedDate"`
+								Target        *struct {
+									CommittedDate *time.Time `json:"committedDate"`
+								} `json:"target"`
+							} `json:"target"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"refs"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return releases, err
+		}
+
+		refs := resp.Data.Repository.Refs
+		for _, n := range refs.Nodes {
+			date := n.Target.CommittedDate
+			if date == nil && n.Target.Target != nil {
+				date = n.Target.Target.CommittedDate
+			}
+			if date != nil {
+				releases = append(releases, Release{Tag: n.Name, Date: *date})
+			}
+		}
+		if !refs.PageInfo.HasNextPage {
+			break
+		}
+		cursor = refs.PageInfo.EndCursor
+	}
+	return releases, nil
+}
+
+// cohortsByQuarter groups PRs by the quarter they merged in.
+func cohortsByQuarter(prs []PullRequest) []Cohort {
+	byLabel := make(map[string][]PullRequest)
+	for _, pr := range prs {
+		label := fmt.Sprintf("%d-Q%d", pr.MergedAt.Year(), (int(pr.MergedAt.Month())-1)/3+1)
+		byLabel[label] = append(byLabel[label], pr)
+	}
+	var labels []string
+	for l := range byLabel {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	var cohorts []Cohort
+	for _, l := range labels {
+		cohorts = append(cohorts, Cohort{Label: l, PRs: byLabel[l]})
+	}
+	return cohorts
+}
+
+// cohortsByRelease assigns each PR to the first release made after it
+// merged, i.e. the PRs merged between tag N and N+1 form cohort N+1. Tags
+// not matching pattern are ignored. If pattern has a capture group, releases
+// with the same captured value are merged into one cohort (e.g. `^v(\d+)\.`
+// groups by major version). PRs merged after the last release are
+// "unreleased".
+func cohortsByRelease(prs []PullRequest, releases []Release, pattern *regexp.Regexp) []Cohort {
+	type tagged struct {
+		Release
+		Cohort string
+	}
+	var tags []tagged
+	for _, r := range releases {
+		label := r.Tag
+		if pattern != nil {
+			m := pattern.FindStringSubmatch(r.Tag)
+			if m == nil {
+				continue
+			}
+			if len(m) > 1 {
+				label = m[1]
+			}
+		}
+		tags = append(tags, tagged{r, label})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Date.Before(tags[j].Date) })
+
+	byLabel := make(map[string][]PullRequest)
+	var order []string
+	add := func(label string, pr PullRequest) {
+		if _, ok := byLabel[label]; !ok {
+			order = append(order, label)
+		}
+		byLabel[label] = append(byLabel[label], pr)
+	}
+
+	sorted := make([]PullRequest, len(prs))
+	copy(sorted, prs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MergedAt.Before(sorted[j].MergedAt) })
+	for _, pr := range sorted {
+		i := sort.Search(len(tags), func(i int) bool { return !tags[i].Date.Before(pr.MergedAt) })
+		if i == len(tags) {
+			add("unreleased", pr)
+		} else {
+			add(tags[i].Cohort, pr)
+		}
+	}
+
+	var cohorts []Cohort
+	for _, l := range order {
+		cohorts = append(cohorts, Cohort{Label: l, PRs: byLabel[l]})
+	}
+	return cohorts
+}
+
+func printCohorts(title string, cohorts []Cohort) {
+	fmt.Printf("🧬 COHORTS BY %s\n", title)
+	printExplanation("Headline metrics per cohort of merged PRs, compared with the cohort before.",
+		"Answers questions like \"did the 2.x release cycle move faster than 1.x?\" without hand-slicing date ranges.")
+
+	if len(cohorts) == 0 {
+		fmt.Println("   No cohorts found.")
+		return
+	}
+
+	fmt.Printf("   %-16s %5s   %-14s %-14s %-14s %-7s %s\n", "Cohort", "PRs", "Median Merge", "P90 Merge", "1st Review", "Rounds", "vs prev")
+	var prev *Metrics
+	for _, c := range cohorts {
+		m := computeMetrics(c.PRs)
+		change := ""
+		if prev != nil && prev.Count > 0 {
+			change = changeLabel(float64(prev.MedianCycleTime), float64(m.MedianCycleTime))
+		}
+		fmt.Printf("   %-16s %5d   %-14s %-14s %-14s %-7s %s\n", limitString(c.Label, 13), m.Count,
+			formatDuration(m.MedianCycleTime), formatDuration(m.P90CycleTime), formatDuration(m.MedianFirstReview),
+			formatNumber(m.AvgRounds, 1), change)
+		prev = &m
+	}
+	fmt.Println("\n   (\"vs prev\" compares median merge time: ▲ slower, ▼ faster.)")
+	for _, c := range cohorts {
+		if len(c.PRs) < 5 {
+			fmt.Println("   (Cohorts with fewer than 5 PRs are noisy.)")
+			break
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// compareMetric is one row of a repo-vs-repo comparison. Sample returns the
+// per-PR values the significance test runs on; nil means the metric is a
+// single number with no test.
+type compareMetric struct {
+	benchmarkMetric
+	Sample func(prs []PullRequest) []float64
+}
+
+var compareMetrics = []compareMetric{
+	{benchmarkMetrics[0], func(prs []PullRequest) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			out = append(out, float64(pr.MergedAt.Sub(pr.CreatedAt)))
+		}
+		return out
+	}},
+	{benchmarkMetrics[1], nil},
+	{benchmarkMetrics[2], func(prs []PullRequest) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			if pr.FirstReviewAt != nil {
+				out = append(out, float64(max(0, pr.FirstReviewAt.Sub(pr.CreatedAt))))
+			}
+		}
+		return out
+	}},
+	{benchmarkMetrics[3], nil}, // Tested as a proportion
+	{benchmarkMetrics[4], func(prs []PullRequest) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			if r := reviewRounds(pr); r > 0 {
+				out = append(out, float64(r))
+			}
+		}
+		return out
+	}},
+	{benchmarkMetrics[5], func(prs []PullRequest) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			out = append(out, float64(pr.Size))
+		}
+		return out
+	}},
+	{benchmarkMetrics[6], nil},
+}
+
+// mannWhitneyP returns the two-sided p-value of a Mann-Whitney U test using
+// the normal approximation with tie correction.
+func mannWhitneyP(a, b []float64) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	type obs struct {
+		V     float64
+		First bool
+	}
+	var all []obs
+	for _, v := range a {
+		all = append(all, obs{v, true})
+	}
+	for _, v := range b {
+		all = append(all, obs{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].V < all[j].V })
+
+	rankSum, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].V == all[i].V {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // Average of ranks i+1..j
+		for k := i; k < j; k++ {
+			if all[k].First {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	u := rankSum - n1*(n1+1)/2
+	n := n1 + n2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (u - n1*n2/2) / sigma
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// proportionP returns the two-sided p-value of a two-proportion z-test.
+func proportionP(x1, n1, x2, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	p1, p2 := float64(x1)/float64(n1), float64(x2)/float64(n2)
+	pool := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pool * (1 - pool) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 1
+	}
+	return math.Erfc(math.Abs(p1-p2) / se / math.Sqrt2)
+}
+
+func reviewedCount(prs []PullRequest) int {
+	n := 0
+	for _, pr := range prs {
+		if reviewRounds(pr) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func printComparison(repoA, repoB string, a, b []PullRequest) {
+	fmt.Printf("⚖️  COMPARISON: %s vs %s\n", repoA, repoB)
+	printExplanation("Key numbers side by side, with a significance test wherever per-PR data allows one.",
+		"Two services can differ by chance alone. Only differences marked significant are worth chasing as process differences.")
+
+	ma, mb := computeMetrics(a), computeMetrics(b)
+	fmt.Printf("   %-20s %-14s %-14s %-10s %s\n", "Metric", limitString(repoA, 13), limitString(repoB, 13), "Diff", "")
+	significant := 0
+	for _, cm := range compareMetrics {
+		va, vb := cm.Value(ma), cm.Value(mb)
+		p := -1.0
+		switch {
+		case cm.Sample != nil:
+			p = mannWhitneyP(cm.Sample(a), cm.Sample(b))
+		case cm.Label == "Reviewed PRs":
+			p = proportionP(reviewedCount(a), len(a), reviewedCount(b), len(b))
+		}
+
+		diff := changeLabel(va, vb)
+		mark := ""
+		switch {
+		case p < 0:
+		case p < 0.01:
+			mark = "‼️  significant (p<0.01)"
+			significant++
+		case p < 0.05:
+			mark = "❗ significant (p<0.05)"
+			significant++
+		default:
+			mark = fmt.Sprintf("   not significant (p=%s)", formatNumber(p, 2))
+		}
+		if mark != "" && p < 0.05 && va != vb {
+			better := repoA
+			if (vb < va) == cm.LowerIsBetter {
+				better = repoB
+			}
+			mark += ", better: " + better
+		}
+		fmt.Printf("   %-20s %-14s %-14s %-10s %s\n", cm.Label, cm.Format(va), cm.Format(vb), diff, mark)
+	}
+	fmt.Printf("\n   (%d and %d merged PRs. Diff is %s relative to %s; ▲ means higher.)\n", len(a), len(b), repoB, repoA)
+	if significant == 0 {
+		fmt.Println("   No significant differences: the two repositories review at a comparable pace.")
+	}
+	archA, whyA := classifyRepo(a)
+	archB, whyB := classifyRepo(b)
+	if archA.Name != archB.Name {
+		fmt.Printf("   ⚠️  Different kinds of repository: %s is a %s (%s), %s a %s (%s). Some differences are expected.\n",
+			repoA, archA.Label, whyA, repoB, archB.Label, whyB)
+	}
+}
+
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	limit := fs.Int("limit", 100, "Max number of merged PRs to fetch per repository")
+	excludeOutliers := fs.Bool("exclude-outliers", false, "Exclude top and bottom 5% of outliers")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck compare [flags] <owner/repoA> <owner/repoB>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	for _, repo := range fs.Args() {
+		if _, _, err := parseRepo(repo); err != nil {
+			slog.Error("invalid repository", "repo", repo, "err", err)
+			os.Exit(1)
+		}
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	var sets [2][]PullRequest
+	for i, repo := range fs.Args() {
+		owner, name, _ := parseRepo(repo)
+		slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+		prs, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+		if err != nil && len(prs) == 0 {
+			slog.Error("fetching merged PRs", "repo", repo, "err", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			slog.Warn("continuing with partial data", "repo", repo, "fetched", len(prs), "err", err)
+		}
+		generated, err := loadGeneratedMatcher(ctx, owner, name, *reqTimeout)
+		if err != nil {
+			slog.Warn("could not fetch .gitattributes", "repo", repo, "err", err)
+		}
+		markGenerated(prs, generated, true)
+		if *excludeOutliers {
+			prs = filterOutliers(prs)
+		}
+		sets[i] = prs
+	}
+
+	printComparison(fs.Arg(0), fs.Arg(1), sets[0], sets[1])
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// testFile matches test sources by the common conventions: test
+// directories, foo_test.go, foo.test.ts, foo.spec.js, test_foo.py and
+// FooTest.java.
+var testFile = regexp.MustCompile(`(^|/)(tests?|__tests__|spec)/|_test\.\w+$|\.(test|spec)\.\w+$|(^|/)test_[^/]+\.py$|Tests?\.(java|kt|cs|swift)$`)
+
+func isTestFile(p string) bool { return testFile.MatchString(p) }
+
+// prComplexity is a complexity proxy that, unlike line count, tells a
+// mechanical rename from a dense change: one point per changed source file,
+// half a point per test file and two points per extra directory spanned.
+// Files beyond the fetched paths count as source.
+func prComplexity(pr PullRequest) float64 {
+	dirs := make(map[string]bool)
+	score := float64(pr.ChangedFiles - len(pr.FilePaths))
+	for _, p := range pr.FilePaths {
+		dirs[path.Dir(p)] = true
+		if isTestFile(p) {
+			score += 0.5
+		} else {
+			score++
+		}
+	}
+	if len(dirs) > 1 {
+		score += 2 * float64(len(dirs)-1)
+	}
+	return score
+}
+
+// pearson is the Pearson correlation of xs and ys, or 0 when either doesn't
+// vary.
+func pearson(xs, ys []float64) float64 {
+	var sumX, sumY, sumXY, sumX2, sumY2 float64
+	n := float64(len(xs))
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumX2 += xs[i] * xs[i]
+		sumY2 += ys[i] * ys[i]
+	}
+	denominator := math.Sqrt((n*sumX2 - sumX*sumX) * (n*sumY2 - sumY*sumY))
+	if denominator == 0 || math.IsNaN(denominator) {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// complexityCorrelation is the Pearson correlation between prComplexity
+// and hours to merge.
+func complexityCorrelation(prs []PullRequest) float64 {
+	var xs, ys []float64
+	for _, pr := range prs {
+		xs = append(xs, prComplexity(pr))
+		ys = append(ys, pr.MergedAt.Sub(pr.CreatedAt).Hours())
+	}
+	return pearson(xs, ys)
+}
+
+// quartileBand is one quarter of the PRs ranked by a measure.
+type quartileBand struct {
+	Max    float64 // Largest measure in the band
+	Median time.Duration
+}
+
+// quartileBands ranks prs by measure and returns the median merge time of
+// each quarter, smallest first.
+func quartileBands(prs []PullRequest, measure func(PullRequest) float64) []quartileBand {
+	sorted := append([]PullRequest(nil), prs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return measure(sorted[i]) < measure(sorted[j]) })
+	var bands []quartileBand
+	for q := 0; q < 4; q++ {
+		part := sorted[len(sorted)*q/4 : len(sorted)*(q+1)/4]
+		if len(part) == 0 {
+			continue
+		}
+		var cycle []time.Duration
+		for _, pr := range part {
+			cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+		bands = append(bands, quartileBand{Max: measure(part[len(part)-1]), Median: percentile(cycle, 50)})
+	}
+	return bands
+}
+
+// printComplexityBands prints merge time per quarter of PRs by line count
+// and by complexity, and which of the two predicts merge time better.
+func printComplexityBands(prs []PullRequest, sizeCorr float64) {
+	complexityCorr := complexityCorrelation(prs)
+	fmt.Printf("\n   Complexity proxy: source files + ½ per test file + 2 per extra directory\n")
+	fmt.Printf("   Correlation Coeff: %s (lines: %s)\n", formatNumber(complexityCorr, 2), formatNumber(sizeCorr, 2))
+	if len(prs) < 8 {
+		return
+	}
+
+	lines := quartileBands(prs, func(pr PullRequest) float64 { return float64(pr.Size) })
+	complexity := quartileBands(prs, prComplexity)
+	fmt.Printf("\n   %-8s %-12s %-14s %-17s %s\n", "Quarter", "Lines up to", "Median merge", "Complexity up to", "Median merge")
+	for i := range lines {
+		fmt.Printf("   %-8s %-12s %-14s %-17s %s\n", fmt.Sprintf("Q%d", i+1), formatInt(int(lines[i].Max)), formatDuration(lines[i].Median),
+			formatNumber(complexity[i].Max, 1), formatDuration(complexity[i].Median))
+	}
+
+	switch {
+	case complexityCorr > 0.3 && complexityCorr > sizeCorr+0.1:
+		fmt.Println("\n   Insight: Spread across files and directories predicts merge time better than line count.")
+		fmt.Println("   Action:  Split PRs by area, not just by size. Big mechanical changes are cheaper than they look.")
+	case sizeCorr > 0.3 && sizeCorr > complexityCorr+0.1:
+		fmt.Println("\n   Insight: Line count predicts merge time better than spread. Dense changes are the slow ones.")
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultConfigFile = ".bottleneck.yml"
+
+// Config is loaded from .bottleneck.yml (or --config) and tunes the analyses.
+type Config struct {
+	// Services maps path prefixes to named services/teams, e.g.
+	// "services/payments": "Payments Team". When set, every directory-based
+	// analysis groups files by service instead of by root directory.
+	Services map[string]string `yaml:"services"`
+
+	// Availability feeds out-of-office data into the reviewer analyses.
+	Availability AvailabilityConfig `yaml:"availability"`
+
+	// Rotation tunes eligibility for `bottleneck rotation`.
+	Rotation RotationConfig `yaml:"rotation"`
+
+	// Teams maps team names to member logins.
+	Teams map[string][]string `yaml:"teams"`
+
+	// Identities merges the accounts of one person and marks bot accounts.
+	Identities IdentityConfig `yaml:"identities"`
+
+	// Policies are SLA rules for different classes of PRs. The first
+	// matching policy applies.
+	Policies []Policy `yaml:"policies"`
+
+	// Goals are targets for key metrics, shown at the top of every report.
+	Goals []Goal `yaml:"goals"`
+
+	// Capacity tunes review capacity planning.
+	Capacity CapacityConfig `yaml:"capacity"`
+
+	// Org weights repositories in the aggregate of multi-repo runs.
+	Org OrgConfig `yaml:"org"`
+
+	// AI configures the optional --ai-insights section.
+	AI AIConfig `yaml:"ai"`
+
+	// FileCategories classify changed files ahead of the built-in
+	// extension-based categories.
+	FileCategories []FileCategory `yaml:"file_categories"`
+
+	// WIP limits the number of simultaneously open PRs per author or team.
+	WIP WIPConfig `yaml:"wip"`
+
+	// Stale exempts intentionally parked PRs from stale detection.
+	Stale StaleConfig `yaml:"stale"`
+
+	// Histogram overrides the distribution buckets.
+	Histogram HistogramConfig `yaml:"histogram"`
+
+	// Generated lists extra gitignore-style patterns of generated files,
+	// excluded from size metrics alongside the built-in ones.
+	Generated []string `yaml:"generated"`
+
+	// Health weights the components of the review health score.
+	Health HealthConfig `yaml:"health"`
+
+	// Archetype sets the kind of repository the health thresholds suit:
+	// auto (default) classifies it from its PRs; service, library, docs,
+	// monorepo or general force one.
+	Archetype string `yaml:"archetype"`
+
+	// Timezones sets the team's primary timezone and where reviewers work.
+	Timezones TimezoneConfig `yaml:"timezones"`
+
+	// ChangeTypes maps custom PR title prefixes to conventional-commit
+	// types, e.g. "hotfix": "fix".
+	ChangeTypes map[string]string `yaml:"change_types"`
+
+	// Branches sets the head branch naming convention: branch types and
+	// the ticket IDs names should carry.
+	Branches BranchConfig `yaml:"branches"`
+
+	// Template names the PR template sections a PR must fill in.
+	Template TemplateConfig `yaml:"template"`
+
+	// Notify configures delivery of ghost reviewer digests.
+	Notify NotifyConfig `yaml:"notify"`
+
+	// Events publishes per-PR records and aggregates to Kafka or NATS
+	// (see bottleneck daemon --publish).
+	Events EventsConfig `yaml:"events"`
+
+	// Sections selects the report sections and their order. A section may
+	// appear more than once with different parameters.
+	Sections []SectionConfig `yaml:"sections"`
+
+	// Presets are named bundles of sections and flag defaults for --preset.
+	Presets map[string]Preset `yaml:"presets"`
+
+	// Privacy is the default --privacy mode: off, team or aggregate.
+	Privacy string `yaml:"privacy"`
+
+	// FirstReview defines when a PR got its first review: review (the
+	// first formal review, default), comment (the first review or comment
+	// by someone other than the author) or human (the first formal review
+	// by someone other than the author, bots excluded).
+	FirstReview string `yaml:"first_review"`
+}
+
+// cfg is the active configuration, loaded once at startup.
+var cfg Config
+
+// loadConfig reads the config file at path. An empty path falls back to
+// .bottleneck.yml in the working directory, which is optional.
+func loadConfig(path string) (Config, error) {
+	var c Config
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return c, err
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// applyConfig loads the config file into cfg and prepares derived state.
+func applyConfig(path string) error {
+	c, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if err := validateHistogram(c.Histogram); err != nil {
+		return err
+	}
+	if err := validateHealth(c.Health); err != nil {
+		return err
+	}
+	if err := validateArchetype(c.Archetype); err != nil {
+		return err
+	}
+	if err := validateAvailability(c.Availability); err != nil {
+		return err
+	}
+	if err := validateSections(c.Sections); err != nil {
+		return err
+	}
+	if err := validatePresets(c.Presets); err != nil {
+		return err
+	}
+	if err := applyIdentities(c.Identities); err != nil {
+		return err
+	}
+	if err := validateTeams(c.Teams); err != nil {
+		return err
+	}
+	if err := applyTimezones(c.Timezones); err != nil {
+		return err
+	}
+	if err := validateGoals(c.Goals); err != nil {
+		return err
+	}
+	if err := validateCapacity(c.Capacity); err != nil {
+		return err
+	}
+	if err := validateOrg(c.Org); err != nil {
+		return err
+	}
+	if err := validateFirstReview(c.FirstReview); err != nil {
+		return err
+	}
+	if err := validateEvents(c.Events); err != nil {
+		return err
+	}
+	cfg = c
+	compileFileCategories()
+	if err := compileBranchTypes(); err != nil {
+		return fmt.Errorf("branches: %w", err)
+	}
+	if c.Privacy != "" {
+		if err := setPrivacy(c.Privacy); err != nil {
+			return fmt.Errorf("privacy: %w", err)
+		}
+	}
+	return nil
+}
+
+// configFields are the optional PR fields config settings read: labels for
+// stale.exempt.labels, the timeline for first_review: comment.
+func configFields() prField {
+	var f prField
+	if len(cfg.Stale.Exempt.Labels) > 0 {
+		f |= fieldLabels
+	}
+	if cfg.FirstReview == firstReviewComment {
+		f |= fieldTimeline
+	}
+	return f
+}
+
+// groupFor returns the analysis group for a file path: the service with the
+// longest matching prefix when a mapping is configured, else the root directory.
+func groupFor(path string) string {
+	if len(cfg.Services) > 0 {
+		prefixes := make([]string, 0, len(cfg.Services))
+		for p := range cfg.Services {
+			prefixes = append(prefixes, p)
+		}
+		sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+		for _, p := range prefixes {
+			prefix := strings.TrimSuffix(p, "/")
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return cfg.Services[p]
+			}
+		}
+	}
+	return rootDir(path)
+}
+
+// groupAt returns the analysis group of a file path at a directory depth:
+// its first depth directories, or groupFor's grouping when depth is 0.
+func groupAt(path string, depth int) string {
+	if depth <= 0 {
+		return groupFor(path)
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) == 1 {
+		return "(root files)"
+	}
+	return strings.Join(parts[:min(depth, len(parts)-1)], "/")
+}
+
+// depthLabel names the grouping of a section grouping at depth.
+func depthLabel(depth int) string {
+	if depth > 0 {
+		return fmt.Sprintf("DIRECTORY (depth %d)", depth)
+	}
+	return groupLabel()
+}
+
+// groupLabel names the grouping used by directory-based sections.
+func groupLabel() string {
+	if len(cfg.Services) > 0 {
+		return "SERVICE"
+	}
+	return "DIRECTORY"
+}
+
+// validateTeams rejects a person listed in two teams, under any of their
+// accounts: teamOf could return either, and SLA policies, the team latency
+// matrix and privacy labels would change from run to run.
+func validateTeams(teams map[string][]string) error {
+	names := make([]string, 0, len(teams))
+	for team := range teams {
+		names = append(names, team)
+	}
+	sort.Strings(names)
+	member := make(map[string]string)
+	for _, team := range names {
+		for _, m := range teams[team] {
+			l := strings.ToLower(canonicalLogin(m))
+			if other, ok := member[l]; ok && other != team {
+				return fmt.Errorf("teams: %s is in both %s and %s", m, other, team)
+			}
+			member[l] = team
+		}
+	}
+	return nil
+}
+
+// teamOf returns the configured team of a login, or "" if unmapped.
+func teamOf(login string) string {
+	for team, members := range cfg.Teams {
+		for _, m := range members {
+			if strings.EqualFold(canonicalLogin(m), canonicalLogin(login)) {
+				return team
+			}
+		}
+	}
+	return ""
+}
+
+// Duration is a time.Duration that also accepts day ("3d") and week ("2w")
+// units in the config file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	v, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// parseDuration extends time.ParseDuration with "d" (24h) and "w" (7d) units.
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for _, unit := range []struct {
+		Suffix string
+		Size   time.Duration
+	}{{"d", 24 * time.Hour}, {"w", 7 * 24 * time.Hour}} {
+		if strings.HasSuffix(s, unit.Suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(s, unit.Suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n * float64(unit.Size)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
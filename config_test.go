@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTeams(t *testing.T) {
+	defer applyIdentities(IdentityConfig{})
+	if err := applyIdentities(IdentityConfig{People: map[string][]string{"alice": {"alice-work"}}}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		teams   map[string][]string
+		wantErr string
+	}{
+		{"disjoint", map[string][]string{"platform": {"alice", "bob"}, "web": {"carol"}}, ""},
+		{"listed twice in a team", map[string][]string{"platform": {"alice", "Alice"}}, ""},
+		{"two teams", map[string][]string{"platform": {"alice", "bob"}, "web": {"bob"}}, "bob is in both platform and web"},
+		{"two teams by alias", map[string][]string{"platform": {"alice"}, "web": {"alice-work"}}, "alice-work is in both platform and web"},
+	}
+	for _, tt := range tests {
+		err := validateTeams(tt.teams)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxCouplingFiles skips PRs touching more files than this when counting
+// co-changes: renames, formatting sweeps and dependency bumps couple
+// everything with everything.
+const maxCouplingFiles = 30
+
+// filePair is two files that changed together in at least one PR.
+type filePair struct {
+	A, B   string
+	Count  int             // PRs changing both
+	Cycle  []time.Duration // Merge times of those PRs
+	Median time.Duration
+	// Confidence is Count over the PRs of the less-changed file: how often
+	// touching one means touching the other.
+	Confidence float64
+}
+
+// coChanges builds the co-change matrix of the non-generated files in prs,
+// keeping pairs that changed together at least minCount times.
+func coChanges(prs []PullRequest, generated *GeneratedMatcher, minCount int) []filePair {
+	fileCount := make(map[string]int)
+	pairs := make(map[[2]string]*filePair)
+	for _, pr := range prs {
+		var files []string
+		for _, p := range pr.FilePaths {
+			if ok, _ := generated.Match(p); !ok {
+				files = append(files, p)
+			}
+		}
+		if len(files) < 2 || len(files) > maxCouplingFiles {
+			continue
+		}
+		sort.Strings(files)
+		cycle := pr.MergedAt.Sub(pr.CreatedAt)
+		for i, a := range files {
+			fileCount[a]++
+			for _, b := range files[i+1:] {
+				key := [2]string{a, b}
+				if pairs[key] == nil {
+					pairs[key] = &filePair{A: a, B: b}
+				}
+				pairs[key].Count++
+				pairs[key].Cycle = append(pairs[key].Cycle, cycle)
+			}
+		}
+	}
+
+	var out []filePair
+	for _, p := range pairs {
+		if p.Count < minCount {
+			continue
+		}
+		p.Median = percentile(p.Cycle, 50)
+		p.Confidence = float64(p.Count) / float64(min(fileCount[p.A], fileCount[p.B]))
+		out = append(out, *p)
+	}
+	return out
+}
+
+// couplingGroups merges strongly coupled pairs into connected groups of
+// files that tend to change as a unit.
+func couplingGroups(pairs []filePair) [][]string {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(f string) string {
+		if parent[f] == "" || parent[f] == f {
+			parent[f] = f
+			return f
+		}
+		parent[f] = find(parent[f])
+		return parent[f]
+	}
+	for _, p := range pairs {
+		if p.Confidence >= 0.5 {
+			parent[find(p.A)] = find(p.B)
+		}
+	}
+
+	members := make(map[string][]string)
+	for f := range parent {
+		root := find(f)
+		members[root] = append(members[root], f)
+	}
+	var groups [][]string
+	for _, g := range members {
+		if len(g) >= 3 {
+			sort.Strings(g)
+			groups = append(groups, g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i]) != len(groups[j]) {
+			return len(groups[i]) > len(groups[j])
+		}
+		return groups[i][0] < groups[j][0]
+	})
+	return groups
+}
+
+func printFileCoupling(prs []PullRequest, generated *GeneratedMatcher) {
+	fmt.Println("🔗 HOT FILE COUPLING")
+	printExplanation("Files that keep changing in the same PRs, ranked by how often and how slowly those PRs merge.",
+		"Files that can't change alone point at architectural coupling. Every PR touching them drags a wider review along.")
+
+	pairs := coChanges(prs, generated, 3)
+	if len(pairs) == 0 {
+		fmt.Println("   No file pairs changed together in 3 or more PRs.")
+		return
+	}
+
+	var cycle []time.Duration
+	for _, pr := range prs {
+		cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+	}
+	overall := percentile(cycle, 50)
+
+	// Frequent and slow first
+	weight := func(p filePair) float64 {
+		return float64(p.Count) * float64(p.Median) / float64(max(overall, time.Minute))
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if weight(pairs[i]) != weight(pairs[j]) {
+			return weight(pairs[i]) > weight(pairs[j])
+		}
+		return pairs[i].A+pairs[i].B < pairs[j].A+pairs[j].B
+	})
+
+	fmt.Printf("   Median merge of all PRs: %s\n\n", formatDuration(overall))
+	for i, p := range pairs {
+		if i == 10 {
+			break
+		}
+		slow := ""
+		if p.Median > overall*3/2 {
+			slow = " 🐢"
+		}
+		fmt.Printf("   %2d PRs (%3.0f%% together) median %-12s%s\n", p.Count, p.Confidence*100, formatDuration(p.Median), slow)
+		fmt.Printf("      %s\n      %s\n", p.A, p.B)
+	}
+
+	if groups := couplingGroups(pairs); len(groups) > 0 {
+		fmt.Println("\n   Files that change as a unit:")
+		for i, g := range groups {
+			if i == 5 {
+				break
+			}
+			shown := g
+			if len(shown) > 6 {
+				shown = shown[:6]
+			}
+			fmt.Printf("   - %d files: %s", len(g), shown[0])
+			for _, f := range shown[1:] {
+				fmt.Printf(", %s", f)
+			}
+			if len(g) > len(shown) {
+				fmt.Printf(", ... (%d more)", len(g)-len(shown))
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Printf("\n   (PRs touching more than %d files are left out. 🐢: 1.5x slower than the median PR.)\n", maxCouplingFiles)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// dominantGroup returns the group that most of a PR's files belong to.
+func dominantGroup(pr PullRequest) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, path := range pr.FilePaths {
+		g := groupFor(path)
+		counts[g]++
+		if counts[g] > counts[best] || (counts[g] == counts[best] && g < best) {
+			best = g
+		}
+	}
+	return best
+}
+
+// enforceRotation requests the on-duty reviewer for new PRs that have no
+// reviewers yet. PRs are recorded in handled so each is considered once.
+func enforceRotation(ctx context.Context, owner, name string, plan *RotationPlan, prs []PullRequest, handled map[int]bool, now time.Time, timeout time.Duration) {
+	week := plan.Current(now)
+	if week == nil {
+		slog.Warn("no rotation week covers today; skipping assignment")
+		return
+	}
+
+	for _, pr := range prs {
+		if handled[pr.Number] {
+			continue
+		}
+		handled[pr.Number] = true
+		if pr.IsDraft || len(pr.Requested) > 0 || len(pr.Reviews) > 0 {
+			continue
+		}
+
+		group := dominantGroup(pr)
+		a := week.For(group)
+		if a == nil {
+			slog.Info("no rotation for group", "pr", pr.Number, "group", group)
+			continue
+		}
+		reviewer := a.Primary
+		if reviewer == pr.Author {
+			reviewer = a.Backup
+		}
+		if reviewer == "" {
+			continue
+		}
+
+		if err := requestReviewers(ctx, owner, name, pr.Number, []string{reviewer}, timeout); err != nil {
+			slog.Error("requesting reviewer", "pr", pr.Number, "reviewer", reviewer, "err", err)
+			continue
+		}
+		slog.Info("requested reviewer", "pr", pr.Number, "reviewer", reviewer, "group", group)
+	}
+}
+
+// notifyGhostReviewers sends a digest to every reviewer sitting on review
+// requests in prs.
+func notifyGhostReviewers(ctx context.Context, owner, name string, prs []PullRequest, mentioned map[string]bool, timeout time.Duration) {
+	owners, err := fetchCodeowners(ctx, owner, name, timeout)
+	if err != nil {
+		slog.Warn("could not fetch CODEOWNERS", "repo", owner+"/"+name, "err", err)
+	}
+	var requested []string
+	for _, pr := range prs {
+		requested = append(requested, pr.Requested...)
+	}
+	avail, warnings := loadAvailability(ctx, cfg.Availability, requested, timeout)
+	for _, w := range warnings {
+		slog.Warn("availability", "err", w)
+	}
+	digests := ghostDigests(prs, owners, avail, clock())
+	slog.Debug("built ghost digests", "repo", owner+"/"+name, "digests", len(digests))
+	sendGhostDigests(ctx, owner, name, digests, mentioned, timeout)
+}
+
+func runDaemon(args []string) {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := fs.Duration("interval", 10*time.Minute, "How often to poll for new PRs")
+	rotationPath := fs.String("rotation", "", "Rotation plan (JSON from `bottleneck rotation --format json`) to enforce by auto-assigning new PRs")
+	notifyGhosts := fs.Bool("notify-ghosts", false, "Send ghost reviewer digests via Slack DM or GitHub mention (see notify: in config)")
+	digestInterval := fs.Duration("digest-interval", 24*time.Hour, "How often to send ghost digests with --notify-ghosts")
+	publish := fs.Bool("publish", false, "Publish per-PR records and repository aggregates to the event stream under events: in config")
+	publishInterval := fs.Duration("publish-interval", time.Hour, "How often to publish with --publish")
+	publishLimit := fs.Int("publish-limit", 100, "Merged PRs fetched for each --publish run")
+	includeExisting := fs.Bool("include-existing", false, "Also assign PRs that were already open when the daemon started")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck daemon [flags] <owner/repo>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	owner, name, err := parseRepo(fs.Arg(0))
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+
+	var plan *RotationPlan
+	if *rotationPath != "" {
+		plan, err = loadRotation(*rotationPath)
+		if err != nil {
+			slog.Error("loading rotation", "err", err)
+			os.Exit(1)
+		}
+	}
+	if plan == nil && !*notifyGhosts && !*publish {
+		slog.Error("nothing to do; pass --rotation to enforce a review rotation, --notify-ghosts to send ghost digests and/or --publish to publish metrics")
+		os.Exit(1)
+	}
+	var sink eventSink
+	if *publish {
+		if sink = newEventSink(cfg.Events, *reqTimeout); sink == nil {
+			slog.Error("--publish needs an event stream; set events.sink and events.url in config")
+			os.Exit(1)
+		}
+	}
+	publishOpts := reportOptions{Limit: *publishLimit, Timeout: *reqTimeout, Delay: *reqDelay, Store: Store{Dir: defaultStoreDir}}
+
+	slog.Info("daemon started", "repo", owner+"/"+name, "interval", *interval, "rotation", *rotationPath, "notify_ghosts", *notifyGhosts, "publish", *publish)
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	handled := make(map[int]bool)
+	firstPoll := true
+	var lastDigest, lastPublish time.Time
+	mentioned := make(map[string]bool) // Review requests already commented on
+	published := make(map[int]bool)    // Merged PRs already published
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		prs, err := fetchPRs(ctx, owner, name, 100, "OPEN", *reqTimeout, *reqDelay)
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			slog.Error("fetching open PRs", "repo", owner+"/"+name, "err", err)
+		default:
+			slog.Debug("polled open PRs", "repo", owner+"/"+name, "prs", len(prs))
+			if firstPoll && !*includeExisting {
+				// Leave the existing backlog alone; only new PRs get assigned
+				for _, pr := range prs {
+					handled[pr.Number] = true
+				}
+			}
+			firstPoll = false
+			if plan != nil {
+				enforceRotation(ctx, owner, name, plan, prs, handled, clock(), *reqTimeout)
+			}
+			if *notifyGhosts && clock().Sub(lastDigest) >= *digestInterval {
+				notifyGhostReviewers(ctx, owner, name, prs, mentioned, *reqTimeout)
+				lastDigest = clock()
+			}
+		}
+		if sink != nil && ctx.Err() == nil && clock().Sub(lastPublish) >= *publishInterval {
+			if err := publishEvents(ctx, sink, owner+"/"+name, publishOpts, published); err != nil && ctx.Err() == nil {
+				slog.Error("publishing events", "repo", owner+"/"+name, "err", err)
+			}
+			lastPublish = clock()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+	slog.Info("daemon stopped", "repo", owner+"/"+name)
+}
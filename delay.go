@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// "Merge branch 'main' into feature", "Merge remote-tracking branch 'origin/main'"
+	mergeCommit = regexp.MustCompile(`^Merge (?:remote-tracking )?branch `)
+	// Labels parking a PR on something outside the team's review loop
+	waitingLabel = regexp.MustCompile(`(?i)block|on[\s-]?hold|waiting|depend|upstream`)
+)
+
+// Delay causes, in report order.
+const (
+	causeTriage     = "triage"
+	causeGhosting   = "reviewer ghosting"
+	causeRework     = "author rework"
+	causeCI         = "CI"
+	causeConflict   = "conflict"
+	causeExternal   = "external dependency"
+	causeMergeDelay = "waiting to merge"
+)
+
+var delayCauses = []string{causeTriage, causeGhosting, causeRework, causeCI, causeConflict, causeExternal, causeMergeDelay}
+
+// externallyBlocked reports whether pr declares a dependency on other work,
+// by label or by "blocked by"/"depends on" in its description.
+func externallyBlocked(pr PullRequest) bool {
+	for _, l := range pr.Labels {
+		if waitingLabel.MatchString(l) {
+			return true
+		}
+	}
+	return dependencyPhrase.MatchString(htmlComment.ReplaceAllString(pr.Body, ""))
+}
+
+// delayBreakdown attributes every stretch of a merged PR's life to the
+// cause holding it up, following who has the ball:
+//
+//   - nobody, until a reviewer is requested or reviews: triage
+//   - the reviewers, from a request or a new push until the next review:
+//     reviewer ghosting
+//   - the author, from a review with feedback until the next push: author
+//     rework, or conflict when that push merges the base branch in
+//   - nobody again, from the last approval until merge: CI while checks
+//     still run (when checks were fetched), else waiting to merge
+//
+// Time with the author or after approval counts as external dependency
+// when the PR says it waits on other work.
+func delayBreakdown(pr PullRequest, checks []CheckRun) map[string]time.Duration {
+	type event struct {
+		At        time.Time
+		Kind      string // request, review or commit
+		State     string
+		BaseMerge bool
+	}
+	var events []event
+	for _, r := range pr.ReviewRequests {
+		events = append(events, event{At: r.At, Kind: "request"})
+	}
+	for _, r := range pr.Reviews {
+		if r.Author != pr.Author && !isBot(r.Author) && r.State != "DISMISSED" {
+			events = append(events, event{At: r.CreatedAt, Kind: "review", State: r.State})
+		}
+	}
+	merges := make(map[time.Time]bool)
+	for _, t := range pr.BaseMerges {
+		merges[t] = true
+	}
+	for _, t := range pr.CommitTimes {
+		events = append(events, event{At: t, Kind: "commit", BaseMerge: merges[t]})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	external := externallyBlocked(pr)
+	out := make(map[string]time.Duration)
+	at, ball := pr.CreatedAt, "nobody"
+	assign := func(until time.Time, cause string) {
+		until = minTime(until, pr.MergedAt)
+		if until.After(at) {
+			out[cause] += until.Sub(at)
+			at = until
+		}
+	}
+	waiting := func(cause string) string {
+		if external {
+			return causeExternal
+		}
+		return cause
+	}
+
+	for _, e := range events {
+		if e.At.After(pr.MergedAt) {
+			break
+		}
+		switch e.Kind {
+		case "request":
+			if ball == "nobody" {
+				assign(e.At, causeTriage)
+				ball = "reviewers"
+			}
+		case "review":
+			switch ball {
+			case "nobody":
+				assign(e.At, causeTriage)
+			case "reviewers":
+				assign(e.At, causeGhosting)
+			case "author":
+				assign(e.At, waiting(causeRework))
+			case "approved":
+				assign(e.At, waiting(causeMergeDelay))
+			}
+			ball = "author"
+			if e.State == "APPROVED" {
+				ball = "approved"
+			}
+		case "commit":
+			cause := ""
+			switch ball {
+			case "author":
+				cause = waiting(causeRework)
+			case "approved":
+				cause = waiting(causeMergeDelay)
+			}
+			if cause == "" {
+				continue
+			}
+			if e.BaseMerge {
+				cause = causeConflict
+			}
+			assign(e.At, cause)
+			if ball == "author" {
+				ball = "reviewers"
+			}
+		}
+	}
+
+	switch ball {
+	case "nobody":
+		assign(pr.MergedAt, causeTriage)
+	case "reviewers":
+		assign(pr.MergedAt, causeGhosting)
+	case "author":
+		assign(pr.MergedAt, waiting(causeRework))
+	case "approved":
+		var lastCheck time.Time
+		for _, c := range checks {
+			if c.CompletedAt.After(lastCheck) {
+				lastCheck = c.CompletedAt
+			}
+		}
+		assign(lastCheck, causeCI)
+		assign(pr.MergedAt, waiting(causeMergeDelay))
+	}
+	return out
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// dominantCause returns the cause with the most time in breakdown and its
+// share of the total.
+func dominantCause(breakdown map[string]time.Duration) (string, float64) {
+	var total time.Duration
+	best := ""
+	for _, c := range delayCauses {
+		total += breakdown[c]
+		if breakdown[c] > breakdown[best] {
+			best = c
+		}
+	}
+	if total == 0 {
+		return "", 0
+	}
+	return best, float64(breakdown[best]) / float64(total)
+}
+
+// slowPRs returns the merged PRs in the slowest quarter by cycle time.
+func slowPRs(prs []PullRequest) []PullRequest {
+	var cycle []time.Duration
+	for _, pr := range prs {
+		cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+	}
+	cutoff := percentile(cycle, 75)
+	var out []PullRequest
+	for _, pr := range prs {
+		if pr.MergedAt.Sub(pr.CreatedAt) >= cutoff {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// printDelayCauses prints the dominant delay cause distribution of the
+// slowest quarter of PRs. checks holds their check runs, or is nil when CI
+// data wasn't fetched.
+func printDelayCauses(prs []PullRequest, checks map[int][]CheckRun) {
+	fmt.Println("🩺 WHY SLOW PRs ARE SLOW")
+	printExplanation("Every hour of the slowest quarter of PRs is attributed to who held the ball: nobody (triage), silent reviewers, the author reworking, CI, merge conflicts, other work it waits on, or nobody pressing merge.",
+		"Turns \"we're slow\" into \"63% of our delay is triage\", which points at one fix instead of ten.")
+
+	slow := slowPRs(prs)
+	if len(slow) == 0 {
+		fmt.Println("   Not enough merged PRs.")
+		return
+	}
+	spent := make(map[string]time.Duration)
+	dominant := make(map[string]int)
+	var total time.Duration
+	for _, pr := range slow {
+		b := delayBreakdown(pr, checks[pr.Number])
+		for c, d := range b {
+			spent[c] += d
+			total += d
+		}
+		if c, _ := dominantCause(b); c != "" {
+			dominant[c]++
+		}
+	}
+	if total == 0 {
+		fmt.Println("   No delay to attribute.")
+		return
+	}
+
+	fmt.Printf("   Slow PRs: %d (cycle time at or above the P75)\n\n", len(slow))
+	fmt.Printf("   %-22s %-12s %6s  %-20s %s\n", "Cause", "Time", "Share", "", "Dominant in")
+	for _, c := range delayCauses {
+		if c == causeCI && checks == nil {
+			continue
+		}
+		if spent[c] == 0 && dominant[c] == 0 {
+			continue
+		}
+		share := float64(spent[c]) / float64(total) * 100
+		fmt.Printf("   %-22s %-12s %5.0f%%  %-20s %d PRs\n", c, formatDuration(spent[c]), share, strings.Repeat("■", int(share/5+0.5)), dominant[c])
+	}
+
+	top, share := dominantCause(spent)
+	fmt.Printf("\n   %.0f%% of the delay on slow PRs is %s.\n", share*100, top)
+	switch top {
+	case causeTriage:
+		fmt.Println("   Action: Assign reviewers automatically (CODEOWNERS, a rotation) so no PR waits for someone to notice it.")
+	case causeGhosting:
+		fmt.Println("   Action: Set a first-response SLO and rebalance load away from requested reviewers who don't respond.")
+	case causeRework:
+		fmt.Println("   Action: Agree on the approach before coding (design notes, draft PRs) to cut late rework.")
+	case causeCI:
+		fmt.Println("   Action: Speed up or de-flake the checks approved PRs wait on (see the CI sections).")
+	case causeConflict:
+		fmt.Println("   Action: Merge smaller PRs sooner, or use a merge queue, so branches don't drift from the base.")
+	case causeExternal:
+		fmt.Println("   Action: Open dependent PRs only once their dependency is close, or stack them explicitly.")
+	case causeMergeDelay:
+		fmt.Println("   Action: Enable auto-merge so approved PRs land without waiting for someone to press merge.")
+	}
+	if checks == nil {
+		fmt.Println("   (Run with --ci to separate CI time from waiting to merge.)")
+	}
+}
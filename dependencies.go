@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// "Blocked by #12", "depends on #12 and #15", "Depends-On: #12, #15"
+	dependencyPhrase = regexp.MustCompile(`(?i)\b(?:blocked[\s-]+by|depends[\s-]+on)\s*:?\s*((?:[\w.-]+/[\w.-]+)?#\d+(?:\s*(?:,|and|&)\s*(?:[\w.-]+/[\w.-]+)?#\d+)*)`)
+	dependencyRef    = regexp.MustCompile(`([\w.-]+/[\w.-]+)?#(\d+)`)
+)
+
+// dependsOn returns the PRs or issues of the same repository that body
+// says it is blocked by. Cross-repository references are skipped.
+func dependsOn(body string) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, m := range dependencyPhrase.FindAllStringSubmatch(htmlComment.ReplaceAllString(body, ""), -1) {
+		for _, ref := range dependencyRef.FindAllStringSubmatch(m[1], -1) {
+			if ref[1] != "" {
+				continue
+			}
+			if n, err := strconv.Atoi(ref[2]); err == nil && !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	return out
+}
+
+// blocker is an open PR that other open PRs depend on.
+type blocker struct {
+	PR      PullRequest
+	Blocked []int // Open PRs waiting on it, directly or down a chain
+	Depth   int   // Levels of PRs waiting behind it
+}
+
+// openStatus describes where an open PR is stuck.
+func openStatus(pr PullRequest, now time.Time) string {
+	latest := make(map[string]string)
+	for _, r := range pr.Reviews {
+		if r.Author != pr.Author && (r.State == "APPROVED" || r.State == "CHANGES_REQUESTED") {
+			latest[r.Author] = r.State
+		}
+	}
+	approved := false
+	for _, s := range latest {
+		if s == "CHANGES_REQUESTED" {
+			return "changes requested"
+		}
+		approved = true
+	}
+	switch {
+	case pr.IsDraft:
+		return "draft"
+	case approved:
+		return "approved, not merged"
+	case isStale(pr, staleCutoff(now)):
+		return "stale"
+	case len(pr.Reviews) == 0:
+		return "waiting for review"
+	default:
+		return "in review"
+	}
+}
+
+// dependencyBlockers builds the blocked-by graph of the open PRs and
+// returns those blocking others, most blocked first. cycles lists PRs that
+// end up waiting on themselves.
+func dependencyBlockers(open []PullRequest) (blockers []blocker, cycles []int) {
+	byNumber := make(map[int]PullRequest)
+	for _, pr := range open {
+		byNumber[pr.Number] = pr
+	}
+	waiters := make(map[int][]int) // Blocker -> PRs depending on it
+	for _, pr := range open {
+		for _, n := range dependsOn(pr.Body) {
+			if _, ok := byNumber[n]; ok && n != pr.Number {
+				waiters[n] = append(waiters[n], pr.Number)
+			}
+		}
+	}
+
+	inCycle := make(map[int]bool)
+	for n, direct := range waiters {
+		seen := make(map[int]bool)
+		depth := 0
+		// Breadth-first down the chain of waiting PRs
+		level := direct
+		for len(level) > 0 {
+			var next []int
+			fresh := false
+			for _, w := range level {
+				if w == n {
+					inCycle[n] = true
+				}
+				if seen[w] {
+					continue
+				}
+				seen[w] = true
+				fresh = true
+				next = append(next, waiters[w]...)
+			}
+			if fresh {
+				depth++
+			}
+			level = next
+		}
+		b := blocker{PR: byNumber[n], Depth: depth}
+		for w := range seen {
+			if w != n {
+				b.Blocked = append(b.Blocked, w)
+			}
+		}
+		sort.Ints(b.Blocked)
+		blockers = append(blockers, b)
+	}
+	for n := range inCycle {
+		cycles = append(cycles, n)
+	}
+	sort.Ints(cycles)
+	sort.Slice(blockers, func(i, j int) bool {
+		if len(blockers[i].Blocked) != len(blockers[j].Blocked) {
+			return len(blockers[i].Blocked) > len(blockers[j].Blocked)
+		}
+		return blockers[i].PR.Number < blockers[j].PR.Number
+	})
+	return blockers, cycles
+}
+
+func printDependencies(open, merged []PullRequest) {
+	fmt.Println("⛓️  BLOCKED-BY CHAINS")
+	printExplanation("Open PRs that say \"blocked by #N\" or \"depends on #N\", and the stuck PRs holding up the most others.",
+		"One stuck PR can hold a whole chain of finished work hostage. These cascades don't show up in per-PR numbers.")
+
+	now := clock()
+	blockers, cycles := dependencyBlockers(open)
+
+	mergedNumbers := make(map[int]bool)
+	for _, pr := range merged {
+		mergedNumbers[pr.Number] = true
+	}
+	var outdated []string
+	for _, pr := range open {
+		for _, n := range dependsOn(pr.Body) {
+			if mergedNumbers[n] {
+				outdated = append(outdated, fmt.Sprintf("#%d (on #%d)", pr.Number, n))
+			}
+		}
+	}
+
+	if len(blockers) == 0 {
+		fmt.Println("   No open PR is blocked by another open PR.")
+	}
+	ref := func(ns []int) string {
+		var parts []string
+		for _, n := range ns {
+			parts = append(parts, "#"+strconv.Itoa(n))
+		}
+		return strings.Join(parts, ", ")
+	}
+	for i, b := range blockers {
+		if i == 10 {
+			fmt.Printf("   ... and %d more blocking PRs\n", len(blockers)-10)
+			break
+		}
+		icon := "🔗"
+		if len(b.Blocked) >= 3 || b.Depth >= 3 {
+			icon = "🚨"
+		}
+		fmt.Printf("   %s #%d %s\n", icon, b.PR.Number, limitString(b.PR.Title, 50))
+		by := ""
+		if privacy != privacyAggregate {
+			by = "by " + person(b.PR.Author) + ", "
+		}
+		fmt.Printf("      %sopen %s, %s\n", by, formatDuration(now.Sub(b.PR.CreatedAt)), openStatus(b.PR, now))
+		fmt.Printf("      Blocks %d PRs (%s), chain %d deep\n", len(b.Blocked), ref(b.Blocked), b.Depth)
+	}
+
+	if len(cycles) > 0 {
+		fmt.Printf("\n   🔁 Circular dependencies: %s wait on each other. Nobody can merge first.\n", ref(cycles))
+	}
+	if len(outdated) > 0 {
+		fmt.Printf("\n   🧹 %d PRs still name an already merged PR as blocker: %s\n", len(outdated), strings.Join(outdated, ", "))
+		fmt.Println("      Rebase and update their descriptions so reviewers know they're ready.")
+	}
+	if len(blockers) > 0 {
+		fmt.Println("\n   Action: Review the top blocker first. Unblocking it frees every PR in its chain.")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// deploymentLimit caps how many deployments are fetched per repository.
+const deploymentLimit = 300
+
+// Deployment is one deployment from the GitHub Deployments API.
+type Deployment struct {
+	Environment string
+	CreatedAt   time.Time
+	DeployedAt  time.Time // When it first reported success
+	OK          bool
+}
+
+// fetchDeployments lists up to limit deployments, newest first.
+func fetchDeployments(ctx context.Context, owner, name string, limit int, timeout, delay time.Duration) ([]Deployment, error) {
+	var deployments []Deployment
+	var cursor string
+
+	queryTmpl := `
+query {
+  repository(owner: %q, name: %q) {
+    deployments(%s) {
+      nodes {
+        environment
+        createdAt
+        statuses(first: 10) { nodes { state createdAt } }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	for len(deployments) < limit {
+		if cursor != "" {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return deployments, pageError(len(deployments), err)
+			}
+		}
+		args := fmt.Sprintf("first: %d, orderBy: {field: CREATED_AT, direction: DESC}", min(100, limit-len(deployments)))
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, owner, name, args), timeout)
+		if err != nil {
+			return deployments, pageError(len(deployments), err)
+		}
+		var resp struct {
+			Data struct {
+				Repository struct {
+					Deployments struct {
+						Nodes []struct {
+							Environment string    `json:"environment"`
+							CreatedAt   time.Time `json:"createdAt"`
+							Statuses    struct {
+								Nodes []struct {
+									State     string    `json:"state"`
+									CreatedAt time.Time `json:"createdAt"`
+								} `json:"nodes"`
+							} `json:"statuses"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"deployments"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return deployments, pageError(len(deployments), err)
+		}
+
+		page := resp.Data.Repository.Deployments
+		for _, n := range page.Nodes {
+			d := Deployment{Environment: n.Environment, CreatedAt: n.CreatedAt}
+			for _, s := range n.Statuses.Nodes {
+				if s.State == "SUCCESS" && (!d.OK || s.CreatedAt.Before(d.DeployedAt)) {
+					d.OK, d.DeployedAt = true, s.CreatedAt
+				}
+			}
+			deployments = append(deployments, d)
+		}
+		if !page.PageInfo.HasNextPage || len(page.Nodes) == 0 {
+			break
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+	return deployments, nil
+}
+
+func printDeployLeadTime(prs []PullRequest, deployments []Deployment) {
+	fmt.Println("🚀 MERGE TO DEPLOY")
+	printExplanation("Lead time from merge to the next successful deployment, per environment, from the GitHub Deployments API.",
+		"Completes the path from PR to user impact. A fast review is wasted when production only gets deployed weekly.")
+
+	if len(deployments) == 0 {
+		fmt.Println("   No deployments recorded. This repository doesn't report them through the Deployments API.")
+		return
+	}
+
+	type envStat struct {
+		Name      string
+		Total, OK int
+		Times     []time.Time // Successful deploys, oldest first
+		Oldest    time.Time   // Oldest fetched deployment
+	}
+	envs := make(map[string]*envStat)
+	for _, d := range deployments {
+		key := strings.ToLower(d.Environment)
+		if envs[key] == nil {
+			envs[key] = &envStat{Name: d.Environment, Oldest: d.CreatedAt}
+		}
+		e := envs[key]
+		e.Total++
+		if d.CreatedAt.Before(e.Oldest) {
+			e.Oldest = d.CreatedAt
+		}
+		if d.OK {
+			e.OK++
+			e.Times = append(e.Times, d.DeployedAt)
+		}
+	}
+	var list []*envStat
+	for _, e := range envs {
+		sort.Slice(e.Times, func(i, j int) bool { return e.Times[i].Before(e.Times[j]) })
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].OK != list[j].OK {
+			return list[i].OK > list[j].OK
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	now := clock()
+	fmt.Printf("   %-16s %8s %8s %9s   %-14s %-14s %s\n", "Environment", "Deploys", "Success", "Per week", "Median lead", "P90 lead", "Not deployed")
+	for _, e := range list {
+		var lead []time.Duration
+		pending := 0
+		for _, pr := range prs {
+			// PRs merged before the oldest fetched deployment can't be placed
+			if pr.MergedAt.Before(e.Oldest) {
+				continue
+			}
+			i := sort.Search(len(e.Times), func(i int) bool { return !e.Times[i].Before(pr.MergedAt) })
+			if i == len(e.Times) {
+				pending++
+				continue
+			}
+			lead = append(lead, e.Times[i].Sub(pr.MergedAt))
+		}
+		weeks := max(now.Sub(e.Oldest).Hours()/(24*7), 1)
+		median, p90 := "-", "-"
+		if len(lead) > 0 {
+			median, p90 = formatDuration(percentile(lead, 50)), formatDuration(percentile(lead, 90))
+		}
+		fmt.Printf("   %-16s %8d %7.0f%% %9s   %-14s %-14s %d\n", limitString(e.Name, 16), e.Total, float64(e.OK)/float64(e.Total)*100,
+			formatNumber(float64(e.OK)/weeks, 1), median, p90, pending)
+	}
+	fmt.Println("\n   (Lead: merge → next successful deployment of that environment, assuming deployments ship the default branch in order.")
+	fmt.Printf("    Not deployed: merged after the last successful deployment. Based on the latest %d deployments.)\n", deploymentLimit)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NotifyConfig delivers ghost digests as private nudges.
+type NotifyConfig struct {
+	// Slack maps GitHub logins to Slack member IDs for direct messages.
+	Slack         map[string]string `yaml:"slack"`
+	SlackTokenEnv string            `yaml:"slack_token_env"` // Env var holding the bot token (default SLACK_BOT_TOKEN)
+	// GitHubMention comments on the waiting PRs, mentioning reviewers who
+	// have no Slack mapping.
+	GitHubMention bool `yaml:"github_mention"`
+	// MinAge is how long a review request must wait before it is included
+	// (default 48h, matching the ghost detector).
+	MinAge Duration `yaml:"min_age"`
+}
+
+// minAge returns the configured digest threshold.
+func (n NotifyConfig) minAge() time.Duration {
+	if n.MinAge > 0 {
+		return time.Duration(n.MinAge)
+	}
+	return 48 * time.Hour
+}
+
+// GhostDigest is one reviewer's list of review requests they are sitting on.
+type GhostDigest struct {
+	Login string
+	PRs   []digestPR
+}
+
+type digestPR struct {
+	Number   int
+	Title    string
+	Waiting  time.Duration
+	Blocking bool // Required CODEOWNER
+}
+
+// ghostDigests groups review requests pending longer than notify.min_age by
+// reviewer. Teams, bots and (in exclude mode) away reviewers are skipped.
+func ghostDigests(prs []PullRequest, owners *Codeowners, avail *Availability, now time.Time) []GhostDigest {
+	minAge := cfg.Notify.minAge()
+	byLogin := make(map[string]*GhostDigest)
+	for _, pr := range prs {
+		if pr.IsDraft {
+			continue
+		}
+		for _, login := range pr.Requested {
+			if strings.Contains(login, "/") || isBot(login) {
+				continue
+			}
+			if away, _ := avail.Away(login, now); away && avail.Exclude() {
+				continue
+			}
+			since := requestedAt(pr, login, now)
+			waiting := now.Sub(since)
+			if waiting < minAge || engagedSince(pr, login, since) {
+				continue
+			}
+			if byLogin[login] == nil {
+				byLogin[login] = &GhostDigest{Login: login}
+			}
+			byLogin[login].PRs = append(byLogin[login].PRs, digestPR{
+				Number:   pr.Number,
+				Title:    pr.Title,
+				Waiting:  waiting,
+				Blocking: owners != nil && owners.IsRequired(pr, login),
+			})
+		}
+	}
+
+	var digests []GhostDigest
+	for _, d := range byLogin {
+		sort.Slice(d.PRs, func(i, j int) bool { return d.PRs[i].Waiting > d.PRs[j].Waiting })
+		digests = append(digests, *d)
+	}
+	sort.Slice(digests, func(i, j int) bool { return digests[i].Login < digests[j].Login })
+	return digests
+}
+
+// Message renders the digest as a short, private nudge.
+func (d GhostDigest) Message(repo string) string {
+	var refs []string
+	shortest := d.PRs[0].Waiting
+	for _, pr := range d.PRs {
+		refs = append(refs, fmt.Sprintf("#%d", pr.Number))
+		if pr.Waiting < shortest {
+			shortest = pr.Waiting
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "👋 Hi %s, you're blocking %s in %s for %s or more:\n", d.Login, joinRefs(refs), repo, formatDuration(shortest))
+	for _, pr := range d.PRs {
+		required := ""
+		if pr.Blocking {
+			required = " (required code owner)"
+		}
+		fmt.Fprintf(&b, "• #%d %s - waiting %s%s\n", pr.Number, limitString(pr.Title, 60), formatDuration(pr.Waiting), required)
+	}
+	b.WriteString("If you can't get to them, removing yourself as a reviewer lets the author find someone else.")
+	return b.String()
+}
+
+func joinRefs(refs []string) string {
+	noun := "PR"
+	if len(refs) > 1 {
+		noun = "PRs"
+	}
+	if len(refs) <= 2 {
+		return noun + " " + strings.Join(refs, " and ")
+	}
+	return noun + " " + strings.Join(refs[:len(refs)-1], ", ") + " and " + refs[len(refs)-1]
+}
+
+// deliverDigest sends a digest by Slack DM when the reviewer is mapped, else
+// by PR comment when GitHub mentions are enabled. It returns the channel used,
+// or "" when the reviewer has no delivery channel.
+func deliverDigest(ctx context.Context, owner, name string, d GhostDigest, timeout time.Duration) (string, error) {
+	repo := owner + "/" + name
+	if id := lookupFold(cfg.Notify.Slack, d.Login); id != "" {
+		return "slack", sendSlackDM(ctx, id, d.Message(repo), timeout)
+	}
+	if !cfg.Notify.GitHubMention {
+		return "", nil
+	}
+	for _, pr := range d.PRs {
+		body := fmt.Sprintf("@%s friendly reminder: this PR has been waiting on your review for %s. If you can't get to it, removing yourself as a reviewer lets the author find someone else.", d.Login, formatDuration(pr.Waiting))
+		args := []string{"-X", "POST", fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, name, pr.Number), "-f", "body=" + body}
+		if _, err := ghAPI(ctx, timeout, args...); err != nil {
+			return "github", fmt.Errorf("commenting on #%d: %w", pr.Number, err)
+		}
+	}
+	return "github", nil
+}
+
+func lookupFold(m map[string]string, key string) string {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// sendSlackDM posts a direct message through the Slack Web API.
+func sendSlackDM(ctx context.Context, user, text string, timeout time.Duration) error {
+	tokenEnv := cfg.Notify.SlackTokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "SLACK_BOT_TOKEN"
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return fmt.Errorf("%s is not set", tokenEnv)
+	}
+
+	body, err := json.Marshal(map[string]string{"channel": user, "text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://slack.com/api/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("decoding Slack response (%s): %w", resp.Status, err)
+	}
+	if !out.OK {
+		return fmt.Errorf("slack: %s", out.Error)
+	}
+	return nil
+}
+
+// sendGhostDigests delivers digests without printing them, for the daemon.
+// A PR comment stays on the PR, so reviewers reached by GitHub mention are
+// mentioned once per pending request: mentioned records the requests
+// ("login#number") commented on, and forgets those no longer pending.
+func sendGhostDigests(ctx context.Context, owner, name string, digests []GhostDigest, mentioned map[string]bool, timeout time.Duration) {
+	pending := make(map[string]bool)
+	for _, d := range digests {
+		for _, pr := range d.PRs {
+			pending[fmt.Sprintf("%s#%d", strings.ToLower(d.Login), pr.Number)] = true
+		}
+	}
+	for key := range mentioned {
+		if !pending[key] {
+			delete(mentioned, key)
+		}
+	}
+
+	for _, d := range digests {
+		byMention := lookupFold(cfg.Notify.Slack, d.Login) == ""
+		if byMention {
+			var unmentioned []digestPR
+			for _, pr := range d.PRs {
+				if !mentioned[fmt.Sprintf("%s#%d", strings.ToLower(d.Login), pr.Number)] {
+					unmentioned = append(unmentioned, pr)
+				}
+			}
+			if len(unmentioned) == 0 {
+				slog.Debug("ghost digest already delivered by mention", "reviewer", d.Login)
+				continue
+			}
+			d.PRs = unmentioned
+		}
+		channel, err := deliverDigest(ctx, owner, name, d, timeout)
+		if channel == "github" && err == nil {
+			for _, pr := range d.PRs {
+				mentioned[fmt.Sprintf("%s#%d", strings.ToLower(d.Login), pr.Number)] = true
+			}
+		}
+		switch {
+		case err != nil:
+			slog.Error("delivering ghost digest", "reviewer", d.Login, "channel", channel, "err", err)
+		case channel == "":
+			slog.Warn("no delivery channel for ghost digest", "reviewer", d.Login)
+		default:
+			slog.Info("delivered ghost digest", "reviewer", d.Login, "channel", channel, "prs", len(d.PRs))
+		}
+	}
+}
+
+func printGhostDigests(ctx context.Context, owner, name string, digests []GhostDigest, send bool, timeout time.Duration) {
+	fmt.Println("📬 GHOST DIGESTS")
+	printExplanation("One private message per ghost reviewer listing every PR they are holding up.",
+		"Public shaming lists are less effective than a direct, private nudge with the exact PRs to look at.")
+
+	if len(digests) == 0 {
+		fmt.Println("   ✅ Nobody is sitting on review requests.")
+		return
+	}
+	for _, d := range digests {
+		fmt.Printf("   To %s:\n", d.Login)
+		for _, line := range strings.Split(d.Message(owner+"/"+name), "\n") {
+			fmt.Printf("      %s\n", line)
+		}
+		if !send {
+			fmt.Println()
+			continue
+		}
+		channel, err := deliverDigest(ctx, owner, name, d, timeout)
+		switch {
+		case err != nil:
+			fmt.Printf("      ❌ Not delivered via %s: %v\n\n", channel, err)
+		case channel == "":
+			fmt.Print("      ⚠️  No Slack mapping and github_mention is off; not delivered.\n\n")
+		default:
+			fmt.Printf("      ✅ Delivered via %s.\n\n", channel)
+		}
+	}
+	if !send {
+		fmt.Println("   Action: Re-run with --notify-ghosts to deliver these via Slack DM or GitHub mention.")
+	}
+}
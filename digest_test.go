@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGhostDigestMessage(t *testing.T) {
+	d := GhostDigest{Login: "alice", PRs: []digestPR{{Number: 7, Title: "Fix parser", Waiting: 20 * time.Hour}}}
+	if msg := d.Message("acme/widgets"); !strings.Contains(msg, "for 20h 0m or more") {
+		t.Errorf("message = %q, want the wait under a day spelled out", msg)
+	}
+}
+
+func TestSendGhostDigestsMentionsOnce(t *testing.T) {
+	t.Setenv(replayEnv, "")
+	saved, savedCfg := ghExec, cfg
+	t.Cleanup(func() { ghExec, cfg = saved, savedCfg })
+	cfg.Notify = NotifyConfig{GitHubMention: true}
+	var comments []string
+	ghExec = func(_ context.Context, args []string) ([]byte, error) {
+		comments = append(comments, args[2])
+		return []byte("{}"), nil
+	}
+
+	mentioned := make(map[string]bool)
+	alice := GhostDigest{Login: "alice", PRs: []digestPR{{Number: 7, Waiting: 50 * time.Hour}}}
+	sendGhostDigests(context.Background(), "acme", "widgets", []GhostDigest{alice}, mentioned, time.Second)
+	sendGhostDigests(context.Background(), "acme", "widgets", []GhostDigest{alice}, mentioned, time.Second)
+	if len(comments) != 1 {
+		t.Fatalf("commented %d times on a request still pending, want once", len(comments))
+	}
+
+	// A new request on another PR gets its own mention; once #7 is
+	// answered, it is forgotten and a later request mentions again
+	alice.PRs = append(alice.PRs, digestPR{Number: 9, Waiting: 49 * time.Hour})
+	sendGhostDigests(context.Background(), "acme", "widgets", []GhostDigest{alice}, mentioned, time.Second)
+	sendGhostDigests(context.Background(), "acme", "widgets", nil, mentioned, time.Second)
+	sendGhostDigests(context.Background(), "acme", "widgets", []GhostDigest{alice}, mentioned, time.Second)
+	want := []string{"repos/acme/widgets/issues/7/comments", "repos/acme/widgets/issues/9/comments",
+		"repos/acme/widgets/issues/7/comments", "repos/acme/widgets/issues/9/comments"}
+	if strings.Join(comments, " ") != strings.Join(want, " ") {
+		t.Errorf("comments = %v, want %v", comments, want)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diversityMinPRs is how many reviewed PRs an author needs before the
+// spread of their reviewers means anything.
+const diversityMinPRs = 5
+
+// authorReviewers is how one author's reviewed PRs spread over reviewers.
+type authorReviewers struct {
+	Author   string
+	Reviewed int            // PRs with at least one human review
+	By       map[string]int // PRs reviewed by each reviewer
+	Top      string
+	TopShare float64 // Share of reviewed PRs the top reviewer reviewed
+}
+
+// Effective is the effective number of reviewers (inverse Simpson index):
+// 1 when one person does every review, n when n people split them evenly.
+func (a authorReviewers) Effective() float64 {
+	total, sumSq := 0, 0
+	for _, n := range a.By {
+		total += n
+		sumSq += n * n
+	}
+	if sumSq == 0 {
+		return 0
+	}
+	return float64(total*total) / float64(sumSq)
+}
+
+// reviewerSpread groups human reviews of non-bot PRs by author.
+func reviewerSpread(prs []PullRequest) map[string]*authorReviewers {
+	spread := make(map[string]*authorReviewers)
+	for _, pr := range prs {
+		if pr.Author == "" || isBot(pr.Author) {
+			continue
+		}
+		var humans []string
+		for _, r := range pr.Reviewers {
+			if !isBot(r) {
+				humans = append(humans, r)
+			}
+		}
+		if len(humans) == 0 {
+			continue
+		}
+		a := spread[pr.Author]
+		if a == nil {
+			a = &authorReviewers{Author: pr.Author, By: make(map[string]int)}
+			spread[pr.Author] = a
+		}
+		a.Reviewed++
+		for _, r := range humans {
+			a.By[r]++
+		}
+	}
+	for _, a := range spread {
+		for r, n := range a.By {
+			if n > a.By[a.Top] || n == a.By[a.Top] && r < a.Top {
+				a.Top = r
+			}
+		}
+		a.TopShare = float64(a.By[a.Top]) / float64(a.Reviewed) * 100
+	}
+	return spread
+}
+
+// reviewLoop is a pair of authors who mostly review each other.
+type reviewLoop struct {
+	A, B         string
+	AByB, BByA   float64 // Share of A's reviewed PRs B reviewed, and the reverse
+	Interchanged int     // PRs of either reviewed by the other
+}
+
+// closedLoops finds pairs of authors who are each other's top reviewer,
+// each reviewing at least half of the other's PRs.
+func closedLoops(spread map[string]*authorReviewers) []reviewLoop {
+	var loops []reviewLoop
+	for _, a := range spread {
+		b := spread[a.Top]
+		if a.Reviewed < diversityMinPRs || b == nil || b.Reviewed < diversityMinPRs || a.Author > b.Author {
+			continue
+		}
+		if b.Top == a.Author && a.TopShare >= 50 && b.TopShare >= 50 {
+			loops = append(loops, reviewLoop{A: a.Author, B: b.Author, AByB: a.TopShare, BByA: b.TopShare,
+				Interchanged: a.By[b.Author] + b.By[a.Author]})
+		}
+	}
+	sort.Slice(loops, func(i, j int) bool {
+		if loops[i].Interchanged != loops[j].Interchanged {
+			return loops[i].Interchanged > loops[j].Interchanged
+		}
+		return loops[i].A < loops[j].A
+	})
+	return loops
+}
+
+func printReviewerDiversity(prs []PullRequest) {
+	fmt.Println("🌈 REVIEWER DIVERSITY")
+	printExplanation("How many different people review each author's PRs, and pairs who mostly review only each other.",
+		"Knowledge spreads through review. An author always reviewed by the same person shares what they know with one colleague, and two people reviewing each other form a silo.")
+
+	spread := reviewerSpread(prs)
+	var authors []*authorReviewers
+	skipped := 0
+	for _, a := range spread {
+		if a.Reviewed < diversityMinPRs {
+			skipped++
+			continue
+		}
+		authors = append(authors, a)
+	}
+	if len(authors) == 0 {
+		fmt.Printf("   No author has %d or more reviewed PRs.\n", diversityMinPRs)
+		return
+	}
+	// Least diverse first
+	sort.Slice(authors, func(i, j int) bool {
+		if ei, ej := authors[i].Effective(), authors[j].Effective(); ei != ej {
+			return ei < ej
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	loops := closedLoops(spread)
+
+	dominated := 0
+	var effective []float64
+	for _, a := range authors {
+		effective = append(effective, a.Effective())
+		if a.TopShare >= 80 {
+			dominated++
+		}
+	}
+	sort.Float64s(effective)
+
+	if !namesShown() {
+		// Diversity between teams or of nobody in particular says nothing:
+		// counts only
+		fmt.Printf("   Authors: %d   Median effective reviewers: %s\n", len(authors), formatNumber(quantile(effective, 50), 1))
+		fmt.Printf("   Authors with one reviewer on 80%%+ of their PRs: %d\n", dominated)
+		fmt.Printf("   Closed review loops (pairs mostly reviewing each other): %d\n", len(loops))
+		return
+	}
+
+	fmt.Printf("   %-20s %8s %10s %10s   %s\n", "Author", "Reviewed", "Reviewers", "Effective", "Top Reviewer")
+	for _, a := range authors {
+		flag := ""
+		if a.TopShare >= 80 {
+			flag = "  ⚠️"
+		}
+		fmt.Printf("   %-20s %8d %10d %10s   %s (%.0f%%)%s\n", limitString(a.Author, 20), a.Reviewed, len(a.By),
+			formatNumber(a.Effective(), 1), a.Top, a.TopShare, flag)
+	}
+	fmt.Println("   (Effective: the number of equally sharing reviewers the spread amounts to; 1.0 means one person reviews everything.)")
+
+	if len(loops) > 0 {
+		fmt.Println("\n   Closed review loops:")
+		for _, l := range loops {
+			fmt.Printf("   🔁 %s ⇄ %s: %s reviews %.0f%% of %s's PRs, %s %.0f%% of %s's\n", l.A, l.B, l.B, l.AByB, l.A, l.A, l.BByA, l.B)
+		}
+	}
+
+	if dominated > 0 || len(loops) > 0 {
+		var names []string
+		for _, a := range authors {
+			if a.TopShare >= 80 {
+				names = append(names, a.Author)
+			}
+		}
+		fmt.Println()
+		if len(names) > 0 {
+			fmt.Printf("   💡 Rotate a second reviewer onto PRs by %s.\n", strings.Join(names, ", "))
+		}
+		if len(loops) > 0 {
+			fmt.Println("   💡 Break closed loops by requesting a reviewer from outside the pair.")
+		}
+	} else {
+		fmt.Println("\n   ✅ No author depends on a single reviewer.")
+	}
+	if skipped > 0 {
+		fmt.Printf("   (%d authors with fewer than %d reviewed PRs left out.)\n", skipped, diversityMinPRs)
+	}
+}
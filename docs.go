@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// docsDir matches documentation and static site trees.
+var docsDir = regexp.MustCompile(`(^|/)(docs?|documentation|website|site)/`)
+
+// isDocsFile reports whether p is documentation: a Docs file by category
+// (a file_categories entry named Docs applies too) or a file in a docs or
+// site directory.
+func isDocsFile(p string) bool {
+	return fileCategory(p) == "Docs" || docsDir.MatchString(p)
+}
+
+// isDocsOnly reports whether every file pr changed is documentation.
+func isDocsOnly(pr PullRequest) bool {
+	if len(pr.FilePaths) == 0 || pr.ChangedFiles > len(pr.FilePaths) {
+		return false
+	}
+	for _, p := range pr.FilePaths {
+		if !isDocsFile(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitDocs separates docs-only PRs from the rest.
+func splitDocs(prs []PullRequest) (code, docs []PullRequest) {
+	for _, pr := range prs {
+		if isDocsOnly(pr) {
+			docs = append(docs, pr)
+		} else {
+			code = append(code, pr)
+		}
+	}
+	return code, docs
+}
+
+// printDocsPRs compares docs-only PRs with the rest. excluded tells whether
+// --exclude-docs took them out of the other sections.
+func printDocsPRs(prs []PullRequest, excluded bool) {
+	fmt.Println("📝 DOCS-ONLY PRs")
+	printExplanation("PRs that change only documentation (Markdown, reStructuredText, docs/ and site/ trees), measured apart from code PRs.",
+		"Docs PRs often merge in minutes. Mixed in, they flatter the velocity numbers of the code that actually needs review.")
+
+	code, docs := splitDocs(prs)
+	if len(docs) == 0 {
+		fmt.Println("   No docs-only PRs in this dataset.")
+		return
+	}
+	type stats struct {
+		Cycle, FirstReview []time.Duration
+	}
+	collect := func(prs []PullRequest) stats {
+		var s stats
+		for _, pr := range prs {
+			s.Cycle = append(s.Cycle, pr.MergedAt.Sub(pr.CreatedAt))
+			if pr.FirstReviewAt != nil && pr.FirstReviewAt.After(pr.CreatedAt) {
+				s.FirstReview = append(s.FirstReview, pr.FirstReviewAt.Sub(pr.CreatedAt))
+			}
+		}
+		return s
+	}
+	medianOrDash := func(d []time.Duration) string {
+		if len(d) == 0 {
+			return "-"
+		}
+		return formatDuration(percentile(d, 50))
+	}
+	all, d, c := collect(prs), collect(docs), collect(code)
+
+	fmt.Printf("   Docs-only PRs: %d of %d (%.0f%%)\n\n", len(docs), len(prs), float64(len(docs))/float64(len(prs))*100)
+	fmt.Printf("   %-12s %5s   %-14s %s\n", "", "PRs", "Median Merge", "Median 1st Review")
+	fmt.Printf("   %-12s %5d   %-14s %s\n", "Docs only", len(docs), medianOrDash(d.Cycle), medianOrDash(d.FirstReview))
+	fmt.Printf("   %-12s %5d   %-14s %s\n", "Code", len(code), medianOrDash(c.Cycle), medianOrDash(c.FirstReview))
+	fmt.Printf("   %-12s %5d   %-14s %s\n", "All", len(prs), medianOrDash(all.Cycle), medianOrDash(all.FirstReview))
+
+	if excluded {
+		fmt.Println("\n   (Docs-only PRs are excluded from the other sections: --exclude-docs.)")
+		return
+	}
+	if codeMedian := percentile(c.Cycle, 50); codeMedian > 0 {
+		if faster := float64(codeMedian-percentile(all.Cycle, 50)) / float64(codeMedian) * 100; faster >= 10 {
+			fmt.Printf("\n   💡 Docs PRs make the median merge time look %.0f%% faster than it is for code. Run with --exclude-docs to leave them out.\n", faster)
+		}
+	}
+}
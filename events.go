@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// EventsConfig publishes computed metrics to an event stream, for
+// organizations that centralize engineering metrics in streaming pipelines.
+type EventsConfig struct {
+	// Sink is kafka (through a Confluent-compatible Kafka REST Proxy) or
+	// nats.
+	Sink string `yaml:"sink"`
+	// URL is the REST Proxy's base URL for kafka, e.g.
+	// http://kafka-rest:8082, and the server for nats, e.g.
+	// nats://nats:4222.
+	URL string `yaml:"url"`
+	// PRTopic receives one record per PR (default bottleneck.prs),
+	// AggregateTopic one per repository and run (default
+	// bottleneck.aggregates). NATS publishes them as subjects.
+	PRTopic        string `yaml:"pr_topic"`
+	AggregateTopic string `yaml:"aggregate_topic"`
+	// TokenEnv names an environment variable holding a bearer token for
+	// the REST Proxy or an auth token for NATS.
+	TokenEnv string `yaml:"token_env"`
+}
+
+func (e EventsConfig) prTopic() string {
+	if e.PRTopic != "" {
+		return e.PRTopic
+	}
+	return "bottleneck.prs"
+}
+
+func (e EventsConfig) aggregateTopic() string {
+	if e.AggregateTopic != "" {
+		return e.AggregateTopic
+	}
+	return "bottleneck.aggregates"
+}
+
+func validateEvents(e EventsConfig) error {
+	switch e.Sink {
+	case "":
+		return nil
+	case "kafka", "nats":
+	default:
+		return fmt.Errorf("events: unknown sink %q (want kafka or nats)", e.Sink)
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("events: %s sink needs a url, e.g. %s", e.Sink, map[string]string{"kafka": "http://kafka-rest:8082", "nats": "nats://nats:4222"}[e.Sink])
+	}
+	if e.Sink == "kafka" && u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("events: the kafka sink publishes through a Kafka REST Proxy and needs an http(s) url, got %q", e.URL)
+	}
+	if e.Sink == "nats" && u.Scheme != "nats" {
+		return fmt.Errorf("events: the nats sink needs a nats:// url, got %q", e.URL)
+	}
+	return nil
+}
+
+// PREvent is the record published per PR. Durations are in hours.
+type PREvent struct {
+	SchemaVersion    string     `json:"schema_version"`
+	Repo             string     `json:"repo"`
+	Number           int        `json:"number"`
+	State            string     `json:"state"`            // merged or open
+	Author           string     `json:"author,omitempty"` // A team under --privacy team, left out under aggregate
+	Draft            bool       `json:"draft"`
+	CreatedAt        time.Time  `json:"created_at"`
+	MergedAt         *time.Time `json:"merged_at,omitempty"`
+	FirstReviewAt    *time.Time `json:"first_review_at,omitempty"`
+	CycleHours       float64    `json:"cycle_hours,omitempty"` // Merged PRs only
+	FirstReviewHours float64    `json:"first_review_hours,omitempty"`
+	Size             int        `json:"size"`
+	ChangedFiles     int        `json:"changed_files"`
+	Reviews          int        `json:"reviews"`
+	Reviewers        int        `json:"reviewers"`
+	Rounds           int        `json:"rounds"`
+}
+
+// AggregateEvent is the record published per repository and run: the
+// repository's --format json report.
+type AggregateEvent struct {
+	SchemaVersion string    `json:"schema_version"`
+	Generated     time.Time `json:"generated"`
+	RepoReport
+}
+
+// newPREvent is the event record of pr.
+func newPREvent(repo string, pr PullRequest) PREvent {
+	e := PREvent{
+		SchemaVersion: reportSchemaVersion,
+		Repo:          repo,
+		Number:        pr.Number,
+		State:         "open",
+		Draft:         pr.IsDraft,
+		CreatedAt:     pr.CreatedAt.UTC(),
+		Size:          pr.Size,
+		ChangedFiles:  pr.ChangedFiles,
+		Reviews:       len(pr.Reviews),
+		Reviewers:     len(pr.Reviewers),
+		Rounds:        reviewRounds(pr),
+	}
+	if privacy != privacyAggregate {
+		e.Author = person(pr.Author)
+	}
+	if !pr.MergedAt.IsZero() {
+		merged := pr.MergedAt.UTC()
+		e.State, e.MergedAt = "merged", &merged
+		e.CycleHours = pr.MergedAt.Sub(pr.CreatedAt).Hours()
+	}
+	if pr.FirstReviewAt != nil {
+		first := pr.FirstReviewAt.UTC()
+		e.FirstReviewAt = &first
+		e.FirstReviewHours = max(0, pr.FirstReviewAt.Sub(pr.CreatedAt)).Hours()
+	}
+	return e
+}
+
+// eventRecord is one message: the key partitions Kafka records, so all
+// records of a PR land in order on one partition.
+type eventRecord struct {
+	Key   string
+	Value any
+}
+
+// eventSink publishes records to a topic (a subject on NATS).
+type eventSink interface {
+	publish(ctx context.Context, topic string, records []eventRecord) error
+}
+
+// newEventSink returns the sink configured under events:, or nil.
+func newEventSink(e EventsConfig, timeout time.Duration) eventSink {
+	var token string
+	if e.TokenEnv != "" {
+		token = os.Getenv(e.TokenEnv)
+		if token == "" {
+			slog.Warn("events token variable is not set", "env", e.TokenEnv)
+		}
+	}
+	switch e.Sink {
+	case "kafka":
+		return &kafkaRESTSink{base: strings.TrimSuffix(e.URL, "/"), token: token, client: &http.Client{Timeout: timeout}}
+	case "nats":
+		return &natsSink{url: e.URL, token: token, timeout: timeout}
+	}
+	return nil
+}
+
+// kafkaRESTSink produces to Kafka through the REST Proxy's v2 API, which
+// keeps bottleneck free of a Kafka client.
+type kafkaRESTSink struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// kafkaBatch is the most records sent in one request.
+const kafkaBatch = 500
+
+func (k *kafkaRESTSink) publish(ctx context.Context, topic string, records []eventRecord) error {
+	for start := 0; start < len(records); start += kafkaBatch {
+		if err := k.produce(ctx, topic, records[start:min(start+kafkaBatch, len(records))]); err != nil {
+			return fmt.Errorf("kafka topic %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+func (k *kafkaRESTSink) produce(ctx context.Context, topic string, records []eventRecord) error {
+	type record struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	}
+	var req struct {
+		Records []record `json:"records"`
+	}
+	for _, r := range records {
+		req.Records = append(req.Records, record(r))
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", k.base+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	httpReq.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if k.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+k.token)
+	}
+	resp, err := k.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		Message string `json:"message"` // On HTTP errors
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, &out); err != nil && resp.StatusCode < 300 {
+		return fmt.Errorf("decoding REST Proxy response (%s): %w", resp.Status, err)
+	}
+	if resp.StatusCode >= 300 {
+		if out.Message == "" {
+			out.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("REST Proxy: %s: %s", resp.Status, out.Message)
+	}
+	failed := 0
+	var first string
+	for _, o := range out.Offsets {
+		if o.ErrorCode != nil {
+			if failed == 0 {
+				first = o.Error
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d records failed: %s", failed, len(records), first)
+	}
+	return nil
+}
+
+// natsSink publishes over the NATS client protocol, one connection per
+// batch: bottleneck publishes every few minutes at most.
+type natsSink struct {
+	url     string
+	token   string
+	timeout time.Duration
+}
+
+func (n *natsSink) publish(ctx context.Context, subject string, records []eventRecord) error {
+	if err := n.send(ctx, subject, records); err != nil {
+		return fmt.Errorf("nats subject %s: %w", subject, err)
+	}
+	return nil
+}
+
+func (n *natsSink) send(ctx context.Context, subject string, records []eventRecord) error {
+	u, err := url.Parse(n.url)
+	if err != nil {
+		return err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	conn, err := (&net.Dialer{Timeout: n.timeout}).DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(n.timeout))
+	r := bufio.NewReader(conn)
+
+	// The server speaks first
+	if line, err := r.ReadString('\n'); err != nil {
+		return err
+	} else if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+	opts := map[string]any{"verbose": false, "pedantic": false, "headers": true, "name": "bottleneck", "lang": "go", "protocol": 1}
+	if n.token != "" {
+		opts["auth_token"] = n.token
+	}
+	if u.User != nil {
+		opts["user"] = u.User.Username()
+		opts["pass"], _ = u.User.Password()
+	}
+	connect, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "CONNECT %s\r\n", connect)
+	for _, rec := range records {
+		payload, err := json.Marshal(rec.Value)
+		if err != nil {
+			return err
+		}
+		header := natsHeader(rec.Key, payload)
+		fmt.Fprintf(w, "HPUB %s %d %d\r\n", subject, len(header), len(header)+len(payload))
+		w.WriteString(header)
+		w.Write(payload)
+		w.WriteString("\r\n")
+	}
+	// A PONG answers the PING once everything before it is processed, or
+	// an -ERR comes first
+	w.WriteString("PING\r\n")
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("server: %s", strings.Trim(strings.TrimPrefix(line, "-ERR"), " '"))
+		case line == "PING":
+			fmt.Fprintf(conn, "PONG\r\n")
+		}
+	}
+}
+
+// natsHeader carries the record key as Bottleneck-Key. The message ID,
+// which JetStream drops duplicates on, is the key and a hash of the
+// payload: open PRs and aggregates are republished under the same key every
+// run, and only an unchanged record is a duplicate.
+func natsHeader(key string, payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return "NATS/1.0\r\nBottleneck-Key: " + key + "\r\nNats-Msg-Id: " + key + "@" + hex.EncodeToString(sum[:8]) + "\r\n\r\n"
+}
+
+// publishEvents fetches repo, publishes a record per merged PR not in
+// published yet and per open PR, then the repository's aggregate. Merged
+// PRs published are added to published, and those that fell out of the
+// fetched window are dropped from it: they won't be fetched again.
+func publishEvents(ctx context.Context, sink eventSink, repo string, o reportOptions, published map[int]bool) error {
+	var errs fetchErrors
+	merged, open, _ := fetchRepoPRs(ctx, repo, o, jsonFields|configFields(), &errs)
+	fieldErrors.takeInto(&errs, repo)
+	for _, e := range errs {
+		slog.Warn("fetching PRs to publish", "repo", repo, "stage", e.Stage, "fetched", e.Fetched, "err", e.Err)
+	}
+	rep, ok := buildRepoReport(ctx, repo, o, merged, open, len(merged) >= o.Limit, &errs)
+	if !ok {
+		return fmt.Errorf("no PRs found")
+	}
+
+	var prs []eventRecord
+	var newMerged []int
+	for _, pr := range merged {
+		if !published[pr.Number] {
+			prs = append(prs, eventRecord{Key: fmt.Sprintf("%s#%d", repo, pr.Number), Value: newPREvent(repo, pr)})
+			newMerged = append(newMerged, pr.Number)
+		}
+	}
+	for _, pr := range open {
+		prs = append(prs, eventRecord{Key: fmt.Sprintf("%s#%d", repo, pr.Number), Value: newPREvent(repo, pr)})
+	}
+	if err := sink.publish(ctx, cfg.Events.prTopic(), prs); err != nil {
+		return err
+	}
+	for _, n := range newMerged {
+		published[n] = true
+	}
+	if !errs.partial(repo) {
+		inWindow := make(map[int]bool, len(merged))
+		for _, pr := range merged {
+			inWindow[pr.Number] = true
+		}
+		for n := range published {
+			if !inWindow[n] {
+				delete(published, n)
+			}
+		}
+	}
+	agg := AggregateEvent{SchemaVersion: reportSchemaVersion, Generated: clock().UTC(), RepoReport: rep}
+	if err := sink.publish(ctx, cfg.Events.aggregateTopic(), []eventRecord{{Key: repo, Value: agg}}); err != nil {
+		return err
+	}
+	slog.Info("published events", "repo", repo, "sink", cfg.Events.Sink, "merged", len(newMerged), "open", len(open))
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKafkaRESTSink(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/bottleneck.prs" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			http.Error(w, `{"error_code":40401,"message":"Topic not found."}`, http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Records []struct {
+				Key   string          `json:"key"`
+				Value json.RawMessage `json:"value"`
+			} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var offsets []string
+		for i, rec := range req.Records {
+			got = append(got, rec.Key)
+			offsets = append(offsets, fmt.Sprintf(`{"partition":0,"offset":%d,"error_code":null,"error":null}`, len(got)+i))
+		}
+		fmt.Fprintf(w, `{"offsets":[%s]}`, strings.Join(offsets, ","))
+	}))
+	defer srv.Close()
+
+	sink := newEventSink(EventsConfig{Sink: "kafka", URL: srv.URL + "/"}, time.Second)
+	var records []eventRecord
+	for i := range 501 {
+		records = append(records, eventRecord{Key: "acme/widgets#" + strconv.Itoa(i), Value: PREvent{Number: i}})
+	}
+	if err := sink.publish(context.Background(), "bottleneck.prs", records); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 501 || got[500] != "acme/widgets#500" {
+		t.Errorf("REST Proxy got %d records, want 501 in order", len(got))
+	}
+	err := sink.publish(context.Background(), "missing", records[:1])
+	if err == nil || !strings.Contains(err.Error(), "Topic not found.") {
+		t.Errorf("err = %v, want the REST Proxy's message", err)
+	}
+}
+
+func TestNATSSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	type msg struct{ subject, header, payload string }
+	msgs := make(chan msg, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"headers\":true}\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			f := strings.Fields(line)
+			switch f[0] {
+			case "HPUB":
+				hdr, _ := strconv.Atoi(f[2])
+				total, _ := strconv.Atoi(f[3])
+				buf := make([]byte, total+2)
+				io.ReadFull(r, buf)
+				msgs <- msg{f[1], string(buf[:hdr]), string(buf[hdr:total])}
+			case "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			}
+		}
+	}()
+
+	sink := newEventSink(EventsConfig{Sink: "nats", URL: "nats://" + ln.Addr().String()}, time.Second)
+	// The same aggregate, republished after it changed
+	err = sink.publish(context.Background(), "bottleneck.aggregates", []eventRecord{
+		{Key: "acme/widgets", Value: map[string]int{"health": 80}},
+		{Key: "acme/widgets", Value: map[string]int{"health": 75}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]bool)
+	for _, payload := range []string{`{"health":80}`, `{"health":75}`} {
+		select {
+		case m := <-msgs:
+			if m.subject != "bottleneck.aggregates" || !strings.Contains(m.header, "Bottleneck-Key: acme/widgets\r\n") || m.payload != payload {
+				t.Errorf("published %+v", m)
+			}
+			_, id, _ := strings.Cut(m.header, "Nats-Msg-Id: ")
+			ids[id] = true
+		default:
+			t.Fatal("nothing published before the PONG")
+		}
+	}
+	if len(ids) != 2 {
+		t.Error("an updated record reused the message ID JetStream deduplicates on")
+	}
+}
+
+func TestPREvent(t *testing.T) {
+	created := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	review := created.Add(2 * time.Hour).In(time.FixedZone("CET", 3600))
+	pr := PullRequest{Number: 7, Author: "alice", CreatedAt: created, MergedAt: created.Add(10 * time.Hour), FirstReviewAt: &review}
+	e := newPREvent("acme/widgets", pr)
+	if e.State != "merged" || e.CycleHours != 10 || e.FirstReviewHours != 2 || e.Author != "alice" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.FirstReviewAt.Location() != time.UTC {
+		t.Errorf("first review at %s, want UTC like the other times", e.FirstReviewAt)
+	}
+	defer setPrivacy(privacyOff)
+	setPrivacy(privacyAggregate)
+	if e := newPREvent("acme/widgets", pr); e.Author != "" {
+		t.Errorf("author %q published under --privacy aggregate", e.Author)
+	}
+}
+
+// recordingSink keeps what is published, by topic.
+type recordingSink map[string][]eventRecord
+
+func (s recordingSink) publish(_ context.Context, topic string, records []eventRecord) error {
+	s[topic] = append(s[topic], records...)
+	return nil
+}
+
+func TestPublishEvents(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 30, Open: 2})
+	sink := recordingSink{}
+	o := reportOptions{Limit: 10, Timeout: time.Second}
+	published := map[int]bool{25: true, 3: true} // #3 merged long before the window
+	if err := publishEvents(context.Background(), sink, "acme/widgets", o, published); err != nil {
+		t.Fatal(err)
+	}
+	// The latest 10 merged PRs are #21-#30; #25 was published before
+	if n := len(sink[cfg.Events.prTopic()]); n != 9+2 {
+		t.Errorf("published %d PR records, want 9 merged and 2 open", n)
+	}
+	if len(published) != 10 || published[3] {
+		t.Errorf("published = %v, want #21-#30 only", published)
+	}
+	if len(sink[cfg.Events.aggregateTopic()]) != 1 {
+		t.Error("no aggregate published")
+	}
+}
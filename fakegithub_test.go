@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScenario configures what the fake GitHub serves and how it fails.
+type fakeScenario struct {
+	Merged, Open   int            // Pull requests in each state
+	Files          map[int]int    // Changed files per PR number (default 1)
+	Reviews        map[int]int    // Reviews per PR number (default 1)
+	NullFields     map[int]string // A PR field resolved to null with a field error, e.g. 5: "files"
+	RateLimitAfter int            // GraphQL requests served before RATE_LIMITED (0: never)
+	FailAfter      int            // GraphQL requests served before FailStatus (0: never)
+	FailStatus     int
+	Latency        time.Duration // Before every response
+	Remaining      int           // GraphQL points left, for /rate_limit
+}
+
+// fakeGitHub is an httptest server speaking enough of GitHub's GraphQL and
+// REST APIs for the fetch layer. newFakeGitHub routes ghExec to it for the
+// rest of the test.
+type fakeGitHub struct {
+	s   fakeScenario
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	queries []string
+}
+
+// fakeNow is when the fake's newest pull request was created.
+var fakeNow = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+func newFakeGitHub(t *testing.T, s fakeScenario) *fakeGitHub {
+	t.Helper()
+	f := &fakeGitHub{s: s}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", f.handleGraphQL)
+	mux.HandleFunc("GET /rate_limit", f.handleRateLimit)
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+
+	t.Setenv(replayEnv, "")
+	t.Setenv(recordEnv, "")
+	saved := ghExec
+	ghExec = f.exec
+	t.Cleanup(func() { ghExec = saved })
+	return f
+}
+
+// exec turns gh api arguments into a request to the fake. Like gh, it
+// returns the body with an error on HTTP errors and GraphQL errors.
+func (f *fakeGitHub) exec(ctx context.Context, args []string) ([]byte, error) {
+	method, fields := "GET", make(map[string]string)
+	for i := 1; i+1 < len(args); i++ {
+		switch args[i] {
+		case "-f", "-F":
+			k, v, _ := strings.Cut(args[i+1], "=")
+			fields[k] = v
+			i++
+		case "-X":
+			method = args[i+1]
+			i++
+		}
+	}
+	var body []byte
+	if args[0] == "graphql" {
+		method = "POST"
+		body, _ = json.Marshal(map[string]string{"query": fields["query"]})
+	}
+	req, err := http.NewRequestWithContext(ctx, method, f.srv.URL+"/"+args[0], bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.srv.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return out, fmt.Errorf("gh: HTTP %d", resp.StatusCode)
+	}
+	if args[0] == "graphql" && bytes.Contains(out, []byte(`"errors"`)) {
+		return out, errors.New("exit status 1")
+	}
+	return out, nil
+}
+
+// requests returns the GraphQL queries served so far.
+func (f *fakeGitHub) requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...)
+}
+
+var (
+	fakePRArgs  = regexp.MustCompile(`pullRequests\(first: (\d+), states: (\w+)`)
+	fakeAfter   = regexp.MustCompile(`after: "([^"]*)"`)
+	fakeFiles   = regexp.MustCompile(`files\(first: (\d+)`)
+	fakeReviews = regexp.MustCompile(`reviews\(first: (\d+)`)
+)
+
+func (f *fakeGitHub) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	f.queries = append(f.queries, req.Query)
+	n := len(f.queries)
+	f.mu.Unlock()
+
+	select {
+	case <-time.After(f.s.Latency):
+	case <-r.Context().Done():
+		return
+	}
+	if f.s.FailAfter > 0 && n > f.s.FailAfter {
+		http.Error(w, http.StatusText(f.s.FailStatus), f.s.FailStatus)
+		return
+	}
+	if f.s.RateLimitAfter > 0 && n > f.s.RateLimitAfter {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		writeFakeErrors(w, map[string]any{"type": "RATE_LIMITED", "message": "API rate limit exceeded for user ID 1."})
+		return
+	}
+
+	m := fakePRArgs.FindStringSubmatch(req.Query)
+	if m == nil {
+		writeFakeErrors(w, map[string]any{"message": "the fake GitHub doesn't serve this query"})
+		return
+	}
+	first, _ := strconv.Atoi(m[1])
+	if first > 100 {
+		writeFakeErrors(w, map[string]any{"type": "EXCESSIVE_PAGE_SIZE", "message": fmt.Sprintf("Requesting %d records on the `pullRequests` connection exceeds the `first` limit of 100 records.", first)})
+		return
+	}
+	offset := 0
+	if a := fakeAfter.FindStringSubmatch(req.Query); a != nil {
+		raw, err := base64.StdEncoding.DecodeString(a[1])
+		if err == nil {
+			offset, err = strconv.Atoi(strings.TrimPrefix(string(raw), "cursor:"))
+		}
+		if err != nil {
+			writeFakeErrors(w, map[string]any{"message": fmt.Sprintf("`%s` does not appear to be a valid cursor.", a[1])})
+			return
+		}
+	}
+
+	// Newest first: merged PRs are 1..Merged, open PRs follow
+	var numbers []int
+	switch m[2] {
+	case "MERGED":
+		for i := f.s.Merged; i >= 1; i-- {
+			numbers = append(numbers, i)
+		}
+	case "OPEN":
+		for i := f.s.Merged + f.s.Open; i > f.s.Merged; i-- {
+			numbers = append(numbers, i)
+		}
+	}
+	end := min(offset+first, len(numbers))
+	offset = min(offset, end)
+
+	nodes := []any{}
+	var errs []any
+	for i, num := range numbers[offset:end] {
+		node := f.node(num, m[2], req.Query)
+		if field, ok := f.s.NullFields[num]; ok {
+			node[field] = nil
+			errs = append(errs, map[string]any{
+				"message": "Something went wrong while executing your query.",
+				"path":    []any{"repository", "pullRequests", "nodes", i, field},
+			})
+		}
+		nodes = append(nodes, node)
+	}
+	resp := map[string]any{"data": map[string]any{"repository": map[string]any{"pullRequests": map[string]any{
+		"nodes": nodes,
+		"pageInfo": map[string]any{
+			"hasNextPage": end < len(numbers),
+			"endCursor":   base64.StdEncoding.EncodeToString([]byte("cursor:" + strconv.Itoa(end))),
+		},
+	}}}}
+	if len(errs) > 0 {
+		resp["errors"] = errs
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// node is pull request num with the fields the query selects. Nested
+// connections are cut at the query's first, as GitHub does.
+func (f *fakeGitHub) node(num int, state, query string) map[string]any {
+	created := fakeNow.Add(-time.Duration(num) * 6 * time.Hour)
+	author := fmt.Sprintf("dev-%d", num%4)
+	node := map[string]any{
+		"number":         num,
+		"createdAt":      created,
+		"updatedAt":      created.Add(2 * time.Hour),
+		"title":          fmt.Sprintf("Change %d", num),
+		"baseRefName":    "main",
+		"headRefName":    fmt.Sprintf("feature/change-%d", num),
+		"isDraft":        false,
+		"additions":      10 * num,
+		"deletions":      num,
+		"author":         map[string]any{"login": author},
+		"reviewRequests": map[string]any{"nodes": []any{}},
+	}
+	if state == "MERGED" {
+		node["mergedAt"] = created.Add(4 * time.Hour)
+		node["mergedBy"] = map[string]any{"login": author}
+	}
+
+	reviews := []any{}
+	count := 1
+	if n, ok := f.s.Reviews[num]; ok {
+		count = n
+	}
+	if m := fakeReviews.FindStringSubmatch(query); m != nil {
+		limit, _ := strconv.Atoi(m[1])
+		count = min(count, limit)
+	}
+	for i := range count {
+		reviews = append(reviews, map[string]any{
+			"createdAt": created.Add(time.Duration(i+1) * time.Hour),
+			"state":     "APPROVED",
+			"author":    map[string]any{"login": fmt.Sprintf("reviewer-%d", i)},
+		})
+	}
+	node["reviews"] = map[string]any{"nodes": reviews}
+
+	if m := fakeFiles.FindStringSubmatch(query); m != nil {
+		total := 1
+		if n, ok := f.s.Files[num]; ok {
+			total = n
+		}
+		limit, _ := strconv.Atoi(m[1])
+		files := []any{}
+		for i := range min(total, limit) {
+			files = append(files, map[string]any{"path": fmt.Sprintf("pkg/file%d.go", i), "additions": 1, "deletions": 0})
+		}
+		node["changedFiles"] = total
+		node["files"] = map[string]any{"nodes": files}
+	}
+	return node
+}
+
+func (f *fakeGitHub) handleRateLimit(w http.ResponseWriter, _ *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{"resources": map[string]any{"graphql": map[string]any{
+		"limit": 5000, "remaining": f.s.Remaining, "used": 5000 - f.s.Remaining, "reset": fakeNow.Add(time.Hour).Unix(),
+	}}})
+}
+
+// writeFakeErrors answers with GraphQL errors and no data.
+func writeFakeErrors(w http.ResponseWriter, errs ...map[string]any) {
+	json.NewEncoder(w).Encode(map[string]any{"errors": errs})
+}
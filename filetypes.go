@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileCategory maps gitignore-style patterns to a named category, e.g.
+// {name: "SQL migrations", patterns: ["migrations/", "*.sql"]}.
+type FileCategory struct {
+	Name     string   `yaml:"name"`
+	Patterns []string `yaml:"patterns"`
+}
+
+// Default categories by extension, used when no configured category matches.
+var extensionCategories = map[string]string{
+	".go":    "Go",
+	".proto": "Protobuf",
+	".sql":   "SQL",
+	".yml":   "YAML",
+	".yaml":  "YAML",
+	".json":  "JSON",
+	".md":    "Docs",
+	".mdx":   "Docs",
+	".rst":   "Docs",
+	".txt":   "Docs",
+	".js":    "JavaScript/TypeScript",
+	".jsx":   "JavaScript/TypeScript",
+	".ts":    "JavaScript/TypeScript",
+	".tsx":   "JavaScript/TypeScript",
+	".py":    "Python",
+	".rs":    "Rust",
+	".java":  "JVM",
+	".kt":    "JVM",
+	".scala": "JVM",
+	".rb":    "Ruby",
+	".swift": "Swift",
+	".c":     "C/C++",
+	".cc":    "C/C++",
+	".cpp":   "C/C++",
+	".h":     "C/C++",
+	".tf":    "Terraform",
+	".sh":    "Shell",
+	".css":   "CSS",
+	".scss":  "CSS",
+	".html":  "HTML",
+}
+
+type compiledCategory struct {
+	Name     string
+	Patterns []*regexp.Regexp
+}
+
+var fileCategories []compiledCategory
+
+// compileFileCategories prepares the configured category patterns.
+func compileFileCategories() {
+	fileCategories = nil
+	for _, c := range cfg.FileCategories {
+		cc := compiledCategory{Name: c.Name}
+		for _, p := range c.Patterns {
+			cc.Patterns = append(cc.Patterns, codeownersPattern(p))
+		}
+		fileCategories = append(fileCategories, cc)
+	}
+}
+
+// fileCategory classifies a path: configured categories first (in order),
+// then by extension, then "Other".
+func fileCategory(p string) string {
+	for _, c := range fileCategories {
+		for _, re := range c.Patterns {
+			if re.MatchString(p) {
+				return c.Name
+			}
+		}
+	}
+	base := path.Base(p)
+	switch {
+	case base == "Dockerfile" || strings.HasPrefix(base, "Dockerfile."):
+		return "Docker"
+	case base == "Makefile":
+		return "Build"
+	}
+	if c, ok := extensionCategories[strings.ToLower(path.Ext(p))]; ok {
+		return c
+	}
+	return "Other"
+}
+
+func printFileTypeAnalysis(prs []PullRequest) {
+	fmt.Println("🗂️  FILE TYPE SEGMENTATION")
+	printExplanation("Merge time and review depth per kind of file changed (code, migrations, config, docs...).", "Infra and migration changes are often the real swamp, hidden inside directory averages.")
+
+	type CatStat struct {
+		Durations []time.Duration
+		Reviews   int
+		Rounds    int
+	}
+	stats := make(map[string]*CatStat)
+
+	for _, pr := range prs {
+		seen := make(map[string]bool)
+		for _, p := range pr.FilePaths {
+			c := fileCategory(p)
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			if _, exists := stats[c]; !exists {
+				stats[c] = &CatStat{}
+			}
+			stats[c].Durations = append(stats[c].Durations, pr.MergedAt.Sub(pr.CreatedAt))
+			stats[c].Reviews += len(pr.Reviews)
+			stats[c].Rounds += reviewRounds(pr)
+		}
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("   No file data available.")
+		return
+	}
+
+	var cats []string
+	for c := range stats {
+		cats = append(cats, c)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		if mi, mj := percentile(stats[cats[i]].Durations, 50), percentile(stats[cats[j]].Durations, 50); mi != mj {
+			return mi > mj
+		}
+		return cats[i] < cats[j]
+	})
+
+	fmt.Printf("   %-22s %5s   %-14s %-12s %s\n", "Category", "PRs", "Median Merge", "Reviews/PR", "Rounds/PR")
+	for _, c := range cats {
+		s := stats[c]
+		n := float64(len(s.Durations))
+		fmt.Printf("   %-22s %5d   %-14s %-12s %s\n", c, len(s.Durations), formatDuration(percentile(s.Durations, 50)), formatNumber(float64(s.Reviews)/n, 1), formatNumber(float64(s.Rounds)/n, 1))
+	}
+	fmt.Println("   (A PR counts toward every category it touches.)")
+}
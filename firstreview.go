@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Definitions of a PR's first review, set by first_review in config.
+const (
+	firstReviewFormal  = "review"  // The first formal review, by anyone (default)
+	firstReviewComment = "comment" // The first review or conversation comment by someone other than the author
+	firstReviewHuman   = "human"   // The first formal review by someone other than the author, bots excluded
+)
+
+func validateFirstReview(def string) error {
+	switch def {
+	case "", firstReviewFormal, firstReviewComment, firstReviewHuman:
+		return nil
+	}
+	return fmt.Errorf("first_review must be %s, %s or %s, got %q", firstReviewFormal, firstReviewComment, firstReviewHuman, def)
+}
+
+// firstReviewAt returns when pr got its first review under the configured
+// definition, or nil if it has none. Reviews are in submission order.
+func firstReviewAt(pr PullRequest) *time.Time {
+	counts := func(r Review) bool {
+		switch cfg.FirstReview {
+		case firstReviewComment:
+			return r.Author != pr.Author
+		case firstReviewHuman:
+			return r.Author != pr.Author && !isBot(r.Author)
+		}
+		return true
+	}
+	var first *time.Time
+	for _, r := range pr.Reviews {
+		if counts(r) {
+			t := r.CreatedAt
+			first = &t
+			break
+		}
+	}
+	if cfg.FirstReview != firstReviewComment {
+		return first
+	}
+	for _, c := range pr.Comments {
+		if c.Author != pr.Author && (first == nil || c.At.Before(*first)) {
+			t := c.At
+			first = &t
+		}
+	}
+	return first
+}
+
+// firstReviewNote describes a non-default first review definition, or
+// returns "" for the default.
+func firstReviewNote() string {
+	switch cfg.FirstReview {
+	case firstReviewComment:
+		return "first_review: comment counts the first review or comment by someone other than the author"
+	case firstReviewHuman:
+		return "first_review: human counts the first formal review by someone other than the author, bots excluded"
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// sunMinReviews is how many PRs someone must have reviewed to count as
+	// a reviewer in follow-the-sun coverage.
+	sunMinReviews = 3
+	// sunMinActivity is how many timestamps it takes to infer where someone
+	// works from their activity.
+	sunMinActivity = 10
+)
+
+// placedReviewer is a reviewer and the timezone they work in.
+type placedReviewer struct {
+	Login    string
+	Location *time.Location
+	Inferred bool // From activity hours, not config
+}
+
+// inferLocation guesses the UTC offset someone works at: the whole-hour
+// offset that puts the most of their activity within work hours on a
+// weekday. It returns nil with too little activity to tell.
+func inferLocation(activity []time.Time) *time.Location {
+	if len(activity) < sunMinActivity {
+		return nil
+	}
+	best, bestHits := 0, -1
+	for _, h := range []int{0, 1, -1, 2, -2, 3, -3, 4, -4, 5, -5, 6, -6, 7, -7, 8, -8, 9, -9, 10, -10, 11, -11, 12, -12, 13, 14} {
+		loc := utcOffset(h)
+		hits := 0
+		for _, t := range activity {
+			if atWork(t, loc) {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = h, hits
+		}
+	}
+	return utcOffset(best)
+}
+
+// utcOffset is a fixed timezone h hours from UTC, named like UTC+05:00.
+func utcOffset(h int) *time.Location {
+	if h == 0 {
+		return time.UTC
+	}
+	sign := "+"
+	if h < 0 {
+		sign = "-"
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%s%02d:00", sign, max(h, -h)), h*3600)
+}
+
+// placeReviewers finds everyone who reviewed at least sunMinReviews PRs and
+// where they work: their configured timezone, else one inferred from when
+// they review, commit and open PRs. It also returns the reviewers that
+// couldn't be placed.
+func placeReviewers(prs []PullRequest) ([]placedReviewer, []string) {
+	reviewed := make(map[string]int)
+	activity := make(map[string][]time.Time)
+	for _, pr := range prs {
+		for _, r := range pr.Reviewers {
+			if !isBot(r) {
+				reviewed[r]++
+			}
+		}
+		for _, r := range pr.Reviews {
+			activity[r.Author] = append(activity[r.Author], r.CreatedAt)
+		}
+		activity[pr.Author] = append(activity[pr.Author], pr.CreatedAt)
+		activity[pr.Author] = append(activity[pr.Author], pr.CommitTimes...)
+	}
+
+	var placed []placedReviewer
+	var unplaced []string
+	for login, n := range reviewed {
+		if n < sunMinReviews {
+			continue
+		}
+		if l := locationOf(login); l != nil {
+			placed = append(placed, placedReviewer{Login: login, Location: l})
+		} else if l := inferLocation(activity[login]); l != nil {
+			placed = append(placed, placedReviewer{Login: login, Location: l, Inferred: true})
+		} else {
+			unplaced = append(unplaced, login)
+		}
+	}
+	sort.Slice(placed, func(i, j int) bool { return placed[i].Login < placed[j].Login })
+	sort.Strings(unplaced)
+	return placed, unplaced
+}
+
+// onShift returns the reviewers at work at t.
+func onShift(reviewers []placedReviewer, t time.Time) []placedReviewer {
+	var out []placedReviewer
+	for _, r := range reviewers {
+		if atWork(t, r.Location) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// waitForShift is how long a PR opened at t waits until a reviewer comes on
+// shift, to the half hour, capped at a week.
+func waitForShift(reviewers []placedReviewer, t time.Time) time.Duration {
+	for d := time.Duration(0); d < 7*24*time.Hour; d += 30 * time.Minute {
+		if len(onShift(reviewers, t.Add(d))) > 0 {
+			return d
+		}
+	}
+	return 7 * 24 * time.Hour
+}
+
+func printFollowTheSun(merged, open []PullRequest) {
+	fmt.Println("🌍 FOLLOW-THE-SUN COVERAGE")
+	printExplanation("When PRs arrive against when reviewers are at work, with reviewer timezones from config or inferred from their activity hours.",
+		"A PR opened when nobody is on shift waits for the next working day. This shows how often that happens and which timezone would close the gap.")
+
+	placed, unplaced := placeReviewers(merged)
+	if len(placed) == 0 {
+		fmt.Printf("   No reviewer with %d or more reviews could be placed in a timezone.\n", sunMinReviews)
+		return
+	}
+
+	// Weekend arrivals wait for Monday whoever is on the team
+	arrivals := make([]time.Time, 0, len(merged)+len(open))
+	weekend := 0
+	for _, pr := range append(append([]PullRequest(nil), merged...), open...) {
+		if pr.IsDraft {
+			continue
+		}
+		if d := pr.CreatedAt.In(primaryLocation).Weekday(); d == time.Saturday || d == time.Sunday {
+			weekend++
+			continue
+		}
+		arrivals = append(arrivals, pr.CreatedAt)
+	}
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+
+	type zone struct {
+		Name      string
+		Reviewers int
+		Inferred  int
+		OnShift   int     // Arrivals with someone of the zone at work
+		Alone     int     // Arrivals only the zone covers
+		Load      float64 // Arrivals shared out among the reviewers on shift
+	}
+	zones := make(map[string]*zone)
+	for _, r := range placed {
+		z := zones[r.Location.String()]
+		if z == nil {
+			z = &zone{Name: r.Location.String()}
+			zones[z.Name] = z
+		}
+		z.Reviewers++
+		if r.Inferred {
+			z.Inferred++
+		}
+	}
+
+	var uncovered []time.Time
+	var waits []time.Duration
+	for _, t := range arrivals {
+		on := onShift(placed, t)
+		if len(on) == 0 {
+			uncovered = append(uncovered, t)
+			waits = append(waits, waitForShift(placed, t))
+			continue
+		}
+		inZone := make(map[string]bool)
+		for _, r := range on {
+			name := r.Location.String()
+			inZone[name] = true
+			zones[name].Load += 1 / float64(len(on))
+		}
+		for name := range inZone {
+			zones[name].OnShift++
+			if len(inZone) == 1 {
+				zones[name].Alone++
+			}
+		}
+	}
+
+	var names []string
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if zones[names[i]].Reviewers != zones[names[j]].Reviewers {
+			return zones[names[i]].Reviewers > zones[names[j]].Reviewers
+		}
+		return names[i] < names[j]
+	})
+
+	inferred := 0
+	for _, r := range placed {
+		if r.Inferred {
+			inferred++
+		}
+	}
+	fmt.Printf("   Reviewers placed: %d from config, %d inferred from activity", len(placed)-inferred, inferred)
+	if len(unplaced) > 0 {
+		fmt.Printf(", %d with too little activity to tell", len(unplaced))
+	}
+	fmt.Printf("\n   PR arrivals: %d on weekdays in %s (%d at the weekend and drafts left out)\n\n", len(arrivals), primaryLocation, weekend)
+
+	pct := func(n int) float64 { return float64(n) / float64(max(len(arrivals), 1)) * 100 }
+	fmt.Printf("   %-20s %9s %10s %10s %14s\n", "Timezone", "Reviewers", "On Shift", "Alone", "PRs/Reviewer")
+	var loads []float64
+	for _, name := range names {
+		z := zones[name]
+		count := fmt.Sprint(z.Reviewers)
+		if z.Inferred > 0 {
+			count += "*"
+		}
+		perReviewer := z.Load / float64(z.Reviewers)
+		loads = append(loads, perReviewer)
+		fmt.Printf("   %-20s %9s %9.0f%% %9.0f%% %14s\n", limitString(name, 20), count, pct(z.OnShift), pct(z.Alone), formatNumber(perReviewer, 1))
+	}
+	fmt.Println("   (On Shift: arrivals with a reviewer of the zone at work. Alone: arrivals only that zone covers. *: includes inferred timezones.)")
+
+	fmt.Println()
+	if len(uncovered) == 0 {
+		fmt.Println("   ✅ Every PR arrived while a reviewer was on shift.")
+	} else {
+		fmt.Printf("   🌙 %.0f%% of PRs arrived with nobody on shift, waiting a median %s (P90 %s) for someone to start work.\n",
+			pct(len(uncovered)), formatDuration(percentile(waits, 50)), formatDuration(percentile(waits, 90)))
+	}
+
+	// A zone whose reviewers take well over the usual share of arrivals
+	// needs more of them
+	sort.Float64s(loads)
+	typical := quantile(loads, 50)
+	for _, name := range names {
+		z := zones[name]
+		if perReviewer := z.Load / float64(z.Reviewers); len(zones) > 1 && typical > 0 && perReviewer >= 1.5*typical {
+			fmt.Printf("   💡 %s needs more reviewers: each takes %s PRs on shift, %sx the typical load.\n",
+				name, formatNumber(perReviewer, 1), formatNumber(perReviewer/typical, 1))
+		}
+	}
+
+	// The work hours that would pick up most of the uncovered arrivals
+	if pct(len(uncovered)) >= 10 {
+		best, bestHits := 0, 0
+		for h := -12; h <= 14; h++ {
+			hits := 0
+			for _, t := range uncovered {
+				if atWork(t, utcOffset(h)) {
+					hits++
+				}
+			}
+			if hits > bestHits {
+				best, bestHits = h, hits
+			}
+		}
+		if bestHits > 0 {
+			start, end := workStart, workEnd
+			fmt.Printf("   💡 A reviewer working %02d:%02d-%02d:%02d in %s would pick up %.0f%% of the PRs now waiting for a shift.\n",
+				int(start.Hours()), int(start.Minutes())%60, int(end.Hours()), int(end.Minutes())%60, utcOffset(best),
+				float64(bestHits)/float64(len(uncovered))*100)
+		}
+	}
+	if inferred > 0 {
+		fmt.Println("   (Inferred timezones can be off for people who keep unusual hours; set them under timezones.people in config.)")
+	}
+	if len(unplaced) > 0 && namesShown() {
+		fmt.Printf("   (Not placed: %s.)\n", strings.Join(unplaced, ", "))
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localeFormat holds the date layouts and number separators of a locale.
+type localeFormat struct {
+	Date      string // Go layout for a full date
+	Month     string // Go layout for a month
+	Decimal   string
+	Thousands string
+}
+
+var locales = map[string]localeFormat{
+	"iso":   {"2006-01-02", "2006-01", ".", ""},
+	"en-US": {"01/02/2006", "Jan 2006", ".", ","},
+	"en-GB": {"02/01/2006", "Jan 2006", ".", ","},
+	"de-DE": {"02.01.2006", "01.2006", ",", "."},
+	"fr-FR": {"02/01/2006", "01/2006", ",", " "},
+	"es-ES": {"02/01/2006", "01/2006", ",", "."},
+	"ja-JP": {"2006/01/02", "2006/01", ".", ","},
+}
+
+var durationFormats = []string{"humanized", "hours", "iso8601"}
+
+// Active output formatting, set from --locale and --duration-format.
+var (
+	activeLocale   = locales["iso"]
+	durationFormat = "humanized"
+)
+
+// setFormatting validates and activates the locale and duration format.
+func setFormatting(locale, duration string) error {
+	l, ok := locales[locale]
+	if !ok {
+		var names []string
+		for n := range locales {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown locale %q (use one of %s)", locale, strings.Join(names, ", "))
+	}
+	known := false
+	for _, f := range durationFormats {
+		known = known || f == duration
+	}
+	if !known {
+		return fmt.Errorf("unknown duration format %q (use %s)", duration, strings.Join(durationFormats, ", "))
+	}
+	activeLocale, durationFormat = l, duration
+	return nil
+}
+
+// formatDuration renders a duration in the active duration format.
+func formatDuration(d time.Duration) string {
+	switch durationFormat {
+	case "hours":
+		return formatNumber(d.Hours(), 1) + "h"
+	case "iso8601":
+		return isoDuration(d)
+	}
+	return humanizeDuration(d)
+}
+
+// isoDuration renders d as an ISO 8601 duration, e.g. P1DT4H30M.
+func isoDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + isoDuration(-d)
+	}
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	var b strings.Builder
+	b.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || seconds > 0 || days == 0 {
+		b.WriteString("T")
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		if seconds > 0 || (days == 0 && hours == 0 && minutes == 0) {
+			fmt.Fprintf(&b, "%dS", seconds)
+		}
+	}
+	return b.String()
+}
+
+// formatDate renders a date in the active locale and the primary timezone.
+func formatDate(t time.Time) string {
+	return t.In(primaryLocation).Format(activeLocale.Date)
+}
+
+// formatMonthKey renders a "2006-01" month key in the active locale.
+func formatMonthKey(key string) string {
+	t, err := time.Parse("2006-01", key)
+	if err != nil {
+		return key
+	}
+	return t.Format(activeLocale.Month)
+}
+
+// formatNumber renders v with the given decimals and the active locale's
+// separators.
+func formatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	if sep := activeLocale.Thousands; sep != "" && len(intPart) > 3 {
+		var b strings.Builder
+		lead := len(intPart) % 3
+		if lead > 0 {
+			b.WriteString(intPart[:lead])
+		}
+		for i := lead; i < len(intPart); i += 3 {
+			if b.Len() > 0 {
+				b.WriteString(sep)
+			}
+			b.WriteString(intPart[i : i+3])
+		}
+		intPart = b.String()
+	}
+
+	out := intPart
+	if frac != "" {
+		out += activeLocale.Decimal + frac
+	}
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		out = "-" + out
+	}
+	return out
+}
+
+// formatInt renders an integer with the active locale's thousands separator.
+func formatInt(n int) string {
+	return formatNumber(float64(n), 0)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Built-in patterns for generated, vendored and lock files. They use the
+// same gitignore-style syntax as CODEOWNERS.
+var defaultGeneratedPatterns = []string{
+	"vendor/",
+	"node_modules/",
+	"third_party/",
+	"*_pb.go",
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+	"*.pb.ts",
+	"*_generated.go",
+	"*.gen.go",
+	"zz_generated*",
+	"*.min.js",
+	"*.min.css",
+	"*.snap",
+	"go.sum",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"Gemfile.lock",
+	"poetry.lock",
+	"composer.lock",
+	"Pipfile.lock",
+}
+
+type generatedRule struct {
+	Pattern string
+	Re      *regexp.Regexp
+}
+
+// GeneratedMatcher recognizes files whose lines are noise for size metrics.
+type GeneratedMatcher struct {
+	Rules []generatedRule
+}
+
+// newGeneratedMatcher combines the built-in patterns, the configured
+// `generated:` patterns and linguist attributes from .gitattributes.
+func newGeneratedMatcher(gitattributes string) *GeneratedMatcher {
+	m := &GeneratedMatcher{}
+	add := func(p string) {
+		m.Rules = append(m.Rules, generatedRule{Pattern: p, Re: codeownersPattern(p)})
+	}
+	for _, p := range defaultGeneratedPatterns {
+		add(p)
+	}
+	for _, p := range cfg.Generated {
+		add(p)
+	}
+	for _, p := range parseGitattributes(gitattributes) {
+		add(p)
+	}
+	return m
+}
+
+// parseGitattributes returns the patterns marked linguist-generated or
+// linguist-vendored.
+func parseGitattributes(content string) []string {
+	var patterns []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for _, attr := range fields[1:] {
+			if attr == "linguist-generated" || attr == "linguist-generated=true" ||
+				attr == "linguist-vendored" || attr == "linguist-vendored=true" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+// Match reports whether path is generated and which pattern matched.
+func (m *GeneratedMatcher) Match(path string) (bool, string) {
+	for _, r := range m.Rules {
+		if r.Re.MatchString(path) {
+			return true, r.Pattern
+		}
+	}
+	return false, ""
+}
+
+// markGenerated fills GeneratedSize for each PR and, when exclude is set,
+// removes those lines from Size. Only the files returned by the API are
+// inspected.
+func markGenerated(prs []PullRequest, m *GeneratedMatcher, exclude bool) {
+	for i := range prs {
+		pr := &prs[i]
+		pr.GeneratedSize = 0
+		for _, p := range pr.FilePaths {
+			if ok, _ := m.Match(p); ok {
+				pr.GeneratedSize += pr.FileLines[p]
+			}
+		}
+		if pr.GeneratedSize > pr.Size {
+			pr.GeneratedSize = pr.Size
+		}
+		if exclude {
+			pr.Size -= pr.GeneratedSize
+		}
+	}
+}
+
+func printGeneratedNoise(prs []PullRequest, m *GeneratedMatcher, excluded bool) {
+	fmt.Println("🧹 GENERATED CODE NOISE")
+	printExplanation("Lines in lockfiles, vendored deps and generated code (protobuf, mocks, linguist-generated).", "A 5,000-line PR that is 4,900 lines of go.sum is not a big PR. Counting it skews every size metric.")
+
+	var total, generated, affected int
+	byPattern := make(map[string]int)
+	for _, pr := range prs {
+		raw := pr.Size
+		if excluded {
+			raw += pr.GeneratedSize
+		}
+		total += raw
+		generated += pr.GeneratedSize
+		if pr.GeneratedSize > 0 {
+			affected++
+		}
+		for _, p := range pr.FilePaths {
+			if ok, pattern := m.Match(p); ok {
+				byPattern[pattern] += pr.FileLines[p]
+			}
+		}
+	}
+
+	if generated == 0 {
+		fmt.Println("   ✅ No generated or vendored files found in the analyzed PRs.")
+		return
+	}
+
+	share := float64(generated) / float64(total) * 100
+	fmt.Printf("   Generated lines: %s of %s (%s%% of all \"size\")\n", formatInt(generated), formatInt(total), formatNumber(share, 1))
+	fmt.Printf("   PRs affected:    %d of %d\n", affected, len(prs))
+
+	var patterns []string
+	for p := range byPattern {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if byPattern[patterns[i]] != byPattern[patterns[j]] {
+			return byPattern[patterns[i]] > byPattern[patterns[j]]
+		}
+		return patterns[i] < patterns[j]
+	})
+	fmt.Println("\n   Top sources:")
+	for i, p := range patterns {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("   %-25s: %s lines\n", limitString(p, 25), formatInt(byPattern[p]))
+	}
+
+	// Size vs speed with and without the noise
+	raw := make([]PullRequest, len(prs))
+	clean := make([]PullRequest, len(prs))
+	for i, pr := range prs {
+		raw[i], clean[i] = pr, pr
+		if excluded {
+			raw[i].Size += pr.GeneratedSize
+		} else {
+			clean[i].Size -= pr.GeneratedSize
+		}
+	}
+	fmt.Printf("\n   Size vs merge time correlation: %s raw, %s without generated lines\n", formatNumber(sizeCorrelation(raw), 2), formatNumber(sizeCorrelation(clean), 2))
+	if excluded {
+		fmt.Println("   (Generated lines are excluded from size metrics. Use --include-generated to keep them.)")
+	} else {
+		fmt.Println("   (Generated lines are included in size metrics because of --include-generated.)")
+	}
+	fmt.Println("   (Only the first files of each PR are inspected, so these numbers are a lower bound.)")
+}
+
+// loadGeneratedMatcher fetches .gitattributes for linguist overrides.
+func loadGeneratedMatcher(ctx context.Context, owner, name string, timeout time.Duration) (*GeneratedMatcher, error) {
+	files, err := fetchRepoFiles(ctx, owner, name, []string{".gitattributes"}, timeout)
+	if err != nil {
+		return newGeneratedMatcher(""), err
+	}
+	return newGeneratedMatcher(files[".gitattributes"]), nil
+}
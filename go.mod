@@ -1,3 +1,5 @@
 module bottleneck
 
 go 1.25.4
+
+require gopkg.in/yaml.v3 v3.0.1
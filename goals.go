@@ -0,0 +1,406 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goal is a target for one metric, e.g. p90 first review under 8 business
+// hours by Q4.
+type Goal struct {
+	Name          string `yaml:"name"`           // Defaults to a description of the metric
+	Metric        string `yaml:"metric"`         // first_review, merge, size, rounds, reviewed or top_reviewer
+	Stat          string `yaml:"stat"`           // median (default), p90 or avg
+	Target        string `yaml:"target"`         // A duration ("8h", "2d"), a number or a percentage
+	BusinessHours bool   `yaml:"business_hours"` // Count only work hours (timezones.work_hours) on weekdays
+	By            string `yaml:"by"`             // Deadline: "Q4", "2026-Q4", "2026-12" or "2026-12-31"
+}
+
+// goalMetric describes a metric a goal can target.
+type goalMetric struct {
+	Label    string
+	Duration bool // Values are hours
+	Percent  bool
+	Higher   bool // Higher values are better
+	// Values returns the per-PR values of the metric.
+	Values func(prs []PullRequest, business bool) []float64
+}
+
+var goalMetrics = map[string]goalMetric{
+	"first_review": {Label: "first review", Duration: true, Values: func(prs []PullRequest, business bool) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			if pr.FirstReviewAt != nil {
+				out = append(out, goalHours(pr.CreatedAt, *pr.FirstReviewAt, business))
+			}
+		}
+		return out
+	}},
+	"merge": {Label: "merge time", Duration: true, Values: func(prs []PullRequest, business bool) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			out = append(out, goalHours(pr.CreatedAt, pr.MergedAt, business))
+		}
+		return out
+	}},
+	"size": {Label: "PR size", Values: func(prs []PullRequest, _ bool) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			out = append(out, float64(pr.Size))
+		}
+		return out
+	}},
+	"rounds": {Label: "review rounds", Values: func(prs []PullRequest, _ bool) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			out = append(out, float64(reviewRounds(pr)))
+		}
+		return out
+	}},
+	// Share metrics yield one 0/100 value per PR, so their average is the
+	// percentage
+	"reviewed": {Label: "PRs reviewed", Percent: true, Higher: true, Values: func(prs []PullRequest, _ bool) []float64 {
+		var out []float64
+		for _, pr := range prs {
+			v := 0.0
+			if len(pr.Reviewers) > 0 {
+				v = 100
+			}
+			out = append(out, v)
+		}
+		return out
+	}},
+	"top_reviewer": {Label: "reviews by the top reviewer", Percent: true, Values: func(prs []PullRequest, _ bool) []float64 {
+		counts := make(map[string]int)
+		for _, pr := range prs {
+			for _, r := range pr.Reviewers {
+				counts[r]++
+			}
+		}
+		top := ""
+		for r, n := range counts {
+			if top == "" || n > counts[top] || n == counts[top] && r < top {
+				top = r
+			}
+		}
+		var out []float64
+		for r, n := range counts {
+			v := 0.0
+			if r == top {
+				v = 100
+			}
+			for i := 0; i < n; i++ {
+				out = append(out, v)
+			}
+		}
+		return out
+	}},
+}
+
+// goalHours is the time from start to end in hours, or only the work hours
+// in the primary timezone when business is set.
+func goalHours(start, end time.Time, business bool) float64 {
+	if end.Before(start) {
+		return 0
+	}
+	if business {
+		return businessDuration(start, end).Hours()
+	}
+	return end.Sub(start).Hours()
+}
+
+// businessDuration is the part of start to end that falls within work hours
+// on weekdays in the primary timezone.
+func businessDuration(start, end time.Time) time.Duration {
+	var total time.Duration
+	s := start.In(primaryLocation)
+	for day := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, primaryLocation); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		from, to := day.Add(workStart), day.Add(workEnd)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			total += to.Sub(from)
+		}
+	}
+	return total
+}
+
+func (g Goal) metric() (goalMetric, error) {
+	m, ok := goalMetrics[g.Metric]
+	if !ok {
+		var names []string
+		for n := range goalMetrics {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return m, fmt.Errorf("unknown metric %q (use %s)", g.Metric, strings.Join(names, ", "))
+	}
+	return m, nil
+}
+
+func (g Goal) stat() string {
+	if g.Stat == "" || goalMetrics[g.Metric].Percent {
+		return "median"
+	}
+	return g.Stat
+}
+
+// target parses the target into the metric's unit: hours for durations.
+func (g Goal) target() (float64, error) {
+	m, err := g.metric()
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(g.Target)
+	if m.Duration {
+		d, err := parseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("target %q is not a duration like 8h or 2d", g.Target)
+		}
+		return d.Hours(), nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("target %q is not a number", g.Target)
+	}
+	return v, nil
+}
+
+// deadline parses By into the end of that day, month or quarter. A bare
+// quarter is in the year of now.
+func (g Goal) deadline(now time.Time) (time.Time, error) {
+	by := strings.ToUpper(strings.TrimSpace(g.By))
+	if by == "" {
+		return time.Time{}, nil
+	}
+	year, quarter := now.In(primaryLocation).Year(), by
+	if y, q, ok := strings.Cut(by, "-Q"); ok {
+		n, err := strconv.Atoi(y)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("deadline %q is not like Q4, 2026-Q4, 2026-12 or 2026-12-31", g.By)
+		}
+		year, quarter = n, "Q"+q
+	}
+	if strings.HasPrefix(quarter, "Q") {
+		q, err := strconv.Atoi(quarter[1:])
+		if err != nil || q < 1 || q > 4 {
+			return time.Time{}, fmt.Errorf("deadline %q is not like Q4, 2026-Q4, 2026-12 or 2026-12-31", g.By)
+		}
+		return time.Date(year, time.Month(q*3+1), 1, 0, 0, 0, 0, primaryLocation).Add(-time.Nanosecond), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", by, primaryLocation); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", by, primaryLocation); err == nil {
+		return t.AddDate(0, 1, 0).Add(-time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("deadline %q is not like Q4, 2026-Q4, 2026-12 or 2026-12-31", g.By)
+}
+
+// validateGoals rejects goals that can't be evaluated.
+func validateGoals(goals []Goal) error {
+	for i, g := range goals {
+		m, err := g.metric()
+		if err == nil {
+			_, err = g.target()
+		}
+		if err == nil {
+			_, err = g.deadline(clock())
+		}
+		if err == nil && g.Stat != "" && g.Stat != "median" && g.Stat != "p90" && g.Stat != "avg" {
+			err = fmt.Errorf("unknown stat %q (use median, p90 or avg)", g.Stat)
+		}
+		if err == nil && g.BusinessHours && !m.Duration {
+			err = fmt.Errorf("business_hours only applies to first_review and merge")
+		}
+		if err != nil {
+			return fmt.Errorf("goals[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (g Goal) label() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	m := goalMetrics[g.Metric]
+	label := m.Label
+	if !m.Percent {
+		label = g.stat() + " " + label
+	}
+	if g.BusinessHours {
+		label += " (business hours)"
+	}
+	return label
+}
+
+// value computes the goal's metric over prs. It reports false when there is
+// nothing to measure.
+func (g Goal) value(prs []PullRequest) (float64, bool) {
+	values := goalMetrics[g.Metric].Values(prs, g.BusinessHours)
+	if len(values) == 0 {
+		return 0, false
+	}
+	if g.stat() == "avg" || goalMetrics[g.Metric].Percent {
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values)), true
+	}
+	sort.Float64s(values)
+	if g.stat() == "p90" {
+		return quantile(values, 90), true
+	}
+	return quantile(values, 50), true
+}
+
+func (g Goal) met(v, target float64) bool {
+	if goalMetrics[g.Metric].Higher {
+		return v >= target
+	}
+	return v <= target
+}
+
+func (g Goal) format(v float64) string {
+	m := goalMetrics[g.Metric]
+	switch {
+	case m.Duration:
+		return formatDuration(time.Duration(v * float64(time.Hour)))
+	case m.Percent:
+		return fmt.Sprintf("%.0f%%", v)
+	case g.Metric == "size":
+		return formatInt(int(math.Round(v)))
+	}
+	return formatNumber(v, 1)
+}
+
+// goalMonth is a goal's value over the PRs merged in one month.
+type goalMonth struct {
+	Month string // 2006-01
+	Value float64
+}
+
+// goalMonths computes the goal per merge month, oldest first, over the last
+// six months with at least five PRs.
+func goalMonths(g Goal, prs []PullRequest) []goalMonth {
+	byMonth := make(map[string][]PullRequest)
+	for _, pr := range prs {
+		key := pr.MergedAt.In(primaryLocation).Format("2006-01")
+		byMonth[key] = append(byMonth[key], pr)
+	}
+	var keys []string
+	for k, ms := range byMonth {
+		if len(ms) >= 5 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	keys = keys[max(0, len(keys)-6):]
+	var out []goalMonth
+	for _, k := range keys {
+		if v, ok := g.value(byMonth[k]); ok {
+			out = append(out, goalMonth{Month: k, Value: v})
+		}
+	}
+	return out
+}
+
+// monthlySlope is the least-squares change per month of the values.
+func monthlySlope(months []goalMonth) float64 {
+	n := float64(len(months))
+	var sumX, sumY, sumXY, sumX2 float64
+	for i, m := range months {
+		x := float64(i)
+		sumX += x
+		sumY += m.Value
+		sumXY += x * m.Value
+		sumX2 += x * x
+	}
+	d := n*sumX2 - sumX*sumX
+	if d == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / d
+}
+
+// printGoals shows each configured goal's current value against its target
+// and whether the monthly trajectory reaches it by the deadline.
+func printGoals(prs []PullRequest) {
+	fmt.Println("🎯 GOALS")
+	printExplanation("Each goal from the config: its value over the merged PRs in this report, the target, and where the monthly trend lands by the deadline.",
+		"Metrics without targets don't change behavior. A goal with a date tells you whether the current pace is enough.")
+
+	now := clock()
+	for _, g := range cfg.Goals {
+		target, _ := g.target()
+		deadline, _ := g.deadline(now)
+		cmp := "<"
+		if goalMetrics[g.Metric].Higher {
+			cmp = ">"
+		}
+		goal := cmp + " " + g.format(target)
+		if !deadline.IsZero() {
+			goal += " by " + formatDate(deadline)
+		}
+
+		v, ok := g.value(prs)
+		if !ok {
+			fmt.Printf("   ❓ %s: no data (goal %s)\n", g.label(), goal)
+			continue
+		}
+		months := goalMonths(g, prs)
+		// The projection starts from the latest month, not the whole window
+		slope, latest := 0.0, v
+		if len(months) >= 3 {
+			slope, latest = monthlySlope(months), months[len(months)-1].Value
+		}
+		improving := slope < 0
+		if goalMetrics[g.Metric].Higher {
+			improving = slope > 0
+		}
+
+		var status string
+		switch {
+		case g.met(v, target):
+			status = "✅ Met"
+		case len(months) < 3:
+			status = "❓ Not met; too few months for a trajectory"
+		case !deadline.IsZero() && now.After(deadline):
+			status = "❌ Missed the deadline"
+		case !improving || slope == 0:
+			status = "⚠️  Off track: not improving"
+		case deadline.IsZero():
+			status = fmt.Sprintf("📈 Improving; at this pace met in %s months", formatNumber(math.Max((target-latest)/slope, 0), 1))
+		default:
+			left := deadline.Sub(now).Hours() / 24 / 30.44
+			projected := latest + slope*left
+			if g.met(projected, target) {
+				status = fmt.Sprintf("📈 On track: projected %s by the deadline", g.format(math.Max(projected, 0)))
+			} else {
+				status = fmt.Sprintf("⚠️  Off track: projected %s by the deadline", g.format(math.Max(projected, 0)))
+			}
+		}
+		fmt.Printf("   %s: %s (goal %s)\n", g.label(), g.format(v), goal)
+		fmt.Printf("      %s\n", status)
+		if len(months) >= 2 {
+			var parts []string
+			for _, m := range months {
+				parts = append(parts, formatMonthKey(m.Month)+" "+g.format(m.Value))
+			}
+			fmt.Printf("      Trajectory: %s\n", strings.Join(parts, " → "))
+		}
+	}
+}
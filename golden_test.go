@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files under testdata/golden")
+
+// goldenRepo is the repository the fixtures in testdata/replay were
+// recorded from. To record them again:
+//
+//	BOTTLENECK_RECORD=testdata/replay go test -run TestGolden -update
+const goldenRepo = "acme/widgets"
+
+// goldenNow is when the fixtures were recorded.
+var goldenNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// goldenRun fetches the fixture data the way analyzeRepo does, with every
+// optional field, as of goldenNow in UTC.
+func goldenRun(t *testing.T) *reportRun {
+	t.Helper()
+	if os.Getenv(recordEnv) == "" {
+		t.Setenv(replayEnv, filepath.Join("testdata", "replay"))
+	}
+	clock = func() time.Time { return goldenNow }
+	t.Cleanup(func() { clock = time.Now })
+	if err := applyTimezones(TimezoneConfig{Primary: "UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { applyTimezones(TimezoneConfig{}) })
+	if err := compileBranchTypes(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	owner, name, _ := parseRepo(goldenRepo)
+	o := reportOptions{
+		Limit:          300,
+		Timeout:        time.Minute,
+		ResponseSLA:    24 * time.Hour,
+		Store:          Store{Dir: t.TempDir()},
+		Slowest:        5,
+		LongTailMinPRs: 5,
+		StaleBranchAge: 30 * 24 * time.Hour,
+		Viewer:         "user-6", // For the my_* sections
+	}
+	merged, err := fetchPRs(ctx, owner, name, o.Limit, "MERGED", o.Timeout, 0)
+	if err != nil {
+		t.Fatalf("merged PRs: %v", err)
+	}
+	open, err := fetchPRs(ctx, owner, name, 100, "OPEN", o.Timeout, 0)
+	if err != nil {
+		t.Fatalf("open PRs: %v", err)
+	}
+	generated, err := loadGeneratedMatcher(ctx, owner, name, o.Timeout)
+	if err != nil {
+		t.Fatalf(".gitattributes: %v", err)
+	}
+	markGenerated(merged, generated, true)
+	markGenerated(open, generated, true)
+	return &reportRun{
+		ctx: ctx, repo: goldenRepo, owner: owner, name: name, o: o, errs: &fetchErrors{},
+		merged: merged, fetched: len(merged), open: open, generated: generated,
+	}
+}
+
+// captureStdout returns what f prints.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	defer func() { os.Stdout = orig }()
+	f()
+	w.Close()
+	return <-out
+}
+
+// checkGolden compares got with testdata/golden/<name>.golden, or rewrites
+// the file with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update to accept it)\n%s", path, firstDiff(string(want), got))
+	}
+}
+
+// firstDiff describes the first line where got departs from want.
+func firstDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < max(len(w), len(g)); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, wl, gl)
+		}
+	}
+	return ""
+}
+
+func TestGoldenHealth(t *testing.T) {
+	r := goldenRun(t)
+	got := captureStdout(t, func() {
+		printHealthScore(healthSnapshot(r.repo, r.merged, r.open, clock()), nil)
+	})
+	checkGolden(t, "health", got)
+}
+
+func TestGoldenSections(t *testing.T) {
+	r := goldenRun(t)
+	for _, sec := range reportSections {
+		t.Run(sec.Name, func(t *testing.T) {
+			got := captureStdout(t, func() { sec.Run(r, SectionConfig{Name: sec.Name}) })
+			checkGolden(t, sec.Name, got)
+		})
+	}
+}
+
+// TestGoldenTeams runs the sections that need a team mapping.
+func TestGoldenTeams(t *testing.T) {
+	r := goldenRun(t)
+	saved := cfg.Teams
+	cfg.Teams = map[string][]string{
+		"Platform": {"user-1", "user-2", "user-3"},
+		"Product":  {"user-4", "user-5", "user-6"},
+		"Data":     {"user-7", "user-8"},
+	}
+	t.Cleanup(func() { cfg.Teams = saved })
+	for _, name := range []string{"team_latency"} {
+		sec := findSection(name)
+		t.Run(name, func(t *testing.T) {
+			got := captureStdout(t, func() { sec.Run(r, SectionConfig{Name: name}) })
+			checkGolden(t, "teams_"+name, got)
+		})
+	}
+}
+
+// TestGoldenStaleExempt runs the stale section with parked PRs exempt.
+func TestGoldenStaleExempt(t *testing.T) {
+	r := goldenRun(t)
+	saved := cfg.Stale
+	cfg.Stale = StaleConfig{Exempt: StaleExemptions{Labels: []string{"enhancement"}, Drafts: true}}
+	t.Cleanup(func() { cfg.Stale = saved })
+	got := captureStdout(t, func() { printStaleAnalysis(r.open) })
+	checkGolden(t, "stale_exempt", got)
+}
+
+// TestGoldenFirstReview runs review efficiency under each first review
+// definition.
+func TestGoldenFirstReview(t *testing.T) {
+	r := goldenRun(t)
+	saved := cfg.FirstReview
+	t.Cleanup(func() { cfg.FirstReview = saved })
+	for _, def := range []string{firstReviewComment, firstReviewHuman} {
+		t.Run(def, func(t *testing.T) {
+			cfg.FirstReview = def
+			merged := append([]PullRequest(nil), r.merged...)
+			for i := range merged {
+				merged[i].FirstReviewAt = firstReviewAt(merged[i])
+			}
+			got := captureStdout(t, func() { printReviewStats(merged) })
+			checkGolden(t, "review_"+def, got)
+		})
+	}
+}
+
+// TestGoldenJSON pins the --format json payload. A change that isn't purely
+// additive needs a major schema_version bump.
+func TestGoldenJSON(t *testing.T) {
+	r := goldenRun(t)
+	var schema struct {
+		Properties struct {
+			SchemaVersion struct {
+				Const string `json:"const"`
+			} `json:"schema_version"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(reportSchema, &schema); err != nil {
+		t.Fatalf("report.schema.json: %v", err)
+	}
+	if schema.Properties.SchemaVersion.Const != reportSchemaVersion {
+		t.Errorf("report.schema.json is version %q, the payload %q", schema.Properties.SchemaVersion.Const, reportSchemaVersion)
+	}
+
+	rep, ok := jsonRepoReport(r.ctx, r.repo, r.o, r.errs)
+	if !ok {
+		t.Fatal("no PRs fetched")
+	}
+	var b strings.Builder
+	if err := writeJSONReport(&b, []RepoReport{rep}, *r.errs); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "report_json", b.String())
+}
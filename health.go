@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HealthConfig weights the sub-scores of the review health score. Missing
+// components keep their default weight; a weight of 0 drops a component.
+type HealthConfig struct {
+	Weights map[string]float64 `yaml:"weights"`
+}
+
+// healthComponent is one sub-score, scored linearly from 100 at Good to 0 at
+// Bad.
+type healthComponent struct {
+	Name   string
+	Label  string
+	Weight float64
+	Good   float64
+	Bad    float64
+	Value  func(h healthInput) float64
+	Format func(v float64) string
+}
+
+type healthInput struct {
+	Metrics  Metrics
+	StalePct float64
+}
+
+var healthComponents = []healthComponent{
+	{"triage", "Triage latency", 25, 4, 72,
+		func(h healthInput) float64 { return h.Metrics.MedianFirstReview.Hours() },
+		func(v float64) string { return formatDuration(time.Duration(v*float64(time.Hour))) + " median" }},
+	{"heroes", "Hero concentration", 20, 20, 60,
+		func(h healthInput) float64 { return h.Metrics.TopReviewerPct },
+		func(v float64) string { return fmt.Sprintf("top reviewer %.0f%%", v) }},
+	{"stale", "Stale backlog", 20, 0, 50,
+		func(h healthInput) float64 { return h.StalePct },
+		func(v float64) string { return fmt.Sprintf("%.0f%% of open PRs", v) }},
+	{"size", "Size discipline", 15, 200, 1000,
+		func(h healthInput) float64 { return float64(h.Metrics.MedianSize) },
+		func(v float64) string { return formatInt(int(v)) + " lines median" }},
+	{"coverage", "Review coverage", 20, 100, 50,
+		func(h healthInput) float64 { return h.Metrics.ReviewedPct },
+		func(v float64) string { return fmt.Sprintf("%.0f%% reviewed", v) }},
+}
+
+// validateHealth rejects unknown components and negative weights.
+func validateHealth(h HealthConfig) error {
+	for name, w := range h.Weights {
+		known := false
+		for _, c := range healthComponents {
+			known = known || c.Name == name
+		}
+		if !known {
+			var names []string
+			for _, c := range healthComponents {
+				names = append(names, c.Name)
+			}
+			return fmt.Errorf("health: unknown component %q (use %s)", name, strings.Join(names, ", "))
+		}
+		if w < 0 {
+			return fmt.Errorf("health: weight of %s must not be negative", name)
+		}
+	}
+	return nil
+}
+
+func (h HealthConfig) weight(c healthComponent) float64 {
+	if w, ok := h.Weights[c.Name]; ok {
+		return w
+	}
+	return c.Weight
+}
+
+// scoreBetween maps v linearly onto 100 (at good) to 0 (at bad), clamped.
+func scoreBetween(v, good, bad float64) float64 {
+	s := (v - bad) / (good - bad) * 100
+	return max(0, min(100, s))
+}
+
+// staleOpen counts open PRs without activity for more than 7 days, leaving
+// out exempt ones.
+func staleOpen(open []PullRequest, now time.Time) int {
+	stale := 0
+	cutoff := staleCutoff(now)
+	for _, pr := range open {
+		if isStale(pr, cutoff) {
+			stale++
+		}
+	}
+	return stale
+}
+
+// areaSnapshots computes the merge speed per area. A PR counts once in each
+// area it touches.
+func areaSnapshots(merged []PullRequest) map[string]AreaSnapshot {
+	type acc struct{ cycle, firstReview []time.Duration }
+	byArea := make(map[string]*acc)
+	for _, pr := range merged {
+		seen := make(map[string]bool)
+		for _, p := range pr.FilePaths {
+			area := groupFor(p)
+			if seen[area] {
+				continue
+			}
+			seen[area] = true
+			if byArea[area] == nil {
+				byArea[area] = &acc{}
+			}
+			a := byArea[area]
+			a.cycle = append(a.cycle, pr.MergedAt.Sub(pr.CreatedAt))
+			if pr.FirstReviewAt != nil && pr.FirstReviewAt.After(pr.CreatedAt) {
+				a.firstReview = append(a.firstReview, pr.FirstReviewAt.Sub(pr.CreatedAt))
+			}
+		}
+	}
+	if len(byArea) == 0 {
+		return nil
+	}
+	out := make(map[string]AreaSnapshot, len(byArea))
+	for area, a := range byArea {
+		s := AreaSnapshot{PRs: len(a.cycle), MedianCycleTime: percentile(a.cycle, 50)}
+		if len(a.firstReview) > 0 {
+			s.MedianFirstReview = percentile(a.firstReview, 50)
+		}
+		out[area] = s
+	}
+	return out
+}
+
+// healthFields are the optional PR fields the health score and snapshot
+// read: commits for review rounds, files for generated lines, areas and
+// the archetype.
+const healthFields = fieldCommits | fieldFiles
+
+// healthSnapshot scores the merged and open PRs into a snapshot.
+func healthSnapshot(repo string, merged, open []PullRequest, now time.Time) Snapshot {
+	snap := Snapshot{
+		Repo:    repo,
+		Taken:   now,
+		Scores:  make(map[string]float64),
+		Metrics: computeMetrics(merged),
+		Open:    len(open),
+		Stale:   staleOpen(open, now),
+	}
+	cutoff := staleCutoff(now)
+	for _, pr := range open {
+		switch {
+		case isStale(pr, cutoff):
+			snap.StalePRs = append(snap.StalePRs, StalePR{Number: pr.Number, Since: pr.UpdatedAt})
+		case pr.UpdatedAt.Before(cutoff):
+			snap.StaleExempt++
+		}
+	}
+	snap.Areas = areaSnapshots(merged)
+	arch, _ := repoArchetype(merged)
+	snap.Archetype = arch.Name
+	in := healthInput{Metrics: snap.Metrics}
+	if len(open) > 0 {
+		in.StalePct = float64(snap.Stale) / float64(len(open)) * 100
+	}
+
+	total, weights := 0.0, 0.0
+	for _, c := range healthComponents {
+		w := cfg.Health.weight(c)
+		switch {
+		case w == 0:
+			continue
+		case c.Name == "stale" && len(open) == 0:
+			continue
+		case c.Name != "stale" && len(merged) == 0:
+			continue
+		case c.Name == "triage" && snap.Metrics.ReviewedPct == 0:
+			continue // No reviews, so no triage latency; coverage scores it
+		}
+		good, bad := arch.bounds(c)
+		s := scoreBetween(c.Value(in), good, bad)
+		snap.Scores[c.Name] = s
+		total += s * w
+		weights += w
+	}
+	if weights > 0 {
+		snap.Health = int(total/weights + 0.5)
+	}
+	return snap
+}
+
+func healthEmoji(score float64) string {
+	switch {
+	case score >= 80:
+		return "🟢"
+	case score >= 60:
+		return "🟡"
+	case score >= 40:
+		return "🟠"
+	}
+	return "🔴"
+}
+
+func printHealthScore(snap Snapshot, history []Snapshot) {
+	fmt.Printf("🩺 REVIEW HEALTH SCORE: %s %d/100\n", healthEmoji(float64(snap.Health)), snap.Health)
+	printExplanation("One 0-100 number built from weighted sub-scores, each scaled between a healthy and an unhealthy threshold.",
+		"A single trackable number for leadership. The sub-scores show which lever moves it.")
+
+	in := healthInput{Metrics: snap.Metrics}
+	if snap.Open > 0 {
+		in.StalePct = float64(snap.Stale) / float64(snap.Open) * 100
+	}
+	for _, c := range healthComponents {
+		s, ok := snap.Scores[c.Name]
+		if !ok {
+			continue
+		}
+		fmt.Printf("   %s %-20s %3.0f  (weight %s, %s)\n", healthEmoji(s), c.Label, s, formatNumber(cfg.Health.weight(c), 0), c.Format(c.Value(in)))
+	}
+	if a, ok := archetypeNamed(snap.Archetype); ok && len(a.Thresholds) > 0 {
+		fmt.Printf("\n   Thresholds for a %s: %s. Set archetype: in config to override.\n", a.Label, a.describe())
+	}
+
+	if len(history) == 0 {
+		return
+	}
+	recent := history[max(0, len(history)-6):]
+	var parts []string
+	for _, h := range recent {
+		parts = append(parts, fmt.Sprintf("%s %d", formatDate(h.Taken), h.Health))
+	}
+	parts = append(parts, fmt.Sprintf("now %d", snap.Health))
+	fmt.Printf("\n   Trend: %s\n", strings.Join(parts, " → "))
+	prev := recent[len(recent)-1].Health
+	if last := recent[len(recent)-1].Archetype; last != "" && last != snap.Archetype {
+		fmt.Printf("   (The last snapshot was scored as %s, this one as %s, so part of the change is the thresholds.)\n", last, snap.Archetype)
+	}
+	switch {
+	case snap.Health > prev:
+		fmt.Printf("   📈 Up %d points since the last snapshot.\n", snap.Health-prev)
+	case snap.Health < prev:
+		fmt.Printf("   📉 Down %d points since the last snapshot.\n", prev-snap.Health)
+	default:
+		fmt.Println("   ➡️  Unchanged since the last snapshot.")
+	}
+}
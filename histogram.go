@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// HistogramConfig sets the upper bounds of the distribution buckets, e.g.
+// [4h, 8h, 24h, 3d]. A final open-ended bucket is always added.
+type HistogramConfig struct {
+	Buckets []Duration `yaml:"buckets"`
+}
+
+var defaultHistogramBuckets = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+type histogramBucket struct {
+	Label string
+	Max   time.Duration
+	Count int
+}
+
+// histogramBuckets returns the configured buckets, or the defaults.
+func histogramBuckets() []histogramBucket {
+	bounds := defaultHistogramBuckets
+	if len(cfg.Histogram.Buckets) > 0 {
+		bounds = nil
+		for _, b := range cfg.Histogram.Buckets {
+			bounds = append(bounds, time.Duration(b))
+		}
+	}
+
+	var buckets []histogramBucket
+	for i, max := range bounds {
+		label := "< " + shortDuration(max)
+		if i > 0 {
+			label = shortDuration(bounds[i-1]) + " - " + shortDuration(max)
+		}
+		buckets = append(buckets, histogramBucket{Label: label, Max: max})
+	}
+	buckets = append(buckets, histogramBucket{
+		Label: "> " + shortDuration(bounds[len(bounds)-1]),
+		Max:   time.Duration(math.MaxInt64),
+	})
+	return buckets
+}
+
+// validateHistogram checks that configured buckets are positive and ascending.
+func validateHistogram(c HistogramConfig) error {
+	for i, b := range c.Buckets {
+		if b <= 0 {
+			return fmt.Errorf("histogram.buckets: %s is not positive", time.Duration(b))
+		}
+		if i > 0 && b <= c.Buckets[i-1] {
+			return fmt.Errorf("histogram.buckets must be ascending")
+		}
+	}
+	return nil
+}
+
+// shortDuration formats a bucket bound in the largest whole unit: 30d as 1mo,
+// then weeks, days, hours and minutes.
+func shortDuration(d time.Duration) string {
+	day := 24 * time.Hour
+	switch {
+	case d >= 30*day && d%(30*day) == 0:
+		return fmt.Sprintf("%dmo", d/(30*day))
+	case d >= 7*day && d%(7*day) == 0:
+		return fmt.Sprintf("%dw", d/(7*day))
+	case d >= day && d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// histogramCounts sorts durations into the histogram buckets.
+func histogramCounts(durations []time.Duration) []histogramBucket {
+	buckets := histogramBuckets()
+	for _, d := range durations {
+		for i := range buckets {
+			if d < buckets[i].Max {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// printDurationHistogram prints bars per bucket plus the cumulative share of
+// durations that fall within each bucket's upper bound. headline formats the
+// closing line from that share and the largest bounded bucket, e.g.
+// "%s%% merge within %s.".
+func printDurationHistogram(durations []time.Duration, headline string) {
+	buckets := histogramCounts(durations)
+
+	maxCount := 0
+	for _, b := range buckets {
+		maxCount = max(maxCount, b.Count)
+	}
+
+	fmt.Printf("   %-12s   %-20s %6s   %s\n", "Bucket", "", "Count", "Cumulative")
+	cumulative := 0
+	for _, b := range buckets {
+		barLen := 0
+		if maxCount > 0 {
+			barLen = (b.Count * 20) / maxCount
+		}
+		bar := strings.Repeat("■", barLen)
+		cumulative += b.Count
+		pct := 0.0
+		if len(durations) > 0 {
+			pct = float64(cumulative) / float64(len(durations)) * 100
+		}
+		fmt.Printf("   %-12s : %-20s %6d   %5s%%\n", b.Label, bar, b.Count, formatNumber(pct, 1))
+	}
+
+	// Headline: the share within the largest bound that still leaves a tail
+	if len(durations) == 0 || len(buckets) < 2 {
+		return
+	}
+	cumulative = 0
+	for _, b := range buckets[:len(buckets)-1] {
+		cumulative += b.Count
+	}
+	last := buckets[len(buckets)-2]
+	fmt.Printf("\n   "+headline+"\n", formatNumber(float64(cumulative)/float64(len(durations))*100, 0), shortDuration(last.Max))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IdentityConfig merges GitHub accounts that belong to the same person and
+// marks service accounts as bots.
+type IdentityConfig struct {
+	// People maps the login a person is reported under to their other
+	// accounts: work and personal accounts, or the old name of a renamed
+	// account.
+	People map[string][]string `yaml:"people"`
+	// Bots lists accounts treated like dependabot: left out of per-person
+	// metrics.
+	Bots []string `yaml:"bots"`
+}
+
+var (
+	// aliases maps lowercased alternate logins to the login they're
+	// reported under.
+	aliases = map[string]string{}
+	// botLogins holds the lowercased logins of configured bot accounts.
+	botLogins = map[string]bool{}
+)
+
+// applyIdentities validates the identity map and installs it.
+func applyIdentities(c IdentityConfig) error {
+	a := make(map[string]string)
+	for name, accounts := range c.People {
+		if name == "" {
+			return fmt.Errorf("identities.people: empty login")
+		}
+		for _, login := range append([]string{name}, accounts...) {
+			l := strings.ToLower(login)
+			if other, ok := a[l]; ok && other != name {
+				return fmt.Errorf("identities.people: %s belongs to both %s and %s", login, other, name)
+			}
+			a[l] = name
+		}
+	}
+	bots := make(map[string]bool)
+	for _, login := range c.Bots {
+		l := strings.ToLower(login)
+		if _, ok := a[l]; ok {
+			return fmt.Errorf("identities: %s is listed as both a person and a bot", login)
+		}
+		bots[l] = true
+	}
+	aliases, botLogins = a, bots
+	return nil
+}
+
+// canonicalLogin returns the login a person is reported under. Logins not
+// in the identity map are returned as is.
+func canonicalLogin(login string) string {
+	if name, ok := aliases[strings.ToLower(login)]; ok {
+		return name
+	}
+	return login
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplyIdentities(t *testing.T) {
+	defer applyIdentities(IdentityConfig{})
+	tests := []struct {
+		name    string
+		c       IdentityConfig
+		wantErr string
+	}{
+		{"aliases and bots", IdentityConfig{People: map[string][]string{"alice": {"alice-work"}}, Bots: []string{"deploy-user"}}, ""},
+		{"alias claimed twice", IdentityConfig{People: map[string][]string{"alice": {"shared"}, "bob": {"Shared"}}}, "belongs to both"},
+		{"person and bot", IdentityConfig{People: map[string][]string{"alice": {"alice-ci"}}, Bots: []string{"Alice-CI"}}, "both a person and a bot"},
+		{"empty login", IdentityConfig{People: map[string][]string{"": {"alice"}}}, "empty login"},
+	}
+	for _, tt := range tests {
+		err := applyIdentities(tt.c)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIdentitiesInReviewCounts(t *testing.T) {
+	defer applyIdentities(IdentityConfig{})
+	if err := applyIdentities(IdentityConfig{People: map[string][]string{"alice": {"alice-work", "alice-old"}}, Bots: []string{"deploy-user"}}); err != nil {
+		t.Fatal(err)
+	}
+	node := func(author string, reviewers ...string) PullRequest {
+		var n GRPCPullRequest
+		n.Author.Login = author
+		var raw []string
+		for _, r := range reviewers {
+			raw = append(raw, `{"author":{"login":"`+r+`"}}`)
+		}
+		if err := json.Unmarshal([]byte(`{"nodes":[`+strings.Join(raw, ",")+`]}`), &n.Reviews); err != nil {
+			t.Fatal(err)
+		}
+		return toPullRequest(n)
+	}
+	prs := []PullRequest{
+		node("bob", "alice-work", "deploy-user"),
+		node("carol", "Alice-Old", "bob"),
+		node("alice-work", "alice", "carol"), // Her own other account isn't a reviewer
+	}
+	if prs[2].Author != "alice" {
+		t.Errorf("author = %q, want alice", prs[2].Author)
+	}
+	counts, total := countReviews(prs)
+	if counts["alice"] != 2 || counts["deploy-user"] != 0 || total != 4 {
+		t.Errorf("counts = %v, total %d; want alice 2 and no bot, 4 in all", counts, total)
+	}
+	if got := byPerson(counts); got["alice"] != 2 || len(got) != 3 {
+		t.Errorf("byPerson = %v", got)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AIConfig points --ai-insights at an OpenAI-compatible chat completions API.
+type AIConfig struct {
+	Endpoint  string   `yaml:"endpoint"`    // Base URL, e.g. https://api.openai.com/v1
+	Model     string   `yaml:"model"`       // e.g. gpt-4o-mini
+	APIKeyEnv string   `yaml:"api_key_env"` // Env var holding the key (default OPENAI_API_KEY)
+	Timeout   Duration `yaml:"timeout"`     // Default 60s
+	// IncludeNames sends repo and people names. Off by default, so only
+	// anonymized numbers leave the machine.
+	IncludeNames bool `yaml:"include_names"`
+}
+
+const aiSystemPrompt = `You are an engineering effectiveness coach. You receive pull request metrics computed by the "bottleneck" CLI for one repository.
+Write a short diagnosis of where the review process is stuck, then 3-5 prioritized, concrete recommendations tailored to these numbers.
+Be specific and reference the numbers. Plain text, no markdown headings, at most 250 words.`
+
+// insightsPayload is what gets sent to the model: numbers, not PRs.
+type insightsPayload struct {
+	Repo             string            `json:"repo,omitempty"`
+	MergedPRs        int               `json:"merged_prs"`
+	MedianCycleTime  string            `json:"median_cycle_time"`
+	P90CycleTime     string            `json:"p90_cycle_time"`
+	MedianTimeToFR   string            `json:"median_time_to_first_review"`
+	ReviewedPct      float64           `json:"reviewed_pct"`
+	AvgReviewRounds  float64           `json:"avg_review_rounds"`
+	MedianSizeLines  int               `json:"median_pr_size_lines"`
+	SizeCorrelation  float64           `json:"size_vs_merge_time_correlation"`
+	TopReviewer      string            `json:"top_reviewer"`
+	TopReviewerPct   float64           `json:"top_reviewer_share_pct"`
+	SlowestAreas     map[string]string `json:"slowest_areas_median_cycle_time"`
+	OpenPRs          int               `json:"open_prs"`
+	StaleOpenPRs     int               `json:"stale_open_prs_over_7d"`
+	GhostedRequests  int               `json:"review_requests_pending_over_48h"`
+	RuleBasedSignals []string          `json:"rule_based_risks"`
+}
+
+func buildInsightsPayload(repo string, merged, open []PullRequest, includeNames bool) insightsPayload {
+	m := computeMetrics(merged)
+	p := insightsPayload{
+		MergedPRs:        m.Count,
+		MedianCycleTime:  formatDuration(m.MedianCycleTime),
+		P90CycleTime:     formatDuration(m.P90CycleTime),
+		MedianTimeToFR:   formatDuration(m.MedianFirstReview),
+		ReviewedPct:      m.ReviewedPct,
+		AvgReviewRounds:  m.AvgRounds,
+		MedianSizeLines:  m.MedianSize,
+		SizeCorrelation:  m.SizeCorrelation,
+		TopReviewerPct:   m.TopReviewerPct,
+		SlowestAreas:     make(map[string]string),
+		OpenPRs:          len(open),
+		RuleBasedSignals: summaryRisks(m),
+	}
+	if includeNames {
+		p.Repo = repo
+		p.TopReviewer = m.TopReviewer
+	} else if m.TopReviewer != "" {
+		// Risk text embeds the top reviewer's name
+		p.TopReviewer = "reviewer-1"
+		for i, r := range p.RuleBasedSignals {
+			p.RuleBasedSignals[i] = strings.ReplaceAll(r, m.TopReviewer, "reviewer-1")
+		}
+	}
+
+	groups := groupCycleTimes(merged)
+	var names []string
+	for g, d := range groups {
+		if len(d) >= 3 {
+			names = append(names, g)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return percentile(groups[names[i]], 50) > percentile(groups[names[j]], 50) })
+	for i, g := range names {
+		if i >= 5 {
+			break
+		}
+		label := g
+		if !includeNames {
+			label = fmt.Sprintf("area-%d", i+1)
+		}
+		p.SlowestAreas[label] = formatDuration(percentile(groups[g], 50))
+	}
+
+	now := clock()
+	for _, pr := range open {
+		if now.Sub(pr.UpdatedAt) > 7*24*time.Hour {
+			p.StaleOpenPRs++
+		}
+		if now.Sub(pr.CreatedAt) > 48*time.Hour {
+			p.GhostedRequests += len(pr.Requested)
+		}
+	}
+	return p
+}
+
+// requestInsights sends the payload to the configured chat completions API.
+func requestInsights(ctx context.Context, c AIConfig, payload insightsPayload) (string, error) {
+	if c.Endpoint == "" || c.Model == "" {
+		return "", fmt.Errorf("ai.endpoint and ai.model must be set in the config file")
+	}
+	keyEnv := c.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = "OPENAI_API_KEY"
+	}
+	timeout := time.Duration(c.Timeout)
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	metrics, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       c.Model,
+		"temperature": 0.2,
+		"messages": []map[string]string{
+			{"role": "system", "content": aiSystemPrompt},
+			{"role": "user", "content": string(metrics)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(c.Endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key := os.Getenv(keyEnv); key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decoding response (%s): %w", resp.Status, err)
+	}
+	if out.Error != nil {
+		return "", fmt.Errorf("%s", out.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK || len(out.Choices) == 0 {
+		return "", fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return strings.TrimSpace(out.Choices[0].Message.Content), nil
+}
+
+func printAIInsights(ctx context.Context, repo string, merged, open []PullRequest) {
+	fmt.Println("🤖 AI INSIGHTS")
+	printExplanation("An LLM reads the computed metrics (not your code or PR text) and writes a tailored diagnosis.", "Rule-based actions are generic. A narrative that weighs all the numbers together is easier to act on.")
+
+	payload := buildInsightsPayload(repo, merged, open, cfg.AI.IncludeNames && namesShown())
+	text, err := requestInsights(ctx, cfg.AI, payload)
+	if err != nil {
+		fmt.Printf("   ❌ Could not get insights: %v\n", err)
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+	switch {
+	case !namesShown():
+		fmt.Println("\n   (Names were anonymized before sending, as --privacy requires.)")
+	case !cfg.AI.IncludeNames:
+		fmt.Println("\n   (Names were anonymized before sending. Set ai.include_names to share them.)")
+	}
+}
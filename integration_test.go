@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Integration tests of the fetch layer against the fake GitHub in
+// fakegithub_test.go.
+
+func TestFetchPaginates(t *testing.T) {
+	f := newFakeGitHub(t, fakeScenario{Merged: 250})
+	prs, err := fetchPRs(context.Background(), "acme", "widgets", 230, "MERGED", time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 230 {
+		t.Fatalf("fetched %d PRs, want 230", len(prs))
+	}
+	for i, pr := range prs {
+		if want := 250 - i; pr.Number != want {
+			t.Fatalf("PR %d is #%d, want #%d: pages overlap or skip", i, pr.Number, want)
+		}
+	}
+	queries := f.requests()
+	if len(queries) != 3 {
+		t.Fatalf("made %d requests, want 3", len(queries))
+	}
+	for i, want := range []string{"first: 100,", "first: 100,", "first: 30,"} {
+		if !strings.Contains(queries[i], want) {
+			t.Errorf("request %d doesn't ask for %q", i+1, want)
+		}
+	}
+	if strings.Contains(queries[0], "after:") || !strings.Contains(queries[1], "after:") {
+		t.Error("only pages after the first should pass a cursor")
+	}
+}
+
+func TestFetchStopsAtLastPage(t *testing.T) {
+	f := newFakeGitHub(t, fakeScenario{Merged: 120, Open: 7})
+	ctx := context.Background()
+	merged, err := fetchPRs(ctx, "acme", "widgets", 300, "MERGED", time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := fetchPRs(ctx, "acme", "widgets", 100, "OPEN", time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 120 || len(open) != 7 {
+		t.Errorf("fetched %d merged and %d open PRs, want 120 and 7", len(merged), len(open))
+	}
+	if n := len(f.requests()); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+	if merged[0].MergedAt.IsZero() || !open[0].MergedAt.IsZero() {
+		t.Error("merge times mixed up between states")
+	}
+}
+
+func TestFetchRateLimited(t *testing.T) {
+	f := newFakeGitHub(t, fakeScenario{Merged: 500, RateLimitAfter: 2})
+	prs, err := fetchPRs(context.Background(), "acme", "widgets", 500, "MERGED", time.Second, 0)
+	if err == nil || !strings.Contains(err.Error(), "API rate limit exceeded") {
+		t.Fatalf("err = %v, want the rate limit error", err)
+	}
+	if !strings.Contains(err.Error(), "failed after 200 PRs") {
+		t.Errorf("err = %v, want the PRs fetched before it", err)
+	}
+	if len(prs) != 200 {
+		t.Errorf("kept %d PRs, want the 200 fetched before the limit", len(prs))
+	}
+	if n := len(f.requests()); n != 3 {
+		t.Errorf("made %d requests, want 3: no retries after the limit", n)
+	}
+}
+
+func TestFetchServerError(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 300, FailAfter: 1, FailStatus: http.StatusBadGateway})
+	var errs fetchErrors
+	o := reportOptions{Limit: 300, Timeout: time.Second}
+	merged, open, _ := fetchRepoPRs(context.Background(), "acme/widgets", o, 0, &errs)
+	if len(merged) != 100 || len(open) != 0 {
+		t.Errorf("kept %d merged and %d open PRs, want 100 and 0", len(merged), len(open))
+	}
+	if !errs.partial("acme/widgets") || len(errs) != 2 {
+		t.Fatalf("errors = %v, want the merged and open fetches", errs)
+	}
+	if errs[0].Stage != "merged PRs" || errs[0].Fetched != 100 || !strings.Contains(errs[0].Err.Error(), "HTTP 502") {
+		t.Errorf("merged PR error = %+v", errs[0])
+	}
+}
+
+func TestFetchTimeout(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 10, Latency: time.Second})
+	_, err := fetchPRs(context.Background(), "acme", "widgets", 10, "MERGED", 50*time.Millisecond, 0)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+}
+
+func TestFetchTruncatedFields(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 3, Files: map[int]int{3: 150}, Reviews: map[int]int{3: 80}})
+	prs, err := fetchPRs(context.Background(), "acme", "widgets", 3, "MERGED", time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := prs[0]
+	if pr.Number != 3 {
+		t.Fatalf("first PR is #%d, want #3", pr.Number)
+	}
+	if len(pr.FilePaths) != 100 || pr.ChangedFiles != 150 {
+		t.Errorf("got %d file paths of %d changed files, want 100 of 150", len(pr.FilePaths), pr.ChangedFiles)
+	}
+	if len(pr.Reviews) != 50 {
+		t.Errorf("got %d reviews, want the 50 the query asks for", len(pr.Reviews))
+	}
+}
+
+func TestFetchNullFields(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 5, NullFields: map[int]string{2: "files", 4: "files"}})
+	prs, err := fetchPRs(context.Background(), "acme", "widgets", 5, "MERGED", time.Second, 0)
+	if err != nil {
+		t.Fatalf("field errors should leave the rest of the data usable: %v", err)
+	}
+	if len(prs) != 5 {
+		t.Fatalf("fetched %d PRs, want 5", len(prs))
+	}
+	for _, pr := range prs {
+		if nulled := pr.Number == 2 || pr.Number == 4; nulled != (len(pr.FilePaths) == 0) {
+			t.Errorf("#%d has %d file paths", pr.Number, len(pr.FilePaths))
+		}
+	}
+	var errs fetchErrors
+	fieldErrors.takeInto(&errs, "acme/widgets")
+	if len(errs) != 1 || errs[0].Stage != "field pullRequests.files" || errs[0].Nulls != 2 {
+		t.Errorf("field errors = %+v, want files null on 2 PRs", errs)
+	}
+}
+
+func TestFetchSelectsPlannedFields(t *testing.T) {
+	f := newFakeGitHub(t, fakeScenario{Merged: 2})
+	prs, err := fetchPRFields(context.Background(), "acme", "widgets", 2, "MERGED", 0, time.Second, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := f.requests()[0]; strings.Contains(q, "files(") || strings.Contains(q, "commits(") {
+		t.Errorf("query selects fields no section asked for:\n%s", q)
+	}
+	if len(prs[0].FilePaths) != 0 || len(prs[0].Reviews) != 1 {
+		t.Errorf("PR #%d has %d files and %d reviews, want 0 and 1", prs[0].Number, len(prs[0].FilePaths), len(prs[0].Reviews))
+	}
+}
+
+func TestFetchRateLimitQuota(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Remaining: 1234})
+	rl, err := fetchRateLimit(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rl.Limit != 5000 || rl.Remaining != 1234 || !rl.ResetAt.Equal(fakeNow.Add(time.Hour)) {
+		t.Errorf("rate limit = %+v", rl)
+	}
+}
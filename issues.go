@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// staleIssueAge is how long an open issue can go without activity before it
+// counts as stale. Issues move slower than PRs.
+const staleIssueAge = 30 * 24 * time.Hour
+
+// Issue is the triage view of one GitHub issue.
+type Issue struct {
+	Number          int
+	Title           string
+	Author          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ClosedAt        *time.Time
+	Labels          []string
+	FirstResponseAt *time.Time // First comment by someone other than the author
+	LabeledAt       *time.Time // First label added
+}
+
+const issueFields = `
+        number
+        title
+        createdAt
+        updatedAt
+        closedAt
+        author { login }
+        labels(first: 10) { nodes { name } }
+        comments(first: 20) { nodes { createdAt author { login } } }
+        timelineItems(first: 5, itemTypes: [LABELED_EVENT]) {
+          nodes { ... on LabeledEvent { createdAt } }
+        }`
+
+// fetchIssues fetches up to limit issues, open and closed, newest first.
+// On failure it returns the issues of earlier pages with the error.
+func fetchIssues(ctx context.Context, owner, name string, limit int, timeout, delay time.Duration) ([]Issue, error) {
+	var issues []Issue
+	var cursor string
+
+	queryTmpl := `
+query {
+  repository(owner: %q, name: %q) {
+    issues(%s) {
+      nodes {` + issueFields + `
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	for len(issues) < limit {
+		if cursor != "" {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return issues, pageError(len(issues), err)
+			}
+		}
+		args := fmt.Sprintf("first: %d, orderBy: {field: CREATED_AT, direction: DESC}", min(100, limit-len(issues)))
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, owner, name, args), timeout)
+		if err != nil {
+			return issues, pageError(len(issues), err)
+		}
+		var resp struct {
+			Data struct {
+				Repository struct {
+					Issues struct {
+						Nodes []struct {
+							Number    int        `json:"number"`
+							Title     string     `json:"title"`
+							CreatedAt time.Time  `json:"createdAt"`
+							UpdatedAt time.Time  `json:"updatedAt"`
+							ClosedAt  *time.Time `json:"closedAt"`
+							Author    struct {
+								Login string `json:"login"`
+							} `json:"author"`
+							Labels struct {
+								Nodes []struct {
+									Name string `json:"name"`
+								} `json:"nodes"`
+							} `json:"labels"`
+							Comments struct {
+								Nodes []struct {
+									CreatedAt time.Time `json:"createdAt"`
+									Author    struct {
+										Login string `json:"login"`
+									} `json:"author"`
+								} `json:"nodes"`
+							} `json:"comments"`
+							TimelineItems struct {
+								Nodes []struct {
+									CreatedAt time.Time `json:"createdAt"`
+								} `json:"nodes"`
+							} `json:"timelineItems"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"issues"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return issues, pageError(len(issues), err)
+		}
+
+		page := resp.Data.Repository.Issues
+		for _, n := range page.Nodes {
+			issue := Issue{
+				Number:    n.Number,
+				Title:     n.Title,
+				Author:    canonicalLogin(n.Author.Login),
+				CreatedAt: n.CreatedAt,
+				UpdatedAt: n.UpdatedAt,
+				ClosedAt:  n.ClosedAt,
+			}
+			for _, l := range n.Labels.Nodes {
+				issue.Labels = append(issue.Labels, l.Name)
+			}
+			for _, c := range n.Comments.Nodes {
+				if login := canonicalLogin(c.Author.Login); login != "" && login != issue.Author && !isBot(login) {
+					t := c.CreatedAt
+					issue.FirstResponseAt = &t
+					break
+				}
+			}
+			if len(n.TimelineItems.Nodes) > 0 {
+				t := n.TimelineItems.Nodes[0].CreatedAt
+				issue.LabeledAt = &t
+			}
+			issues = append(issues, issue)
+		}
+		slog.Debug("fetched page", "repo", owner+"/"+name, "issues", len(page.Nodes), "total", len(issues))
+
+		if !page.PageInfo.HasNextPage || len(page.Nodes) == 0 {
+			break
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+	return issues, nil
+}
+
+func printIssueTriage(issues []Issue) {
+	fmt.Println("📥 ISSUE TRIAGE")
+	printExplanation("Time to first response and first label on issues, stale open issues, and throughput per label.",
+		"The issue queue is a bottleneck too. Reporters who wait weeks for a first reply stop reporting, and unlabeled issues never reach the right people.")
+
+	if len(issues) == 0 {
+		fmt.Println("   No issues in this repository.")
+		return
+	}
+	now := clock()
+	var response, triage []time.Duration
+	var stale []Issue
+	open, unanswered, unlabeled := 0, 0, 0
+	for _, is := range issues {
+		if is.FirstResponseAt != nil {
+			response = append(response, is.FirstResponseAt.Sub(is.CreatedAt))
+		}
+		if is.LabeledAt != nil {
+			triage = append(triage, max(is.LabeledAt.Sub(is.CreatedAt), 0))
+		}
+		if is.ClosedAt != nil {
+			continue
+		}
+		open++
+		if is.FirstResponseAt == nil && !isBot(is.Author) {
+			unanswered++
+		}
+		if len(is.Labels) == 0 {
+			unlabeled++
+		}
+		if now.Sub(is.UpdatedAt) > staleIssueAge {
+			stale = append(stale, is)
+		}
+	}
+
+	fmt.Printf("   Issues fetched: %d (%d open) since %s\n\n", len(issues), open, formatDate(issues[len(issues)-1].CreatedAt))
+	medianOrDash := func(d []time.Duration, p float64) string {
+		if len(d) == 0 {
+			return "-"
+		}
+		return formatDuration(percentile(d, p))
+	}
+	fmt.Printf("   %-24s %-14s %-14s %s\n", "", "Median", "P90", "Still waiting (open)")
+	fmt.Printf("   %-24s %-14s %-14s %d\n", "Time to first response", medianOrDash(response, 50), medianOrDash(response, 90), unanswered)
+	fmt.Printf("   %-24s %-14s %-14s %d\n", "Time to first label", medianOrDash(triage, 50), medianOrDash(triage, 90), unlabeled)
+
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+	fmt.Printf("\n   Stale open issues (no activity for %s): %d\n", formatDuration(staleIssueAge), len(stale))
+	for i, is := range stale {
+		if i == 5 {
+			fmt.Printf("   ... and %d more\n", len(stale)-5)
+			break
+		}
+		fmt.Printf("   - #%-6d %-45s idle %s\n", is.Number, limitString(is.Title, 45), formatDuration(now.Sub(is.UpdatedAt)))
+	}
+
+	type labelStat struct {
+		Name           string
+		Opened, Closed int
+		ToClose        []time.Duration
+	}
+	labels := make(map[string]*labelStat)
+	for _, is := range issues {
+		for _, l := range is.Labels {
+			key := strings.ToLower(l)
+			if labels[key] == nil {
+				labels[key] = &labelStat{Name: l}
+			}
+			s := labels[key]
+			s.Opened++
+			if is.ClosedAt != nil {
+				s.Closed++
+				s.ToClose = append(s.ToClose, is.ClosedAt.Sub(is.CreatedAt))
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return
+	}
+	var list []*labelStat
+	for _, s := range labels {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Opened != list[j].Opened {
+			return list[i].Opened > list[j].Opened
+		}
+		return list[i].Name < list[j].Name
+	})
+	fmt.Printf("\n   %-22s %7s %7s %7s   %s\n", "Label", "Opened", "Closed", "Open", "Median to close")
+	for i, s := range list {
+		if i == 10 {
+			break
+		}
+		fmt.Printf("   %-22s %7d %7d %7d   %s\n", limitString(s.Name, 22), s.Opened, s.Closed, s.Opened-s.Closed, medianOrDash(s.ToClose, 50))
+	}
+	fmt.Println("   (Labels are counted on the fetched issues; a growing Open column means that queue isn't keeping up.)")
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// reportSchemaVersion is the version of the --format json contract, in every
+// payload as schema_version. The minor version goes up when fields are
+// added; the major version when a field is removed, renamed or changes
+// meaning. Bump it together with report.schema.json.
+const reportSchemaVersion = "1.1.0"
+
+// reportSchema is the JSON Schema of the --format json output, printed by
+// --schema.
+//
+//go:embed report.schema.json
+var reportSchema []byte
+
+// jsonFields are the optional PR fields the JSON report reads: those of
+// its health score.
+const jsonFields = healthFields
+
+// JSONReport is the --format json output of a run.
+type JSONReport struct {
+	SchemaVersion string       `json:"schema_version"`
+	Generated     time.Time    `json:"generated"`
+	Repositories  []RepoReport `json:"repositories"`
+	Errors        []JSONError  `json:"errors"`
+}
+
+// RepoReport is the headline state of one repository. Durations are in
+// hours.
+type RepoReport struct {
+	Repo      string             `json:"repo"`
+	Partial   bool               `json:"partial"` // Some data couldn't be fetched
+	Health    int                `json:"health"`
+	Scores    map[string]float64 `json:"scores"`
+	Archetype string             `json:"archetype"`
+	Merged    JSONMetrics        `json:"merged"`
+	Open      JSONOpen           `json:"open"`
+	Months    []JSONMonth        `json:"months"`
+	Areas     []JSONArea         `json:"areas"`
+}
+
+// JSONMetrics are the metrics of the merged PRs analyzed.
+type JSONMetrics struct {
+	Count                  int     `json:"count"`
+	MedianCycleHours       float64 `json:"median_cycle_hours"`
+	P90CycleHours          float64 `json:"p90_cycle_hours"`
+	MedianFirstReviewHours float64 `json:"median_first_review_hours"`
+	ReviewedPct            float64 `json:"reviewed_pct"`
+	AvgRounds              float64 `json:"avg_rounds"`
+	MedianSize             int     `json:"median_size"`
+	TopReviewer            string  `json:"top_reviewer,omitempty"` // A team under --privacy team, left out under aggregate
+	TopReviewerPct         float64 `json:"top_reviewer_pct"`
+}
+
+// JSONOpen is the open PR backlog.
+type JSONOpen struct {
+	Count       int       `json:"count"`
+	Stale       int       `json:"stale"`
+	StaleExempt int       `json:"stale_exempt"` // Inactive but exempt by stale.exempt
+	StalePRs    []StalePR `json:"stale_prs"`
+}
+
+// JSONMonth is one calendar month of merged PRs.
+type JSONMonth struct {
+	Month         string  `json:"month"` // 2006-01
+	PRs           int     `json:"prs"`
+	AvgCycleHours float64 `json:"avg_cycle_hours"`
+	Partial       string  `json:"partial,omitempty"` // Why the month is incomplete
+}
+
+// JSONArea is the merge speed of the PRs touching one directory or service.
+type JSONArea struct {
+	Area                   string  `json:"area"`
+	PRs                    int     `json:"prs"`
+	MedianCycleHours       float64 `json:"median_cycle_hours"`
+	MedianFirstReviewHours float64 `json:"median_first_review_hours"`
+}
+
+// JSONError is a fetch that failed during the run.
+type JSONError struct {
+	Repo    string `json:"repo"`
+	Stage   string `json:"stage"`
+	Fetched int    `json:"fetched"`
+	Error   string `json:"error"`
+}
+
+// jsonRepoReport is analyzeRepo for --format json: it fetches the PRs and
+// builds the repository's headline numbers instead of printing sections. It
+// reports false when no PR could be fetched.
+func jsonRepoReport(ctx context.Context, repo string, o reportOptions, errs *fetchErrors) (RepoReport, bool) {
+	merged, open, sample := fetchRepoPRs(ctx, repo, o, jsonFields|configFields(), errs)
+	return buildRepoReport(ctx, repo, o, merged, open, sample == nil && len(merged) >= o.Limit, errs)
+}
+
+// buildRepoReport computes the JSON report of fetched PRs. truncated
+// reports the merged PRs stopped at a fetch limit, so their oldest month is
+// incomplete.
+func buildRepoReport(ctx context.Context, repo string, o reportOptions, merged, open []PullRequest, truncated bool, errs *fetchErrors) (RepoReport, bool) {
+	owner, name, _ := parseRepo(repo)
+	if len(merged) == 0 && len(open) == 0 {
+		return RepoReport{}, false
+	}
+
+	generated, err := loadGeneratedMatcher(ctx, owner, name, o.Timeout)
+	if err != nil {
+		slog.Warn("could not fetch .gitattributes", "repo", repo, "err", err)
+	}
+	markGenerated(merged, generated, !o.IncludeGenerated)
+	markGenerated(open, generated, !o.IncludeGenerated)
+	if o.ExcludeDocs {
+		merged, _ = splitDocs(merged)
+	}
+
+	snap := healthSnapshot(repo, merged, open, clock())
+	if o.Snapshot {
+		if err := o.Store.Save(snap); err != nil {
+			slog.Error("saving snapshot", "repo", repo, "err", err)
+		} else {
+			slog.Info("saved snapshot", "repo", repo, "health", snap.Health)
+		}
+	}
+	if o.ExcludeOutliers {
+		merged = filterOutliers(merged)
+	}
+
+	m := computeMetrics(merged)
+	rep := RepoReport{
+		Repo:      repo,
+		Partial:   errs.partial(repo),
+		Health:    snap.Health,
+		Scores:    snap.Scores,
+		Archetype: snap.Archetype,
+		Merged: JSONMetrics{
+			Count:                  m.Count,
+			MedianCycleHours:       m.MedianCycleTime.Hours(),
+			P90CycleHours:          m.P90CycleTime.Hours(),
+			MedianFirstReviewHours: m.MedianFirstReview.Hours(),
+			ReviewedPct:            m.ReviewedPct,
+			AvgRounds:              m.AvgRounds,
+			MedianSize:             m.MedianSize,
+			TopReviewerPct:         m.TopReviewerPct,
+		},
+		Open:   JSONOpen{Count: snap.Open, Stale: snap.Stale, StaleExempt: snap.StaleExempt, StalePRs: snap.StalePRs},
+		Months: []JSONMonth{},
+		Areas:  []JSONArea{},
+	}
+	if rep.Open.StalePRs == nil {
+		rep.Open.StalePRs = []StalePR{}
+	}
+	if m.TopReviewer != "" && privacy != privacyAggregate {
+		rep.Merged.TopReviewer = person(m.TopReviewer)
+	}
+
+	stored, err := o.Store.LoadMonths(repo)
+	if err != nil {
+		slog.Warn("could not read backfilled months", "repo", repo, "err", err)
+	}
+	months := monthlyStats(merged, stored, clock(), truncated)
+	if o.ExcludePartial {
+		months = completeMonths(months)
+	}
+	for _, mo := range months {
+		rep.Months = append(rep.Months, JSONMonth{Month: mo.Month, PRs: mo.Count, AvgCycleHours: mo.Avg().Hours(), Partial: mo.Partial})
+	}
+	for area, a := range snap.Areas {
+		rep.Areas = append(rep.Areas, JSONArea{Area: area, PRs: a.PRs, MedianCycleHours: a.MedianCycleTime.Hours(), MedianFirstReviewHours: a.MedianFirstReview.Hours()})
+	}
+	sort.Slice(rep.Areas, func(i, j int) bool {
+		if rep.Areas[i].PRs != rep.Areas[j].PRs {
+			return rep.Areas[i].PRs > rep.Areas[j].PRs
+		}
+		return rep.Areas[i].Area < rep.Areas[j].Area
+	})
+	return rep, true
+}
+
+// newJSONReport is the JSON report of a run's repositories and fetch errors.
+func newJSONReport(repos []RepoReport, errs fetchErrors) JSONReport {
+	out := JSONReport{
+		SchemaVersion: reportSchemaVersion,
+		Generated:     clock().UTC(),
+		Repositories:  repos,
+		Errors:        []JSONError{},
+	}
+	if out.Repositories == nil {
+		out.Repositories = []RepoReport{}
+	}
+	for _, e := range errs {
+		out.Errors = append(out.Errors, JSONError{Repo: e.Repo, Stage: e.Stage, Fetched: e.Fetched, Error: e.Err.Error()})
+	}
+	return out
+}
+
+// writeJSONReport writes the JSON report of a run as one document.
+func writeJSONReport(w io.Writer, repos []RepoReport, errs fetchErrors) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newJSONReport(repos, errs))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// requestedAt returns when reviewer was last asked to review pr before t.
+// Reviews nobody asked for count from PR creation.
+func requestedAt(pr PullRequest, reviewer string, t time.Time) time.Time {
+	at := pr.CreatedAt
+	for _, r := range pr.ReviewRequests {
+		if r.Reviewer == reviewer && !r.At.After(t) && r.At.After(at) {
+			at = r.At
+		}
+	}
+	return at
+}
+
+// responseTimes collects, per reviewer, the wait from being requested to
+// their first review of each PR, less the time they were away (see
+// Availability.responseWait).
+func responseTimes(prs []PullRequest, avail *Availability) map[string][]time.Duration {
+	out := make(map[string][]time.Duration)
+	for _, pr := range prs {
+		seen := make(map[string]bool)
+		for _, r := range pr.Reviews {
+			if r.Author == "" || r.Author == pr.Author || isBot(r.Author) || seen[r.Author] {
+				continue
+			}
+			seen[r.Author] = true
+			if d, ok := avail.responseWait(r.Author, requestedAt(pr, r.Author, r.CreatedAt), r.CreatedAt); ok {
+				out[r.Author] = append(out[r.Author], d)
+			}
+		}
+	}
+	return out
+}
+
+func printReviewerLeaderboard(prs []PullRequest, avail *Availability, sla time.Duration, anonymize bool) {
+	fmt.Println("🏅 REVIEWER RESPONSE LEADERBOARD")
+	printExplanation("Reviewers ranked by median time from review request to their first review, and share within the SLA.",
+		"A little friendly recognition for fast unblockers. It is NOT a performance metric: timezones,",
+		"on-call weeks and deep-work days all shape these numbers. Celebrate, don't punish.",
+	)
+
+	type entry struct {
+		Name   string
+		Count  int
+		Median time.Duration
+		HitPct float64
+	}
+	var entries []entry
+	for name, times := range responseTimes(prs, avail) {
+		if len(times) < 3 {
+			continue
+		}
+		hits := 0
+		for _, d := range times {
+			if d <= sla {
+				hits++
+			}
+		}
+		entries = append(entries, entry{
+			Name:   name,
+			Count:  len(times),
+			Median: percentile(times, 50),
+			HitPct: float64(hits) / float64(len(times)) * 100,
+		})
+	}
+	if len(entries) == 0 {
+		fmt.Println("   Not enough reviews yet (each reviewer needs at least 3).")
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Median != entries[j].Median {
+			return entries[i].Median < entries[j].Median
+		}
+		if entries[i].HitPct != entries[j].HitPct {
+			return entries[i].HitPct > entries[j].HitPct
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	fmt.Printf("   SLA: first response within %s\n\n", formatDuration(sla))
+	fmt.Printf("   %-4s %-20s %7s   %-15s %s\n", "Rank", "Reviewer", "Reviews", "Median Response", "Within SLA")
+	for i, e := range entries {
+		name := e.Name
+		if anonymize {
+			name = fmt.Sprintf("reviewer-%d", i+1)
+		}
+		medal := ""
+		switch i {
+		case 0:
+			medal = " 🥇"
+		case 1:
+			medal = " 🥈"
+		case 2:
+			medal = " 🥉"
+		}
+		fmt.Printf("   %-4d %-20s %7d   %-15s %5.0f%%%s\n", i+1, limitString(name, 17), e.Count, formatDuration(e.Median), e.HitPct, medal)
+	}
+	fmt.Println("\n   (Reviews without an explicit request count from PR creation. Reviewers with fewer than 3 reviews are omitted.")
+	fmt.Println("   Time reviewers were away, by availability in config, isn't counted.)")
+}
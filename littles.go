@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// littleCheck holds the three Little's Law quantities measured over one
+// window: L = λ × W.
+type littleCheck struct {
+	From, To   time.Time
+	WIP        float64       // Measured average open PRs
+	Throughput float64       // PRs merged per day
+	Cycle      time.Duration // Mean merge time of the PRs merged in the window
+	Open       int           // Open PRs fetched (capped at 100)
+}
+
+// Predicted is the average WIP Little's Law expects from throughput and
+// cycle time.
+func (c littleCheck) Predicted() float64 { return c.Throughput * c.Cycle.Hours() / 24 }
+
+// littlesLaw measures WIP, throughput and cycle time over the complete weeks
+// after the oldest fetched merge, the window queueWeeks uses. WIP is sampled
+// every six hours. ok is false when the window is shorter than four weeks.
+func littlesLaw(merged, open []PullRequest, now time.Time) (littleCheck, bool) {
+	weeks := queueWeeks(merged, open, now)
+	if len(weeks) < 4 {
+		return littleCheck{}, false
+	}
+	c := littleCheck{From: weeks[0].Start, To: weeks[len(weeks)-1].Start.AddDate(0, 0, 7), Open: len(open)}
+
+	var total time.Duration
+	count := 0
+	for _, pr := range merged {
+		if !pr.MergedAt.Before(c.From) && pr.MergedAt.Before(c.To) {
+			total += pr.MergedAt.Sub(pr.CreatedAt)
+			count++
+		}
+	}
+	days := c.To.Sub(c.From).Hours() / 24
+	c.Throughput = float64(count) / days
+	if count > 0 {
+		c.Cycle = total / time.Duration(count)
+	}
+
+	all := append(append([]PullRequest(nil), merged...), open...)
+	samples, sum := 0, 0
+	for t := c.From; t.Before(c.To); t = t.Add(6 * time.Hour) {
+		for _, pr := range all {
+			if !pr.CreatedAt.After(t) && (pr.MergedAt.IsZero() || pr.MergedAt.After(t)) {
+				sum++
+			}
+		}
+		samples++
+	}
+	c.WIP = float64(sum) / float64(samples)
+	return c, true
+}
+
+func printLittlesLaw(merged, open []PullRequest) {
+	fmt.Println("⚖️  LITTLE'S LAW CHECK")
+	printExplanation("Average open PRs should equal throughput × average merge time (L = λ × W). This cross-checks the three measured numbers against each other.",
+		"When they disagree, the data is usually incomplete (long-lived PRs outside the fetched window, closed PRs, more than 100 open PRs) or the queue isn't steady. Knowing which builds trust in the rest of the report.")
+
+	c, ok := littlesLaw(merged, open, clock())
+	if !ok {
+		fmt.Println("   Not enough complete weeks in this dataset (need 4).")
+		return
+	}
+	if c.Throughput == 0 {
+		fmt.Println("   No PRs merged in the window.")
+		return
+	}
+	predicted := c.Predicted()
+	fmt.Printf("   Window: %s - %s\n\n", formatDate(c.From), formatDate(c.To.Add(-time.Nanosecond)))
+	fmt.Printf("   Throughput (λ):          %s PRs/day\n", formatNumber(c.Throughput, 2))
+	fmt.Printf("   Mean merge time (W):     %s\n", formatDuration(c.Cycle))
+	fmt.Printf("   Expected open PRs (λW):  %s\n", formatNumber(predicted, 1))
+	fmt.Printf("   Measured open PRs (L):   %s\n", formatNumber(c.WIP, 1))
+
+	gap := (c.WIP - predicted) / predicted * 100
+	fmt.Println()
+	switch {
+	case math.Abs(gap) <= 25:
+		fmt.Printf("   ✅ Consistent within %.0f%%. Throughput, merge time and WIP tell the same story.\n", math.Abs(gap))
+	case gap < 0:
+		fmt.Printf("   ⚠️  Measured WIP is %.0f%% below what throughput and merge time imply. Likely data gaps:\n", -gap)
+		if c.Open >= 100 {
+			fmt.Println("      - Only 100 open PRs are fetched, and this repository has at least that many.")
+		}
+		fmt.Println("      - PRs that were open in the window but merged before the oldest fetched merge are missing from WIP.")
+		fmt.Println("      - A burst of old PRs merging at once inflates the mean merge time. Check the slowest PRs.")
+	default:
+		fmt.Printf("   ⚠️  Measured WIP is %.0f%% above what throughput and merge time imply. Likely causes:\n", gap)
+		fmt.Println("      - Long-lived open PRs that never merge add to WIP but not to merge time (survivorship bias).")
+		fmt.Println("      - The queue is growing: arrivals outpace merges, so today's WIP is ahead of past throughput.")
+	}
+	fmt.Println("   (Closed-without-merge PRs aren't fetched, so they count in neither WIP nor throughput.)")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// addLogFlags registers --log-level and --log-format on a command's flags.
+func addLogFlags(fs *flag.FlagSet) (level, format *string) {
+	level = fs.String("log-level", "info", "Log level: debug, info, warn or error")
+	format = fs.String("log-format", "text", "Log format: text or json")
+	return level, format
+}
+
+// setupLogging sends structured logs to stderr, keeping stdout for the report.
+func setupLogging(level, format string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid --log-level %q (use debug, info, warn or error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: l}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid --log-format %q (use text or json)", format)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
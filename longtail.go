@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// authorTail is one author's share of the slowest merges.
+type authorTail struct {
+	Name       string
+	PRs        int
+	Slow       int
+	Expected   float64 // Slow PRs expected from the sizes of their PRs
+	MedianSize int
+}
+
+// Rate is the share of the author's PRs among the slowest merges.
+func (a authorTail) Rate() float64 { return float64(a.Slow) / float64(a.PRs) }
+
+// VsSize is how many times more slow PRs the author has than their PR
+// sizes predict, or 0 when none are predicted.
+func (a authorTail) VsSize() float64 {
+	if a.Expected == 0 {
+		return 0
+	}
+	return float64(a.Slow) / a.Expected
+}
+
+// longTailAuthors rates authors with at least minPRs merged PRs by how many
+// of them landed in the slowest 10% of merges. The expectation from PR size
+// comes from each size quartile's own share of slow PRs, so an author of
+// big PRs isn't flagged for being slow in proportion. It also returns the
+// number of authors below minPRs and the slow cutoff.
+func longTailAuthors(prs []PullRequest, minPRs int) ([]authorTail, int, time.Duration) {
+	cycle := func(pr PullRequest) time.Duration { return pr.MergedAt.Sub(pr.CreatedAt) }
+	sorted := append([]PullRequest(nil), prs...)
+	sort.Slice(sorted, func(i, j int) bool { return cycle(sorted[i]) > cycle(sorted[j]) })
+	limit := max(len(prs)/10, 1)
+	cutoff := cycle(sorted[limit-1])
+	slow := make(map[int]bool)
+	for _, pr := range sorted[:limit] {
+		slow[pr.Number] = true
+	}
+
+	var sizes []float64
+	for _, pr := range prs {
+		sizes = append(sizes, float64(pr.Size))
+	}
+	sort.Float64s(sizes)
+	q1, q2, q3 := quantile(sizes, 25), quantile(sizes, 50), quantile(sizes, 75)
+	band := func(pr PullRequest) int {
+		s := float64(pr.Size)
+		switch {
+		case s <= q1:
+			return 0
+		case s <= q2:
+			return 1
+		case s <= q3:
+			return 2
+		}
+		return 3
+	}
+	var bandPRs, bandSlow [4]int
+	for _, pr := range prs {
+		bandPRs[band(pr)]++
+		if slow[pr.Number] {
+			bandSlow[band(pr)]++
+		}
+	}
+
+	type acc struct {
+		authorTail
+		sizes []float64
+	}
+	byAuthor := make(map[string]*acc)
+	for _, pr := range prs {
+		if pr.Author == "" || isBot(pr.Author) {
+			continue
+		}
+		name := pr.Author
+		if privacy == privacyTeam {
+			name = person(pr.Author)
+		}
+		a := byAuthor[name]
+		if a == nil {
+			a = &acc{authorTail: authorTail{Name: name}}
+			byAuthor[name] = a
+		}
+		a.PRs++
+		a.sizes = append(a.sizes, float64(pr.Size))
+		b := band(pr)
+		a.Expected += float64(bandSlow[b]) / float64(bandPRs[b])
+		if slow[pr.Number] {
+			a.Slow++
+		}
+	}
+
+	var out []authorTail
+	skipped := 0
+	for _, a := range byAuthor {
+		if a.PRs < minPRs {
+			skipped++
+			continue
+		}
+		sort.Float64s(a.sizes)
+		a.MedianSize = int(quantile(a.sizes, 50))
+		out = append(out, a.authorTail)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rate() != out[j].Rate() {
+			return out[i].Rate() > out[j].Rate()
+		}
+		if out[i].VsSize() != out[j].VsSize() {
+			return out[i].VsSize() > out[j].VsSize()
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, skipped, cutoff
+}
+
+func printLongTailAuthors(prs []PullRequest, minPRs int) {
+	fmt.Println("🐌 LONG TAIL CONTRIBUTORS (Handling the Slowest 10%)")
+	printExplanation("The share of each author's PRs that landed in the slowest 10% of merges, next to what the size of their PRs predicts.",
+		"A rate, not a count: prolific authors aren't flagged for writing more PRs, and big-PR authors aren't flagged for PRs that are slow because they're big.",
+		"These devs might be tackling the hardest problems, or they need help breaking down tasks. Prevents burnout.")
+
+	tails, skipped, cutoff := longTailAuthors(prs, minPRs)
+	fmt.Printf("   Slow: merged in %s or more (the slowest 10%%). Authors with fewer than %d PRs left out: %d\n\n", formatDuration(cutoff), minPRs, skipped)
+	var flagged []authorTail
+	for _, t := range tails {
+		if t.Slow > 0 {
+			flagged = append(flagged, t)
+		}
+	}
+	if len(tails) == 0 {
+		fmt.Println("   No author has enough merged PRs to rate.")
+		return
+	}
+	if len(flagged) == 0 {
+		fmt.Println("   ✅ None of the rated authors' PRs are among the slowest.")
+		return
+	}
+
+	if privacy == privacyAggregate {
+		over := 0
+		for _, t := range flagged {
+			if t.VsSize() >= 2 {
+				over++
+			}
+		}
+		rates := make([]float64, 0, len(tails))
+		for _, t := range tails {
+			rates = append(rates, t.Rate()*100)
+		}
+		fmt.Printf("   Authors rated: %d   With slow PRs: %d   At 2x or more of what their PR sizes predict: %d\n", len(tails), len(flagged), over)
+		sort.Float64s(rates)
+		fmt.Printf("   Slow rate per author: median %.0f%%, P90 %.0f%%\n", quantile(rates, 50), quantile(rates, 90))
+		fmt.Println("   (Note: Authors with high rates might be tackling the hardest complexity, not working slowly.)")
+		return
+	}
+
+	fmt.Printf("   %-18s %5s %5s %6s   %-11s %s\n", "Author", "PRs", "Slow", "Rate", "Median size", "vs size")
+	for i, t := range flagged {
+		if i == 5 {
+			break
+		}
+		vs := "-"
+		if t.Expected > 0 {
+			vs = formatNumber(t.VsSize(), 1) + "x"
+		}
+		fmt.Printf("   %-18s %5d %5d %5.0f%%   %-11s %s\n", limitString(t.Name, 15), t.PRs, t.Slow, t.Rate()*100, formatInt(t.MedianSize), vs)
+	}
+	fmt.Println("\n   (vs size: slow PRs relative to what the author's PR sizes predict; 1.0x is as expected.)")
+	fmt.Println("   (Note: These authors might be tackling the hardest complexity, not working slowly.)")
+}
@@ -5,9 +5,10 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"math"
+	"log/slog"
 	"os"
 	"os/exec"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -34,170 +35,792 @@ type GRPCPullRequest struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 	MergedAt  time.Time `json:"mergedAt"`
 	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	BaseRef   string    `json:"baseRefName"`
+	HeadRef   string    `json:"headRefName"`
+	IsDraft   bool      `json:"isDraft"`
 	Additions int       `json:"additions"`
 	Deletions int       `json:"deletions"`
+	Changed   int       `json:"changedFiles"`
 	Author    struct {
 		Login string `json:"login"`
 	}
+	MergedBy struct {
+		Login string `json:"login"`
+	} `json:"mergedBy"`
+	HeadRepo *struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"headRepository"` // Null once a fork is deleted
 	Reviews struct {
 		Nodes []struct {
 			CreatedAt time.Time `json:"createdAt"`
+			State     string    `json:"state"`
 			Author    struct {
 				Login string `json:"login"`
 			} `json:"author"`
 		}
 	}
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				CommittedDate   time.Time `json:"committedDate"`
+				MessageHeadline string    `json:"messageHeadline"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
 	ReviewRequests struct {
 		Nodes []struct {
 			RequestedReviewer struct {
-				Login string `json:"login"`
+				Login        string `json:"login"`
+				CombinedSlug string `json:"combinedSlug"` // Teams, as org/team
 			} `json:"requestedReviewer"`
 		}
 	} `json:"reviewRequests"`
+	TimelineItems struct {
+		Nodes []struct {
+			Type              string    `json:"__typename"`
+			CreatedAt         time.Time `json:"createdAt"`
+			RequestedReviewer struct {
+				Login string `json:"login"`
+			} `json:"requestedReviewer"`
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"` // IssueComment
+		} `json:"nodes"`
+	} `json:"timelineItems"`
 	Files struct {
 		Nodes []struct {
-			Path string `json:"path"`
+			Path      string `json:"path"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
 		} `json:"nodes"`
 	} `json:"files"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	ClosingIssuesReferences struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"closingIssuesReferences"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	AssignedEvents struct {
+		Nodes []struct {
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"nodes"`
+	} `json:"assignedEvents"` // The first only
+	ProjectEvents struct {
+		Nodes []struct {
+			Type           string    `json:"__typename"`
+			CreatedAt      time.Time `json:"createdAt"`
+			PreviousStatus string    `json:"previousStatus"`
+			Status         string    `json:"status"`
+			Project        struct {
+				Title string `json:"title"`
+			} `json:"project"`
+		} `json:"nodes"`
+	} `json:"projectEvents"`
+	MergeCommit *struct {
+		MessageHeadline string `json:"messageHeadline"`
+		Parents         struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"parents"`
+	} `json:"mergeCommit"`
 }
 
 type PullRequest struct {
-	Number        int
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	MergedAt      time.Time
-	FirstReviewAt *time.Time
-	Author        string
-	Title         string
-	Size          int
-	FilePaths     []string
-	Reviewers     []string // Who actually reviewed
-	Requested     []string // Who is requested (for open PRs)
+	Number         int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	MergedAt       time.Time
+	FirstReviewAt  *time.Time
+	Author         string
+	MergedBy       string
+	AutoMergeAt    *time.Time // When auto-merge was (last) enabled, if still on at merge
+	Title          string
+	Body           string
+	BaseRef        string
+	HeadRef        string
+	HeadRepo       string // owner/name of the head branch's repository; "" when deleted
+	IsDraft        bool
+	Size           int
+	FilePaths      []string       // Up to the first 100 changed files
+	ChangedFiles   int            // Total changed files, including any beyond FilePaths
+	FileLines      map[string]int // Lines changed per path
+	GeneratedSize  int            // Lines in generated/vendored files (excluded from Size)
+	Labels         []string
+	LinkedIssues   int      // Issues this PR closes
+	Reviewers      []string // Who actually reviewed
+	Requested      []string // Who is requested (for open PRs); teams as org/team
+	Reviews        []Review
+	ReviewRequests []ReviewRequest // Request history (users only)
+	Comments       []Comment       // Conversation comments, not review comments
+	CommitTimes    []time.Time
+	BaseMerges     []time.Time   // Commits merging a branch in, usually the base to resolve conflicts
+	MergeMethod    string        // merge, squash or rebase, inferred from the merge commit; "" when unknown
+	Assignees      []string      // Current assignees
+	AssignedAt     *time.Time    // First assignment
+	ProjectMoves   []ProjectMove // Projects (v2) board history
+}
+
+// ProjectMove is a pull request's addition to a Projects (v2) board (From
+// and To empty) or a move between two of its status columns.
+type ProjectMove struct {
+	Project  string
+	From, To string
+	At       time.Time
+}
+
+type ReviewRequest struct {
+	Reviewer string
+	At       time.Time
+}
+
+type Comment struct {
+	Author string
+	At     time.Time
+}
+
+type Review struct {
+	Author    string
+	State     string // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	CreatedAt time.Time
 }
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "rotation":
+			runRotation(os.Args[2:])
+			return
+		case "daemon":
+			runDaemon(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "summary":
+			runSummary(os.Args[2:])
+			return
+		case "benchmark":
+			runBenchmark(os.Args[2:])
+			return
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "backfill":
+			runBackfill(os.Args[2:])
+			return
+		case "simulate":
+			runSimulate(os.Args[2:])
+			return
+		case "pr":
+			runPR(os.Args[2:])
+			return
+		case "me":
+			runMe(os.Args[2:])
+			return
+		case "annotate":
+			runAnnotate(os.Args[2:])
+			return
+		case "digest":
+			runDigest(os.Args[2:])
+			return
+		}
+	}
+
 	// 1. Parse Flags
 	excludeOutliers := flag.Bool("exclude-outliers", false, "Exclude top and bottom 5% of outliers")
 	limit := flag.Int("limit", 100, "Max number of PRs to fetch (max 100 for GraphQL)")
 	reqTimeout := flag.Duration("timeout", 30*time.Second, "Timeout for each API request")
 	reqDelay := flag.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := flag.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	preset := flag.String("preset", "", "Apply a named preset of sections and flag defaults: maintainer, manager, team-retro or one from presets: in config")
+	view := flag.String("view", "", "Report variant for an audience: ic (your queue and ghosts), lead (team health, ghosts, heroes) or exec (trends, score, forecast)")
+	sample := flag.Int("sample", 0, "Analyze a sample of this many merged PRs, stratified by month, instead of the latest --limit")
+	sampleMonths := flag.Int("sample-months", 12, "Months to draw the --sample from")
+	cohorts := flag.String("cohorts", "", "Compare cohorts of merged PRs: quarter or release")
+	tagPattern := flag.String("tag-pattern", "", "Regexp selecting release tags for --cohorts release and release cadence; a capture group merges tags into one cohort, e.g. ^v(\\d+)\\.")
+	dryRun := flag.Bool("dry-run", false, "Estimate the GraphQL rate-limit cost of the run and exit without fetching PRs")
+	plan := flag.Bool("plan", false, "Print the queries, pages, estimated duration and cost, and the analyses of the run, and exit without fetching PRs")
+	org := flag.String("org", "", "Analyze every non-archived, non-fork repository of this organization")
+	assignReviewers := flag.Bool("assign-reviewers", false, "Request suggested reviewers on open PRs that have none")
+	aiInsights := flag.Bool("ai-insights", false, "Append an LLM-written diagnosis of the metrics (requires ai: in config)")
+	failOnSLA := flag.Bool("fail-on-sla", false, "Exit with status 2 if any open PR violates its SLA policy")
+	leaderboard := flag.Bool("leaderboard", false, "Rank reviewers by response time to review requests (opt-in)")
+	responseSLA := flag.Duration("response-sla", 24*time.Hour, "First-response target used by --leaderboard and the review request burn-down")
+	ghostDigest := flag.Bool("ghost-digest", false, "Preview a private digest message for each ghost reviewer")
+	notifyGhosts := flag.Bool("notify-ghosts", false, "Send ghost digests via Slack DM or GitHub mention (see notify: in config)")
+	burnout := flag.Bool("burnout", false, "Add a maintainer burnout risk indicator per reviewer (opt-in; per team or counts only under --privacy)")
+	anonymize := flag.Bool("anonymize", false, "Replace reviewer names in the leaderboard with rank-based aliases")
+	privacyMode := flag.String("privacy", "", "Hide individuals: team (people appear as their team from teams: in config) or aggregate (counts and distributions only, nobody named). Default: privacy: in config, else off")
+	summaryOnly := flag.Bool("summary", false, "Print only a compact scorecard of key numbers with trend arrows")
+	explain := flag.Bool("explain", true, "Print the Concept/Why paragraphs (off by default with --summary)")
+	locale := flag.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := flag.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	excludeDocs := flag.Bool("exclude-docs", false, "Leave docs-only PRs out of every section but the docs-only comparison")
+	excludePartial := flag.Bool("exclude-partial-months", false, "Leave the current month and a month cut off by --limit out of trends and the forecast")
+	snapshot := flag.Bool("snapshot", false, "Save this run's health score and key metrics to the local store for trending")
+	storeDir := flag.String("store", defaultStoreDir, "Directory of the local snapshot store (health snapshots and backfilled months)")
+	ci := flag.Bool("ci", false, "Also analyze CI check runs of merged PRs: flaky checks and queue vs execution time (one extra query per 10 PRs)")
+	issues := flag.Bool("issues", false, "Also analyze issue triage: first response, stale issues and per-label throughput (fetches up to --limit issues)")
+	staleBranches := flag.Bool("stale-branches", false, "Also list branches with commits but no open PR, untouched for --stale-branch-days (fetches up to --limit branches)")
+	staleBranchDays := flag.Int("stale-branch-days", 30, "Days without a commit after which --stale-branches counts a branch as stale")
+	slowest := flag.Int("slowest", 5, "Number of slowest merged PRs to break down in the case-study section")
+	longTailMin := flag.Int("long-tail-min-prs", 5, "Merged PRs an author needs to be rated in the long-tail section")
+	chartsDir := flag.String("charts-dir", "", "Also write the monthly trend, merge time histogram and reviewer load of each repository as SVG charts to this directory (SVG only, no PNG)")
+	includeGenerated := flag.Bool("include-generated", false, "Count generated, vendored and lock files in PR size")
+	skipPreflight := flag.Bool("skip-preflight", false, "Don't check the token's access to what the run needs before fetching")
+	format := flag.String("format", "text", "Output format: text (the report) or json (headline numbers per repository; see --schema)")
+	schema := flag.Bool("schema", false, "Print the JSON Schema of --format json and exit")
+	lowMemory := flag.Bool("low-memory", false, "Stream merged PRs into approximate sketches instead of keeping them, for scans of tens of thousands of PRs; prints a compact summary instead of the sections")
+	logLevel, logFormat := addLogFlags(flag.CommandLine)
 	flag.Parse()
 
-	args := flag.Args()
-	if len(args) < 1 {
-		fmt.Println("Usage: go run main.go [flags] <owner/repo>")
-		flag.PrintDefaults()
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	repo := args[0]
-	parts := strings.Split(repo, "/")
-	if len(parts) != 2 {
-		fmt.Println("Error: Repo must be in format owner/repo")
+	if *schema {
+		os.Stdout.Write(reportSchema)
+		return
+	}
+
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if *preset != "" && *view != "" {
+		slog.Error("--preset and --view both pick the sections; use one")
 		os.Exit(1)
 	}
-	owner, name := parts[0], parts[1]
+	if *preset != "" {
+		if err := applyPreset(flag.CommandLine, *preset); err != nil {
+			slog.Error("applying preset", "err", err)
+			os.Exit(1)
+		}
+	}
+	if *view != "" {
+		if err := applyView(flag.CommandLine, *view); err != nil {
+			slog.Error("applying view", "err", err)
+			os.Exit(1)
+		}
+	}
 
-	// 2. Fetch Data (Merged PRs for Stats)
-	fmt.Printf("🔍 Fetching merged PRs for %s (limit %d)...\n", repo, *limit)
-	mergedPRs, err := fetchPRs(owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
-	if err != nil {
-		fmt.Printf("Error fetching Merged PRs: %v\n", err)
+	if *privacyMode != "" {
+		if err := setPrivacy(*privacyMode); err != nil {
+			slog.Error("invalid --privacy", "err", err)
+			os.Exit(1)
+		}
+	}
+	if !namesShown() && (*leaderboard || *ghostDigest || *notifyGhosts || *assignReviewers) {
+		slog.Warn("--leaderboard, --ghost-digest, --notify-ghosts and --assign-reviewers name individuals and are skipped", "privacy", privacy)
+	}
+
+	showExplanations = *explain
+	if *summaryOnly {
+		showExplanations = false
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "explain" {
+				showExplanations = *explain
+			}
+		})
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
 		os.Exit(1)
 	}
 
-	// 3. Fetch Data (Open PRs for Ghosts/Stale) - Limit 100 is usually enough for active backlog
-	fmt.Printf("🔍 Fetching open PRs for analysis (limit 100)...")
-	openPRs, err := fetchPRs(owner, name, 100, "OPEN", *reqTimeout, *reqDelay)
-	if err != nil {
-		fmt.Printf("Error fetching Open PRs: %v\n", err)
-		// We continue even if open PRs fail, just to show merged stats
+	if *cohorts != "" && *cohorts != "quarter" && *cohorts != "release" {
+		slog.Error("--cohorts must be quarter or release", "cohorts", *cohorts)
+		os.Exit(1)
+	}
+	var tagRe *regexp.Regexp
+	if *tagPattern != "" {
+		re, err := regexp.Compile(*tagPattern)
+		if err != nil {
+			slog.Error("invalid --tag-pattern", "err", err)
+			os.Exit(1)
+		}
+		tagRe = re
+	}
+	if *format != "text" && *format != "json" {
+		slog.Error("--format must be text or json", "format", *format)
+		os.Exit(1)
+	}
+	if *format == "json" && *lowMemory {
+		slog.Error("--format json needs every PR and can't be combined with --low-memory")
+		os.Exit(1)
+	}
+	if *slowest < 1 {
+		slog.Error("--slowest must be at least 1")
+		os.Exit(1)
+	}
+	if *longTailMin < 1 {
+		slog.Error("--long-tail-min-prs must be at least 1")
+		os.Exit(1)
+	}
+	if *sample > 0 && *sampleMonths < 1 {
+		slog.Error("--sample-months must be at least 1")
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	repos := flag.Args()
+	if *org != "" {
+		orgRepos, err := fetchOrgRepos(ctx, *org, *reqTimeout, *reqDelay)
+		if err != nil && len(orgRepos) == 0 {
+			slog.Error("listing organization repositories", "org", *org, "err", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			slog.Warn("repository list is incomplete", "org", *org, "repos", len(orgRepos), "err", err)
+		}
+		repos = append(repos, orgRepos...)
+	}
+	if len(repos) < 1 {
+		fmt.Println("Usage: go run main.go [flags] <owner/repo> [owner/repo...]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	for _, repo := range repos {
+		if _, _, err := parseRepo(repo); err != nil {
+			slog.Error("invalid repository", "repo", repo, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	opts := reportOptions{
+		ExcludeOutliers:  *excludeOutliers,
+		Limit:            *limit,
+		Timeout:          *reqTimeout,
+		Delay:            *reqDelay,
+		AssignReviewers:  *assignReviewers,
+		AIInsights:       *aiInsights,
+		Leaderboard:      *leaderboard,
+		Burnout:          *burnout,
+		ResponseSLA:      *responseSLA,
+		Anonymize:        *anonymize,
+		SummaryOnly:      *summaryOnly,
+		IncludeGenerated: *includeGenerated,
+		Sample:           *sample,
+		SampleMonths:     *sampleMonths,
+		Cohorts:          *cohorts,
+		TagPattern:       tagRe,
+		GhostDigest:      *ghostDigest || *notifyGhosts,
+		NotifyGhosts:     *notifyGhosts,
+		Snapshot:         *snapshot,
+		Store:            Store{Dir: *storeDir},
+		ExcludePartial:   *excludePartial,
+		ExcludeDocs:      *excludeDocs,
+		Issues:           *issues,
+		CI:               *ci,
+		StaleBranches:    *staleBranches,
+		StaleBranchAge:   time.Duration(*staleBranchDays) * 24 * time.Hour,
+		ChartsDir:        *chartsDir,
+		Slowest:          *slowest,
+		LongTailMinPRs:   *longTailMin,
+		LowMemory:        *lowMemory,
+		JSON:             *format == "json",
+	}
+
+	var startRL *RateLimit
+	if rl, err := fetchRateLimit(ctx, *reqTimeout); err != nil {
+		slog.Warn("could not read rate limit", "err", err)
+	} else {
+		startRL = &rl
+	}
+	if *plan {
+		printPlan(repos, opts, startRL)
+		return
+	}
+	if !*skipPreflight {
+		if missing, err := preflight(ctx, repos, opts); err != nil {
+			slog.Warn("could not check the token's access", "err", err)
+		} else if !reportMissingAccess(missing) {
+			os.Exit(1)
+		}
+	}
+	if *dryRun {
+		printDryRun(repos, opts, startRL)
+		return
+	}
+	if plansViewer(opts) && !opts.JSON {
+		login, err := fetchViewer(ctx, *reqTimeout)
+		if err != nil {
+			slog.Error("finding the authenticated user", "err", err)
+			os.Exit(1)
+		}
+		opts.Viewer = canonicalLogin(login) // Your other accounts count as you
+	}
+	repoCost := estimateRepoCost(opts)
+	if startRL != nil && repoCost*len(repos) > startRL.Remaining {
+		slog.Warn("run may exceed the GraphQL rate limit", "estimate", repoCost*len(repos), "remaining", startRL.Remaining, "resets", startRL.ResetAt)
+	}
+
+	var errs fetchErrors
+	var agg orgAggregate
+	var reports []RepoReport
+	violations, analyzed := 0, 0
+	for i, repo := range repos {
+		if ctx.Err() != nil {
+			errs.add(repo, "all data", 0, ctx.Err())
+			continue
+		}
+		if startRL != nil && i > 0 {
+			// Stop scanning before the quota runs dry mid-repository
+			if rl, err := fetchRateLimit(ctx, *reqTimeout); err == nil && rl.Remaining < repoCost {
+				errs.add(repo, "all data", 0, fmt.Errorf("rate limit nearly exhausted (%d points left, resets %s)", rl.Remaining, rl.ResetAt.Format("15:04")))
+				continue
+			}
+		}
+		if opts.JSON {
+			if rep, ok := jsonRepoReport(ctx, repo, opts, &errs); ok {
+				reports = append(reports, rep)
+				analyzed++
+			}
+			continue
+		}
+		if len(repos) > 1 {
+			fmt.Println(strings.Repeat("=", 60))
+			fmt.Printf("📦 %s\n", repo)
+			fmt.Println(strings.Repeat("=", 60))
+		}
+		if opts.LowMemory {
+			if analyzeRepoLowMemory(ctx, repo, opts, &errs, &agg) {
+				analyzed++
+			}
+			continue
+		}
+		v, ok := analyzeRepo(ctx, repo, opts, &errs, &agg)
+		violations += v
+		if ok {
+			analyzed++
+		}
+	}
+
+	if opts.JSON {
+		if err := writeJSONReport(os.Stdout, reports, errs); err != nil {
+			slog.Error("writing JSON report", "err", err)
+			os.Exit(1)
+		}
+		if analyzed == 0 && len(errs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	if len(agg) > 1 {
+		fmt.Println(strings.Repeat("=", 60))
+		printOrgAggregate(agg)
+		fmt.Println(strings.Repeat("-", 60))
+	}
+	if startRL != nil {
+		if end, err := fetchRateLimit(context.WithoutCancel(ctx), *reqTimeout); err == nil && !*summaryOnly {
+			printAPIBudget(*startRL, end, repoCost*len(repos))
+			fmt.Println(strings.Repeat("-", 60))
+		} else if err == nil {
+			slog.Info("api budget", "used", startRL.Remaining-end.Remaining, "remaining", end.Remaining, "resets", end.ResetAt)
+		}
+	}
+	if len(errs) > 0 {
+		printFetchErrors(errs)
+		fmt.Println(strings.Repeat("-", 60))
+	}
+	if analyzed == 0 && len(errs) > 0 {
+		os.Exit(1)
+	}
+	if *failOnSLA && violations > 0 {
+		os.Exit(2)
+	}
+}
+
+// reportOptions are the flags that shape the per-repo report.
+type reportOptions struct {
+	ExcludeOutliers  bool
+	Limit            int
+	Timeout          time.Duration
+	Delay            time.Duration
+	AssignReviewers  bool
+	AIInsights       bool
+	Leaderboard      bool
+	Burnout          bool
+	ResponseSLA      time.Duration
+	Anonymize        bool
+	SummaryOnly      bool
+	IncludeGenerated bool
+	Sample           int
+	SampleMonths     int
+	Cohorts          string // "", "quarter" or "release"
+	TagPattern       *regexp.Regexp
+	GhostDigest      bool
+	NotifyGhosts     bool
+	Snapshot         bool
+	Store            Store
+	ExcludePartial   bool
+	ExcludeDocs      bool
+	Issues           bool
+	CI               bool
+	StaleBranches    bool
+	StaleBranchAge   time.Duration
+	ChartsDir        string
+	Slowest          int
+	LongTailMinPRs   int
+	LowMemory        bool
+	JSON             bool   // --format json: headline numbers instead of sections
+	Viewer           string // Authenticated user, for the my_* sections
+}
+
+// analyzeRepo fetches and reports on one repository. Fetch failures are
+// recorded in errs; when part of the data was fetched the report continues
+// with it. The merged PRs analyzed are added to agg. It returns the number
+// of open SLA violations and whether any data could be analyzed.
+func analyzeRepo(ctx context.Context, repo string, o reportOptions, errs *fetchErrors, agg *orgAggregate) (int, bool) {
+	owner, name, _ := parseRepo(repo)
+	fields := reportFields(o)
+	if fields&fieldFiles == 0 {
+		slog.Info("no planned section reads changed files; sizes include generated files", "repo", repo)
 	}
 
+	mergedPRs, openPRs, sample := fetchRepoPRs(ctx, repo, o, fields, errs)
 	if len(mergedPRs) == 0 && len(openPRs) == 0 {
 		fmt.Println("No PRs found.")
-		return
+		return 0, false
+	}
+	fetched := len(mergedPRs)
+	if errs.partial(repo) {
+		fmt.Println("⚠️  PARTIAL DATASET: some data for this repository could not be fetched (see ERRORS at the end).")
+		fmt.Println("   The numbers below only cover what was fetched.")
+	}
+
+	// Generated files (lockfiles, vendor/, protobuf...) don't count toward size
+	generated, err := loadGeneratedMatcher(ctx, owner, name, o.Timeout)
+	if err != nil {
+		slog.Warn("could not fetch .gitattributes", "repo", repo, "err", err)
+	}
+	markGenerated(mergedPRs, generated, !o.IncludeGenerated)
+	markGenerated(openPRs, generated, !o.IncludeGenerated)
+
+	// Docs-only PRs merge fast and flatter velocity (Optional)
+	var docsPRs []PullRequest
+	if o.ExcludeDocs {
+		mergedPRs, docsPRs = splitDocs(mergedPRs)
+	}
+
+	// Health score leads every report
+	history, err := o.Store.Load(repo)
+	if err != nil {
+		slog.Warn("could not read snapshot history", "repo", repo, "err", err)
+	}
+	snap := healthSnapshot(repo, mergedPRs, openPRs, clock())
+	printHealthScore(snap, history)
+	fmt.Println(strings.Repeat("-", 60))
+	if len(cfg.Goals) > 0 && len(mergedPRs) > 0 {
+		printGoals(mergedPRs)
+		fmt.Println(strings.Repeat("-", 60))
+	}
+	if o.Snapshot {
+		if err := o.Store.Save(snap); err != nil {
+			slog.Error("saving snapshot", "repo", repo, "err", err)
+		} else {
+			slog.Info("saved snapshot", "repo", repo, "health", snap.Health)
+		}
+	}
+
+	if o.SummaryOnly {
+		if o.ExcludeOutliers {
+			mergedPRs = filterOutliers(mergedPRs)
+		}
+		printScorecard(mergedPRs, openPRs)
+		agg.add(repo, mergedPRs)
+		return 0, true
 	}
 
-	// --- Merged PR Analysis ---
 	if len(mergedPRs) > 0 {
 		// Filter Outliers (Optional)
-		if *excludeOutliers {
+		if o.ExcludeOutliers {
 			originalCount := len(mergedPRs)
 			mergedPRs = filterOutliers(mergedPRs)
 			fmt.Printf("✂️  Outlier filtering active. Reduced from %d to %d PRs.\n", originalCount, len(mergedPRs))
 		}
+		if len(docsPRs) > 0 {
+			fmt.Printf("📝 Docs-only PRs excluded: %d.\n", len(docsPRs))
+		}
 		fmt.Println(strings.Repeat("-", 60))
 
-		printGeneralStats(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printReviewStats(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printSizeAnalysis(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printHotspots(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printLongTailAuthors(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printTrends(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printForecast(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
-		printHistogram(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
+		if sample != nil {
+			printSamplingNotes(*sample, mergedPRs)
+			fmt.Println(strings.Repeat("-", 60))
+		}
+	}
 
-		// NEW: Hero Syndrome (Uses Merged Data)
-		printHeroAnalysis(mergedPRs)
-		fmt.Println(strings.Repeat("-", 60))
+	agg.add(repo, mergedPRs)
+
+	// Sections run in the order set in config, or the default order
+	run := &reportRun{
+		ctx: ctx, repo: repo, owner: owner, name: name, o: o, errs: errs,
+		merged: mergedPRs, fetched: fetched, open: openPRs, sample: sample, generated: generated, docs: docsPRs,
+		history: history,
 	}
+	runSections(run)
+	fieldErrors.takeInto(errs, repo) // From data sections fetched
 
-	// --- Open PR Analysis ---
-	if len(openPRs) > 0 {
-		// NEW: Stale PRs
-		printStaleAnalysis(openPRs)
-		fmt.Println(strings.Repeat("-", 60))
+	if o.ChartsDir != "" {
+		files, err := writeCharts(o.ChartsDir, repo, mergedPRs, run.months())
+		if err != nil {
+			slog.Error("writing charts", "repo", repo, "dir", o.ChartsDir, "err", err)
+		}
+		for _, f := range files {
+			slog.Info("wrote chart", "repo", repo, "file", f)
+		}
+	}
+	return run.violations, true
+}
 
-		// NEW: Ghost Reviewers
-		printGhostAnalysis(openPRs)
-		fmt.Println(strings.Repeat("-", 60))
+// fetchRepoPRs fetches the merged PRs (or a sample of them) and the open
+// PRs of one repository with the optional fields in fields. Failures are
+// recorded in errs, and whatever was fetched before them is returned.
+func fetchRepoPRs(ctx context.Context, repo string, o reportOptions, fields prField, errs *fetchErrors) (merged, open []PullRequest, sample *SamplePlan) {
+	owner, name, _ := parseRepo(repo)
+	// 2. Fetch Data (Merged PRs for Stats)
+	var err error
+	if o.Sample > 0 {
+		slog.Info("sampling merged PRs", "repo", repo, "sample", o.Sample, "months", o.SampleMonths)
+		var plan SamplePlan
+		merged, plan, err = sampleMergedPRs(ctx, owner, name, o.Sample, o.SampleMonths, o.Timeout, o.Delay)
+		sample = &plan
+	} else {
+		slog.Info("fetching merged PRs", "repo", repo, "limit", o.Limit)
+		live := newLiveStats(repo, o.Limit)
+		merged, err = collectPRs(streamPRs(ctx, owner, name, o.Limit, "MERGED", fields, o.Timeout, o.Delay), live.add)
+		live.done()
+	}
+	if err != nil {
+		slog.Error("fetching merged PRs", "repo", repo, "fetched", len(merged), "err", err)
+		errs.add(repo, "merged PRs", len(merged), err)
+	}
+
+	// 3. Fetch Data (Open PRs for Ghosts/Stale) - Limit 100 is usually enough for active backlog
+	slog.Info("fetching open PRs", "repo", repo, "limit", 100)
+	open, err = fetchPRFields(ctx, owner, name, 100, "OPEN", fields, o.Timeout, o.Delay)
+	if err != nil {
+		// We continue even if open PRs fail, just to show merged stats
+		slog.Error("fetching open PRs", "repo", repo, "fetched", len(open), "err", err)
+		errs.add(repo, "open PRs", len(open), err)
 	}
+
+	fieldErrors.takeInto(errs, repo)
+	return merged, open, sample
+}
+
+// parseRepo splits an owner/repo argument.
+func parseRepo(repo string) (string, string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("repo must be in format owner/repo")
+	}
+	return parts[0], parts[1], nil
 }
 
 // Generic Fetch Function for both OPEN and MERGED
-func fetchPRs(owner, name string, limit int, state string, timeout time.Duration, delay time.Duration) ([]PullRequest, error) {
-	var allPRs []PullRequest
-	var cursor string
+func fetchPRs(ctx context.Context, owner, name string, limit int, state string, timeout time.Duration, delay time.Duration) ([]PullRequest, error) {
+	return fetchPRFields(ctx, owner, name, limit, state, allFields, timeout, delay)
+}
 
-	// GraphQL Query Template
-	// We fetch reviews (for heroes) and reviewRequests (for ghosts)
-	queryTmpl := `
+// fetchPRFields is fetchPRs selecting only the optional fields in fields.
+func fetchPRFields(ctx context.Context, owner, name string, limit int, state string, fields prField, timeout time.Duration, delay time.Duration) ([]PullRequest, error) {
+	return collectPRs(streamPRs(ctx, owner, name, limit, state, fields, timeout, delay), nil)
+}
+
+// prPage is one page of a pull request fetch. The last page sent carries
+// the error when the fetch failed.
+type prPage struct {
+	PRs []PullRequest
+	Err error
+}
+
+// streamPRs fetches pull requests in the background and sends each page as
+// soon as it's converted, so consumers analyze one page while the next is
+// in flight. The channel is closed after the last page.
+func streamPRs(ctx context.Context, owner, name string, limit int, state string, fields prField, timeout time.Duration, delay time.Duration) <-chan prPage {
+	pages := make(chan prPage, 1)
+
+	go func() {
+		defer close(pages)
+		var cursor string
+		fetched := 0
+		for fetched < limit {
+			if fetched > 0 {
+				if err := sleepCtx(ctx, delay); err != nil {
+					pages <- prPage{Err: pageError(fetched, err)}
+					return
+				}
+			}
+
+			query := prQuery(owner, name, state, min(limit-fetched, 100), cursor, fields)
+
+			// On failure, consumers keep what earlier pages sent so they can
+			// continue with a partial dataset.
+			output, err := ghGraphQL(ctx, query, timeout)
+			if err != nil {
+				pages <- prPage{Err: pageError(fetched, err)}
+				return
+			}
+
+			var resp GraphQLResponse
+			if err := json.Unmarshal(output, &resp); err != nil {
+				pages <- prPage{Err: pageError(fetched, err)}
+				return
+			}
+
+			nodes := resp.Data.Repository.PullRequests.Nodes
+			if len(nodes) == 0 {
+				return
+			}
+
+			page := make([]PullRequest, 0, len(nodes))
+			for _, node := range nodes {
+				page = append(page, toPullRequest(node))
+			}
+			fetched += len(page)
+			slog.Debug("fetched page", "repo", owner+"/"+name, "state", state, "prs", len(nodes), "total", fetched)
+			pages <- prPage{PRs: page}
+
+			if !resp.Data.Repository.PullRequests.PageInfo.HasNextPage {
+				return
+			}
+			cursor = resp.Data.Repository.PullRequests.PageInfo.EndCursor
+		}
+	}()
+	return pages
+}
+
+// prQuery is the GraphQL query for one page of first pull requests in
+// state, after cursor when it is set.
+func prQuery(owner, name, state string, first int, cursor string, fields prField) string {
+	// Order by Created DESC for Merged, Updated DESC for Open (usually better for stale checks)
+	orderBy := "CREATED_AT"
+	if state == "OPEN" {
+		orderBy = "UPDATED_AT"
+	}
+
+	args := fmt.Sprintf("first: %d, states: %s, orderBy: {field: %s, direction: DESC}", first, state, orderBy)
+	if cursor != "" {
+		args += fmt.Sprintf(`, after: "%s"`, cursor)
+	}
+
+	return fmt.Sprintf(`
 query {
   repository(owner: "%s", name: "%s") {
     pullRequests(%s) {
       nodes {
-        number
-        createdAt
-        updatedAt
-        mergedAt
-        title
-        additions
-        deletions
-        author { login }
-        reviews(first: 10) {
-          nodes {
-            createdAt
-            author { login }
-          }
-        }
-        reviewRequests(first: 10) {
-          nodes {
-            requestedReviewer {
-              ... on User { login }
-            }
-          }
-        }
-        files(first: 5) {
-          nodes { path }
-        }
+`+prSelection(fields)+`
       }
       pageInfo {
         hasNextPage
@@ -205,128 +828,452 @@ query {
       }
     }
   }
+}`, owner, name, args)
+}
+
+// collectPRs drains pages into one slice, calling onPage (when set) with
+// each page as it arrives. It returns the fetch error, if any, with the PRs
+// received before it.
+func collectPRs(pages <-chan prPage, onPage func([]PullRequest)) ([]PullRequest, error) {
+	var all []PullRequest
+	var err error
+	for p := range pages {
+		if p.Err != nil {
+			err = p.Err
+			continue
+		}
+		all = append(all, p.PRs...)
+		if onPage != nil {
+			onPage(p.PRs)
+		}
+	}
+	return all, err
+}
+
+// searchPRs fetches pull requests matching a GitHub search query (e.g.
+// "is:merged merged:2024-07-01..2024-09-30"), scoped to the repository.
+// GitHub caps search results at 1000.
+func searchPRs(ctx context.Context, owner, name, search string, limit int, timeout time.Duration, delay time.Duration) ([]PullRequest, error) {
+	var allPRs []PullRequest
+	var cursor string
+
+	queryTmpl := `
+query {
+  search(%s) {
+    nodes {
+      ... on PullRequest {
+` + prFields + `
+      }
+    }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+  }
 }`
 
+	q := fmt.Sprintf("repo:%s/%s is:pr %s", owner, name, search)
 	for len(allPRs) < limit {
 		if len(allPRs) > 0 {
-			time.Sleep(delay)
-		}
-
-		remaining := limit - len(allPRs)
-		toFetch := 100
-		if remaining < 100 {
-			toFetch = remaining
+			if err := sleepCtx(ctx, delay); err != nil {
+				return allPRs, pageError(len(allPRs), err)
+			}
 		}
 
-		// Order by Created DESC for Merged, Updated DESC for Open (usually better for stale checks)
-		orderBy := "CREATED_AT"
-		if state == "OPEN" {
-			orderBy = "UPDATED_AT"
+		toFetch := limit - len(allPRs)
+		if toFetch > 100 {
+			toFetch = 100
 		}
-
-		args := fmt.Sprintf("first: %d, states: %s, orderBy: {field: %s, direction: DESC}", toFetch, state, orderBy)
+		args := fmt.Sprintf("query: %q, type: ISSUE, first: %d", q, toFetch)
 		if cursor != "" {
 			args += fmt.Sprintf(`, after: "%s"`, cursor)
 		}
 
-		query := fmt.Sprintf(queryTmpl, owner, name, args)
-
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-
-		cmd := exec.CommandContext(ctx, "gh", "api", "graphql", "-f", fmt.Sprintf("query=%s", query))
-		output, err := cmd.Output()
-
-		if ctx.Err() == context.DeadlineExceeded {
-			return nil, fmt.Errorf("request timed out after %v", timeout)
-		}
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, args), timeout)
 		if err != nil {
-			return nil, err
+			return allPRs, pageError(len(allPRs), err)
 		}
 
-		var resp GraphQLResponse
+		var resp struct {
+			Data struct {
+				Search struct {
+					Nodes    []GRPCPullRequest `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"search"`
+			} `json:"data"`
+		}
 		if err := json.Unmarshal(output, &resp); err != nil {
-			return nil, err
+			return allPRs, pageError(len(allPRs), err)
 		}
 
-		nodes := resp.Data.Repository.PullRequests.Nodes
+		nodes := resp.Data.Search.Nodes
 		if len(nodes) == 0 {
 			break
 		}
-
 		for _, node := range nodes {
-			pr := PullRequest{
-				Number:    node.Number,
-				CreatedAt: node.CreatedAt,
-				UpdatedAt: node.UpdatedAt,
-				MergedAt:  node.MergedAt,
-				Author:    node.Author.Login,
-				Title:     node.Title,
-				Size:      node.Additions + node.Deletions,
-			}
+			allPRs = append(allPRs, toPullRequest(node))
+		}
+		slog.Debug("fetched search page", "query", q, "prs", len(nodes), "total", len(allPRs))
 
-			// Process Reviews
-			if len(node.Reviews.Nodes) > 0 {
-				// First review time
-				t := node.Reviews.Nodes[0].CreatedAt
-				pr.FirstReviewAt = &t
-
-				// Collect Reviewers
-				seen := make(map[string]bool)
-				for _, r := range node.Reviews.Nodes {
-					if r.Author.Login != "" && r.Author.Login != pr.Author && !seen[r.Author.Login] {
-						pr.Reviewers = append(pr.Reviewers, r.Author.Login)
-						seen[r.Author.Login] = true
-					}
-				}
-			}
+		if !resp.Data.Search.PageInfo.HasNextPage {
+			break
+		}
+		cursor = resp.Data.Search.PageInfo.EndCursor
+	}
 
-			// Process Requested Reviewers
-			for _, req := range node.ReviewRequests.Nodes {
-				if req.RequestedReviewer.Login != "" {
-					pr.Requested = append(pr.Requested, req.RequestedReviewer.Login)
-				}
+	return allPRs, nil
+}
+
+// prField is an optional part of the pull request selection, fetched only
+// when something in the report reads it. Scalars, reviews and review
+// requests are always fetched.
+type prField uint8
+
+const (
+	fieldCommits     prField = 1 << iota // Commit times: review rounds, base merges
+	fieldTimeline                        // Review request events, auto-merge, comments
+	fieldFiles                           // Changed files: paths, generated lines
+	fieldLabels                          // Labels and linked issues
+	fieldBody                            // Description
+	fieldMergeCommit                     // Merge commit: merge method
+	fieldAssignees                       // Assignees and the first assignment
+	fieldProjects                        // Projects (v2) board additions and status changes
+
+	allFields = fieldCommits | fieldTimeline | fieldFiles | fieldLabels | fieldBody | fieldMergeCommit | fieldAssignees | fieldProjects
+)
+
+// connections is the number of connections the selection requests per pull
+// request, which GraphQL query cost is computed from.
+func (f prField) connections() int {
+	n := 2 // reviews, reviewRequests
+	for _, c := range []struct {
+		field prField
+		n     int
+	}{{fieldCommits, 1}, {fieldTimeline, 1}, {fieldFiles, 1}, {fieldLabels, 2}, {fieldMergeCommit, 1}, {fieldAssignees, 2}, {fieldProjects, 1}} {
+		if f&c.field != 0 {
+			n += c.n
+		}
+	}
+	return n
+}
+
+// skipped names the optional fields not in f.
+func (f prField) skipped() []string {
+	var out []string
+	for _, c := range []struct {
+		field prField
+		name  string
+	}{{fieldCommits, "commits"}, {fieldTimeline, "timeline"}, {fieldFiles, "files"}, {fieldLabels, "labels"}, {fieldBody, "body"}, {fieldMergeCommit, "merge commit"}, {fieldAssignees, "assignees"}, {fieldProjects, "project boards"}} {
+		if f&c.field == 0 {
+			out = append(out, c.name)
+		}
+	}
+	return out
+}
+
+// prSelection is the GraphQL selection for a pull request node with the
+// optional fields in f. Any query that returns pull requests decodes into
+// GRPCPullRequest through it. We always fetch reviews (for heroes) and
+// reviewRequests (for ghosts).
+func prSelection(f prField) string {
+	var b strings.Builder
+	b.WriteString(`
+number
+createdAt
+updatedAt
+mergedAt
+title
+baseRefName
+headRefName
+headRepository { nameWithOwner }
+isDraft
+additions
+deletions
+author { login }
+mergedBy { login }
+reviews(first: 50) {
+  nodes {
+    createdAt
+    state
+    author { login }
+  }
+}
+reviewRequests(first: 10) {
+  nodes {
+    requestedReviewer {
+      ... on User { login }
+      ... on Team { combinedSlug }
+    }
+  }
+}`)
+	if f&fieldBody != 0 {
+		b.WriteString(`
+body`)
+	}
+	if f&fieldCommits != 0 {
+		b.WriteString(`
+commits(last: 50) {
+  nodes {
+    commit { committedDate messageHeadline }
+  }
+}`)
+	}
+	if f&fieldTimeline != 0 {
+		b.WriteString(`
+timelineItems(first: 100, itemTypes: [REVIEW_REQUESTED_EVENT, AUTO_MERGE_ENABLED_EVENT, AUTO_MERGE_DISABLED_EVENT, ISSUE_COMMENT]) {
+  nodes {
+    __typename
+    ... on ReviewRequestedEvent {
+      createdAt
+      requestedReviewer { ... on User { login } }
+    }
+    ... on AutoMergeEnabledEvent { createdAt }
+    ... on AutoMergeDisabledEvent { createdAt }
+    ... on IssueComment { createdAt author { login } }
+  }
+}`)
+	}
+	if f&fieldFiles != 0 {
+		b.WriteString(`
+changedFiles
+files(first: 100) {
+  nodes { path additions deletions }
+}`)
+	}
+	if f&fieldLabels != 0 {
+		b.WriteString(`
+labels(first: 20) {
+  nodes { name }
+}
+closingIssuesReferences { totalCount }`)
+	}
+	if f&fieldMergeCommit != 0 {
+		b.WriteString(`
+mergeCommit { messageHeadline parents { totalCount } }`)
+	}
+	if f&fieldAssignees != 0 {
+		b.WriteString(`
+assignees(first: 10) {
+  nodes { login }
+}
+assignedEvents: timelineItems(first: 1, itemTypes: [ASSIGNED_EVENT]) {
+  nodes {
+    ... on AssignedEvent { createdAt }
+  }
+}`)
+	}
+	if f&fieldProjects != 0 {
+		b.WriteString(`
+projectEvents: timelineItems(first: 100, itemTypes: [ADDED_TO_PROJECT_V2_EVENT, PROJECT_V2_ITEM_STATUS_CHANGED_EVENT]) {
+  nodes {
+    __typename
+    ... on AddedToProjectV2Event { createdAt project { title } }
+    ... on ProjectV2ItemStatusChangedEvent { createdAt previousStatus status project { title } }
+  }
+}`)
+	}
+	return b.String()
+}
+
+// prFields is the full pull request selection.
+var prFields = prSelection(allFields)
+
+// toPullRequest flattens a GraphQL pull request node.
+func toPullRequest(node GRPCPullRequest) PullRequest {
+	pr := PullRequest{
+		Number:       node.Number,
+		CreatedAt:    node.CreatedAt,
+		UpdatedAt:    node.UpdatedAt,
+		MergedAt:     node.MergedAt,
+		Author:       canonicalLogin(node.Author.Login),
+		MergedBy:     canonicalLogin(node.MergedBy.Login),
+		Title:        node.Title,
+		Body:         node.Body,
+		BaseRef:      node.BaseRef,
+		HeadRef:      node.HeadRef,
+		IsDraft:      node.IsDraft,
+		Size:         node.Additions + node.Deletions,
+		ChangedFiles: node.Changed,
+		LinkedIssues: node.ClosingIssuesReferences.TotalCount,
+	}
+
+	// Process Reviews
+	if len(node.Reviews.Nodes) > 0 {
+		// Collect Reviewers
+		seen := make(map[string]bool)
+		for _, r := range node.Reviews.Nodes {
+			login := canonicalLogin(r.Author.Login)
+			if login != "" && login != pr.Author && !seen[login] {
+				pr.Reviewers = append(pr.Reviewers, login)
+				seen[login] = true
 			}
+			pr.Reviews = append(pr.Reviews, Review{Author: login, State: r.State, CreatedAt: r.CreatedAt})
+		}
+	}
 
-			// Process Files
-			for _, f := range node.Files.Nodes {
-				pr.FilePaths = append(pr.FilePaths, f.Path)
+	// Process Commits (used to detect re-review cycles)
+	for _, c := range node.Commits.Nodes {
+		pr.CommitTimes = append(pr.CommitTimes, c.Commit.CommittedDate)
+		if mergeCommit.MatchString(c.Commit.MessageHeadline) {
+			pr.BaseMerges = append(pr.BaseMerges, c.Commit.CommittedDate)
+		}
+	}
+
+	// Process Requested Reviewers
+	for _, req := range node.ReviewRequests.Nodes {
+		if req.RequestedReviewer.Login != "" {
+			pr.Requested = append(pr.Requested, canonicalLogin(req.RequestedReviewer.Login))
+		} else if req.RequestedReviewer.CombinedSlug != "" {
+			pr.Requested = append(pr.Requested, req.RequestedReviewer.CombinedSlug)
+		}
+	}
+
+	for _, e := range node.TimelineItems.Nodes {
+		switch e.Type {
+		case "AutoMergeEnabledEvent":
+			t := e.CreatedAt
+			pr.AutoMergeAt = &t
+		case "AutoMergeDisabledEvent":
+			pr.AutoMergeAt = nil
+		case "IssueComment":
+			pr.Comments = append(pr.Comments, Comment{Author: canonicalLogin(e.Author.Login), At: e.CreatedAt})
+		default:
+			if e.RequestedReviewer.Login != "" {
+				pr.ReviewRequests = append(pr.ReviewRequests, ReviewRequest{Reviewer: canonicalLogin(e.RequestedReviewer.Login), At: e.CreatedAt})
 			}
+		}
+	}
+
+	// First review time, by the configured definition
+	pr.FirstReviewAt = firstReviewAt(pr)
+
+	// Process Files
+	pr.FileLines = make(map[string]int)
+	for _, f := range node.Files.Nodes {
+		pr.FilePaths = append(pr.FilePaths, f.Path)
+		pr.FileLines[f.Path] = f.Additions + f.Deletions
+	}
 
-			allPRs = append(allPRs, pr)
+	for _, l := range node.Labels.Nodes {
+		pr.Labels = append(pr.Labels, l.Name)
+	}
+	for _, a := range node.Assignees.Nodes {
+		pr.Assignees = append(pr.Assignees, canonicalLogin(a.Login))
+	}
+	if len(node.AssignedEvents.Nodes) > 0 {
+		t := node.AssignedEvents.Nodes[0].CreatedAt
+		pr.AssignedAt = &t
+	}
+	for _, e := range node.ProjectEvents.Nodes {
+		m := ProjectMove{Project: e.Project.Title, At: e.CreatedAt}
+		if e.Type == "ProjectV2ItemStatusChangedEvent" {
+			m.From, m.To = e.PreviousStatus, e.Status
 		}
+		pr.ProjectMoves = append(pr.ProjectMoves, m)
+	}
+	if node.HeadRepo != nil {
+		pr.HeadRepo = node.HeadRepo.NameWithOwner
+	}
+	if c := node.MergeCommit; c != nil && !node.MergedAt.IsZero() {
+		pr.MergeMethod = mergeMethod(c.Parents.TotalCount, c.MessageHeadline, node.Number)
+	}
+	return pr
+}
 
-		if !resp.Data.Repository.PullRequests.PageInfo.HasNextPage {
-			break
+// ghGraphQL runs a GraphQL query through the gh CLI and returns the raw response.
+// Field errors in a response that still has data are warnings, not
+// failures (see checkGraphQLErrors).
+func ghGraphQL(ctx context.Context, query string, timeout time.Duration) ([]byte, error) {
+	output, err := ghAPI(ctx, timeout, "graphql", "-f", fmt.Sprintf("query=%s", query))
+	return output, checkGraphQLErrors(query, output, err)
+}
+
+// ghToken, when set, is the GitHub token gh runs with instead of its own
+// login: the active tenant's in a multi-tenant server.
+var ghToken string
+
+// ghAPI runs `gh api` with the given arguments, or replays a recorded
+// response (see recorder.go).
+func ghAPI(ctx context.Context, timeout time.Duration, args ...string) ([]byte, error) {
+	if dir := os.Getenv(replayEnv); dir != "" {
+		return replayFixture(dir, args)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	output, err := ghExec(reqCtx, args)
+	slog.Debug("gh api", "endpoint", args[0], "duration", time.Since(start), "bytes", len(output), "err", err)
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if reqCtx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("request timed out after %v", timeout)
+	}
+	if dir := os.Getenv(recordEnv); dir != "" {
+		if recErr := fixtureRecorder.record(dir, args, output, err); recErr != nil {
+			slog.Warn("could not record fixture", "endpoint", args[0], "err", recErr)
 		}
-		cursor = resp.Data.Repository.PullRequests.PageInfo.EndCursor
 	}
+	return output, err
+}
 
-	return allPRs, nil
+// ghExec runs `gh api` with args and returns its standard output. Like gh,
+// it returns the response body along with the error when the request
+// failed. Integration tests swap it for a fake GitHub server.
+var ghExec = func(ctx context.Context, args []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gh", append([]string{"api"}, args...)...)
+	if ghToken != "" {
+		cmd.Env = append(os.Environ(), "GH_TOKEN="+ghToken)
+	}
+	output, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		slog.Debug("gh api stderr", "endpoint", args[0], "stderr", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
 }
 
 // --- Stats Functions ---
 
-func printHeroAnalysis(prs []PullRequest) {
-	fmt.Println("🦸 HERO SYNDROME DETECTOR")
-	fmt.Println("   • Concept: Identifies developers reviewing a disproportionate amount of code.")
-	fmt.Println("   • Why:     Heroes are single points of failure. If they leave or burn out, velocity crashes.")
-	fmt.Println("")
-
-	reviewCounts := make(map[string]int)
-	totalReviews := 0
+// showExplanations toggles the Concept/Why paragraphs under section headers.
+var showExplanations = true
 
-	for _, pr := range prs {
-		for _, reviewer := range pr.Reviewers {
-			reviewCounts[reviewer]++
-			totalReviews++
+// printExplanation prints a section's concept and rationale. Extra why lines
+// continue the rationale.
+func printExplanation(concept string, why ...string) {
+	if !showExplanations {
+		return
+	}
+	fmt.Printf("   • Concept: %s\n", concept)
+	for i, line := range why {
+		if i == 0 {
+			fmt.Printf("   • Why:     %s\n", line)
+		} else {
+			fmt.Printf("              %s\n", line)
 		}
 	}
+	fmt.Println("")
+}
+
+func printHeroAnalysis(prs []PullRequest) {
+	fmt.Println("🦸 HERO SYNDROME DETECTOR")
+	printExplanation("Identifies developers reviewing a disproportionate amount of code.", "Heroes are single points of failure. If they leave or burn out, velocity crashes.")
+
+	reviewCounts, totalReviews := countReviews(prs)
 
 	if totalReviews == 0 {
 		fmt.Println("   No reviews found in this dataset.")
 		return
 	}
+	if privacy == privacyAggregate {
+		printHeroShares(reviewCounts, totalReviews)
+		return
+	}
+	reviewCounts = byPerson(reviewCounts)
 
 	// Sort
 	type Reviewer struct {
@@ -356,7 +1303,7 @@ func printHeroAnalysis(prs []PullRequest) {
 				riskLevel = "✅ Healthy"
 			}
 
-			fmt.Printf("   %s: %d reviews (%.1f%%) - %s\n", h.Name, h.Count, percentage, riskLevel)
+			fmt.Printf("   %s: %d reviews (%s%%) - %s\n", h.Name, h.Count, formatNumber(percentage, 1), riskLevel)
 		}
 	}
 
@@ -365,22 +1312,65 @@ func printHeroAnalysis(prs []PullRequest) {
 	}
 }
 
+// printHeroShares reports review concentration without naming anyone, for
+// aggregate privacy mode.
+func printHeroShares(reviewCounts map[string]int, totalReviews int) {
+	top1, top3, half := loadShares(reviewCounts)
+	fmt.Printf("   Reviewers: %d   Reviews: %d\n", len(reviewCounts), totalReviews)
+	fmt.Printf("   Busiest reviewer: %s%% of reviews   Busiest three: %s%%\n", formatNumber(top1, 1), formatNumber(top3, 1))
+	fmt.Printf("   Reviewers doing half of all reviews: %d\n", half)
+	switch {
+	case top1 > 50:
+		fmt.Println("   🚨 CRITICAL RISK: One reviewer does more than half of all reviews.")
+	case top1 > 30:
+		fmt.Println("   ⚠️  High Load: One reviewer does more than 30% of all reviews.")
+	default:
+		fmt.Println("   ✅ Load is well-distributed. No single reviewer is a bottleneck.")
+	}
+}
+
+// countReviews returns how many PRs each person reviewed, and the total.
+// Bots aren't people and are left out.
+func countReviews(prs []PullRequest) (map[string]int, int) {
+	reviewCounts := make(map[string]int)
+	totalReviews := 0
+
+	for _, pr := range prs {
+		for _, reviewer := range pr.Reviewers {
+			if isBot(reviewer) {
+				continue
+			}
+			reviewCounts[reviewer]++
+			totalReviews++
+		}
+	}
+	return reviewCounts, totalReviews
+}
+
 func printStaleAnalysis(prs []PullRequest) {
 	fmt.Println("📉 STALE PR DETECTOR (The Graveyard)")
-	fmt.Println("   • Concept: Open PRs that haven't been touched in >7 days.")
-	fmt.Println("   • Why:     Stale PRs rot, cause conflicts, and discourage the team.")
-	fmt.Println("")
+	printExplanation("Open PRs that haven't been touched in >7 days.", "Stale PRs rot, cause conflicts, and discourage the team.")
 
-	now := time.Now()
-	staleThreshold := 7 * 24 * time.Hour
+	now := clock()
+	cutoff := staleCutoff(now)
 	staleCount := 0
+	exempt := make(map[string]int) // Inactive but parked, by reason
 
 	for _, pr := range prs {
-		if now.Sub(pr.UpdatedAt) > staleThreshold {
-			staleCount++
-			days := int(now.Sub(pr.UpdatedAt).Hours() / 24)
-			fmt.Printf("   💀 #%d (%s) by %s - %d days inactive\n", pr.Number, limitString(pr.Title, 40), pr.Author, days)
+		if !pr.UpdatedAt.Before(cutoff) {
+			continue
 		}
+		if reason := staleExemption(pr); reason != "" {
+			exempt[reason]++
+			continue
+		}
+		staleCount++
+		days := int(now.Sub(pr.UpdatedAt).Hours() / 24)
+		by := ""
+		if privacy != privacyAggregate {
+			by = " by " + person(pr.Author)
+		}
+		fmt.Printf("   💀 #%d (%s)%s - %d days inactive\n", pr.Number, limitString(pr.Title, 40), by, days)
 	}
 
 	if staleCount == 0 {
@@ -388,18 +1378,37 @@ func printStaleAnalysis(prs []PullRequest) {
 	} else {
 		fmt.Printf("\n   Action: Ping these authors or close the PRs.\n")
 	}
+	if len(exempt) > 0 {
+		reasons := make([]string, 0, len(exempt))
+		total := 0
+		for r, n := range exempt {
+			reasons = append(reasons, fmt.Sprintf("%s: %d", r, n))
+			total += n
+		}
+		sort.Strings(reasons)
+		fmt.Printf("   🅿️  Parked: %d inactive PRs exempt by stale.exempt in config (%s)\n", total, strings.Join(reasons, ", "))
+	}
+	fmt.Printf("   (Cutoff: no activity since %s 00:00 %s.)\n", formatDate(cutoff), primaryLocation)
 }
 
-func printGhostAnalysis(prs []PullRequest) {
+func printGhostAnalysis(prs []PullRequest, owners *Codeowners, avail *Availability) {
 	fmt.Println("👻 GHOST REVIEWER DETECTOR")
-	fmt.Println("   • Concept: Reviewers requested >48h ago who haven't responded.")
-	fmt.Println("   • Why:     Silent blocking. The PR owner is waiting for a notification that never comes.")
-	fmt.Println("")
+	printExplanation("Reviewers requested >48h ago who haven't responded.", "Silent blocking. The PR owner is waiting for a notification that never comes.")
 
-	now := time.Now()
+	now := clock()
 	ghostThreshold := 48 * time.Hour
 
-ghosts := make(map[string]int)
+	type Ghost struct {
+		Name     string
+		Blocking int // PRs where they are a required CODEOWNER
+		Optional int // Courtesy requests
+		Away     string
+	}
+	ghosts := make(map[string]*Ghost)
+	excludedAway := make(map[string]bool)
+	waiting := make(map[int]bool)   // PRs with a ghost
+	engaged := make(map[string]int) // Requested reviewers discussing, per name
+	engagedPRs := make(map[int]bool)
 
 	for _, pr := range prs {
 		// Only check PRs that are older than 48h, otherwise the request is fresh
@@ -407,26 +1416,147 @@ ghosts := make(map[string]int)
 			for _, reviewer := range pr.Requested {
 				// Simple logic: If you are still in "Requested", you haven't reviewed yet.
 				// (GitHub moves you from Requested -> Reviews once you submit)
-				ghosts[reviewer]++
+				away, reason := avail.Away(reviewer, now)
+				if away && avail.Exclude() {
+					excludedAway[reviewer] = true
+					continue
+				}
+				name := reviewer
+				if privacy == privacyTeam {
+					name = person(reviewer) // Ghosts add up per team
+				}
+				// Commenting without a verdict isn't silence
+				if engagedSince(pr, reviewer, requestedAt(pr, reviewer, now)) {
+					engaged[name]++
+					engagedPRs[pr.Number] = true
+					continue
+				}
+				if _, exists := ghosts[name]; !exists {
+					ghosts[name] = &Ghost{Name: name}
+				}
+				if namesShown() {
+					ghosts[name].Away = reason
+				}
+				if owners != nil && owners.IsRequired(pr, reviewer) {
+					ghosts[name].Blocking++
+				} else {
+					ghosts[name].Optional++
+				}
+				waiting[pr.Number] = true
 			}
 		}
 	}
 
 	if len(ghosts) == 0 {
 		fmt.Println("   ✅ No ghosts found. Everyone is responding (or PRs are new).")
+		printEngaged(engaged, len(engagedPRs))
+		if len(excludedAway) > 0 {
+			fmt.Printf("   (%d away reviewers excluded.)\n", len(excludedAway))
+		}
 		return
 	}
 
-	// Sort
-	var names []string
-	for n := range ghosts {
-		names = append(names, n)
+	// Sort: required (truly blocking) ghosts first
+	var list []*Ghost
+	for _, g := range ghosts {
+		list = append(list, g)
 	}
-	sort.Slice(names, func(i, j int) bool { return ghosts[names[i]] > ghosts[names[j]] })
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Blocking != list[j].Blocking {
+			return list[i].Blocking > list[j].Blocking
+		}
+		if list[i].Optional != list[j].Optional {
+			return list[i].Optional > list[j].Optional
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	if privacy == privacyAggregate {
+		required, most := 0, 0
+		for _, g := range list {
+			if g.Blocking > 0 {
+				required++
+			}
+			most = max(most, g.Blocking+g.Optional)
+		}
+		fmt.Printf("   👻 %d reviewers haven't responded to requests on %d PRs (>48h)\n", len(list), len(waiting))
+		if owners != nil {
+			fmt.Printf("   🚨 %d of them are required CODEOWNERs\n", required)
+		}
+		fmt.Printf("   Most PRs waiting on one reviewer: %d\n", most)
+	} else {
+		for _, g := range list {
+			count := g.Blocking + g.Optional
+			away := ""
+			if g.Away != "" {
+				away = fmt.Sprintf(" 🌴 (%s)", g.Away)
+			}
+			if owners == nil {
+				fmt.Printf("   👻 %s: Blocking %d PRs (>48h)%s\n", g.Name, count, away)
+				continue
+			}
+			switch {
+			case g.Optional == 0:
+				fmt.Printf("   🚨 %s: Blocking %d PRs (>48h) - required CODEOWNER%s\n", g.Name, count, away)
+			case g.Blocking == 0:
+				fmt.Printf("   👻 %s: Waiting on %d PRs (>48h) - optional request%s\n", g.Name, count, away)
+			default:
+				fmt.Printf("   🚨 %s: Blocking %d PRs (>48h) - required CODEOWNER on %d, optional on %d%s\n", g.Name, count, g.Blocking, g.Optional, away)
+			}
+		}
+	}
+
+	printEngaged(engaged, len(engagedPRs))
 
-	for _, name := range names {
-		count := ghosts[name]
-		fmt.Printf("   👻 %s: Blocking %d PRs (>48h)\n", name, count)
+	if len(excludedAway) > 0 {
+		fmt.Printf("\n   (%d away reviewers excluded.)\n", len(excludedAway))
+	}
+	if owners == nil {
+		fmt.Println("\n   (No CODEOWNERS file found; required vs optional reviewers can't be distinguished.)")
+	}
+}
+
+// engagedSince reports whether reviewer has joined pr's discussion since
+// since without approving or requesting changes: a conversation comment, or
+// a review that only comments. Review thread comments arrive as COMMENTED
+// reviews.
+func engagedSince(pr PullRequest, reviewer string, since time.Time) bool {
+	for _, c := range pr.Comments {
+		if strings.EqualFold(c.Author, reviewer) && !c.At.Before(since) {
+			return true
+		}
+	}
+	for _, r := range pr.Reviews {
+		if strings.EqualFold(r.Author, reviewer) && r.State == "COMMENTED" && !r.CreatedAt.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// printEngaged lists the requested reviewers left out of the ghosts because
+// they are discussing the PR, with the number of PRs each.
+func printEngaged(engaged map[string]int, prs int) {
+	if len(engaged) == 0 {
+		return
+	}
+	fmt.Println("\n   💬 Engaged but not approved (commented since the request, no approval or change request yet):")
+	if privacy == privacyAggregate {
+		fmt.Printf("      %d reviewers on %d PRs\n", len(engaged), prs)
+		return
+	}
+	names := make([]string, 0, len(engaged))
+	for n := range engaged {
+		names = append(names, n)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if engaged[names[i]] != engaged[names[j]] {
+			return engaged[names[i]] > engaged[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for _, n := range names {
+		fmt.Printf("      %s: %d PRs\n", n, engaged[n])
 	}
 }
 
@@ -473,15 +1603,13 @@ func printGeneralStats(prs []PullRequest) {
 	}
 
 	fmt.Println("📊 GENERAL STATISTICS")
-	fmt.Println("   • Concept: Measures the total lifecycle of a Pull Request from creation to merge.")
-	fmt.Println("   • Why:     High average vs median indicates outliers dragging the team down. This is your baseline velocity.")
-	fmt.Println("")
+	printExplanation("Measures the total lifecycle of a Pull Request from creation to merge.", "High average vs median indicates outliers dragging the team down. This is your baseline velocity.")
 
 	fmt.Printf("   Count:   %d\n", len(prs))
-	fmt.Printf("   Average: %s\n", humanizeDuration(avg))
-	fmt.Printf("   Median:  %s\n", humanizeDuration(median))
-	fmt.Printf("   Min:     %s\n", humanizeDuration(durations[0]))
-	fmt.Printf("   Max:     %s\n", humanizeDuration(durations[len(durations)-1]))
+	fmt.Printf("   Average: %s\n", formatDuration(avg))
+	fmt.Printf("   Median:  %s\n", formatDuration(median))
+	fmt.Printf("   Min:     %s\n", formatDuration(durations[0]))
+	fmt.Printf("   Max:     %s\n", formatDuration(durations[len(durations)-1]))
 }
 
 func printReviewStats(prs []PullRequest) {
@@ -506,49 +1634,28 @@ func printReviewStats(prs []PullRequest) {
 	}
 
 	fmt.Println("🚦 REVIEW EFFICIENCY")
-	fmt.Println("   • Concept: Splits time into 'Waiting for Review' vs 'Active Review Process'.")
-	fmt.Println("   • Why:     Helps distinguish between a Triage problem (ignoring PRs) and a Complexity problem (hard to approve).")
-	fmt.Println("")
+	printExplanation("Splits time into 'Waiting for Review' vs 'Active Review Process'.", "Helps distinguish between a Triage problem (ignoring PRs) and a Complexity problem (hard to approve).")
 
 	if countWait == 0 {
 		fmt.Println("   No reviews detected (Direct merges?).")
 	} else {
 		avgWait := totalWait / time.Duration(countWait)
 		avgReview := totalReview / time.Duration(countReview)
-		fmt.Printf("   Avg Time to First Review:   %s (Triage Speed)\n", humanizeDuration(avgWait))
-		fmt.Printf("   Avg Review to Merge:        %s (Coding/Fixing Speed)\n", humanizeDuration(avgReview))
+		fmt.Printf("   Avg Time to First Review:   %s (Triage Speed)\n", formatDuration(avgWait))
+		fmt.Printf("   Avg Review to Merge:        %s (Coding/Fixing Speed)\n", formatDuration(avgReview))
+	}
+	if note := firstReviewNote(); note != "" {
+		fmt.Printf("   (%s.)\n", note)
 	}
 }
 
 func printSizeAnalysis(prs []PullRequest) {
 	fmt.Println("📐 SIZE vs SPEED ANALYSIS")
-	fmt.Println("   • Concept: Correlation between lines of code changed and merge duration.")
-	fmt.Println("   • Why:     Determines if 'Big PRs' are the bottleneck or if the process is slow regardless of size.")
-	fmt.Println("")
-
-	var sumX, sumY, sumXY, sumX2, sumY2 float64
-	n := float64(len(prs))
-
-	for _, pr := range prs {
-		size := float64(pr.Size)                                   // X (Lines changed)
-		duration := float64(pr.MergedAt.Sub(pr.CreatedAt).Hours()) // Y (Hours)
-
-		sumX += size
-		sumY += duration
-		sumXY += size * duration
-		sumX2 += size * size
-		sumY2 += duration * duration
-	}
+	printExplanation("Correlation between lines of code changed and merge duration.", "Determines if 'Big PRs' are the bottleneck or if the process is slow regardless of size.")
 
-	numerator := n*sumXY - sumX*sumY
-	denominator := math.Sqrt((n*sumX2 - sumX*sumX) * (n*sumY2 - sumY*sumY))
+	correlation := sizeCorrelation(prs)
 
-	correlation := 0.0
-	if denominator != 0 {
-		correlation = numerator / denominator
-	}
-
-	fmt.Printf("   Correlation Coeff: %.2f  (Range: -1.0 to +1.0)\n", correlation)
+	fmt.Printf("   Correlation Coeff: %s  (Range: -1.0 to +1.0)\n", formatNumber(correlation, 2))
 
 	if correlation > 0.5 {
 		fmt.Println("   🚨 RESULT: Strong Positive Correlation (> 0.5)")
@@ -563,13 +1670,24 @@ func printSizeAnalysis(prs []PullRequest) {
 		fmt.Println("      Insight: Small PRs are getting stuck just as often as huge ones.")
 		fmt.Println("      Action:  Your bottleneck is likely PROCESS (Triage/CI/Availability), not code size.")
 	}
+
+	printComplexityBands(prs, correlation)
+	printSizeTrend(prs)
 }
 
-func printHotspots(prs []PullRequest) {
-	fmt.Println("🔥 DIRECTORY HOTSPOTS (Avg Merge Time)")
-	fmt.Println("   • Concept: Average merge time grouped by root directory.")
-	fmt.Println("   • Why:     Identifies parts of the codebase that are 'swamps'—hard to review, prone to debate, or lacking owners.")
-	fmt.Println("")
+// sizeCorrelation is the Pearson correlation between lines changed and hours to merge.
+func sizeCorrelation(prs []PullRequest) float64 {
+	var xs, ys []float64
+	for _, pr := range prs {
+		xs = append(xs, float64(pr.Size))                      // X (Lines changed)
+		ys = append(ys, pr.MergedAt.Sub(pr.CreatedAt).Hours()) // Y (Hours)
+	}
+	return pearson(xs, ys)
+}
+
+func printHotspots(prs []PullRequest, depth int) {
+	fmt.Printf("🔥 %s HOTSPOTS (Avg Merge Time)\n", depthLabel(depth))
+	printExplanation("Average merge time grouped by root directory (or configured service).", "Identifies parts of the codebase that are 'swamps'—hard to review, prone to debate, or lacking owners.")
 
 	type DirStat struct {
 		TotalDuration time.Duration
@@ -582,11 +1700,7 @@ func printHotspots(prs []PullRequest) {
 		duration := pr.MergedAt.Sub(pr.CreatedAt)
 
 		for _, path := range pr.FilePaths {
-			parts := strings.Split(path, "/")
-			root := parts[0]
-			if len(parts) == 1 {
-				root = "(root files)"
-			}
+			root := groupAt(path, depth)
 
 			if !seenDirs[root] {
 				if _, exists := stats[root]; !exists {
@@ -612,134 +1726,107 @@ func printHotspots(prs []PullRequest) {
 		}
 		s := stats[d]
 		avg := s.TotalDuration / time.Duration(s.Count)
-		fmt.Printf("   %-20s: %s (avg over %d PRs)\n", d, humanizeDuration(avg), s.Count)
+		fmt.Printf("   %-20s: %s (avg over %d PRs)\n", d, formatDuration(avg), s.Count)
 	}
 }
 
-func printLongTailAuthors(prs []PullRequest) {
-	fmt.Println("🐌 LONG TAIL CONTRIBUTORS (Handling the Slowest 10%)")
-	fmt.Println("   • Concept: Authors frequently found in the slowest 10% of merges.")
-	fmt.Println("   • Why:     These devs might be tackling the hardest problems, or they need help breaking down tasks. Prevents burnout.")
-	fmt.Println("")
-
-	sortedPRs := make([]PullRequest, len(prs))
-	copy(sortedPRs, prs)
-	sort.Slice(sortedPRs, func(i, j int) bool { return sortedPRs[i].MergedAt.Sub(sortedPRs[i].CreatedAt) > sortedPRs[j].MergedAt.Sub(sortedPRs[j].CreatedAt) })
-
-	limit := len(prs) / 10
-	if limit == 0 {
-		limit = 1
-	}
-	slowest := sortedPRs[:limit]
-
-	authorCounts := make(map[string]int)
-	for _, pr := range slowest {
-		authorCounts[pr.Author]++
+// rootDir returns the top-level directory of a file path, bucketing files
+// at the repository root together.
+func rootDir(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) == 1 {
+		return "(root files)"
 	}
-
-	var authors []string
-	for a := range authorCounts {
-		authors = append(authors, a)
-	}
-	sort.Slice(authors, func(i, j int) bool { return authorCounts[authors[i]] > authorCounts[authors[j]] })
-
-	for i, a := range authors {
-		if i >= 5 {
-			break
-		}
-		fmt.Printf("   %-15s: %d slow PRs\n", a, authorCounts[a])
-	}
-	fmt.Println("   (Note: These authors might be tackling the hardest complexity, not working slowly.)")
+	return parts[0]
 }
 
-func printTrends(prs []PullRequest) {
+func printTrends(months []monthStat) {
 	fmt.Println("📈 MONTHLY TRENDS")
-	fmt.Println("   • Concept: Monthly average merge times over the requested period.")
-	fmt.Println("   • Why:     Spot if the team is getting faster (🚀) or bogging down (🐢) over time.")
-	fmt.Println("")
+	printExplanation("Monthly average merge times over the requested period.", "Spot if the team is getting faster (🚀) or bogging down (🐢) over time.")
 
-	type MonthStats struct {
-		TotalDuration time.Duration
-		Count         int
-	}
-	stats := make(map[string]*MonthStats)
-	var months []string
-
-	for _, pr := range prs {
-		m := pr.MergedAt.Format("2006-01")
-		if _, exists := stats[m]; !exists {
-			stats[m] = &MonthStats{}
-			months = append(months, m)
-		}
-		stats[m].TotalDuration += pr.MergedAt.Sub(pr.CreatedAt)
-		stats[m].Count++
-	}
-
-	sort.Strings(months)
+	// With two years of months, arrows compare seasonally adjusted merge
+	// times so the December dip doesn't read as a trend
+	d, adjusted := decomposeMonths(months)
 
 	var prevAvg time.Duration
-	for _, m := range months {
-		s := stats[m]
-		avg := s.TotalDuration / time.Duration(s.Count)
+	stored, partial := 0, 0
+	for _, s := range months {
+		avg := s.Avg()
+		compared := avg
+		if adjusted {
+			compared -= d.Effect(s.Month)
+		}
 
 		trend := ""
 		if prevAvg != 0 {
-			if avg < prevAvg {
+			if compared < prevAvg {
 				trend = "🚀"
-			} else if avg > prevAvg {
+			} else if compared > prevAvg {
 				trend = "🐢"
 			} else {
 				trend = "➖"
 			}
 		}
-		prevAvg = avg
-		fmt.Printf("   %s: %-15s (%2d PRs) %s\n", m, humanizeDuration(avg), s.Count, trend)
+		prevAvg = compared
+		mark := ""
+		if s.Stored {
+			mark = "*"
+			stored++
+		}
+		note := ""
+		if s.Partial != "" {
+			note = " ◐ " + s.Partial
+			partial++
+		}
+		fmt.Printf("   %s%-2s %-15s (%2d PRs) %-9s %s%s\n", formatMonthKey(s.Month), mark+":", formatDuration(avg), s.Count, "M:F "+maintenanceRatio(s.Maintenance, s.Features), trend, note)
+	}
+	printMaintenanceTrend(months)
+	if adjusted {
+		fmt.Println("   (Arrows compare seasonally adjusted merge times; see SEASONALITY.)")
+	}
+	if stored > 0 {
+		fmt.Printf("\n   (* %d complete months from `bottleneck backfill`.)\n", stored)
+	}
+	if partial > 0 {
+		fmt.Println("   (◐ Partial months mix fewer, often faster, PRs. Treat their arrows with care, or hide them with --exclude-partial-months.)")
 	}
 }
 
-func printForecast(prs []PullRequest) {
+func printForecast(months []monthStat, merged, open []PullRequest) {
 	fmt.Println("🔮 FORECAST (Next 30 Days)")
-	fmt.Println("   • Concept: A 3-month moving average projection of merge times.")
-	fmt.Println("   • Why:     Predicts where your velocity is heading if current habits continue.")
-	fmt.Println("")
-
-	type MonthStat struct {
-		Total time.Duration
-		Count int
-	}
-	stats := make(map[string]*MonthStat)
-	var months []string
-
-	for _, pr := range prs {
-		m := pr.MergedAt.Format("2006-01")
-		if _, exists := stats[m]; !exists {
-			stats[m] = &MonthStat{}
-			months = append(months, m)
-		}
-		stats[m].Total += pr.MergedAt.Sub(pr.CreatedAt)
-		stats[m].Count++
-	}
-	sort.Strings(months)
+	printExplanation("A 3-month moving average of merge times, weighted by the number of PRs merged each month, next to weekly merge throughput and open backlog growth.",
+		"Predicts where your velocity is heading if current habits continue. Cycle time alone can look stable while the queue explodes.")
 
 	if len(months) < 3 {
-		fmt.Println("   (Not enough data for a reliable forecast. Need 3+ months.)")
-		return
+		fmt.Println("   (Not enough data for a reliable merge time forecast. Need 3+ months.)")
+	} else {
+		printCycleForecast(months)
 	}
+	printThroughputForecast(merged, open)
+}
 
+// printCycleForecast predicts merge time from the last three months.
+func printCycleForecast(months []monthStat) {
 	last3 := months[len(months)-3:]
-	var totalAvg time.Duration
+	var total time.Duration
+	count := 0
 
 	fmt.Println("   Based on last 3 months:")
 	for _, m := range last3 {
-		s := stats[m]
-		avg := s.Total / time.Duration(s.Count)
-		totalAvg += avg
-		fmt.Printf("   - %s: %s\n", m, humanizeDuration(avg))
+		total += m.Total
+		count += m.Count
+		note := ""
+		if m.Partial != "" {
+			note = " ◐ " + m.Partial
+		}
+		fmt.Printf("   - %s: %s (%d PRs)%s\n", formatMonthKey(m.Month), formatDuration(m.Avg()), m.Count, note)
 	}
 
-	forecast := totalAvg / 3
-	first := stats[last3[0]].Total / time.Duration(stats[last3[0]].Count)
-	last := stats[last3[2]].Total / time.Duration(stats[last3[2]].Count)
+	// Pooled mean: every PR counts once, so a thin month doesn't weigh as
+	// much as a full one
+	forecast := total / time.Duration(count)
+	first := last3[0].Avg()
+	last := last3[2].Avg()
 
 	trendEmoji := "➡️"
 	trendText := "Stable"
@@ -755,49 +1842,45 @@ func printForecast(prs []PullRequest) {
 		trendText = "Speeding Up"
 	}
 
-	fmt.Printf("\n   🎯 PREDICTION: ~%s / PR\n", humanizeDuration(forecast))
+	fmt.Printf("\n   🎯 PREDICTION: ~%s / PR\n", formatDuration(forecast))
 	fmt.Printf("   🏁 TREND:      %s %s\n", trendEmoji, trendText)
 }
 
 func printHistogram(prs []PullRequest) {
 	fmt.Println("📊 MERGE TIME DISTRIBUTION")
-	fmt.Println("   • Concept: Distribution of merge times into buckets.")
-	fmt.Println("   • Why:     Averages lie. This reveals the 'long tail' of stuck PRs that frustrate the team.")
-	fmt.Println("")
+	printExplanation("Distribution of merge times into buckets, with the cumulative share merged by each bound.", "Averages lie. This reveals the 'long tail' of stuck PRs that frustrate the team.")
 
-	buckets := []struct {
-		Label string
-		Max   time.Duration
-		Count int
-	}{
-		{"< 1h", time.Hour, 0},
-		{"1h - 1d", 24 * time.Hour, 0},
-		{"1d - 1w", 7 * 24 * time.Hour, 0},
-		{"1w - 1mo", 30 * 24 * time.Hour, 0},
-		{"> 1mo", time.Duration(math.MaxInt64), 0},
+	var durations []time.Duration
+	for _, pr := range prs {
+		durations = append(durations, pr.MergedAt.Sub(pr.CreatedAt))
 	}
+	printDurationHistogram(durations, "%s%% merge within %s.")
+}
 
-	maxCount := 0
+func printFirstReviewHistogram(prs []PullRequest) {
+	fmt.Println("⏱️  TIME TO FIRST REVIEW DISTRIBUTION")
+	printExplanation("Distribution of the wait from PR creation to the first review.", "Triage latency is the most common bottleneck. An average hides whether most PRs wait minutes or days.")
+
+	var durations []time.Duration
 	for _, pr := range prs {
-		d := pr.MergedAt.Sub(pr.CreatedAt)
-		for i := range buckets {
-			if d < buckets[i].Max {
-				buckets[i].Count++
-				if buckets[i].Count > maxCount {
-					maxCount = buckets[i].Count
-				}
-				break
-			}
+		if pr.FirstReviewAt == nil {
+			continue
 		}
+		wait := pr.FirstReviewAt.Sub(pr.CreatedAt)
+		if wait < 0 {
+			wait = 0
+		}
+		durations = append(durations, wait)
+	}
+	if len(durations) == 0 {
+		fmt.Println("   No reviews detected (Direct merges?).")
+		return
 	}
 
-	for _, b := range buckets {
-		barLen := 0
-		if maxCount > 0 {
-			barLen = (b.Count * 20) / maxCount
-		}
-		bar := strings.Repeat("■", barLen)
-		fmt.Printf("   %-10s : %-20s (%d)\n", b.Label, bar, b.Count)
+	fmt.Printf("   Median: %s   P90: %s\n\n", formatDuration(percentile(durations, 50)), formatDuration(percentile(durations, 90)))
+	printDurationHistogram(durations, "%s%% get a first review within %s.")
+	if unreviewed := len(prs) - len(durations); unreviewed > 0 {
+		fmt.Printf("   (%d PRs merged without any review are not included.)\n", unreviewed)
 	}
 }
 
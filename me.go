@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fetchViewer returns the login of the authenticated user.
+func fetchViewer(ctx context.Context, timeout time.Duration) (string, error) {
+	output, err := ghGraphQL(ctx, "query { viewer { login } }", timeout)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Data struct {
+			Viewer struct {
+				Login string `json:"login"`
+			} `json:"viewer"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return "", err
+	}
+	if resp.Data.Viewer.Login == "" {
+		return "", fmt.Errorf("no authenticated user")
+	}
+	return resp.Data.Viewer.Login, nil
+}
+
+// printPersonalStats prints login's own numbers next to the team medians.
+// Nobody else is named: other people's PRs and reviews only feed the
+// medians.
+func printPersonalStats(login string, merged, open []PullRequest, responseSLA time.Duration, now time.Time) {
+	fmt.Printf("🙋 YOUR REVIEW NUMBERS (%s)\n", login)
+	printExplanation("Your PRs' wait times, your response time to review requests and the requests waiting on you, next to team medians.",
+		"Self-serve insight into your own flow. Only your numbers are shown, never a teammate's.")
+
+	var mine []PullRequest
+	for _, pr := range merged {
+		if strings.EqualFold(pr.Author, login) {
+			mine = append(mine, pr)
+		}
+	}
+	you, team := computeMetrics(mine), computeMetrics(merged)
+	fmt.Println("   Your merged PRs")
+	if len(mine) == 0 {
+		fmt.Println("      None in the fetched window.")
+	} else {
+		fmt.Printf("      %-26s %-14s %s\n", "", "You", "Whole team (median)")
+		fmt.Printf("      %-26s %-14d %d\n", "PRs merged", you.Count, team.Count)
+		fmt.Printf("      %-26s %-14s %s\n", "Time to first review", formatDuration(you.MedianFirstReview), formatDuration(team.MedianFirstReview))
+		fmt.Printf("      %-26s %-14s %s\n", "Cycle time", formatDuration(you.MedianCycleTime), formatDuration(team.MedianCycleTime))
+		fmt.Printf("      %-26s %-14s %s\n", "Review rounds (avg)", formatNumber(you.AvgRounds, 1), formatNumber(team.AvgRounds, 1))
+		fmt.Printf("      %-26s %-14s %s\n", "PR size (lines)", formatInt(you.MedianSize), formatInt(team.MedianSize))
+	}
+
+	fmt.Println("\n   Your reviews")
+	responses := responseTimes(merged, nil)
+	var yours []time.Duration
+	var everyone []time.Duration
+	for reviewer, d := range responses {
+		if strings.EqualFold(reviewer, login) {
+			yours = d
+		}
+		everyone = append(everyone, percentile(d, 50))
+	}
+	if len(yours) == 0 {
+		fmt.Println("      No reviews in the fetched window.")
+	} else {
+		within := 0
+		for _, d := range yours {
+			if d <= responseSLA {
+				within++
+			}
+		}
+		fmt.Printf("      Reviews given: %d. Median response to a request: %s (median reviewer: %s)\n", len(yours), formatDuration(percentile(yours, 50)), formatDuration(percentile(everyone, 50)))
+		fmt.Printf("      Within %s: %.0f%%\n", formatDuration(responseSLA), float64(within)/float64(len(yours))*100)
+	}
+
+	type waiting struct {
+		PR    PullRequest
+		Since time.Time
+	}
+	var requests []waiting
+	var authored []PullRequest
+	for _, pr := range open {
+		if strings.EqualFold(pr.Author, login) {
+			authored = append(authored, pr)
+		}
+		for _, r := range pr.Requested {
+			if strings.EqualFold(r, login) {
+				requests = append(requests, waiting{pr, requestedAt(pr, r, now)})
+				break
+			}
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Since.Before(requests[j].Since) })
+	fmt.Printf("\n   Waiting on you: %d review requests\n", len(requests))
+	for _, w := range requests {
+		fmt.Printf("      #%-6d %-45s requested %s ago\n", w.PR.Number, limitString(w.PR.Title, 45), formatDuration(now.Sub(w.Since)))
+	}
+
+	sort.Slice(authored, func(i, j int) bool { return authored[i].CreatedAt.Before(authored[j].CreatedAt) })
+	fmt.Printf("\n   Your open PRs: %d\n", len(authored))
+	for _, pr := range authored {
+		fmt.Printf("      #%-6d %-45s %-22s open %s\n", pr.Number, limitString(pr.Title, 45), openStatus(pr, now), formatDuration(now.Sub(pr.CreatedAt)))
+	}
+}
+
+func runMe(args []string) {
+	fs := flag.NewFlagSet("me", flag.ExitOnError)
+	limit := fs.Int("limit", 300, "Max number of merged PRs to fetch per repository")
+	responseSLA := fs.Duration("response-sla", 24*time.Hour, "First-response target for your reviews")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck me [flags] <owner/repo> [owner/repo...]")
+		fmt.Println("Shows the authenticated user's own numbers only.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	for _, repo := range fs.Args() {
+		if _, _, err := parseRepo(repo); err != nil {
+			slog.Error("invalid repository", "repo", repo, "err", err)
+			os.Exit(1)
+		}
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	login, err := fetchViewer(ctx, *reqTimeout)
+	if err != nil {
+		slog.Error("finding the authenticated user", "err", err)
+		os.Exit(1)
+	}
+	login = canonicalLogin(login) // Your other accounts count as you
+	for i, repo := range fs.Args() {
+		owner, name, _ := parseRepo(repo)
+		slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+		merged, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+		if err != nil {
+			slog.Warn("continuing with partial data", "repo", repo, "fetched", len(merged), "err", err)
+		}
+		slog.Info("fetching open PRs", "repo", repo, "limit", 100)
+		open, err := fetchPRs(ctx, owner, name, 100, "OPEN", *reqTimeout, *reqDelay)
+		if err != nil {
+			slog.Warn("continuing with partial data", "repo", repo, "fetched", len(open), "err", err)
+		}
+		if fs.NArg() > 1 {
+			if i > 0 {
+				fmt.Println(strings.Repeat("-", 60))
+			}
+			fmt.Printf("📦 %s\n", repo)
+		}
+		printPersonalStats(login, merged, open, *responseSLA, clock())
+	}
+}
+
+// printMyGhosts lists login's open PRs with reviewers requested over 48h
+// ago who haven't responded. It reports whether anything was printed.
+func printMyGhosts(login string, open []PullRequest, now time.Time) bool {
+	type ghost struct {
+		PR       PullRequest
+		Reviewer string
+		Since    time.Time
+	}
+	var ghosts []ghost
+	for _, pr := range open {
+		if !strings.EqualFold(pr.Author, login) {
+			continue
+		}
+		for _, r := range pr.Requested {
+			if since := requestedAt(pr, r, now); now.Sub(since) > 48*time.Hour && !engagedSince(pr, r, since) {
+				ghosts = append(ghosts, ghost{pr, r, since})
+			}
+		}
+	}
+	if len(ghosts) == 0 {
+		return false
+	}
+	sort.Slice(ghosts, func(i, j int) bool { return ghosts[i].Since.Before(ghosts[j].Since) })
+
+	fmt.Printf("👻 YOUR GHOSTS (%s)\n", login)
+	printExplanation("Reviewers requested on your open PRs over 48h ago who haven't responded.",
+		"A nudge or a different reviewer unblocks you faster than waiting.")
+	for _, g := range ghosts {
+		fmt.Printf("   #%-6d %-45s %-20s silent %s\n", g.PR.Number, limitString(g.PR.Title, 45), person(g.Reviewer), formatDuration(now.Sub(g.Since)))
+	}
+	return true
+}
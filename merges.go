@@ -0,0 +1,354 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// squashSuffix is the "(#123)" GitHub appends to squash commit titles.
+var squashSuffix = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+
+var mergeMethods = []string{"squash", "rebase", "merge"}
+
+// mergeMethod infers how a PR was merged from its merge commit. Two parents
+// mean a merge commit. A squash commit's title ends in the PR number, as
+// GitHub writes it; a rebase merge keeps the last commit's title as it was.
+// A squash whose title was edited to drop the number reads as a rebase.
+func mergeMethod(parents int, headline string, number int) string {
+	if parents > 1 {
+		return "merge"
+	}
+	if m := squashSuffix.FindStringSubmatch(headline); m != nil && m[1] == fmt.Sprint(number) {
+		return "squash"
+	}
+	return "rebase"
+}
+
+// approvers returns who approved pr, excluding its author.
+func approvers(pr PullRequest) map[string]bool {
+	out := make(map[string]bool)
+	for _, r := range pr.Reviews {
+		if r.State == "APPROVED" && r.Author != "" && r.Author != pr.Author {
+			out[r.Author] = true
+		}
+	}
+	return out
+}
+
+func printMergeAuthority(prs []PullRequest) {
+	fmt.Println("🔀 MERGE AUTHORITY")
+	printExplanation("Who presses the merge button, and whether that's the author, an approver or someone else.",
+		"A single person merging everything is a second kind of hero: when they're out, approved PRs pile up.")
+
+	type merger struct {
+		Name     string
+		Merges   int
+		Own      int // Merged their own PR
+		Approver int // Had approved the PR they merged
+	}
+	mergers := make(map[string]*merger)
+	total, own, approver, unapproved, bots := 0, 0, 0, 0, 0
+	for _, pr := range prs {
+		if pr.MergedBy == "" {
+			continue
+		}
+		if isBot(pr.MergedBy) {
+			// A merge bot or queue isn't a person who can be out
+			bots++
+			continue
+		}
+		total++
+		key := pr.MergedBy
+		if privacy == privacyTeam {
+			key = person(pr.MergedBy)
+		}
+		m := mergers[key]
+		if m == nil {
+			m = &merger{Name: key}
+			mergers[key] = m
+		}
+		m.Merges++
+		approved := approvers(pr)
+		switch {
+		case pr.MergedBy == pr.Author:
+			m.Own++
+			own++
+		case approved[pr.MergedBy]:
+			m.Approver++
+			approver++
+		}
+		if len(approved) == 0 {
+			unapproved++
+		}
+	}
+	if total == 0 {
+		if bots > 0 {
+			fmt.Printf("   All %d merges were made by bots.\n", bots)
+			return
+		}
+		fmt.Println("   No merger information in this dataset.")
+		return
+	}
+
+	var list []*merger
+	for _, m := range mergers {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Merges != list[j].Merges {
+			return list[i].Merges > list[j].Merges
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	pct := func(n, of int) float64 { return float64(n) / float64(of) * 100 }
+	fmt.Printf("   Merged by: author %.0f%%, an approver %.0f%%, someone else %.0f%%\n",
+		pct(own, total), pct(approver, total), pct(total-own-approver, total))
+	fmt.Printf("   Merged without any approval: %d (%.0f%%)\n", unapproved, pct(unapproved, total))
+	if bots > 0 {
+		fmt.Printf("   Merged by bots (left out): %d\n", bots)
+	}
+	fmt.Println()
+
+	top := list[0]
+	share := pct(top.Merges, total)
+	if privacy == privacyAggregate {
+		fmt.Printf("   Mergers: %d   Busiest merger: %.0f%% of merges\n\n", len(list), share)
+		switch {
+		case len(list) == 1:
+			fmt.Println("   🚨 CRITICAL: One person merges every PR.")
+		case share > 50:
+			fmt.Printf("   🚨 CRITICAL: One person merges %.0f%% of PRs. Let authors merge once approved.\n", share)
+		case share > 30:
+			fmt.Printf("   ⚠️  One person merges %.0f%% of PRs. Consider spreading merge rights.\n", share)
+		default:
+			fmt.Println("   ✅ Merge authority is spread across the team.")
+		}
+		return
+	}
+
+	fmt.Printf("   %-20s %7s %7s   %-8s %s\n", "Merger", "Merges", "Share", "Own PRs", "Approved first")
+	for i, m := range list {
+		if i == 10 {
+			fmt.Printf("   ... and %d more\n", len(list)-10)
+			break
+		}
+		fmt.Printf("   %-20s %7d %6.0f%%   %-8d %d\n", limitString(m.Name, 17), m.Merges, pct(m.Merges, total), m.Own, m.Approver)
+	}
+
+	fmt.Println()
+	switch {
+	case len(list) == 1:
+		fmt.Printf("   🚨 CRITICAL: %s merges every PR.\n", top.Name)
+	case share > 50:
+		fmt.Printf("   🚨 CRITICAL: %s merges %.0f%% of PRs. Let authors merge once approved.\n", top.Name, share)
+	case share > 30:
+		fmt.Printf("   ⚠️  %s merges %.0f%% of PRs. Consider spreading merge rights.\n", top.Name, share)
+	default:
+		fmt.Println("   ✅ Merge authority is spread across the team.")
+	}
+}
+
+// approvalToMerge returns the time from the last approval before merge to
+// the merge itself.
+func approvalToMerge(pr PullRequest) (time.Duration, bool) {
+	var last time.Time
+	for _, r := range pr.Reviews {
+		if r.State == "APPROVED" && r.Author != pr.Author && !r.CreatedAt.After(pr.MergedAt) && r.CreatedAt.After(last) {
+			last = r.CreatedAt
+		}
+	}
+	if last.IsZero() {
+		return 0, false
+	}
+	return pr.MergedAt.Sub(last), true
+}
+
+func printAutoMerge(prs []PullRequest) {
+	fmt.Println("🤖 AUTO-MERGE ADOPTION")
+	printExplanation("Share of PRs merged by GitHub auto-merge, and their approval-to-merge gap compared with manual merges.",
+		"Approved PRs waiting for someone to come back and press merge is pure idle time. Auto-merge should remove it.")
+
+	var auto, manual []time.Duration
+	autoCount := 0
+	type month struct{ Auto, Total int }
+	months := make(map[string]*month)
+	for _, pr := range prs {
+		key := pr.MergedAt.Format("2006-01")
+		if months[key] == nil {
+			months[key] = &month{}
+		}
+		months[key].Total++
+		isAuto := pr.AutoMergeAt != nil && !pr.AutoMergeAt.After(pr.MergedAt)
+		if isAuto {
+			autoCount++
+			months[key].Auto++
+		}
+		gap, ok := approvalToMerge(pr)
+		if !ok {
+			continue
+		}
+		if isAuto {
+			auto = append(auto, gap)
+		} else {
+			manual = append(manual, gap)
+		}
+	}
+	if len(prs) == 0 {
+		return
+	}
+
+	fmt.Printf("   Auto-merged: %d of %d PRs (%.0f%%)\n\n", autoCount, len(prs), float64(autoCount)/float64(len(prs))*100)
+	if autoCount == 0 {
+		fmt.Println("   No PRs were merged with auto-merge.")
+		fmt.Println("   Action: Enable \"Allow auto-merge\" in the repository settings and encourage authors to use it.")
+		return
+	}
+
+	fmt.Printf("   %-8s %6s   %-16s %s\n", "Merge", "PRs", "Median gap", "P90 gap")
+	for _, g := range []struct {
+		Name string
+		Gaps []time.Duration
+	}{{"Auto", auto}, {"Manual", manual}} {
+		if len(g.Gaps) == 0 {
+			fmt.Printf("   %-8s %6d   %-16s %s\n", g.Name, 0, "-", "-")
+			continue
+		}
+		fmt.Printf("   %-8s %6d   %-16s %s\n", g.Name, len(g.Gaps), formatDuration(percentile(g.Gaps, 50)), formatDuration(percentile(g.Gaps, 90)))
+	}
+	fmt.Println("   (Gap: last approval → merge. PRs merged without approval are left out.)")
+
+	var keys []string
+	for k := range months {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Println("\n   Adoption by month:")
+	for _, k := range keys {
+		m := months[k]
+		share := float64(m.Auto) / float64(m.Total) * 100
+		fmt.Printf("   %-10s %5.0f%% %s (%d of %d)\n", formatMonthKey(k), share, strings.Repeat("█", int(share/5)), m.Auto, m.Total)
+	}
+
+	if len(auto) > 0 && len(manual) > 0 {
+		saved := percentile(manual, 50) - percentile(auto, 50)
+		if saved > 0 {
+			fmt.Printf("\n   💡 Auto-merge cuts the median approval-to-merge gap by %s.\n", formatDuration(saved))
+		}
+	}
+}
+
+func printMergeMethods(prs []PullRequest) {
+	fmt.Println("🧬 MERGE METHODS")
+	printExplanation("Merge time and revert rate of PRs by how they were merged (squash, rebase or merge commit), and each method's share by month.",
+		"Teams moving to squash-only want to know whether it changed anything. The method is inferred from the merge commit's parents and title.")
+
+	type group struct {
+		Cycle    []time.Duration
+		Reverted int
+	}
+	groups := make(map[string]*group)
+	months := make(map[string]map[string]int)
+	reverted := revertedPRs(prs)
+	known := 0
+	for _, pr := range prs {
+		if pr.MergeMethod == "" {
+			continue
+		}
+		known++
+		g := groups[pr.MergeMethod]
+		if g == nil {
+			g = &group{}
+			groups[pr.MergeMethod] = g
+		}
+		g.Cycle = append(g.Cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if reverted[pr.Number] {
+			g.Reverted++
+		}
+		key := pr.MergedAt.In(primaryLocation).Format("2006-01")
+		if months[key] == nil {
+			months[key] = make(map[string]int)
+		}
+		months[key][pr.MergeMethod]++
+	}
+	if known == 0 {
+		fmt.Println("   No merge commits in this dataset.")
+		return
+	}
+
+	fmt.Printf("   %-8s %5s %6s   %-14s %-14s %s\n", "Method", "PRs", "Share", "Median Merge", "P90 Merge", "Reverted")
+	for _, m := range mergeMethods {
+		g := groups[m]
+		if g == nil {
+			fmt.Printf("   %-8s %5d %6s   %-14s %-14s %s\n", m, 0, "-", "-", "-", "-")
+			continue
+		}
+		n := len(g.Cycle)
+		fmt.Printf("   %-8s %5d %5.0f%%   %-14s %-14s %d (%s%%)\n", m, n, float64(n)/float64(known)*100,
+			formatDuration(percentile(g.Cycle, 50)), formatDuration(percentile(g.Cycle, 90)), g.Reverted, formatNumber(float64(g.Reverted)/float64(n)*100, 1))
+	}
+	if unknown := len(prs) - known; unknown > 0 {
+		fmt.Printf("   (%d PRs without merge commit data left out.)\n", unknown)
+	}
+
+	var keys []string
+	for k := range months {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("\n   %-22s%6s %6s %6s\n", "Adoption by month:", "squash", "rebase", "merge")
+	dominant := func(k string) string {
+		best := ""
+		for _, m := range mergeMethods {
+			if best == "" || months[k][m] > months[k][best] {
+				best = m
+			}
+		}
+		return best
+	}
+	for _, k := range keys {
+		total := 0
+		for _, n := range months[k] {
+			total += n
+		}
+		share := func(m string) string { return fmt.Sprintf("%5.0f%%", float64(months[k][m])/float64(total)*100) }
+		fmt.Printf("   %-10s %4d PRs   %6s %6s %6s\n", formatMonthKey(k), total, share("squash"), share("rebase"), share("merge"))
+	}
+
+	// The month the current main method took over, and merge time and
+	// reverts either side of it
+	latest := dominant(keys[len(keys)-1])
+	since := len(keys) - 1
+	for since > 0 && dominant(keys[since-1]) == latest {
+		since--
+	}
+	if since == 0 {
+		fmt.Printf("\n   %s has been the main method throughout.\n", latest)
+		return
+	}
+	var before, after []time.Duration
+	revBefore, revAfter := 0, 0
+	for _, pr := range prs {
+		if pr.MergeMethod == "" {
+			continue
+		}
+		if pr.MergedAt.In(primaryLocation).Format("2006-01") < keys[since] {
+			before = append(before, pr.MergedAt.Sub(pr.CreatedAt))
+			if reverted[pr.Number] {
+				revBefore++
+			}
+		} else {
+			after = append(after, pr.MergedAt.Sub(pr.CreatedAt))
+			if reverted[pr.Number] {
+				revAfter++
+			}
+		}
+	}
+	fmt.Printf("\n   💡 %s became the main method in %s. Median merge %s before, %s since; reverted %s%% before, %s%% since.\n",
+		latest, formatMonthKey(keys[since]), formatDuration(percentile(before, 50)), formatDuration(percentile(after, 50)),
+		formatNumber(float64(revBefore)/float64(len(before))*100, 1), formatNumber(float64(revAfter)/float64(len(after))*100, 1))
+	fmt.Println("   (Other changes in the same months count too: this is a before/after comparison, not a controlled one.)")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Metrics are the headline numbers of a set of merged PRs.
+type Metrics struct {
+	Count             int
+	MedianCycleTime   time.Duration
+	P90CycleTime      time.Duration
+	MedianFirstReview time.Duration
+	ReviewedPct       float64 // Share of PRs that got at least one review
+	AvgRounds         float64
+	MedianSize        int
+	TopReviewer       string
+	TopReviewerPct    float64 // Share of all reviews done by the top reviewer
+	SizeCorrelation   float64
+}
+
+func computeMetrics(prs []PullRequest) Metrics {
+	m := Metrics{Count: len(prs)}
+	if len(prs) == 0 {
+		return m
+	}
+
+	var cycle, firstReview []time.Duration
+	var sizes []int
+	totalRounds, reviewed := 0, 0
+	for _, pr := range prs {
+		cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		sizes = append(sizes, pr.Size)
+		if pr.FirstReviewAt != nil {
+			wait := pr.FirstReviewAt.Sub(pr.CreatedAt)
+			if wait < 0 {
+				wait = 0
+			}
+			firstReview = append(firstReview, wait)
+		}
+		if r := reviewRounds(pr); r > 0 {
+			totalRounds += r
+			reviewed++
+		}
+	}
+
+	m.MedianCycleTime = percentile(cycle, 50)
+	m.P90CycleTime = percentile(cycle, 90)
+	m.MedianFirstReview = percentile(firstReview, 50)
+	m.ReviewedPct = float64(reviewed) / float64(len(prs)) * 100
+	if reviewed > 0 {
+		m.AvgRounds = float64(totalRounds) / float64(reviewed)
+	}
+	sort.Ints(sizes)
+	m.MedianSize = sizes[len(sizes)/2]
+	m.SizeCorrelation = sizeCorrelation(prs)
+
+	counts, total := countReviews(prs)
+	for name, c := range counts {
+		pct := float64(c) / float64(total) * 100
+		if pct > m.TopReviewerPct || (pct == m.TopReviewerPct && name < m.TopReviewer) {
+			m.TopReviewer, m.TopReviewerPct = name, pct
+		}
+	}
+	return m
+}
+
+// percentile returns the p-th percentile (0-100) using the nearest-rank method.
+func percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
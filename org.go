@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// fetchOrgRepos lists the non-archived, non-fork repositories of an
+// organization, most recently pushed first. On failure it returns the
+// repositories listed so far.
+func fetchOrgRepos(ctx context.Context, org string, timeout, delay time.Duration) ([]string, error) {
+	var repos []string
+	var cursor string
+
+	queryTmpl := `
+query {
+  organization(login: %q) {
+    repositories(%s) {
+      nodes { nameWithOwner }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	for {
+		if len(repos) > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return repos, err
+			}
+		}
+		args := "first: 100, isArchived: false, isFork: false, orderBy: {field: PUSHED_AT, direction: DESC}"
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, org, args), timeout)
+		if err != nil {
+			return repos, err
+		}
+		var resp struct {
+			Data struct {
+				Organization *struct {
+					Repositories struct {
+						Nodes []struct {
+							NameWithOwner string `json:"nameWithOwner"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"repositories"`
+				} `json:"organization"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return repos, err
+		}
+		if resp.Data.Organization == nil {
+			return repos, fmt.Errorf("organization %q not found", org)
+		}
+
+		r := resp.Data.Organization.Repositories
+		for _, n := range r.Nodes {
+			repos = append(repos, n.NameWithOwner)
+		}
+		if !r.PageInfo.HasNextPage {
+			return repos, nil
+		}
+		cursor = r.PageInfo.EndCursor
+	}
+}
+
+// OrgConfig tunes how multi-repository runs are aggregated.
+type OrgConfig struct {
+	// Weighting is how much each repository counts in the org aggregate:
+	// volume (merged PRs, the default), headcount or weights.
+	Weighting string `yaml:"weighting"`
+	// Headcount maps repositories to the number of engineers working in
+	// them. Unmapped repositories count their distinct PR authors.
+	Headcount map[string]int `yaml:"headcount"`
+	// Weights maps repositories to explicit weights. Unmapped repositories
+	// weigh 1.
+	Weights map[string]float64 `yaml:"weights"`
+}
+
+func validateOrg(c OrgConfig) error {
+	switch c.Weighting {
+	case "", "volume", "headcount", "weights":
+	default:
+		return fmt.Errorf("org: weighting must be volume, headcount or weights, not %q", c.Weighting)
+	}
+	for repo, n := range c.Headcount {
+		if n < 0 {
+			return fmt.Errorf("org: headcount of %s must not be negative", repo)
+		}
+	}
+	for repo, w := range c.Weights {
+		if w < 0 {
+			return fmt.Errorf("org: weight of %s must not be negative", repo)
+		}
+	}
+	return nil
+}
+
+// orgRepo is one analyzed repository's contribution to the org aggregate.
+type orgRepo struct {
+	Repo      string
+	Metrics   Metrics
+	Authors   int
+	Archetype string
+	Reviews   map[string]int // PRs reviewed per person
+}
+
+// orgAggregate collects the analyzed repositories of a multi-repo run.
+type orgAggregate []orgRepo
+
+func (a *orgAggregate) add(repo string, merged []PullRequest) {
+	if len(merged) == 0 {
+		return
+	}
+	authors := make(map[string]bool)
+	for _, pr := range merged {
+		if pr.Author != "" && !isBot(pr.Author) {
+			authors[pr.Author] = true
+		}
+	}
+	arch, _ := repoArchetype(merged)
+	reviews, _ := countReviews(merged)
+	*a = append(*a, orgRepo{Repo: repo, Metrics: computeMetrics(merged), Authors: len(authors), Archetype: arch.Label, Reviews: reviews})
+}
+
+// weights returns each repository's weight under the configured scheme,
+// normalized to sum to 1, and a note on where they came from.
+func (a orgAggregate) weights(c OrgConfig) ([]float64, string) {
+	raw := make([]float64, len(a))
+	note := "merged PRs per repo"
+	for i, r := range a {
+		switch c.Weighting {
+		case "headcount":
+			note = "engineers per repo (org.headcount; unmapped repos count their PR authors)"
+			if n, ok := c.Headcount[r.Repo]; ok {
+				raw[i] = float64(n)
+			} else {
+				raw[i] = float64(r.Authors)
+			}
+		case "weights":
+			note = "org.weights in config (unmapped repos weigh 1)"
+			raw[i] = 1
+			if w, ok := c.Weights[r.Repo]; ok {
+				raw[i] = w
+			}
+		default:
+			raw[i] = float64(r.Metrics.Count)
+		}
+	}
+	total := 0.0
+	for _, w := range raw {
+		total += w
+	}
+	for i := range raw {
+		if total > 0 {
+			raw[i] /= total
+		} else {
+			raw[i] = 1 / float64(len(raw))
+		}
+	}
+	return raw, note
+}
+
+func printOrgAggregate(agg orgAggregate) {
+	fmt.Println("🏢 ORG AGGREGATE")
+	printExplanation("Each metric across all analyzed repositories: unweighted (every repository counts the same) and weighted by volume, head-count or configured weights.",
+		"An unweighted average lets a tiny repository with three slow PRs drag the org numbers as much as the main product.")
+
+	weights, note := agg.weights(cfg.Org)
+	fmt.Printf("   %-30s %6s %7s  %s\n", "Repository", "PRs", "Weight", "Kind")
+	for i, r := range agg {
+		fmt.Printf("   %-30s %6d %6.0f%%  %s\n", limitString(r.Repo, 29), r.Metrics.Count, weights[i]*100, r.Archetype)
+	}
+
+	fmt.Printf("\n   %-22s %-14s %s\n", "Metric", "Unweighted", "Weighted")
+	for _, bm := range benchmarkMetrics {
+		unweighted, weighted := 0.0, 0.0
+		for i, r := range agg {
+			v := bm.Value(r.Metrics)
+			unweighted += v / float64(len(agg))
+			weighted += v * weights[i]
+		}
+		flag := ""
+		if unweighted > 0 && math.Abs(weighted-unweighted)/unweighted > 0.25 {
+			flag = " ⚠️"
+		}
+		fmt.Printf("   %-22s %-14s %s%s\n", bm.Label, bm.Format(unweighted), bm.Format(weighted), flag)
+	}
+	fmt.Printf("\n   (Means of the per-repository values. Weighted by %s; change with org.weighting in config.\n", note)
+	fmt.Println("    ⚠️ The two differ by over 25%: a few repositories are skewing the unweighted number.)")
+
+	fmt.Println()
+	printOrgHeroes(agg)
+}
+
+// printOrgHeroes is the hero detector across all analyzed repositories.
+// Identities are merged before counting, so a person reviewing in several
+// repositories, under any of their accounts, is tallied once.
+func printOrgHeroes(agg orgAggregate) {
+	fmt.Println("   Reviewers across all repositories:")
+	counts := make(map[string]int)
+	repos := make(map[string]int)
+	total := 0
+	for _, r := range agg {
+		for login, n := range r.Reviews {
+			counts[login] += n
+			repos[login]++
+			total += n
+		}
+	}
+	if total == 0 {
+		fmt.Println("   No reviews found in these repositories.")
+		return
+	}
+	if privacy == privacyAggregate {
+		top1, top3, half := loadShares(counts)
+		fmt.Printf("   Reviewers: %d   Busiest reviewer: %s%% of reviews   Busiest three: %s%%\n", len(counts), formatNumber(top1, 1), formatNumber(top3, 1))
+		fmt.Printf("   Reviewers doing half of all reviews: %d\n", half)
+		return
+	}
+	if privacy == privacyTeam {
+		counts, repos = byPerson(counts), nil
+	}
+
+	type reviewer struct {
+		Name  string
+		Count int
+	}
+	var list []reviewer
+	for name, n := range counts {
+		list = append(list, reviewer{name, n})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	foundRisk := false
+	for i, h := range list {
+		share := float64(h.Count) / float64(total) * 100
+		if i == 5 || share <= 20 {
+			break
+		}
+		risk := "✅ Healthy"
+		switch {
+		case share > 50:
+			risk, foundRisk = "🚨 CRITICAL RISK", true
+		case share > 30:
+			risk, foundRisk = "⚠️  High Load", true
+		}
+		where := ""
+		switch {
+		case repos == nil:
+		case repos[h.Name] == 1:
+			where = " in 1 repo"
+		default:
+			where = fmt.Sprintf(" in %d repos", repos[h.Name])
+		}
+		fmt.Printf("   %s: %d reviews (%s%%)%s - %s\n", h.Name, h.Count, formatNumber(share, 1), where, risk)
+	}
+	if !foundRisk {
+		fmt.Println("   ✅ No single reviewer carries the org's review load.")
+	}
+}
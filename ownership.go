@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ownershipShare is one reviewer's share of a directory's reviews.
+type ownershipShare struct {
+	Login   string
+	Earlier int // Reviewed PRs in the earlier half
+	Later   int // Reviewed PRs in the later half
+}
+
+// ownershipDrift is a directory whose earlier reviewers have stepped back.
+type ownershipDrift struct {
+	Group             string
+	EarlierPRs        int
+	LaterPRs          int
+	Faded             []ownershipShare // Key reviewers who stopped participating
+	Retained          float64          // Share of later reviews by earlier reviewers
+	NewcomerReviewers []string         // New to the directory, with 2+ reviews
+}
+
+// reviewedGroups returns the directories (at depth, see groupAt) touched by
+// pr and its distinct non-bot reviewers other than the author.
+func reviewedGroups(pr PullRequest, depth int) (groups []string, reviewers []string) {
+	seen := make(map[string]bool)
+	for _, path := range pr.FilePaths {
+		if g := groupAt(path, depth); !seen[g] {
+			seen[g] = true
+			groups = append(groups, g)
+		}
+	}
+	seenReviewer := make(map[string]bool)
+	for _, r := range pr.Reviews {
+		if r.Author == "" || r.Author == pr.Author || isBot(r.Author) || seenReviewer[r.Author] {
+			continue
+		}
+		seenReviewer[r.Author] = true
+		reviewers = append(reviewers, r.Author)
+	}
+	return groups, reviewers
+}
+
+// ownershipDrifts splits prs by merge time into an earlier and a later half
+// and flags directories whose key earlier reviewers (at least 20% of its
+// reviewed PRs, and 3 or more) reviewed less than a quarter as often since.
+func ownershipDrifts(prs []PullRequest, depth int) []ownershipDrift {
+	sorted := append([]PullRequest(nil), prs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MergedAt.Before(sorted[j].MergedAt) })
+	half := len(sorted) / 2
+
+	type dirStat struct {
+		PRs     [2]int
+		Reviews [2]int
+		By      map[string]*ownershipShare
+	}
+	stats := make(map[string]*dirStat)
+	for i, pr := range sorted {
+		period := 0
+		if i >= half {
+			period = 1
+		}
+		groups, reviewers := reviewedGroups(pr, depth)
+		for _, g := range groups {
+			s := stats[g]
+			if s == nil {
+				s = &dirStat{By: make(map[string]*ownershipShare)}
+				stats[g] = s
+			}
+			s.PRs[period]++
+			for _, r := range reviewers {
+				if s.By[r] == nil {
+					s.By[r] = &ownershipShare{Login: r}
+				}
+				if period == 0 {
+					s.By[r].Earlier++
+				} else {
+					s.By[r].Later++
+				}
+				s.Reviews[period]++
+			}
+		}
+	}
+
+	var drifts []ownershipDrift
+	for g, s := range stats {
+		if s.PRs[0] < 5 || s.PRs[1] < 5 || s.Reviews[1] == 0 {
+			continue
+		}
+		d := ownershipDrift{Group: g, EarlierPRs: s.PRs[0], LaterPRs: s.PRs[1]}
+		retained := 0
+		for _, sh := range s.By {
+			earlierShare := float64(sh.Earlier) / float64(s.PRs[0])
+			laterShare := float64(sh.Later) / float64(s.PRs[1])
+			if sh.Earlier >= 3 && earlierShare >= 0.2 && laterShare < earlierShare/4 {
+				d.Faded = append(d.Faded, *sh)
+			}
+			if sh.Earlier > 0 {
+				retained += sh.Later
+			} else if sh.Later >= 2 {
+				d.NewcomerReviewers = append(d.NewcomerReviewers, sh.Login)
+			}
+		}
+		if len(d.Faded) == 0 {
+			continue
+		}
+		d.Retained = float64(retained) / float64(s.Reviews[1])
+		sort.Slice(d.Faded, func(i, j int) bool {
+			if d.Faded[i].Earlier != d.Faded[j].Earlier {
+				return d.Faded[i].Earlier > d.Faded[j].Earlier
+			}
+			return d.Faded[i].Login < d.Faded[j].Login
+		})
+		sort.Strings(d.NewcomerReviewers)
+		drifts = append(drifts, d)
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Retained != drifts[j].Retained {
+			return drifts[i].Retained < drifts[j].Retained
+		}
+		return drifts[i].Group < drifts[j].Group
+	})
+	return drifts
+}
+
+func printOwnershipDrift(prs []PullRequest, depth int) {
+	fmt.Printf("🧭 %s OWNERSHIP DRIFT\n", depthLabel(depth))
+	printExplanation("Reviewers who carried a directory in the earlier half of the dataset but have stopped reviewing it in the later half.",
+		"When the people who know an area step back, reviews there slow down and quality slips. This is the early warning before a swamp forms.")
+
+	if len(prs) < 20 {
+		fmt.Println("   Not enough merged PRs to compare two halves (need 20).")
+		return
+	}
+	sorted := append([]PullRequest(nil), prs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MergedAt.Before(sorted[j].MergedAt) })
+	half := sorted[len(sorted)/2].MergedAt
+	fmt.Printf("   Earlier: %s - %s   Later: %s - %s\n\n", formatDate(sorted[0].MergedAt), formatDate(half), formatDate(half), formatDate(sorted[len(sorted)-1].MergedAt))
+
+	drifts := ownershipDrifts(prs, depth)
+	if len(drifts) == 0 {
+		fmt.Println("   ✅ Key reviewers are still active in every directory.")
+		return
+	}
+	for i, d := range drifts {
+		if i == 10 {
+			fmt.Printf("   ... and %d more\n", len(drifts)-10)
+			break
+		}
+		icon := "⚠️ "
+		if d.Retained < 0.25 {
+			icon = "🚨"
+		}
+		fmt.Printf("   %s %-25s %3.0f%% of recent reviews by earlier reviewers (%d -> %d PRs)\n", icon, limitString(d.Group, 25), d.Retained*100, d.EarlierPRs, d.LaterPRs)
+		if !namesShown() {
+			fmt.Printf("      Stepped back: %d reviewers   Taking over: %d new reviewers\n", len(d.Faded), len(d.NewcomerReviewers))
+			continue
+		}
+		var faded []string
+		for _, f := range d.Faded {
+			faded = append(faded, fmt.Sprintf("%s (%d -> %d)", f.Login, f.Earlier, f.Later))
+		}
+		fmt.Printf("      Stepped back: %s\n", strings.Join(faded, ", "))
+		if len(d.NewcomerReviewers) > 0 {
+			fmt.Printf("      Taking over:  %s\n", strings.Join(d.NewcomerReviewers, ", "))
+		} else {
+			fmt.Println("      Taking over:  nobody new")
+		}
+	}
+	fmt.Println("\n   (Counts are reviewed PRs per half. 🚨: under 25% of recent reviews come from earlier reviewers.)")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FetchError records a failed fetch. When Fetched > 0 the analysis
+// continued with the partial data.
+type FetchError struct {
+	Repo    string
+	Stage   string // What was being fetched, e.g. "merged PRs"
+	Fetched int
+	Nulls   int // For a field GraphQL returned null: on how many items
+	Err     error
+}
+
+type fetchErrors []FetchError
+
+func (e *fetchErrors) add(repo, stage string, fetched int, err error) {
+	*e = append(*e, FetchError{Repo: repo, Stage: stage, Fetched: fetched, Err: err})
+}
+
+// partial reports whether any fetch for repo failed.
+func (e fetchErrors) partial(repo string) bool {
+	for _, f := range e {
+		if f.Repo == repo {
+			return true
+		}
+	}
+	return false
+}
+
+// pageError annotates a pagination failure with how much was fetched.
+func pageError(fetched int, err error) error {
+	if fetched == 0 {
+		return err
+	}
+	return fmt.Errorf("failed after %d PRs: %w", fetched, err)
+}
+
+func printFetchErrors(errs fetchErrors) {
+	fmt.Println("⚠️  ERRORS")
+	printExplanation("Fetches that failed during this run. Affected repositories were analyzed with whatever data arrived.",
+		"One flaky page shouldn't throw away a long scan, but you should know which numbers are incomplete.")
+
+	for _, e := range errs {
+		status := "skipped"
+		if e.Fetched > 0 {
+			status = fmt.Sprintf("partial, %d fetched", e.Fetched)
+		}
+		if e.Nulls > 0 {
+			status = fmt.Sprintf("null on %d items, the rest fetched", e.Nulls)
+		}
+		reason := e.Err.Error()
+		if errors.Is(e.Err, context.Canceled) {
+			reason = "interrupted"
+		}
+		fmt.Printf("   ❌ %s: %s (%s)\n", e.Repo, e.Stage, status)
+		fmt.Printf("      %s\n", reason)
+	}
+}
+
+// graphQLError is an entry of a GraphQL response's errors array. Errors with
+// a path are about one field, which is null in the data; the rest of the
+// response is valid.
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+// field renders the error's path without list indices and connection
+// nodes, so the same field on every pull request reads the same, e.g.
+// "pullRequests.files".
+func (e graphQLError) field() string {
+	var parts []string
+	for _, p := range e.Path {
+		if s, ok := p.(string); ok && s != "nodes" && s != "repository" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// checkGraphQLErrors reads the errors array of a GraphQL response. When the
+// response still has data, field errors are logged and recorded in
+// fieldErrors and the response is usable; gh's exit error is dropped.
+// Otherwise the errors are the failure, which says more than gh's exit
+// status.
+func checkGraphQLErrors(query string, output []byte, err error) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if json.Unmarshal(output, &resp) != nil || len(resp.Errors) == 0 {
+		return err
+	}
+	hasData := len(resp.Data) > 0 && string(resp.Data) != "null"
+	var failures []string
+	for _, e := range resp.Errors {
+		if len(e.Path) <= 1 || !hasData { // A top-level field is the whole result
+			failures = append(failures, e.Message)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("GraphQL: %s", strings.Join(failures, "; "))
+	}
+	repo := queryRepo(query)
+	for _, e := range resp.Errors {
+		if first := fieldErrors.add(repo, e); first {
+			slog.Warn("GraphQL field error; the field is null and the rest of the data is used",
+				"repo", repo, "field", e.field(), "type", e.Type, "err", e.Message)
+		}
+	}
+	return nil
+}
+
+var (
+	repositoryArg = regexp.MustCompile(`repository\(owner: "([^"]+)", name: "([^"]+)"\)`)
+	repoQualifier = regexp.MustCompile(`\brepo:([\w.-]+/[\w.-]+)`)
+)
+
+// queryRepo returns the repository a query reads, or "" for none.
+func queryRepo(query string) string {
+	if m := repositoryArg.FindStringSubmatch(query); m != nil {
+		return m[1] + "/" + m[2]
+	}
+	if m := repoQualifier.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// fieldErrorLog collects GraphQL field errors until a report takes them,
+// counted by repository, field and message. Fetches run in goroutines,
+// hence the lock.
+type fieldErrorLog struct {
+	mu     sync.Mutex
+	counts map[[3]string]int
+}
+
+var fieldErrors = &fieldErrorLog{counts: make(map[[3]string]int)}
+
+// add counts e and reports whether it is the first of its kind.
+func (l *fieldErrorLog) add(repo string, e graphQLError) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	k := [3]string{repo, e.field(), e.Message}
+	l.counts[k]++
+	return l.counts[k] == 1
+}
+
+// takeInto moves the field errors of repo into errs, so the report shows
+// which numbers rest on missing fields.
+func (l *fieldErrorLog) takeInto(errs *fetchErrors, repo string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var keys [][3]string
+	for k := range l.counts {
+		if k[0] == repo {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i][1]+keys[i][2] < keys[j][1]+keys[j][2] })
+	for _, k := range keys {
+		*errs = append(*errs, FetchError{Repo: repo, Stage: "field " + k[1], Nulls: l.counts[k], Err: errors.New(k[2])})
+		delete(l.counts, k)
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A minimal PDF writer for the executive summary: A4 pages, the standard
+// Helvetica fonts (no embedding) and WinAnsi text. It keeps the binary free
+// of a PDF dependency and of a headless browser.
+
+const (
+	pdfPageWidth  = 595.0 // A4 in points
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+)
+
+// helveticaWidths are the Helvetica glyph widths of ASCII 32-126, in
+// thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfReplacements stand in for symbols the standard fonts don't have.
+var pdfReplacements = strings.NewReplacer("▲", "+", "▼", "-", "➖", "=", "→", "->", "≥", ">=", "≤", "<=")
+
+// winAnsi converts s to WinAnsi bytes. Characters outside the encoding
+// become '?'.
+func winAnsi(s string) []byte {
+	special := map[rune]byte{'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97}
+	var out []byte
+	for _, r := range pdfReplacements.Replace(s) {
+		switch {
+		case r >= 32 && r < 127, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case special[r] != 0:
+			out = append(out, special[r])
+		case r >= 0xFE00 && r <= 0xFE0F:
+			// Variation selectors have no glyph
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// textWidth approximates the width of s in points. Bold text runs about 5%
+// wider.
+func textWidth(s string, size float64, bold bool) float64 {
+	w := 0
+	for _, b := range winAnsi(s) {
+		if b >= 32 && b < 127 {
+			w += helveticaWidths[b-32]
+		} else {
+			w += 556
+		}
+	}
+	if bold {
+		w = w * 105 / 100
+	}
+	return float64(w) * size / 1000
+}
+
+// wrapText splits s into lines no wider than width.
+func wrapText(s string, size float64, bold bool, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && textWidth(next, size, bold) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfDoc lays text out top to bottom, starting a new page when one fills.
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	y     float64 // Baseline of the next line
+}
+
+func (d *pdfDoc) newPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// ensure starts a new page unless height points still fit on this one.
+func (d *pdfDoc) ensure(height float64) {
+	if len(d.pages) == 0 || d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+func (d *pdfDoc) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	var esc bytes.Buffer
+	for _, b := range winAnsi(s) {
+		if b == '(' || b == ')' || b == '\\' {
+			esc.WriteByte('\\')
+		}
+		esc.WriteByte(b)
+	}
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, esc.Bytes())
+}
+
+func (d *pdfDoc) rule(x1, x2, y float64) {
+	fmt.Fprintf(d.pages[len(d.pages)-1], "0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", x1, y, x2, y)
+}
+
+// paragraph writes wrapped text, indented by indent points.
+func (d *pdfDoc) paragraph(s string, size float64, bold bool, indent float64) {
+	lead := size * 1.4
+	for _, line := range wrapText(s, size, bold, pdfPageWidth-2*pdfMargin-indent) {
+		d.ensure(lead)
+		d.y -= lead
+		d.text(pdfMargin+indent, d.y, size, bold, line)
+	}
+}
+
+func (d *pdfDoc) heading(s string, size float64) {
+	// Keep a heading on the page of the line after it
+	d.ensure(size*2.2 + 16)
+	d.y -= size * 0.8
+	d.paragraph(s, size, true, 0)
+	d.y -= 4
+}
+
+func (d *pdfDoc) bullets(items []string, empty string) {
+	if len(items) == 0 {
+		items = []string{empty}
+	}
+	for _, item := range items {
+		d.ensure(15)
+		d.text(pdfMargin+4, d.y-15, 10.5, false, "•")
+		d.paragraph(item, 10.5, false, 16)
+		d.y -= 2
+	}
+}
+
+// table writes rows in columns starting at the given x offsets. The first
+// row is the header.
+func (d *pdfDoc) table(cols []float64, rows [][]string) {
+	const size, lead = 10.0, 18.0
+	for i, row := range rows {
+		d.ensure(lead)
+		d.y -= lead
+		for j, cell := range row {
+			width := pdfPageWidth - pdfMargin - cols[j]
+			if j+1 < len(cols) {
+				width = cols[j+1] - cols[j] - 8
+			}
+			for r := []rune(cell); textWidth(cell, size, i == 0) > width && len(r) > 1; {
+				r = r[:len(r)-1]
+				cell = string(r) + "…"
+			}
+			d.text(pdfMargin+cols[j], d.y, size, i == 0, cell)
+		}
+		d.rule(pdfMargin, pdfPageWidth-pdfMargin, d.y-6)
+	}
+}
+
+// write serializes the document, numbering the pages in the footer.
+func (d *pdfDoc) write(w io.Writer) error {
+	for i, page := range d.pages {
+		label := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", pdfPageWidth-pdfMargin-textWidth(label, 8, false), pdfMargin/2, label)
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3-4: fonts, then a page and its content
+	// stream per page
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeSummaryPDF renders the executive summary with the layout of the
+// HTML version.
+func writeSummaryPDF(w io.Writer, s ExecSummary) error {
+	var d pdfDoc
+	d.newPage()
+	d.paragraph(fmt.Sprintf("Engineering Velocity Summary: %s (%s)", s.Repo, s.Period), 18, true, 0)
+	d.y -= 6
+	d.paragraph(s.Headline, 11, false, 0)
+
+	d.heading("Key Numbers", 13)
+	rows := [][]string{{"Metric", s.Period, s.PrevPeriod, "Change"}}
+	for _, k := range s.KeyNumbers {
+		rows = append(rows, []string{k.Label, k.Value, k.Previous, k.Change})
+	}
+	d.table([]float64{0, 210, 310, 410}, rows)
+
+	d.heading("Biggest Improvements", 13)
+	d.bullets(s.Improvements, "No significant improvements vs "+s.PrevPeriod+".")
+	d.heading("Biggest Regressions", 13)
+	d.bullets(s.Regressions, "No significant regressions vs "+s.PrevPeriod+".")
+	d.heading("Top Risks", 13)
+	d.bullets(s.Risks, "No major risks detected.")
+	return d.write(w)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// prPageLatency is a typical round trip for a page of 100 pull requests
+	// with their reviews, commits and files.
+	prPageLatency = 3 * time.Second
+	// requestLatency is a typical round trip for any other request.
+	requestLatency = time.Second
+)
+
+// fetchStep is one kind of request the report makes per repository.
+type fetchStep struct {
+	What    string
+	Pages   int // Requests, each but the first after --delay
+	Cost    int // GraphQL points
+	Latency time.Duration
+}
+
+// repoFetchPlan lists the requests the report makes for one repository,
+// from the query shape and the planned sections.
+func repoFetchPlan(o reportOptions) []fetchStep {
+	fields := reportFields(o)
+	if o.JSON {
+		fields = jsonFields | configFields()
+	}
+	if o.LowMemory {
+		// Sketches read commits and files; open PRs are only counted
+		step := fetchStep{What: fmt.Sprintf("Merged PRs (up to %d, 100 per page)", o.Limit), Latency: prPageLatency}
+		for remaining := o.Limit; remaining > 0; remaining -= 100 {
+			step.Pages++
+			step.Cost += pageCost(min(remaining, 100), fieldCommits|fieldFiles|configFields())
+		}
+		return []fetchStep{step, {What: "Open PRs (up to 100)", Pages: 1, Cost: pageCost(100, configFields()), Latency: prPageLatency}}
+	}
+	var steps []fetchStep
+	if o.Sample > 0 {
+		windows := o.SampleMonths * 4
+		perWindow := max(1, o.Sample/windows)
+		steps = append(steps, fetchStep{
+			What:    fmt.Sprintf("Merged PR sample (%d over %d months, searched by week)", o.Sample, o.SampleMonths),
+			Pages:   (windows+49)/50 + windows*((perWindow+99)/100),
+			Cost:    estimateSampleCost(o.Sample, o.SampleMonths),
+			Latency: prPageLatency,
+		})
+	} else {
+		step := fetchStep{What: fmt.Sprintf("Merged PRs (up to %d, 100 per page)", o.Limit), Latency: prPageLatency}
+		for remaining := o.Limit; remaining > 0; remaining -= 100 {
+			step.Pages++
+			step.Cost += pageCost(min(remaining, 100), fields)
+		}
+		steps = append(steps, step)
+	}
+	steps = append(steps, fetchStep{What: "Open PRs (up to 100)", Pages: 1, Cost: pageCost(100, fields), Latency: prPageLatency})
+	if o.JSON {
+		return append(steps, fetchStep{What: ".gitattributes", Pages: 1, Cost: 1, Latency: requestLatency})
+	}
+	steps = append(steps,
+		fetchStep{What: ".gitattributes, CODEOWNERS and the PR template", Pages: 3, Cost: 3, Latency: requestLatency},
+		fetchStep{What: "Published releases, or tags when there are none", Pages: 2, Cost: 2, Latency: requestLatency},
+		fetchStep{What: "Deployments (up to 300)", Pages: 3, Cost: 3, Latency: requestLatency},
+	)
+	for _, s := range plannedSections(o) {
+		if s.Name == "cohorts" && (s.By == "release" || s.By == "" && o.Cohorts == "release") {
+			steps = append(steps, fetchStep{What: "Tags for release cohorts (up to 500)", Pages: 5, Cost: 5, Latency: requestLatency})
+		}
+	}
+	if plansSection(o, "flaky_checks") || plansSection(o, "ci_timing") {
+		batches := (o.Limit + ciBatch - 1) / ciBatch
+		steps = append(steps, fetchStep{What: fmt.Sprintf("Check suites and runs (%d PRs per request)", ciBatch), Pages: batches, Cost: batches * 2, Latency: requestLatency})
+	}
+	if plansSection(o, "issues") {
+		pages := (o.Limit + 99) / 100
+		steps = append(steps, fetchStep{What: fmt.Sprintf("Issues (up to %d)", o.Limit), Pages: pages, Cost: pages * 3, Latency: requestLatency})
+	}
+	if plansSection(o, "stale_branches") {
+		pages := (o.Limit + 99) / 100
+		steps = append(steps,
+			fetchStep{What: "Default branch", Pages: 1, Cost: 1, Latency: requestLatency},
+			fetchStep{What: fmt.Sprintf("Branches (up to %d)", o.Limit), Pages: pages, Cost: pages * 2, Latency: requestLatency})
+	}
+	if cfg.Availability.GitHubStatus {
+		steps = append(steps, fetchStep{What: "User statuses", Pages: 1, Cost: 1, Latency: requestLatency})
+	}
+	return steps
+}
+
+// printPlan prints what a run would fetch and analyze: the requests per
+// repository with their pages, points and duration, the analyses in
+// order, and the pull request query itself.
+func printPlan(repos []string, o reportOptions, rl *RateLimit) {
+	fmt.Println("📋 PLAN")
+	printExplanation("What this run would fetch and analyze, worked out from the flags and config without fetching anything.",
+		"Check a large org scan or a new config before it spends time and quota.")
+
+	shown := repos
+	if len(shown) > 5 {
+		shown = shown[:5]
+	}
+	fmt.Printf("   Repositories: %d (%s", len(repos), strings.Join(shown, ", "))
+	if len(repos) > len(shown) {
+		fmt.Printf(" and %d more", len(repos)-len(shown))
+	}
+	fmt.Println(")")
+
+	steps := repoFetchPlan(o)
+	fmt.Println("\n   Requests per repository:")
+	fmt.Printf("   %6s %7s  %s\n", "Pages", "Points", "Fetch")
+	pages, cost := 0, 0
+	var took time.Duration
+	for _, s := range steps {
+		fmt.Printf("   %6d %7d  %s\n", s.Pages, s.Cost, s.What)
+		pages += s.Pages
+		cost += s.Cost
+		took += time.Duration(s.Pages) * (s.Latency + o.Delay)
+	}
+	fmt.Printf("   %6d %7d  Total\n", pages, cost)
+	if skipped := reportFields(o).skipped(); len(skipped) > 0 && !o.LowMemory && !o.JSON {
+		fmt.Printf("   (PR fields skipped, no planned section reads them: %s.)\n", strings.Join(skipped, ", "))
+	}
+
+	total := cost * len(repos)
+	fmt.Println()
+	fmt.Printf("   Requests:          %d\n", pages*len(repos))
+	fmt.Printf("   Estimated cost:    ~%d points\n", total)
+	fmt.Printf("   Estimated time:    ~%s (about %s a PR page and %s any other request, plus --delay %s)\n",
+		formatDuration(took*time.Duration(len(repos))), formatDuration(prPageLatency), formatDuration(requestLatency), o.Delay)
+	printQuotaFit(total, rl)
+
+	fmt.Println("\n   Analyses, in order (sections of merged or open PRs are skipped when there are none):")
+	for i, name := range plannedAnalyses(o) {
+		fmt.Printf("   %2d. %s\n", i+1, name)
+	}
+
+	owner, name, _ := parseRepo(repos[0])
+	fields := reportFields(o)
+	if o.JSON {
+		fields = jsonFields | configFields()
+	}
+	if o.LowMemory {
+		fields = fieldCommits | fieldFiles | configFields()
+	}
+	if o.Sample > 0 {
+		w := sampleWindows(clock(), o.SampleMonths)[0].Window
+		fmt.Printf("\n   Merged PRs are searched week by week, first with: repo:%s/%s is:pr is:merged merged:%s\n", owner, name, w.searchRange())
+		fmt.Printf("\n   Open PR query for %s:\n", repos[0])
+		printQuery(prQuery(owner, name, "OPEN", 100, "", fields))
+	} else {
+		fmt.Printf("\n   Merged PR query for %s (first page; later pages add after: \"<endCursor>\"):\n", repos[0])
+		printQuery(prQuery(owner, name, "MERGED", min(o.Limit, 100), "", fields))
+		if o.LowMemory {
+			// Open PRs only feed the queue count
+			fmt.Printf("\n   Open PR query for %s:\n", repos[0])
+			printQuery(prQuery(owner, name, "OPEN", 100, "", configFields()))
+			return
+		}
+		fmt.Println("\n   Open PRs use the same query with:")
+		printQuery("first: 100, states: OPEN, orderBy: {field: UPDATED_AT, direction: DESC}")
+	}
+}
+
+// plannedAnalyses names what the report prints for each repository, in
+// order.
+func plannedAnalyses(o reportOptions) []string {
+	out := []string{"health score"}
+	if len(cfg.Goals) > 0 {
+		out = append(out, "goals")
+	}
+	switch {
+	case o.JSON:
+		return []string{"JSON report: health score, merged PR metrics, open PRs, months and areas"}
+	case o.LowMemory:
+		return []string{"low-memory summary"}
+	case o.SummaryOnly:
+		return append(out, "scorecard")
+	}
+	for _, s := range plannedSections(o) {
+		sec := findSection(s.Name)
+		if sec == nil {
+			continue
+		}
+		name := s.Name
+		if sec.Personal && !namesShown() {
+			name += " (skipped: names individuals)"
+		}
+		out = append(out, name)
+	}
+	if o.ChartsDir != "" {
+		out = append(out, "charts in "+o.ChartsDir)
+	}
+	return out
+}
+
+// printQuery prints a query indented under the plan.
+func printQuery(q string) {
+	for _, line := range strings.Split(strings.Trim(q, "\n"), "\n") {
+		fmt.Println(strings.TrimRight("      "+line, " "))
+	}
+}
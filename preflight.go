@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// access is something the token has to be allowed for part of a run.
+type access struct {
+	Name     string
+	Classic  string // OAuth scope of a classic token
+	Grant    string // Permission of a fine-grained token or GitHub App
+	UsedBy   string
+	Required bool // Without it nothing can be analyzed
+	// field is the GraphQL selection checking the access, inside the
+	// repository, or at the top level for org access. Errors on it, by its
+	// first word, mean the access is missing. Write access can't be checked
+	// by reading and has no field.
+	field string
+	org   bool
+}
+
+// requiredAccess lists what the run needs the token for.
+func requiredAccess(o reportOptions) []access {
+	needs := []access{{
+		Name: "repository", Classic: "repo (private repositories only)", Grant: "Pull requests: read, Contents: read",
+		UsedBy: "every report", Required: true, field: "pullRequests(first: 1) { totalCount }",
+	}}
+	if plansSection(o, "ghosts") || plansSection(o, "suggestions") || plansSection(o, "ghost_digest") {
+		needs = append(needs, access{
+			Name: "teams", Classic: "read:org", Grant: "Members: read (organization)",
+			UsedBy: "team review requests in ghosts and suggestions", field: "teams(first: 1) { totalCount }", org: true,
+		})
+	}
+	if o.CI || plansSection(o, "flaky_checks") || plansSection(o, "ci_timing") {
+		needs = append(needs, access{
+			Name: "checks", Classic: "repo (private repositories only)", Grant: "Checks: read",
+			UsedBy: "--ci: flaky checks and CI timing", field: "defaultBranchRef { target { ... on Commit { checkSuites(first: 1) { totalCount } } } }",
+		})
+	}
+	if o.Issues || plansSection(o, "issues") {
+		needs = append(needs, access{
+			Name: "issues", Classic: "repo (private repositories only)", Grant: "Issues: read",
+			UsedBy: "--issues: issue triage", field: "issues(first: 1) { totalCount }",
+		})
+	}
+	if plansSection(o, "deployments") {
+		needs = append(needs, access{
+			Name: "deployments", Classic: "repo (private repositories only)", Grant: "Deployments: read",
+			UsedBy: "merge to deploy lead time", field: "deployments(first: 1) { totalCount }",
+		})
+	}
+	if plansSection(o, "project_boards") {
+		needs = append(needs, access{
+			Name: "projects", Classic: "read:project", Grant: "Projects: read (organization)",
+			UsedBy: "project board column dwell times", field: "projectsV2(first: 1) { totalCount }",
+		})
+	}
+	if o.AssignReviewers || o.NotifyGhosts {
+		needs = append(needs, access{
+			Name: "pull request writes", Classic: "repo or public_repo", Grant: "Pull requests: write",
+			UsedBy: "--assign-reviewers and --notify-ghosts mentions",
+		})
+	}
+	return needs
+}
+
+// missingAccess is access a probe found missing.
+type missingAccess struct {
+	access
+	Repo string
+	Err  string
+}
+
+// preflight checks the token against what the run needs, with one query per
+// repository owner, before anything is fetched. It returns the access found
+// missing; write access is checked only for classic tokens, whose scopes
+// GitHub lists.
+func preflight(ctx context.Context, repos []string, o reportOptions) ([]missingAccess, error) {
+	needs := requiredAccess(o)
+	var missing []missingAccess
+
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		owner, name, _ := parseRepo(repo)
+		if seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		m, err := probeAccess(ctx, owner, name, needs, o.Timeout)
+		if err != nil {
+			return missing, err
+		}
+		missing = append(missing, m...)
+	}
+
+	for _, a := range needs {
+		if a.field != "" {
+			continue
+		}
+		scopes, classic, err := tokenScopes(ctx, o.Timeout)
+		switch {
+		case err != nil:
+			return missing, err
+		case !classic:
+			slog.Info("can't check write access of a fine-grained token or GitHub App", "access", a.Name, "needs", a.Grant)
+		case !scopes["repo"] && !scopes["public_repo"]:
+			var granted []string
+			for s := range scopes {
+				granted = append(granted, s)
+			}
+			sort.Strings(granted)
+			missing = append(missing, missingAccess{access: a, Err: "token scopes: " + strings.Join(granted, ", ")})
+		}
+	}
+	return missing, nil
+}
+
+// probeAccess runs one query selecting every field of needs on a repository
+// and reports the needs whose field came back with an error.
+func probeAccess(ctx context.Context, owner, name string, needs []access, timeout time.Duration) ([]missingAccess, error) {
+	var repoFields, orgFields []string
+	for _, a := range needs {
+		switch {
+		case a.field == "":
+		case a.org:
+			orgFields = append(orgFields, a.field)
+		default:
+			repoFields = append(repoFields, a.field)
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "query {\n  repository(owner: %q, name: %q) {\n    %s\n  }\n", owner, name, strings.Join(repoFields, "\n    "))
+	if len(orgFields) > 0 {
+		fmt.Fprintf(&b, "  organization(login: %q) {\n    %s\n  }\n", owner, strings.Join(orgFields, "\n    "))
+	}
+	b.WriteString("}")
+
+	// Read errors directly: these are expected, and not field errors of a
+	// report (see checkGraphQLErrors)
+	output, err := ghAPI(ctx, timeout, "graphql", "-f", "query="+b.String())
+	var resp struct {
+		Errors []graphQLError `json:"errors"`
+	}
+	if jsonErr := json.Unmarshal(output, &resp); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		return nil, err
+	}
+
+	repo := owner + "/" + name
+	var missing []missingAccess
+	for _, e := range resp.Errors {
+		if len(e.Path) == 0 {
+			return nil, fmt.Errorf("GraphQL: %s", e.Message)
+		}
+		top, _ := e.Path[0].(string)
+		if top == "organization" && e.Type == "NOT_FOUND" {
+			continue // A user's repositories: no teams to read
+		}
+		for _, a := range needs {
+			first, _, _ := strings.Cut(a.field, "(")
+			first, _, _ = strings.Cut(first, " ")
+			hit := a.Required && len(e.Path) == 1 && top == "repository" // No repository at all
+			for _, p := range e.Path[1:] {
+				hit = hit || p == first
+			}
+			if hit && a.org == (top == "organization") {
+				missing = append(missing, missingAccess{access: a, Repo: repo, Err: e.Message})
+			}
+		}
+	}
+	return missing, nil
+}
+
+// tokenScopes returns the scopes of a classic token, from the X-OAuth-Scopes
+// header. Fine-grained tokens and GitHub Apps don't send it: classic is
+// false for them.
+func tokenScopes(ctx context.Context, timeout time.Duration) (map[string]bool, bool, error) {
+	output, err := ghAPI(ctx, timeout, "-i", "rate_limit")
+	if err != nil {
+		return nil, false, err
+	}
+	headers, _, _ := strings.Cut(strings.ReplaceAll(string(output), "\r\n", "\n"), "\n\n")
+	for _, line := range strings.Split(headers, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "X-OAuth-Scopes") {
+			continue
+		}
+		scopes := make(map[string]bool)
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes[s] = true
+			}
+		}
+		return scopes, true, nil
+	}
+	return nil, false, nil
+}
+
+// reportMissingAccess logs what the token lacks and what each gap affects.
+// It returns false when the run can't go ahead.
+func reportMissingAccess(missing []missingAccess) bool {
+	ok := true
+	for _, m := range missing {
+		attrs := []any{"access", m.Name, "scope", m.Classic, "permission", m.Grant, "for", m.UsedBy}
+		if m.Repo != "" {
+			attrs = append(attrs, "repo", m.Repo)
+		}
+		attrs = append(attrs, "err", m.Err)
+		if m.Required {
+			slog.Error("token is missing access", attrs...)
+			ok = false
+		} else {
+			slog.Warn("token is missing access; affected numbers will be incomplete", attrs...)
+		}
+	}
+	if !ok {
+		slog.Error("token preflight failed: grant the access above, or pass --skip-preflight to try anyway")
+	}
+	return ok
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset bundles a section selection with flag defaults, so a role-specific
+// report is one --preset away. Flags given on the command line win over the
+// preset's.
+type Preset struct {
+	Description string            `yaml:"description"`
+	Sections    []SectionConfig   `yaml:"sections"`
+	Flags       map[string]string `yaml:"flags"` // Flag name (without dashes) to value, e.g. limit: "300"
+}
+
+// builtinPresets ship with the binary. Presets in config replace a built-in
+// of the same name.
+var builtinPresets = map[string]Preset{
+	"maintainer": {
+		Description: "The open review queue and what slows it down today",
+		Sections: []SectionConfig{
+			{Name: "review"}, {Name: "stale"}, {Name: "aging"}, {Name: "ghosts"}, {Name: "burndown"},
+			{Name: "dependencies"}, {Name: "suggestions"}, {Name: "stacks"}, {Name: "queue"},
+			{Name: "flaky_checks"}, {Name: "ci_timing"}, {Name: "sla"},
+		},
+		Flags: map[string]string{"explain": "false"},
+	},
+	"manager": {
+		Description: "Trends, delivery and risk over the last quarters",
+		Sections: []SectionConfig{
+			{Name: "general"}, {Name: "review"}, {Name: "trends"}, {Name: "forecast"},
+			{Name: "cohorts", By: "quarter"}, {Name: "releases"}, {Name: "deployments"},
+			{Name: "histogram"}, {Name: "heroes"}, {Name: "ownership_drift"}, {Name: "sla"},
+		},
+		Flags: map[string]string{"limit": "500", "exclude-partial-months": "true"},
+	},
+	"team-retro": {
+		Description: "How the team reviewed together, for a retrospective",
+		Sections: []SectionConfig{
+			{Name: "general"}, {Name: "review"}, {Name: "rounds"}, {Name: "size"}, {Name: "description"},
+			{Name: "first_review_histogram"}, {Name: "review_hours"}, {Name: "hotspots"},
+			{Name: "heroes"}, {Name: "approvals"}, {Name: "leaderboard"}, {Name: "stale"},
+		},
+		Flags: map[string]string{"anonymize": "true", "limit": "200"},
+	},
+}
+
+// presetFlagsDenied are flags a preset may not set: they pick the preset
+// and config, or take effect before presets are applied.
+var presetFlagsDenied = map[string]bool{"preset": true, "view": true, "config": true, "log-level": true, "log-format": true}
+
+// findPreset returns the named preset from config or the built-ins.
+func findPreset(name string) (Preset, bool) {
+	if p, ok := cfg.Presets[name]; ok {
+		return p, true
+	}
+	p, ok := builtinPresets[name]
+	return p, ok
+}
+
+// presetNames lists every available preset, sorted.
+func presetNames() []string {
+	seen := make(map[string]bool)
+	for n := range builtinPresets {
+		seen[n] = true
+	}
+	for n := range cfg.Presets {
+		seen[n] = true
+	}
+	var names []string
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validatePresets checks the sections of presets defined in config. Their
+// flags are checked when the preset is applied.
+func validatePresets(presets map[string]Preset) error {
+	for name, p := range presets {
+		if err := validateSections(p.Sections); err != nil {
+			return fmt.Errorf("presets.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// applyPreset sets the preset's flags on fs, skipping those given on the
+// command line, and makes its sections the report's.
+func applyPreset(fs *flag.FlagSet, name string) error {
+	p, ok := findPreset(name)
+	if !ok {
+		return fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(presetNames(), ", "))
+	}
+	return setPreset(fs, "preset "+name, p)
+}
+
+// setPreset applies p, named label in errors: its flags on fs, skipping
+// those given on the command line, and its sections.
+func setPreset(fs *flag.FlagSet, label string, p Preset) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	keys := make([]string, 0, len(p.Flags))
+	for k := range p.Flags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if fs.Lookup(k) == nil {
+			return fmt.Errorf("%s: unknown flag %q", label, k)
+		}
+		if presetFlagsDenied[k] {
+			return fmt.Errorf("%s: flag %q can't be set by a preset", label, k)
+		}
+		if explicit[k] {
+			continue
+		}
+		if err := fs.Set(k, p.Flags[k]); err != nil {
+			return fmt.Errorf("%s: flag %s: %w", label, k, err)
+		}
+	}
+	if len(p.Sections) > 0 {
+		cfg.Sections = p.Sections
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Privacy modes decide how people appear in the report, for organizations
+// whose policy or works council rules out individual performance metrics.
+const (
+	privacyOff       = "off"
+	privacyTeam      = "team"      // People appear as their configured team
+	privacyAggregate = "aggregate" // Nobody is named; per-person lists become counts
+)
+
+// privacy is the active privacy mode.
+var privacy = privacyOff
+
+func setPrivacy(mode string) error {
+	switch mode {
+	case privacyOff, privacyAggregate:
+	case privacyTeam:
+		if len(cfg.Teams) == 0 {
+			return fmt.Errorf("privacy mode team needs teams: in config")
+		}
+	default:
+		return fmt.Errorf("unknown privacy mode %q (want off, team or aggregate)", mode)
+	}
+	privacy = mode
+	return nil
+}
+
+// namesShown reports whether the report may name individuals.
+func namesShown() bool { return privacy == privacyOff }
+
+// person returns how login appears in the report: the login itself, its
+// team in team mode, or a dash in aggregate mode.
+func person(login string) string {
+	switch privacy {
+	case privacyTeam:
+		if t := teamOf(login); t != "" {
+			return t
+		}
+		return "(no team)"
+	case privacyAggregate:
+		return "-"
+	}
+	return login
+}
+
+// byPerson re-keys per-login counts by person, so team mode adds members
+// up into their team.
+func byPerson(counts map[string]int) map[string]int {
+	out := make(map[string]int)
+	for login, n := range counts {
+		out[person(login)] += n
+	}
+	return out
+}
+
+// loadShares describes how concentrated counts are without naming anyone:
+// the share of the busiest one and three, and how many it takes to cover
+// half the total.
+func loadShares(counts map[string]int) (top1, top3 float64, half int) {
+	var values []int
+	total := 0
+	for _, n := range counts {
+		values = append(values, n)
+		total += n
+	}
+	if total == 0 {
+		return 0, 0, 0
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	covered := 0
+	for i, n := range values {
+		covered += n
+		if i == 0 {
+			top1 = float64(covered) / float64(total) * 100
+		}
+		if i < 3 {
+			top3 = float64(covered) / float64(total) * 100
+		}
+		if half == 0 && covered*2 >= total {
+			half = i + 1
+		}
+	}
+	return top1, top3, half
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// columnDwell is the time pr spent in each status column of a Projects (v2)
+// board, up to its merge (or now, while open), and the column it is in at
+// the end. A column visited twice counts both visits. Time before the first
+// known status, e.g. between the addition to the board and a status being
+// set, isn't attributed to any column.
+func columnDwell(pr PullRequest, project string, now time.Time) (dwell map[string]time.Duration, column string, since time.Time) {
+	end := now
+	if !pr.MergedAt.IsZero() {
+		end = pr.MergedAt
+	}
+	dwell = make(map[string]time.Duration)
+	since = pr.CreatedAt
+	for _, m := range pr.ProjectMoves {
+		if m.Project != project || m.At.After(end) {
+			continue
+		}
+		if m.From == "" && m.To == "" {
+			// Added to the board
+			column, since = "", m.At
+			continue
+		}
+		if column == "" {
+			column = m.From
+		}
+		if column != "" {
+			dwell[column] += max(0, m.At.Sub(since))
+		}
+		column, since = m.To, m.At
+	}
+	if column != "" {
+		dwell[column] += max(0, end.Sub(since))
+	}
+	return dwell, column, since
+}
+
+// printProjectDwell reports, per Projects (v2) board the PRs are on, how
+// long PRs sit in each status column and which open PRs have sat longest
+// in theirs.
+func printProjectDwell(merged, open []PullRequest, now time.Time) {
+	fmt.Println("🗂️  PROJECT BOARDS")
+	printExplanation("Time PRs spend in each status column of the GitHub Projects boards they are on (In Review, Blocked, QA, ...), up to the merge.",
+		"The PR's own timestamps miss the team's workflow states: a PR can wait days in Blocked or QA with nothing on the PR itself.")
+
+	all := append(append([]PullRequest(nil), merged...), open...)
+	prsOn := make(map[string]int)
+	for _, pr := range all {
+		seen := make(map[string]bool)
+		for _, m := range pr.ProjectMoves {
+			if !seen[m.Project] {
+				seen[m.Project] = true
+				prsOn[m.Project]++
+			}
+		}
+	}
+	if len(prsOn) == 0 {
+		fmt.Println("   No PR is on a Projects board, or the token can't read them (gh auth refresh -s read:project).")
+		return
+	}
+	var projects []string
+	for p := range prsOn {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if prsOn[projects[i]] != prsOn[projects[j]] {
+			return prsOn[projects[i]] > prsOn[projects[j]]
+		}
+		return projects[i] < projects[j]
+	})
+	if len(projects) > 3 {
+		fmt.Printf("   The 3 boards with the most PRs of %d.\n", len(projects))
+		projects = projects[:3]
+	}
+
+	for i, project := range projects {
+		if i > 0 {
+			fmt.Println()
+		}
+		byColumn := make(map[string][]time.Duration)
+		position := make(map[string][]int) // Where in a PR's path the column comes, to order columns as the workflow
+		var total time.Duration
+		type sitting struct {
+			pr     PullRequest
+			column string
+			since  time.Time
+		}
+		var waiting []sitting
+		for _, pr := range all {
+			dwell, column, since := columnDwell(pr, project, now)
+			for c, d := range dwell {
+				byColumn[c] = append(byColumn[c], d)
+				total += d
+			}
+			seen := make(map[string]bool)
+			for _, m := range pr.ProjectMoves {
+				for _, c := range []string{m.From, m.To} {
+					if m.Project == project && c != "" && !seen[c] {
+						seen[c] = true
+						position[c] = append(position[c], len(seen))
+					}
+				}
+			}
+			if pr.MergedAt.IsZero() && column != "" && !pr.IsDraft {
+				waiting = append(waiting, sitting{pr, column, since})
+			}
+		}
+
+		fmt.Printf("   Board %q: %d PRs\n", project, prsOn[project])
+		if total == 0 {
+			fmt.Println("   No status changes yet.")
+			continue
+		}
+		var columns []string
+		avgPos := make(map[string]float64)
+		for c := range byColumn {
+			columns = append(columns, c)
+			sum := 0
+			for _, p := range position[c] {
+				sum += p
+			}
+			avgPos[c] = float64(sum) / float64(max(1, len(position[c])))
+		}
+		sort.Slice(columns, func(i, j int) bool {
+			if avgPos[columns[i]] != avgPos[columns[j]] {
+				return avgPos[columns[i]] < avgPos[columns[j]]
+			}
+			return columns[i] < columns[j]
+		})
+
+		fmt.Printf("   %-20s %5s   %-12s %-12s %s\n", "Column", "PRs", "Median", "P90", "Share of time")
+		slowest, slowestShare := "", 0.0
+		for _, c := range columns {
+			var sum time.Duration
+			for _, d := range byColumn[c] {
+				sum += d
+			}
+			share := float64(sum) / float64(total) * 100
+			if share > slowestShare {
+				slowest, slowestShare = c, share
+			}
+			fmt.Printf("   %-20s %5d   %-12s %-12s %.0f%%\n", limitString(c, 20), len(byColumn[c]),
+				formatDuration(percentile(byColumn[c], 50)), formatDuration(percentile(byColumn[c], 90)), share)
+		}
+		if len(columns) > 1 {
+			fmt.Printf("   🐢 %s holds PRs longest: %.0f%% of their time on the board.\n", slowest, slowestShare)
+		}
+
+		if len(waiting) == 0 {
+			continue
+		}
+		sort.Slice(waiting, func(i, j int) bool { return waiting[i].since.Before(waiting[j].since) })
+		fmt.Println("   Open PRs longest in their column (drafts left out):")
+		for i, w := range waiting {
+			if i == 5 {
+				fmt.Printf("      ... and %d more\n", len(waiting)-5)
+				break
+			}
+			fmt.Printf("      #%-6d %-45s %s for %s\n", w.pr.Number, limitString(w.pr.Title, 45), w.column, formatDuration(now.Sub(w.since)))
+		}
+	}
+}
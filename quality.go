@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	htmlComment    = regexp.MustCompile(`(?s)<!--.*?-->`)
+	issueReference = regexp.MustCompile(`(?i:close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?|related to)\s+(?:[\w.-]+/[\w.-]+)?#\d+|github\.com/[\w.-]+/[\w.-]+/issues/\d+|\b[A-Z][A-Z0-9]+-[1-9]\d+\b`)
+	checklistItem  = regexp.MustCompile(`(?m)^\s*[-*]\s+\[[ xX]\]`)
+)
+
+// descriptionSignals are the quality heuristics of a PR's title and body.
+type descriptionSignals struct {
+	GoodTitle   bool // 10-72 characters
+	HasBody     bool // At least 50 characters once template comments are stripped
+	LinkedIssue bool
+	Checklist   bool
+}
+
+func (d descriptionSignals) score() int {
+	n := 0
+	for _, ok := range []bool{d.GoodTitle, d.HasBody, d.LinkedIssue, d.Checklist} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+func describePR(pr PullRequest) descriptionSignals {
+	body := strings.TrimSpace(htmlComment.ReplaceAllString(pr.Body, ""))
+	titleLen := len([]rune(strings.TrimSpace(pr.Title)))
+	return descriptionSignals{
+		GoodTitle:   titleLen >= 10 && titleLen <= 72,
+		HasBody:     len(body) >= 50,
+		LinkedIssue: pr.LinkedIssues > 0 || issueReference.MatchString(body),
+		Checklist:   checklistItem.MatchString(body),
+	}
+}
+
+func printDescriptionQuality(prs []PullRequest) {
+	fmt.Println("📝 PR DESCRIPTION QUALITY")
+	printExplanation("Checks title length, description body, linked issues and checklists, then compares merge speed.", "If well-described PRs merge faster here, that's a culture-changing stat worth sharing with the team.")
+
+	type cohort struct {
+		Cycle, FirstReview []time.Duration
+		Rounds, Reviewed   int
+	}
+	add := func(c *cohort, pr PullRequest) {
+		c.Cycle = append(c.Cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if pr.FirstReviewAt != nil && pr.FirstReviewAt.After(pr.CreatedAt) {
+			c.FirstReview = append(c.FirstReview, pr.FirstReviewAt.Sub(pr.CreatedAt))
+		}
+		if r := reviewRounds(pr); r > 0 {
+			c.Rounds += r
+			c.Reviewed++
+		}
+	}
+	rounds := func(c cohort) string {
+		if c.Reviewed == 0 {
+			return "-"
+		}
+		return formatNumber(float64(c.Rounds)/float64(c.Reviewed), 1)
+	}
+	medianOrDash := func(d []time.Duration) string {
+		if len(d) == 0 {
+			return "-"
+		}
+		return formatDuration(percentile(d, 50))
+	}
+
+	signals := []struct {
+		Label string
+		Has   func(descriptionSignals) bool
+	}{
+		{"Good title (10-72 chars)", func(d descriptionSignals) bool { return d.GoodTitle }},
+		{"Description body", func(d descriptionSignals) bool { return d.HasBody }},
+		{"Linked issue", func(d descriptionSignals) bool { return d.LinkedIssue }},
+		{"Checklist", func(d descriptionSignals) bool { return d.Checklist }},
+		{"Well-described (3+ of the above)", func(d descriptionSignals) bool { return d.score() >= 3 }},
+	}
+
+	fmt.Printf("   %-33s %6s   %-17s %-17s %s\n", "Signal", "Share", "Median Merge", "Median 1st Review", "Rounds")
+	fmt.Printf("   %-33s %6s   %-17s %-17s %s\n", "", "", "(with / without)", "(with / without)", "(w / w/o)")
+
+	var wellMerge, otherMerge []time.Duration
+	for i, s := range signals {
+		var with, without cohort
+		for _, pr := range prs {
+			if s.Has(describePR(pr)) {
+				add(&with, pr)
+			} else {
+				add(&without, pr)
+			}
+		}
+		share := float64(len(with.Cycle)) / float64(len(prs)) * 100
+		fmt.Printf("   %-33s %5.0f%%   %-17s %-17s %s / %s\n", s.Label, share,
+			medianOrDash(with.Cycle)+" / "+medianOrDash(without.Cycle),
+			medianOrDash(with.FirstReview)+" / "+medianOrDash(without.FirstReview),
+			rounds(with), rounds(without))
+		if i == len(signals)-1 {
+			wellMerge, otherMerge = with.Cycle, without.Cycle
+		}
+	}
+
+	if len(wellMerge) < 5 || len(otherMerge) < 5 {
+		fmt.Println("\n   (Not enough PRs on both sides for a reliable comparison.)")
+		return
+	}
+	well, other := percentile(wellMerge, 50), percentile(otherMerge, 50)
+	if other == 0 {
+		return
+	}
+	change := pctChange(float64(other), float64(well))
+	fmt.Println()
+	if change < 0 {
+		fmt.Printf("   💡 Well-described PRs merge %.0f%% faster here (median %s vs %s).\n", -change, formatDuration(well), formatDuration(other))
+	} else {
+		fmt.Printf("   💡 Well-described PRs don't merge faster here (median %s vs %s).\n", formatDuration(well), formatDuration(other))
+	}
+}
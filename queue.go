@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// weekOf returns midnight of the Monday starting t's week in the primary
+// timezone.
+func weekOf(t time.Time) time.Time {
+	local := t.In(primaryLocation)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, primaryLocation)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// queueWeek is one week of the review queue.
+type queueWeek struct {
+	Start    time.Time
+	Arrivals int             // PRs opened (drafts excluded)
+	Served   int             // PRs that got their first review
+	Merged   int             // PRs merged
+	Waits    []time.Duration // Wait for first review of the PRs opened this week
+}
+
+// queueWeeks tallies arrivals and first reviews per week. Weeks start after
+// the oldest merge in merged: earlier weeks lack the PRs that merged before
+// the fetched window and would look quiet.
+func queueWeeks(merged, open []PullRequest, now time.Time) []queueWeek {
+	if len(merged) == 0 {
+		return nil
+	}
+	oldest := merged[0].MergedAt
+	for _, pr := range merged {
+		if pr.MergedAt.Before(oldest) {
+			oldest = pr.MergedAt
+		}
+	}
+	first := weekOf(oldest).AddDate(0, 0, 7)
+	current := weekOf(now)
+
+	byWeek := make(map[time.Time]*queueWeek)
+	week := func(t time.Time) *queueWeek {
+		w := weekOf(t)
+		if w.Before(first) || !w.Before(current) {
+			return nil // Before the window, or still in progress
+		}
+		if byWeek[w] == nil {
+			byWeek[w] = &queueWeek{Start: w}
+		}
+		return byWeek[w]
+	}
+	for d := first; d.Before(current); d = d.AddDate(0, 0, 7) {
+		week(d)
+	}
+
+	for _, pr := range append(append([]PullRequest(nil), merged...), open...) {
+		if pr.IsDraft {
+			continue
+		}
+		if w := week(pr.CreatedAt); w != nil {
+			w.Arrivals++
+			switch {
+			case pr.FirstReviewAt != nil:
+				w.Waits = append(w.Waits, max(pr.FirstReviewAt.Sub(pr.CreatedAt), 0))
+			case pr.MergedAt.IsZero():
+				w.Waits = append(w.Waits, now.Sub(pr.CreatedAt)) // Still waiting, at least this long
+			}
+		}
+		if pr.FirstReviewAt != nil {
+			if w := week(*pr.FirstReviewAt); w != nil {
+				w.Served++
+			}
+		}
+		if !pr.MergedAt.IsZero() {
+			if w := week(pr.MergedAt); w != nil {
+				w.Merged++
+			}
+		}
+	}
+
+	var out []queueWeek
+	for _, w := range byWeek {
+		out = append(out, *w)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// waitFactor is the M/M/1 expected time in queue at utilization rho, in
+// multiples of the time to serve one PR. ok is false when the queue grows
+// without bound.
+func waitFactor(rho float64) (factor float64, ok bool) {
+	if rho >= 1 {
+		return 0, false
+	}
+	return rho / (1 - rho), true
+}
+
+func printQueueTheory(merged, open []PullRequest) {
+	fmt.Println("🚦 REVIEW QUEUE LOAD")
+	printExplanation("Weekly PR arrival rate against the team's review service rate, the resulting utilization, and how much queueing theory says waits grow.",
+		"Waits don't grow linearly with load. Near full utilization every extra PR adds more wait than the last, which is why wait times spike when the team is busy.")
+
+	weeks := queueWeeks(merged, open, clock())
+	if len(weeks) < 4 {
+		fmt.Println("   Not enough complete weeks in this dataset (need 4).")
+		return
+	}
+
+	// Service rate: what the team reviews in a busy week, i.e. its capacity
+	// when work is waiting
+	var served []float64
+	for _, w := range weeks {
+		served = append(served, float64(w.Served))
+	}
+	sort.Float64s(served)
+	mu := served[(len(served)-1)*9/10]
+	if mu == 0 {
+		fmt.Println("   No first reviews in this dataset.")
+		return
+	}
+
+	fmt.Printf("   Service rate (P90 of weekly first reviews): %.0f PRs/week\n\n", mu)
+	fmt.Printf("   %-12s %8s %8s %7s   %-14s %s\n", "Week", "Arrived", "Served", "Util.", "Observed wait", "Wait factor")
+	shown := weeks
+	if len(shown) > 12 {
+		shown = shown[len(shown)-12:]
+	}
+	var busy, calm []time.Duration
+	for _, w := range weeks {
+		if len(w.Waits) == 0 {
+			continue
+		}
+		switch rho := float64(w.Arrivals) / mu; {
+		case rho >= 0.8:
+			busy = append(busy, w.Waits...)
+		case rho < 0.5:
+			calm = append(calm, w.Waits...)
+		}
+	}
+	for _, w := range shown {
+		rho := float64(w.Arrivals) / mu
+		observed := "-"
+		if len(w.Waits) > 0 {
+			observed = formatDuration(percentile(w.Waits, 50))
+		}
+		predicted := "∞ (queue grows)"
+		if f, ok := waitFactor(rho); ok {
+			predicted = formatNumber(f, 1) + "x"
+		}
+		icon := ""
+		switch {
+		case rho >= 1:
+			icon = " 🚨"
+		case rho >= 0.8:
+			icon = " ⚠️"
+		}
+		fmt.Printf("   %-12s %8d %8d %6.0f%%   %-14s %s%s\n", formatDate(w.Start), w.Arrivals, w.Served, rho*100, observed, predicted, icon)
+	}
+
+	fmt.Println("\n   How wait grows with utilization (multiples of one PR's service time):")
+	for _, rho := range []float64{0.5, 0.7, 0.8, 0.9, 0.95} {
+		factor, _ := waitFactor(rho)
+		fmt.Printf("   %3.0f%%  %-5s %s\n", rho*100, formatNumber(factor, 1)+"x", strings.Repeat("█", min(int(factor*2), 40)))
+	}
+
+	if len(busy) > 0 && len(calm) > 0 {
+		fmt.Printf("\n   💡 Median wait in busy weeks (≥80%%): %s vs calm weeks (<50%%): %s.\n",
+			formatDuration(percentile(busy, 50)), formatDuration(percentile(calm, 50)))
+	}
+	fmt.Println("   (Wait factor: M/M/1 queue wait in multiples of one PR's service time, assuming random arrivals and one pooled queue. It shows the trend, not exact waits.)")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// RateLimit is the GraphQL API quota of the authenticated user.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Used      int
+	ResetAt   time.Time
+}
+
+// fetchRateLimit reads the GraphQL quota. The REST rate_limit endpoint does
+// not count against any quota itself.
+func fetchRateLimit(ctx context.Context, timeout time.Duration) (RateLimit, error) {
+	output, err := ghAPI(ctx, timeout, "rate_limit")
+	if err != nil {
+		return RateLimit{}, err
+	}
+	var resp struct {
+		Resources struct {
+			GraphQL struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Used      int   `json:"used"`
+				Reset     int64 `json:"reset"`
+			} `json:"graphql"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return RateLimit{}, err
+	}
+	g := resp.Resources.GraphQL
+	return RateLimit{Limit: g.Limit, Remaining: g.Remaining, Used: g.Used, ResetAt: time.Unix(g.Reset, 0)}, nil
+}
+
+// pageCost estimates the GraphQL points of fetching n pull requests with
+// the fields in f in one page, following GitHub's formula: one request per
+// connection per parent, divided by 100 and rounded, with a minimum of 1.
+func pageCost(n int, f prField) int {
+	return int(math.Max(1, math.Round(float64(1+n*f.connections())/100)))
+}
+
+// estimateRepoCost estimates the points the report spends on one repository.
+func estimateRepoCost(o reportOptions) int {
+	cost := 0
+	for _, step := range repoFetchPlan(o) {
+		cost += step.Cost
+	}
+	return cost
+}
+
+func printDryRun(repos []string, o reportOptions, rl *RateLimit) {
+	perRepo := estimateRepoCost(o)
+	total := perRepo * len(repos)
+
+	fmt.Println("🧮 DRY RUN: ESTIMATED API COST")
+	printExplanation("GraphQL points this run would spend, computed from the query shape before fetching anything.",
+		"Org-wide scans can burn through the hourly quota. Check the cost before you start.")
+
+	fmt.Printf("   Repositories:      %d\n", len(repos))
+	if o.Sample > 0 {
+		fmt.Printf("   Merged PR sample:  %d per repository, over %d months\n", o.Sample, o.SampleMonths)
+	} else {
+		fmt.Printf("   Merged PR limit:   %d per repository\n", o.Limit)
+	}
+	fmt.Printf("   Estimated cost:    ~%d points (%d per repository)\n", total, perRepo)
+	if skipped := reportFields(o).skipped(); len(skipped) > 0 {
+		fmt.Printf("   Fields skipped:    %s (no planned section reads them)\n", strings.Join(skipped, ", "))
+	}
+	printQuotaFit(total, rl)
+}
+
+// printQuotaFit prints the remaining quota and whether total points fit it.
+func printQuotaFit(total int, rl *RateLimit) {
+	if rl == nil {
+		fmt.Println("   Remaining quota:   unknown (could not read rate limit)")
+		return
+	}
+	fmt.Printf("   Remaining quota:   %d of %d (resets %s)\n", rl.Remaining, rl.Limit, rl.ResetAt.Format("15:04"))
+	if total > rl.Remaining {
+		fmt.Printf("\n   🚨 This run would exceed the remaining quota. Lower --limit or wait until %s.\n", rl.ResetAt.Format("15:04"))
+	} else {
+		fmt.Printf("\n   ✅ Fits the remaining quota (%.0f%% of it).\n", float64(total)/float64(max(rl.Remaining, 1))*100)
+	}
+}
+
+func printAPIBudget(start, end RateLimit, estimate int) {
+	fmt.Println("📡 API BUDGET")
+	printExplanation("GraphQL rate-limit points consumed by this run and what is left.",
+		"Shows how close scheduled or org-wide runs get to the hourly quota.")
+
+	used := start.Remaining - end.Remaining
+	if end.ResetAt.After(start.ResetAt) {
+		// The window reset mid-run; usage in the new window is all we know
+		used = end.Used
+	}
+	fmt.Printf("   Used:       %d points (estimated ~%d)\n", used, estimate)
+	fmt.Printf("   Remaining:  %d of %d\n", end.Remaining, end.Limit)
+	fmt.Printf("   Resets at:  %s\n", end.ResetAt.Format("15:04"))
+	if end.Limit > 0 && end.Remaining < end.Limit/10 {
+		fmt.Println("   ⚠️  Less than 10% of the quota is left.")
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// With BOTTLENECK_RECORD set to a directory, every gh api response is
+// sanitized and saved there as a fixture. With BOTTLENECK_REPLAY set,
+// responses are read from the fixtures instead of calling gh, so a recorded
+// run can be repeated offline: in tests, or to reproduce a bug report.
+const (
+	recordEnv = "BOTTLENECK_RECORD"
+	replayEnv = "BOTTLENECK_REPLAY"
+)
+
+// fixture is one recorded gh api call.
+type fixture struct {
+	Args   []string        `json:"args"`
+	Output json.RawMessage `json:"output,omitempty"` // JSON responses
+	Raw    string          `json:"raw,omitempty"`    // Anything else
+	Error  string          `json:"error,omitempty"`
+}
+
+// recorder sanitizes responses as they are recorded. People and teams get
+// pseudonyms, numbered in the order they are first seen, so the same login
+// maps to the same pseudonym across all fixtures of a run.
+type recorder struct {
+	mu     sync.Mutex
+	logins map[string]string
+	teams  map[string]string
+}
+
+var fixtureRecorder = &recorder{logins: make(map[string]string), teams: make(map[string]string)}
+
+var (
+	mention      = regexp.MustCompile(`(^|\s)@([\w-]+(?:/[\w.-]+)?)`)
+	emailAddress = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	branchWord   = regexp.MustCompile(`^(?i:feature|feat|bugfix|hotfix|fix|refactor|chore|maintenance|maint|docs?|tests?|ci|build|release|dependabot|renovate|main|master|develop|trunk)\b`)
+)
+
+// fixtureKey names the fixture of a call.
+func fixtureKey(args []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// replayFixture returns the recorded response to a gh api call.
+func replayFixture(dir string, args []string) ([]byte, error) {
+	key := fixtureKey(args)
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded response to gh api %s (fixture %s) in %s", args[0], key, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", key, err)
+	}
+	output := []byte(f.Raw)
+	if len(f.Output) > 0 {
+		output = f.Output
+	}
+	if f.Error != "" {
+		return output, errors.New(f.Error)
+	}
+	return output, nil
+}
+
+// record sanitizes a gh api response and saves it to dir. Logins in the
+// arguments are replaced too, so the calls a replay makes with pseudonyms
+// (for a user's status, say) find their fixture.
+func (r *recorder) record(dir string, args []string, output []byte, callErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := fixture{}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(output))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil {
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r.sanitize(v, "")); err != nil {
+			return err
+		}
+		f.Output = b.Bytes()
+	} else if len(output) > 0 {
+		f.Raw = r.maskText(string(output))
+	}
+	if callErr != nil {
+		f.Error = callErr.Error()
+	}
+	for _, a := range args {
+		f.Args = append(f.Args, r.sanitizeArg(a))
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fixtureKey(f.Args)+".json"), append(data, '\n'), 0o644)
+}
+
+// sanitize walks a decoded response, replacing what identifies people and
+// masking free text. Keys are visited in order so pseudonyms are stable.
+func (r *recorder) sanitize(v any, key string) any {
+	switch x := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			x[k] = r.sanitize(x[k], k)
+		}
+	case []any:
+		for i := range x {
+			x[i] = r.sanitize(x[i], key)
+		}
+	case string:
+		switch key {
+		case "login":
+			return r.login(x)
+		case "combinedSlug":
+			return r.team(x)
+		case "title":
+			return maskTitle(x)
+		case "body", "message":
+			return r.maskBody(x)
+		case "messageHeadline":
+			if mergeCommit.MatchString(x) {
+				return x
+			}
+			return maskWords(x)
+		case "text", "email":
+			return r.maskText(x)
+		case "headRefName", "baseRefName", "branchName":
+			return r.maskBranch(x)
+		case "nameWithOwner":
+			// Forks are named after their owner
+			if owner, name, ok := strings.Cut(x, "/"); ok {
+				if p, ok := r.logins[owner]; ok {
+					return p + "/" + name
+				}
+			}
+		}
+	}
+	return v
+}
+
+func (r *recorder) login(login string) string {
+	if login == "" || isBot(login) {
+		return login
+	}
+	if p, ok := r.logins[login]; ok {
+		return p
+	}
+	p := fmt.Sprintf("user-%d", len(r.logins)+1)
+	r.logins[login] = p
+	return p
+}
+
+// team keeps the organization of an org/team slug.
+func (r *recorder) team(slug string) string {
+	if p, ok := r.teams[slug]; ok {
+		return p
+	}
+	org, _, _ := strings.Cut(slug, "/")
+	p := fmt.Sprintf("%s/team-%d", org, len(r.teams)+1)
+	r.teams[slug] = p
+	return p
+}
+
+// maskText replaces email addresses and @mentions in files such as
+// CODEOWNERS, keeping everything else.
+func (r *recorder) maskText(s string) string {
+	s = emailAddress.ReplaceAllStringFunc(s, func(e string) string {
+		return r.login(e) + "@example.com"
+	})
+	return mention.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mention.FindStringSubmatch(m)
+		if strings.Contains(sub[2], "/") {
+			return sub[1] + "@" + r.team(sub[2])
+		}
+		return sub[1] + "@" + r.login(sub[2])
+	})
+}
+
+// maskBody masks a description line by line. Lines the analyses read
+// structure from are kept: headings, checklists, stack listings and
+// references such as "depends on #12" or "reverts #34".
+func (r *recorder) maskBody(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		switch {
+		case markdownHeading.MatchString(line), checklistLine.MatchString(line), hasStackMarker(line),
+			stackListEntry.MatchString(line), dependencyPhrase.MatchString(line), revertReference.MatchString(line):
+			lines[i] = r.maskText(line)
+		default:
+			lines[i] = maskWords(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maskBranch masks a branch name segment by segment, keeping what the
+// branch analyses read: type prefixes such as feature/, default branch
+// names and the shape of ticket IDs. Segments naming a login get its
+// pseudonym, so stacked branches still chain.
+func (r *recorder) maskBranch(ref string) string {
+	segments := strings.Split(ref, "/")
+	for i, s := range segments {
+		if p, ok := r.logins[s]; ok {
+			segments[i] = p
+			continue
+		}
+		keep := len(branchWord.FindString(s))
+		segments[i] = s[:keep] + strings.Map(func(c rune) rune {
+			switch {
+			case unicode.IsUpper(c):
+				return 'X'
+			case unicode.IsLetter(c):
+				return 'x'
+			}
+			return c
+		}, s[keep:])
+	}
+	return strings.Join(segments, "/")
+}
+
+// sanitizeArg replaces the logins already given pseudonyms in a gh api
+// argument, such as user(login: "octocat") in a query.
+func (r *recorder) sanitizeArg(arg string) string {
+	for real, p := range r.logins {
+		arg = strings.ReplaceAll(arg, fmt.Sprintf("%q", real), fmt.Sprintf("%q", p))
+	}
+	return arg
+}
+
+// maskTitle masks a PR title, keeping a conventional-commit or maintenance
+// prefix and the shape of reverts. Masking is deterministic, so a revert
+// still names the title of the PR it reverts.
+func maskTitle(title string) string {
+	if m := revertTitle.FindStringSubmatch(title); m != nil {
+		return `Revert "` + maskTitle(m[1]) + `"`
+	}
+	keep := 0
+	if loc := conventionalTitle.FindStringIndex(title); loc != nil {
+		keep = loc[1]
+	} else if loc := maintenanceTitle.FindStringIndex(title); loc != nil {
+		keep = loc[1]
+	}
+	return title[:keep] + maskWords(title[keep:])
+}
+
+// maskWords replaces every letter with x, keeping the length, numbers and
+// punctuation.
+func maskWords(s string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) {
+			return 'x'
+		}
+		return c
+	}, s)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRecorder() *recorder {
+	return &recorder{logins: make(map[string]string), teams: make(map[string]string)}
+}
+
+func TestRecorderSanitizes(t *testing.T) {
+	r := newTestRecorder()
+	var v any
+	if err := json.Unmarshal([]byte(`{
+		"author": {"login": "octocat"},
+		"mergedBy": {"login": "renovate[bot]"},
+		"reviews": {"nodes": [{"author": {"login": "hubot"}}, {"author": {"login": "octocat"}}]},
+		"reviewRequests": {"nodes": [{"requestedReviewer": {"combinedSlug": "acme/payments"}}]},
+		"title": "fix(billing): refund Initech twice",
+		"baseRefName": "octocat/refunds",
+		"headRefName": "feature/PAY-12-Initech",
+		"body": "## Summary\nRefunds Initech.\n- [x] Tests added\nDepends on #12",
+		"commits": {"nodes": [{"commit": {"messageHeadline": "Merge branch 'main' into fix"}}, {"commit": {"messageHeadline": "Secret plan"}}]}
+	}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(r.sanitize(v, ""))
+	got := string(out)
+
+	for _, leaked := range []string{"octocat", "hubot", "payments", "Initech", "Secret"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("%q survived sanitizing: %s", leaked, got)
+		}
+	}
+	for _, kept := range []string{
+		`"renovate[bot]"`, `"acme/team-1"`, `"fix(billing): xxxxxx xxxxxxx xxxxx"`,
+		`"user-1/xxxxxxx"`, `"feature/XXX-12-Xxxxxxx"`, `## Summary`, `- [x] Tests added`, `Depends on #12`, `Merge branch 'main' into fix`,
+	} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("want %s kept in %s", kept, got)
+		}
+	}
+	if r.login("octocat") != "user-1" || r.login("hubot") != "user-2" {
+		t.Errorf("pseudonyms not numbered in order: %v", r.logins)
+	}
+}
+
+func TestMaskTitleKeepsReverts(t *testing.T) {
+	title := "feat: add the thing"
+	got := maskTitle(`Revert "` + title + `"`)
+	if want := `Revert "` + maskTitle(title) + `"`; got != want {
+		t.Errorf("maskTitle(revert) = %q, want %q", got, want)
+	}
+	if got := maskTitle("Bump lodash from 4.17.20 to 4.17.21"); !strings.HasPrefix(got, "Bump ") || strings.Contains(got, "lodash") {
+		t.Errorf("maskTitle(bump) = %q", got)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestRecorder()
+	pr := []byte(`{"data": {"repository": {"pullRequest": {"author": {"login": "octocat"}}}}}`)
+	if err := r.record(dir, []string{"graphql", "-f", "query=pr"}, pr, nil); err != nil {
+		t.Fatal(err)
+	}
+	// A call made with the login is recorded under its pseudonym, so a
+	// replay of the sanitized data finds it
+	status := []byte(`{"data": {"u0": {"login": "octocat", "status": {"message": "On leave at Initech"}}}}`)
+	if err := r.record(dir, []string{"graphql", "-f", `query=user(login: "octocat")`}, status, errors.New("exit status 1")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(replayEnv, dir)
+	ctx := context.Background()
+	out, err := ghGraphQL(ctx, "pr", time.Second)
+	if err != nil || !strings.Contains(string(out), `"user-1"`) {
+		t.Errorf("replay pr = %s, %v", out, err)
+	}
+	out, err = ghAPI(ctx, time.Second, "graphql", "-f", `query=user(login: "user-1")`)
+	if err == nil || err.Error() != "exit status 1" {
+		t.Errorf("replay status err = %v, want the recorded error", err)
+	}
+	if strings.Contains(string(out), "Initech") {
+		t.Errorf("status message not masked: %s", out)
+	}
+	if _, err := ghAPI(ctx, time.Second, "rate_limit"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("missing fixture err = %v", err)
+	}
+}
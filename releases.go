@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fetchPublishedReleases lists up to 100 published GitHub releases, newest
+// first, dated by when they were published. Drafts and prereleases are
+// skipped.
+func fetchPublishedReleases(ctx context.Context, owner, name string, timeout time.Duration) ([]Release, error) {
+	query := fmt.Sprintf(`
+query {
+  repository(owner: %q, name: %q) {
+    releases(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { tagName publishedAt isDraft isPrerelease }
+    }
+  }
+}`, owner, name)
+	output, err := ghGraphQL(ctx, query, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				Releases struct {
+					Nodes []struct {
+						TagName      string     `json:"tagName"`
+						PublishedAt  *time.Time `json:"publishedAt"`
+						IsDraft      bool       `json:"isDraft"`
+						IsPrerelease bool       `json:"isPrerelease"`
+					} `json:"nodes"`
+				} `json:"releases"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, err
+	}
+	var releases []Release
+	for _, n := range resp.Data.Repository.Releases.Nodes {
+		if n.IsDraft || n.IsPrerelease || n.PublishedAt == nil {
+			continue
+		}
+		releases = append(releases, Release{Tag: n.TagName, Date: *n.PublishedAt})
+	}
+	return releases, nil
+}
+
+// fetchReleaseDates returns the repository's releases, oldest first: its
+// published GitHub releases, or its tags when it publishes none. Tags not
+// matching pattern are dropped. source says which was used.
+func fetchReleaseDates(ctx context.Context, owner, name string, pattern *regexp.Regexp, timeout, delay time.Duration) (releases []Release, source string, err error) {
+	releases, err = fetchPublishedReleases(ctx, owner, name, timeout)
+	source = "GitHub releases"
+	if err == nil && len(releases) == 0 {
+		releases, err = fetchReleases(ctx, owner, name, 100, timeout, delay)
+		source = "tags"
+	}
+	var out []Release
+	for _, r := range releases {
+		if pattern == nil || pattern.MatchString(r.Tag) {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, source, err
+}
+
+// releasedAt returns the first release at or after t in releases (oldest
+// first).
+func releasedAt(releases []Release, t time.Time) (Release, bool) {
+	i := sort.Search(len(releases), func(i int) bool { return !releases[i].Date.Before(t) })
+	if i == len(releases) {
+		return Release{}, false
+	}
+	return releases[i], true
+}
+
+func printReleaseCadence(prs []PullRequest, releases []Release, source string) {
+	fmt.Println("🚢 RELEASE CADENCE")
+	printExplanation("How often releases ship, how long merged PRs wait for the next release, and what is merged but not yet released.",
+		"Merging fast means little if changes sit unreleased for a month. Merge-to-release is the closest proxy for merge-to-production.")
+
+	if len(releases) == 0 {
+		fmt.Println("   No releases or tags found.")
+		return
+	}
+	now := clock()
+	var oldest time.Time
+	for _, pr := range prs {
+		if oldest.IsZero() || pr.MergedAt.Before(oldest) {
+			oldest = pr.MergedAt
+		}
+	}
+
+	// Cadence over the window the merged PRs cover
+	var window []Release
+	for _, r := range releases {
+		if !r.Date.Before(oldest) {
+			window = append(window, r)
+		}
+	}
+	last := releases[len(releases)-1]
+	fmt.Printf("   Source: %s. Latest: %s on %s (%s ago)\n", source, last.Tag, formatDate(last.Date), formatDuration(now.Sub(last.Date)))
+	if len(window) > 0 && !oldest.IsZero() {
+		months := now.Sub(oldest).Hours() / (24 * 30.44)
+		fmt.Printf("   Releases since %s: %d (%s per month)\n", formatDate(oldest), len(window), formatNumber(float64(len(window))/max(months, 1), 1))
+	}
+	var intervals []time.Duration
+	for i := 1; i < len(window); i++ {
+		intervals = append(intervals, window[i].Date.Sub(window[i-1].Date))
+	}
+	if len(intervals) > 0 {
+		fmt.Printf("   Time between releases: median %s, longest %s\n", formatDuration(percentile(intervals, 50)), formatDuration(percentile(intervals, 100)))
+	}
+
+	var toRelease, waiting []time.Duration
+	var unreleased []PullRequest
+	for _, pr := range prs {
+		if r, ok := releasedAt(releases, pr.MergedAt); ok {
+			toRelease = append(toRelease, r.Date.Sub(pr.MergedAt))
+		} else {
+			unreleased = append(unreleased, pr)
+			waiting = append(waiting, now.Sub(pr.MergedAt))
+		}
+	}
+	if len(toRelease) > 0 {
+		fmt.Printf("\n   Merge → release: median %s, P90 %s (%d PRs)\n", formatDuration(percentile(toRelease, 50)), formatDuration(percentile(toRelease, 90)), len(toRelease))
+	}
+
+	fmt.Println()
+	if len(unreleased) == 0 {
+		fmt.Println("   ✅ Every merged PR has been released.")
+		return
+	}
+	sort.Slice(unreleased, func(i, j int) bool { return unreleased[i].MergedAt.Before(unreleased[j].MergedAt) })
+	oldestWait := percentile(waiting, 100)
+	icon := "📦"
+	if oldestWait > 30*24*time.Hour {
+		icon = "⚠️ "
+	}
+	fmt.Printf("   %s Merged but unreleased: %d PRs, oldest merged %s ago, median %s\n", icon, len(unreleased), formatDuration(oldestWait), formatDuration(percentile(waiting, 50)))
+	var refs []string
+	for i, pr := range unreleased {
+		if i == 5 {
+			refs = append(refs, fmt.Sprintf("... and %d more", len(unreleased)-5))
+			break
+		}
+		refs = append(refs, fmt.Sprintf("#%d", pr.Number))
+	}
+	fmt.Printf("      Oldest first: %s\n", strings.Join(refs, ", "))
+	if oldestWait > 30*24*time.Hour {
+		fmt.Println("   Action: Cut a release, or automate releases on merge so finished work reaches users.")
+	}
+	if source == "tags" {
+		fmt.Println("   (Dated by the tagged commit, so a release tagged later on an older commit looks earlier.)")
+	}
+}
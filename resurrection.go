@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// staleSighting is the first snapshot that saw a PR stale.
+type staleSighting struct {
+	Number int
+	Since  time.Time // Last activity before going stale
+	Seen   time.Time // When the snapshot was taken
+}
+
+// staleSightings lists every PR recorded stale in history, as of the
+// earliest snapshot that saw it.
+func staleSightings(history []Snapshot) []staleSighting {
+	seen := make(map[int]bool)
+	var out []staleSighting
+	for _, snap := range history {
+		for _, s := range snap.StalePRs {
+			if !seen[s.Number] {
+				seen[s.Number] = true
+				out = append(out, staleSighting{Number: s.Number, Since: s.Since, Seen: snap.Taken})
+			}
+		}
+	}
+	return out
+}
+
+// revivalTrigger names the first activity on pr after since, the moment it
+// went quiet, and when it happened. It returns "" when nothing fetched
+// happened before the PR merged or, for open PRs, until now.
+func revivalTrigger(pr PullRequest, since time.Time) (string, time.Time) {
+	var trigger string
+	var at time.Time
+	consider := func(t time.Time, what string) {
+		if t.After(since) && (at.IsZero() || t.Before(at)) {
+			trigger, at = what, t
+		}
+	}
+
+	// Reviewers involved before the PR went quiet, to tell a new reviewer
+	// from a re-request
+	involved := make(map[string]bool)
+	for _, r := range pr.ReviewRequests {
+		if !r.At.After(since) {
+			involved[r.Reviewer] = true
+		}
+	}
+	for _, r := range pr.Reviews {
+		if !r.CreatedAt.After(since) {
+			involved[r.Author] = true
+		}
+	}
+
+	for _, c := range pr.Comments {
+		switch {
+		case c.Author == pr.Author:
+			consider(c.At, "Author comment")
+		case isBot(c.Author):
+			consider(c.At, "Bot comment")
+		default:
+			consider(c.At, "Nudge comment")
+		}
+	}
+	for _, r := range pr.ReviewRequests {
+		if involved[r.Reviewer] {
+			consider(r.At, "Review re-requested")
+		} else {
+			consider(r.At, "New reviewer")
+		}
+	}
+	for _, r := range pr.Reviews {
+		consider(r.CreatedAt, "Review")
+	}
+	for _, t := range pr.CommitTimes {
+		consider(t, "New commits")
+	}
+	if !pr.MergedAt.IsZero() && !at.Before(pr.MergedAt) {
+		return "", time.Time{}
+	}
+	return trigger, at
+}
+
+// printResurrections follows up on PRs that earlier snapshots recorded as
+// stale: how many were merged after all, and what activity came first when
+// they came back to life. It returns false when no snapshot recorded any.
+func printResurrections(history []Snapshot, merged, open []PullRequest) bool {
+	sightings := staleSightings(history)
+	if len(sightings) == 0 {
+		return false
+	}
+	fmt.Println("🧟 STALE PR RESURRECTIONS")
+	printExplanation("PRs that an earlier snapshot (--snapshot) found stale, >7 days inactive, followed up: merged since, revived but still open, still stale, or gone.",
+		"Shows whether nudging works: which activity preceded a stale PR coming back, and whether it then made it in.")
+
+	mergedBy := make(map[int]PullRequest, len(merged))
+	for _, pr := range merged {
+		mergedBy[pr.Number] = pr
+	}
+	openBy := make(map[int]PullRequest, len(open))
+	for _, pr := range open {
+		openBy[pr.Number] = pr
+	}
+
+	type outcome struct {
+		Revived, Merged int
+	}
+	triggers := make(map[string]*outcome)
+	var quiet, toMerge []time.Duration
+	resurrected, revivedOpen, stillStale, gone := 0, 0, 0, 0
+	cutoff := staleCutoff(clock())
+	for _, s := range sightings {
+		pr, isMerged := mergedBy[s.Number]
+		if !isMerged {
+			var isOpen bool
+			if pr, isOpen = openBy[s.Number]; !isOpen {
+				gone++
+				continue
+			}
+			if isStale(pr, cutoff) {
+				stillStale++
+				continue
+			}
+		}
+
+		trigger, at := revivalTrigger(pr, s.Since)
+		if trigger == "" {
+			trigger = "Other activity"
+			if isMerged {
+				trigger = "Merged as is"
+			}
+		}
+		if triggers[trigger] == nil {
+			triggers[trigger] = &outcome{}
+		}
+		triggers[trigger].Revived++
+		if !at.IsZero() {
+			quiet = append(quiet, at.Sub(s.Since))
+		}
+		if isMerged {
+			resurrected++
+			triggers[trigger].Merged++
+			if !at.IsZero() {
+				toMerge = append(toMerge, pr.MergedAt.Sub(at))
+			}
+		} else {
+			revivedOpen++
+		}
+	}
+
+	total := len(sightings)
+	fmt.Printf("   Stale PRs tracked: %d (snapshots since %s)\n", total, formatDate(history[0].Taken))
+	fmt.Printf("   Merged since: %d (%.0f%% resurrection rate)   Revived, still open: %d   Still stale: %d   Closed or not fetched: %d\n",
+		resurrected, float64(resurrected)/float64(total)*100, revivedOpen, stillStale, gone)
+	if len(quiet) > 0 {
+		fmt.Printf("   Median time stale before revival: %s", formatDuration(percentile(quiet, 50)))
+		if len(toMerge) > 0 {
+			fmt.Printf("   Median revival to merge: %s", formatDuration(percentile(toMerge, 50)))
+		}
+		fmt.Println()
+	}
+	if len(triggers) == 0 {
+		return true
+	}
+
+	names := make([]string, 0, len(triggers))
+	for t := range triggers {
+		names = append(names, t)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if triggers[names[i]].Revived != triggers[names[j]].Revived {
+			return triggers[names[i]].Revived > triggers[names[j]].Revived
+		}
+		return names[i] < names[j]
+	})
+	fmt.Printf("\n   %-22s %7s %7s %s\n", "What came first", "Revived", "Merged", "Merge rate")
+	for _, t := range names {
+		o := triggers[t]
+		fmt.Printf("   %-22s %7d %7d %9.0f%%\n", t, o.Revived, o.Merged, float64(o.Merged)/float64(o.Revived)*100)
+	}
+
+	if n := triggers["Nudge comment"]; n != nil && n.Revived >= 3 {
+		rest, restMerged := 0, 0
+		for t, o := range triggers {
+			if t != "Nudge comment" {
+				rest += o.Revived
+				restMerged += o.Merged
+			}
+		}
+		nudged := float64(n.Merged) / float64(n.Revived)
+		switch {
+		case rest > 0 && nudged >= float64(restMerged)/float64(rest)+0.15:
+			fmt.Println("\n   💡 Nudges work here: PRs revived by a comment merge more often than those revived otherwise.")
+		case rest > 0 && nudged+0.15 <= float64(restMerged)/float64(rest):
+			fmt.Println("\n   💡 Nudge comments revive PRs that then stall again. A new reviewer or a rebase does more.")
+		}
+	}
+	fmt.Println("   (Nudge: a comment by someone other than the author or a bot. Closed PRs and merges beyond the fetched window count as gone.)")
+	return true
+}
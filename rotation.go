@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RotationConfig tunes who is eligible for the review rotation.
+type RotationConfig struct {
+	// Reviewers explicitly lists eligible reviewers per group. Groups not
+	// listed use everyone who reviewed them at least twice.
+	Reviewers map[string][]string `yaml:"reviewers"`
+	// Exclude removes people from every rotation.
+	Exclude []string `yaml:"exclude"`
+}
+
+// RotationPlan is a weekly reviewer-on-duty schedule per group.
+type RotationPlan struct {
+	Repo      string         `json:"repo"`
+	Generated time.Time      `json:"generated"`
+	Weeks     []RotationWeek `json:"weeks"`
+}
+
+type RotationWeek struct {
+	Start       string               `json:"start"` // Monday, YYYY-MM-DD
+	Assignments []RotationAssignment `json:"assignments"`
+}
+
+type RotationAssignment struct {
+	Group   string `json:"group"`
+	Primary string `json:"primary"`
+	Backup  string `json:"backup,omitempty"`
+}
+
+// Current returns the week of the plan that contains t.
+func (p *RotationPlan) Current(t time.Time) *RotationWeek {
+	for i := range p.Weeks {
+		start, err := time.ParseInLocation("2006-01-02", p.Weeks[i].Start, primaryLocation)
+		if err != nil {
+			continue
+		}
+		if !t.Before(start) && t.Before(start.AddDate(0, 0, 7)) {
+			return &p.Weeks[i]
+		}
+	}
+	return nil
+}
+
+// For returns the assignment of a group in this week.
+func (w *RotationWeek) For(group string) *RotationAssignment {
+	for i := range w.Assignments {
+		if w.Assignments[i].Group == group {
+			return &w.Assignments[i]
+		}
+	}
+	return nil
+}
+
+// buildRotation schedules a primary and backup reviewer per group per week.
+// Each person's load starts at their historical reviews per week; every shift
+// adds the group's typical weekly review volume, and the least loaded
+// available person is picked next.
+func buildRotation(repo string, prs []PullRequest, start time.Time, weeks int, avail *Availability) RotationPlan {
+	excluded := make(map[string]bool)
+	for _, e := range cfg.Rotation.Exclude {
+		excluded[strings.ToLower(e)] = true
+	}
+
+	// Historical reviews per group and overall span of the dataset
+	groupReviews := make(map[string]map[string]int)
+	var first, last time.Time
+	for _, pr := range prs {
+		if first.IsZero() || pr.CreatedAt.Before(first) {
+			first = pr.CreatedAt
+		}
+		if pr.MergedAt.After(last) {
+			last = pr.MergedAt
+		}
+		seenGroups := make(map[string]bool)
+		for _, path := range pr.FilePaths {
+			g := groupFor(path)
+			if seenGroups[g] {
+				continue
+			}
+			seenGroups[g] = true
+			if groupReviews[g] == nil {
+				groupReviews[g] = make(map[string]int)
+			}
+			for _, r := range pr.Reviewers {
+				groupReviews[g][r]++
+			}
+		}
+	}
+	spanWeeks := last.Sub(first).Hours() / (24 * 7)
+	if spanWeeks < 1 {
+		spanWeeks = 1
+	}
+
+	counts, _ := countReviews(prs)
+	load := make(map[string]float64)
+	for name, c := range counts {
+		load[name] = float64(c) / spanWeeks
+	}
+
+	// Eligible reviewers and weekly volume per group
+	eligible := make(map[string][]string)
+	groupWeekly := make(map[string]float64)
+	for g, reviewers := range groupReviews {
+		total := 0
+		for _, c := range reviewers {
+			total += c
+		}
+		groupWeekly[g] = float64(total) / spanWeeks
+
+		if explicit, ok := cfg.Rotation.Reviewers[g]; ok {
+			eligible[g] = explicit
+			continue
+		}
+		for r, c := range reviewers {
+			if c >= 2 && !isBot(r) {
+				eligible[g] = append(eligible[g], r)
+			}
+		}
+	}
+	for g, explicit := range cfg.Rotation.Reviewers {
+		if _, ok := eligible[g]; !ok {
+			eligible[g] = explicit
+		}
+	}
+
+	var groups []string
+	for g, people := range eligible {
+		var keep []string
+		for _, p := range people {
+			if !excluded[strings.ToLower(p)] {
+				keep = append(keep, p)
+			}
+		}
+		eligible[g] = keep
+		if len(keep) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	// Busiest groups pick first
+	sort.Slice(groups, func(i, j int) bool {
+		if groupWeekly[groups[i]] != groupWeekly[groups[j]] {
+			return groupWeekly[groups[i]] > groupWeekly[groups[j]]
+		}
+		return groups[i] < groups[j]
+	})
+
+	plan := RotationPlan{Repo: repo, Generated: clock()}
+	for w := 0; w < weeks; w++ {
+		weekStart := start.AddDate(0, 0, 7*w)
+		week := RotationWeek{Start: weekStart.Format("2006-01-02")}
+
+		for _, g := range groups {
+			var candidates []string
+			for _, p := range eligible[g] {
+				if away, _ := avail.Away(p, weekStart); !away {
+					candidates = append(candidates, p)
+				}
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			sort.Slice(candidates, func(i, j int) bool {
+				if load[candidates[i]] != load[candidates[j]] {
+					return load[candidates[i]] < load[candidates[j]]
+				}
+				return candidates[i] < candidates[j]
+			})
+
+			a := RotationAssignment{Group: g, Primary: candidates[0]}
+			if len(candidates) > 1 {
+				a.Backup = candidates[1]
+			}
+			shift := groupWeekly[g]
+			if shift == 0 {
+				shift = 1
+			}
+			load[a.Primary] += shift
+			week.Assignments = append(week.Assignments, a)
+		}
+		plan.Weeks = append(plan.Weeks, week)
+	}
+	return plan
+}
+
+// nextMonday returns midnight of the first Monday after t.
+func nextMonday(t time.Time) time.Time {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for {
+		d = d.AddDate(0, 0, 1)
+		if d.Weekday() == time.Monday {
+			return d
+		}
+	}
+}
+
+func writeRotation(w io.Writer, plan RotationPlan, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	case "markdown":
+		fmt.Fprintf(w, "# Review Rotation: %s\n\n", plan.Repo)
+		fmt.Fprintln(w, "| Week | Group | Primary | Backup |")
+		fmt.Fprintln(w, "|------|-------|---------|--------|")
+		for _, week := range plan.Weeks {
+			for _, a := range week.Assignments {
+				fmt.Fprintf(w, "| %s | %s | @%s | %s |\n", week.Start, a.Group, a.Primary, mentionOrDash(a.Backup))
+			}
+		}
+		return nil
+	case "text":
+		fmt.Fprintln(w, "🔄 REVIEW ROTATION")
+		fmt.Fprintln(w, "   • Concept: Weekly reviewer-on-duty plan per group, balanced by historical review load.")
+		fmt.Fprintln(w, "   • Why:     Spreads review work deliberately instead of letting it pile up on the same heroes.")
+		for _, week := range plan.Weeks {
+			fmt.Fprintf(w, "\n   Week of %s\n", week.Start)
+			for _, a := range week.Assignments {
+				backup := ""
+				if a.Backup != "" {
+					backup = fmt.Sprintf(" (backup: %s)", a.Backup)
+				}
+				fmt.Fprintf(w, "   %-20s: %s%s\n", a.Group, a.Primary, backup)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (use text, json or markdown)", format)
+}
+
+func mentionOrDash(login string) string {
+	if login == "" {
+		return "-"
+	}
+	return "@" + login
+}
+
+func runRotation(args []string) {
+	fs := flag.NewFlagSet("rotation", flag.ExitOnError)
+	limit := fs.Int("limit", 300, "Max number of merged PRs to learn review load from")
+	weeks := fs.Int("weeks", 4, "Number of weeks to plan")
+	startFlag := fs.String("start", "", "First week's Monday as YYYY-MM-DD (default: next Monday)")
+	format := fs.String("format", "text", "Output format: text, json or markdown")
+	output := fs.String("output", "", "Write the plan to this file instead of stdout")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck rotation [flags] <owner/repo>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+
+	start := nextMonday(clock().In(primaryLocation))
+	if *startFlag != "" {
+		start, err = time.ParseInLocation("2006-01-02", *startFlag, primaryLocation)
+		if err != nil {
+			slog.Error("invalid --start", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	prs, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+	if err != nil && len(prs) == 0 {
+		slog.Error("fetching merged PRs", "repo", repo, "err", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Warn("planning from a partial dataset", "repo", repo, "prs", len(prs), "err", err)
+	}
+
+	var reviewers []string
+	counts, _ := countReviews(prs)
+	for r := range counts {
+		reviewers = append(reviewers, r)
+	}
+	avail, warnings := loadAvailability(ctx, cfg.Availability, reviewers, *reqTimeout)
+	for _, w := range warnings {
+		slog.Warn("availability", "err", w)
+	}
+
+	plan := buildRotation(repo, prs, start, *weeks, avail)
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			slog.Error("creating output file", "err", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeRotation(out, plan, *format); err != nil {
+		slog.Error("writing rotation", "err", err)
+		os.Exit(1)
+	}
+}
+
+// loadRotation reads a plan previously exported with --format json.
+func loadRotation(path string) (*RotationPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan RotationPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &plan, nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reviewRounds counts the review iterations a PR went through.
+// The first review opens round 1. Every "changes requested" review that is
+// answered by new commits and then reviewed again starts another round.
+// PRs that were never reviewed have 0 rounds.
+func reviewRounds(pr PullRequest) int {
+	var reviews []Review
+	for _, r := range pr.Reviews {
+		if r.Author != "" && r.Author != pr.Author {
+			reviews = append(reviews, r)
+		}
+	}
+	if len(reviews) == 0 {
+		return 0
+	}
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].CreatedAt.Before(reviews[j].CreatedAt) })
+
+	rounds := 1
+	var changesRequestedAt *time.Time
+	for _, r := range reviews {
+		if changesRequestedAt != nil && hasCommitBetween(pr.CommitTimes, *changesRequestedAt, r.CreatedAt) {
+			rounds++
+			changesRequestedAt = nil
+		}
+		if r.State == "CHANGES_REQUESTED" {
+			t := r.CreatedAt
+			changesRequestedAt = &t
+		}
+	}
+	return rounds
+}
+
+func hasCommitBetween(commits []time.Time, from, to time.Time) bool {
+	for _, c := range commits {
+		if c.After(from) && c.Before(to) {
+			return true
+		}
+	}
+	return false
+}
+
+func printReviewRounds(prs []PullRequest) {
+	fmt.Println("🔁 REVIEW ROUNDS")
+	printExplanation("Counts review iterations per PR (changes requested → new commits → re-review).", "One-round merges signal clear PRs and aligned reviewers. Five-round slogs burn both sides.")
+
+	buckets := []struct {
+		Label string
+		Count int
+	}{
+		{"0 (none)", 0},
+		{"1 round", 0},
+		{"2 rounds", 0},
+		{"3 rounds", 0},
+		{"4 rounds", 0},
+		{"5+ rounds", 0},
+	}
+
+	type DirStat struct {
+		TotalRounds int
+		Count       int
+	}
+	stats := make(map[string]*DirStat)
+
+	totalRounds, reviewed := 0, 0
+	for _, pr := range prs {
+		rounds := reviewRounds(pr)
+		idx := rounds
+		if idx > 5 {
+			idx = 5
+		}
+		buckets[idx].Count++
+
+		if rounds == 0 {
+			continue
+		}
+		totalRounds += rounds
+		reviewed++
+
+		seenDirs := make(map[string]bool)
+		for _, path := range pr.FilePaths {
+			root := groupFor(path)
+			if seenDirs[root] {
+				continue
+			}
+			seenDirs[root] = true
+			if _, exists := stats[root]; !exists {
+				stats[root] = &DirStat{}
+			}
+			stats[root].TotalRounds += rounds
+			stats[root].Count++
+		}
+	}
+
+	if reviewed == 0 {
+		fmt.Println("   No reviews detected (Direct merges?).")
+		return
+	}
+
+	maxCount := 0
+	for _, b := range buckets {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	for _, b := range buckets {
+		bar := strings.Repeat("■", (b.Count*20)/maxCount)
+		fmt.Printf("   %-10s : %-20s (%d)\n", b.Label, bar, b.Count)
+	}
+	fmt.Printf("\n   Avg Rounds (reviewed PRs): %s\n", formatNumber(float64(totalRounds)/float64(reviewed), 1))
+
+	if len(stats) == 0 {
+		return
+	}
+
+	var dirs []string
+	for d := range stats {
+		dirs = append(dirs, d)
+	}
+	avg := func(d string) float64 { return float64(stats[d].TotalRounds) / float64(stats[d].Count) }
+	sort.Slice(dirs, func(i, j int) bool {
+		if avg(dirs[i]) != avg(dirs[j]) {
+			return avg(dirs[i]) > avg(dirs[j])
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	fmt.Printf("\n   Most iterations by %s:\n", strings.ToLower(groupLabel()))
+	for i, d := range dirs {
+		if i >= 5 {
+			break
+		}
+		fmt.Printf("   %-20s: %s rounds (avg over %d PRs)\n", d, formatNumber(avg(d), 1), stats[d].Count)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sampleStratum is one window of a stratified sample. Each month is split
+// into roughly weekly windows so a month's sample isn't all from its start.
+type sampleStratum struct {
+	Window     Period
+	Month      string // 2006-01
+	Population int    // Merged PRs in the window
+	Size       int    // PRs drawn from it
+}
+
+// SamplePlan describes how a stratified sample was drawn.
+type SamplePlan struct {
+	Strata     []sampleStratum
+	Population int
+	Size       int
+}
+
+// sampleWindows splits the last n calendar months (including the current
+// one) into windows starting on the 1st, 8th, 15th and 22nd.
+func sampleWindows(now time.Time, months int) []sampleStratum {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(months - 1), 0)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	var strata []sampleStratum
+	for m := first; m.Before(end); m = m.AddDate(0, 1, 0) {
+		next := m.AddDate(0, 1, 0)
+		for _, day := range []int{1, 8, 15, 22} {
+			start := time.Date(m.Year(), m.Month(), day, 0, 0, 0, 0, time.UTC)
+			stop := start.AddDate(0, 0, 7)
+			if day == 22 || stop.After(next) {
+				stop = next
+			}
+			if stop.After(end) {
+				stop = end
+			}
+			if !start.Before(stop) {
+				break
+			}
+			strata = append(strata, sampleStratum{
+				Window: Period{Label: start.Format("2006-01-02"), Start: start, End: stop},
+				Month:  m.Format("2006-01"),
+			})
+		}
+	}
+	return strata
+}
+
+// countMerged fills in the population of each stratum. Counts are batched
+// as aliased search queries, which only cost a point per batch.
+func countMerged(ctx context.Context, owner, name string, strata []sampleStratum, timeout time.Duration) error {
+	for start := 0; start < len(strata); start += 50 {
+		end := min(start+50, len(strata))
+		var b strings.Builder
+		b.WriteString("query {\n")
+		for i := start; i < end; i++ {
+			q := fmt.Sprintf("repo:%s/%s is:pr is:merged merged:%s", owner, name, strata[i].Window.searchRange())
+			fmt.Fprintf(&b, "  w%d: search(query: %q, type: ISSUE, first: 0) { issueCount }\n", i, q)
+		}
+		b.WriteString("}")
+
+		output, err := ghGraphQL(ctx, b.String(), timeout)
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Data map[string]struct {
+				IssueCount int `json:"issueCount"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return err
+		}
+		for i := start; i < end; i++ {
+			strata[i].Population = resp.Data[fmt.Sprintf("w%d", i)].IssueCount
+		}
+	}
+	return nil
+}
+
+// allocateSample distributes n draws across strata proportionally to their
+// population, using the largest remainder method.
+func allocateSample(strata []sampleStratum, n int) {
+	total := 0
+	for _, s := range strata {
+		total += s.Population
+	}
+	if total == 0 {
+		return
+	}
+	if n > total {
+		n = total
+	}
+
+	type remainder struct {
+		Index int
+		Frac  float64
+	}
+	var rems []remainder
+	assigned := 0
+	for i := range strata {
+		exact := float64(n) * float64(strata[i].Population) / float64(total)
+		strata[i].Size = int(exact)
+		assigned += strata[i].Size
+		rems = append(rems, remainder{i, exact - math.Floor(exact)})
+	}
+	sort.SliceStable(rems, func(a, b int) bool { return rems[a].Frac > rems[b].Frac })
+	for _, r := range rems {
+		if assigned >= n {
+			break
+		}
+		if strata[r.Index].Size < strata[r.Index].Population {
+			strata[r.Index].Size++
+			assigned++
+		}
+	}
+}
+
+// sampleMergedPRs draws a stratified sample of about n merged PRs from the
+// last months instead of fetching every PR: within each window, PRs are
+// drawn at random, so the sample's margin of error and confidence interval
+// hold. On a fetch failure it returns the PRs drawn so far.
+func sampleMergedPRs(ctx context.Context, owner, name string, n, months int, timeout, delay time.Duration) ([]PullRequest, SamplePlan, error) {
+	plan := SamplePlan{Strata: sampleWindows(clock(), months)}
+	if err := countMerged(ctx, owner, name, plan.Strata, timeout); err != nil {
+		return nil, plan, err
+	}
+	allocateSample(plan.Strata, n)
+
+	var prs []PullRequest
+	for _, s := range plan.Strata {
+		plan.Population += s.Population
+		if s.Size == 0 {
+			continue
+		}
+		if len(prs) > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return prs, plan, pageError(len(prs), err)
+			}
+		}
+		got, err := drawStratum(ctx, owner, name, s, timeout, delay)
+		prs = append(prs, got...)
+		if err != nil {
+			plan.Size = len(prs)
+			return prs, plan, pageError(len(prs), err)
+		}
+	}
+	plan.Size = len(prs)
+	return prs, plan, nil
+}
+
+// drawStratum picks s.Size of the PRs merged in s's window at random. It
+// lists the window's PR numbers, a point per 100, then fetches the drawn
+// ones in full. Search lists at most searchCap PRs of a window; the draw is
+// from those.
+func drawStratum(ctx context.Context, owner, name string, s sampleStratum, timeout, delay time.Duration) ([]PullRequest, error) {
+	q := fmt.Sprintf("repo:%s/%s is:pr is:merged merged:%s", owner, name, s.Window.searchRange())
+	var numbers []int
+	var cursor string
+	for len(numbers) < searchCap {
+		if len(numbers) > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return nil, err
+			}
+		}
+		args := fmt.Sprintf("query: %q, type: ISSUE, first: 100", q)
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+		output, err := ghGraphQL(ctx, fmt.Sprintf("query {\n  search(%s) {\n    nodes { ... on PullRequest { number } }\n    pageInfo { hasNextPage endCursor }\n  }\n}", args), timeout)
+		if err != nil {
+			return nil, err
+		}
+		var resp struct {
+			Data struct {
+				Search struct {
+					Nodes []struct {
+						Number int `json:"number"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"search"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return nil, err
+		}
+		for _, n := range resp.Data.Search.Nodes {
+			numbers = append(numbers, n.Number)
+		}
+		if len(resp.Data.Search.Nodes) == 0 || !resp.Data.Search.PageInfo.HasNextPage {
+			break
+		}
+		cursor = resp.Data.Search.PageInfo.EndCursor
+	}
+	rand.Shuffle(len(numbers), func(i, j int) { numbers[i], numbers[j] = numbers[j], numbers[i] })
+	return fetchPRsByNumber(ctx, owner, name, numbers[:min(s.Size, len(numbers))], timeout, delay)
+}
+
+// sampleBatch is how many PRs one query fetches by number.
+const sampleBatch = 25
+
+// fetchPRsByNumber fetches the given PRs with every field, newest first. On
+// failure it returns the batches fetched so far.
+func fetchPRsByNumber(ctx context.Context, owner, name string, numbers []int, timeout, delay time.Duration) ([]PullRequest, error) {
+	var prs []PullRequest
+	for start := 0; start < len(numbers); start += sampleBatch {
+		if start > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return prs, err
+			}
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "query {\n  repository(owner: %q, name: %q) {\n", owner, name)
+		for _, n := range numbers[start:min(start+sampleBatch, len(numbers))] {
+			fmt.Fprintf(&b, "    pr%d: pullRequest(number: %d) {\n%s\n    }\n", n, n, prFields)
+		}
+		b.WriteString("  }\n}")
+		output, err := ghGraphQL(ctx, b.String(), timeout)
+		if err != nil {
+			return prs, err
+		}
+		var resp struct {
+			Data struct {
+				Repository map[string]*GRPCPullRequest `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return prs, err
+		}
+		for _, node := range resp.Data.Repository {
+			if node != nil {
+				prs = append(prs, toPullRequest(*node))
+			}
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool { return prs[i].Number > prs[j].Number })
+	return prs, nil
+}
+
+// estimateSampleCost estimates the GraphQL points of drawing a sample: the
+// counts, a page of numbers per window (more in busy ones) and the drawn
+// PRs by number.
+func estimateSampleCost(n, months int) int {
+	windows := months * 4
+	return (windows+49)/50 + windows + (n+sampleBatch-1)/sampleBatch*pageCost(sampleBatch, allFields)
+}
+
+func printSamplingNotes(plan SamplePlan, prs []PullRequest) {
+	fmt.Println("🎲 SAMPLING")
+	printExplanation("A random sample stratified by month (and week within each month), sized in proportion to each window's merged PRs.",
+		"Very large repos can't be fully fetched interactively. A representative sample answers most questions at a fraction of the cost.")
+
+	if plan.Population == 0 || len(prs) == 0 {
+		fmt.Println("   No merged PRs found in the sampled period.")
+		return
+	}
+
+	months := make(map[string][2]int)
+	var order []string
+	for _, s := range plan.Strata {
+		m, ok := months[s.Month]
+		if !ok {
+			order = append(order, s.Month)
+		}
+		months[s.Month] = [2]int{m[0] + s.Population, m[1] + s.Size}
+	}
+	fmt.Printf("   Sample: %s of %s merged PRs (%s%%) over %d months\n\n",
+		formatInt(len(prs)), formatInt(plan.Population), formatNumber(float64(len(prs))/float64(plan.Population)*100, 1), len(order))
+	for _, m := range order {
+		fmt.Printf("   %-10s %6s of %-6s\n", formatMonthKey(m), formatInt(months[m][1]), formatInt(months[m][0]))
+	}
+
+	// 95% margin of error for shares, with finite population correction
+	n, N := float64(len(prs)), float64(plan.Population)
+	moe := 0.0
+	if N > 1 {
+		moe = 1.96 * math.Sqrt(0.25/n) * math.Sqrt((N-n)/(N-1)) * 100
+	}
+	fmt.Printf("\n   Percentages (e.g. %% reviewed) are within ±%s points at 95%% confidence.\n", formatNumber(moe, 1))
+
+	// Distribution-free 95% confidence interval of the median
+	var cycle []time.Duration
+	for _, pr := range prs {
+		cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+	}
+	sort.Slice(cycle, func(i, j int) bool { return cycle[i] < cycle[j] })
+	half := 1.96 * math.Sqrt(n) / 2
+	lo := max(0, int(math.Floor(n/2-half)))
+	hi := min(len(cycle)-1, int(math.Ceil(n/2+half)))
+	fmt.Printf("   Median cycle time: %s (95%% CI %s - %s)\n", formatDuration(percentile(cycle, 50)), formatDuration(cycle[lo]), formatDuration(cycle[hi]))
+	if len(prs) < 100 {
+		fmt.Println("   ⚠️  Small sample: treat per-directory and per-person breakdowns as anecdotal.")
+	}
+	capped := 0
+	for _, s := range plan.Strata {
+		if s.Population > searchCap {
+			capped++
+		}
+	}
+	if capped > 0 {
+		fmt.Printf("   ⚠️  %d weeks merged over %s PRs; GitHub search lists only %s of them, so their draw is from those.\n", capped, formatInt(searchCap), formatInt(searchCap))
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// printScorecard prints the key numbers of the merged PRs, with trend arrows
+// comparing the more recent half of the PRs against the older half.
+func printScorecard(merged, open []PullRequest) {
+	fmt.Println("📋 SCORECARD")
+	printExplanation("A handful of key numbers, each compared between the older and the more recent half of the merged PRs.",
+		"A quick daily check. Run without --summary for the full breakdown behind any number that moved.")
+
+	sorted := make([]PullRequest, len(merged))
+	copy(sorted, merged)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MergedAt.Before(sorted[j].MergedAt) })
+	half := len(sorted) / 2
+	m, prev := computeMetrics(sorted[half:]), computeMetrics(sorted[:half])
+	trend := func(p, c float64) string {
+		if prev.Count == 0 {
+			return ""
+		}
+		return changeLabel(p, c)
+	}
+
+	row := func(label, value, change string) {
+		fmt.Printf("   %-24s %-12s %s\n", label, value, change)
+	}
+	row("Merged PRs analyzed", fmt.Sprintf("%d", len(merged)), "")
+	if len(merged) > 0 {
+		row("Median cycle time", formatDuration(m.MedianCycleTime), trend(float64(prev.MedianCycleTime), float64(m.MedianCycleTime)))
+		row("P90 cycle time", formatDuration(m.P90CycleTime), trend(float64(prev.P90CycleTime), float64(m.P90CycleTime)))
+		row("Median first review", formatDuration(m.MedianFirstReview), trend(float64(prev.MedianFirstReview), float64(m.MedianFirstReview)))
+		row("Reviewed PRs", fmt.Sprintf("%.0f%%", m.ReviewedPct), trend(prev.ReviewedPct, m.ReviewedPct))
+		row("Avg review rounds", formatNumber(m.AvgRounds, 1), trend(prev.AvgRounds, m.AvgRounds))
+		row("Median PR size", formatInt(m.MedianSize)+" lines", trend(float64(prev.MedianSize), float64(m.MedianSize)))
+		row("Top reviewer share", fmt.Sprintf("%.0f%%", m.TopReviewerPct), trend(prev.TopReviewerPct, m.TopReviewerPct))
+	}
+
+	now := clock()
+	stale, ghosts := staleOpen(open, now), 0
+	for _, pr := range open {
+		if now.Sub(pr.CreatedAt) > 48*time.Hour {
+			ghosts += len(pr.Requested)
+		}
+	}
+	row("Stale open PRs (>7d)", fmt.Sprintf("%d of %d", stale, len(open)), "")
+	row("Ghosted requests (>48h)", fmt.Sprintf("%d", ghosts), "")
+	if prev.Count > 0 {
+		fmt.Printf("\n   (Trends compare the newest %d merged PRs with the %d before them.)\n", m.Count, prev.Count)
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// decomposition is a classical additive split of the monthly average merge
+// time, in hours, into trend, yearly seasonal and residual components.
+// Trend and Residual are NaN for the six months at either end, where the
+// centered moving average isn't defined, and for months without PRs.
+type decomposition struct {
+	Months                              []string // 2006-01, consecutive
+	Observed, Trend, Seasonal, Residual []float64
+	ByMonth                             [12]float64 // Seasonal effect per calendar month, January first
+}
+
+// Adjusted is the seasonally adjusted value of month i.
+func (d decomposition) Adjusted(i int) float64 { return d.Observed[i] - d.Seasonal[i] }
+
+// Effect is the seasonal effect of the calendar month of a 2006-01 key.
+func (d decomposition) Effect(month string) time.Duration {
+	return time.Duration(d.ByMonth[monthIndex(month)] * float64(time.Hour))
+}
+
+// decomposeMonths decomposes the complete months with a 2x12 centered
+// moving average as trend. It needs 24 months with PRs, so every calendar
+// month has a detrended value to estimate its seasonal effect from.
+func decomposeMonths(months []monthStat) (decomposition, bool) {
+	var d decomposition
+	values := make(map[string]float64)
+	for _, m := range months {
+		if m.Partial == "" && m.Count > 0 {
+			values[m.Month] = m.Avg().Hours()
+		}
+	}
+	if len(values) < 24 {
+		return d, false
+	}
+	var first, last string
+	for k := range values {
+		if first == "" || k < first {
+			first = k
+		}
+		if k > last {
+			last = k
+		}
+	}
+	start, _ := time.Parse("2006-01", first)
+	for t := start; t.Format("2006-01") <= last; t = t.AddDate(0, 1, 0) {
+		key := t.Format("2006-01")
+		v, ok := values[key]
+		if !ok {
+			v = math.NaN()
+		}
+		d.Months = append(d.Months, key)
+		d.Observed = append(d.Observed, v)
+	}
+
+	n := len(d.Observed)
+	d.Trend = make([]float64, n)
+	for i := range d.Trend {
+		d.Trend[i] = math.NaN()
+		if i < 6 || i+6 >= n {
+			continue
+		}
+		sum := 0.5*d.Observed[i-6] + 0.5*d.Observed[i+6]
+		for j := i - 5; j <= i+5; j++ {
+			sum += d.Observed[j]
+		}
+		d.Trend[i] = sum / 12 // NaN when a month in the window had no PRs
+	}
+
+	var sums [12]float64
+	var counts [12]int
+	for i := range d.Observed {
+		if detrended := d.Observed[i] - d.Trend[i]; !math.IsNaN(detrended) {
+			m := monthIndex(d.Months[i])
+			sums[m] += detrended
+			counts[m]++
+		}
+	}
+	avg := 0.0
+	for m := range sums {
+		if counts[m] == 0 {
+			return d, false
+		}
+		d.ByMonth[m] = sums[m] / float64(counts[m])
+		avg += d.ByMonth[m] / 12
+	}
+	for m := range d.ByMonth {
+		d.ByMonth[m] -= avg // Seasonal effects sum to zero over a year
+	}
+
+	d.Seasonal = make([]float64, n)
+	d.Residual = make([]float64, n)
+	for i := range d.Observed {
+		d.Seasonal[i] = d.ByMonth[monthIndex(d.Months[i])]
+		d.Residual[i] = d.Observed[i] - d.Trend[i] - d.Seasonal[i]
+	}
+	return d, true
+}
+
+// monthIndex returns the zero-based calendar month of a 2006-01 key.
+func monthIndex(key string) int {
+	t, _ := time.Parse("2006-01", key)
+	return int(t.Month()) - 1
+}
+
+// signedHours formats an hour offset with its sign.
+func signedHours(h float64) string {
+	if h < 0 {
+		return "-" + formatDuration(time.Duration(-h*float64(time.Hour)))
+	}
+	return "+" + formatDuration(time.Duration(h*float64(time.Hour)))
+}
+
+func printSeasonality(prs []PullRequest, months []monthStat) {
+	fmt.Println("🗓️  SEASONALITY")
+	printExplanation("Merge time split into a trend, a seasonal pattern (by weekday opened and by month of the year) and what's left over.",
+		"December and the summer holidays slow every team down. Without separating them out, a seasonal dip looks like a trend.")
+
+	// Weekly: mean merge time by the weekday a PR was opened, against the
+	// overall mean
+	var sums [7]float64
+	var counts [7]int
+	total := 0.0
+	for _, pr := range prs {
+		h := pr.MergedAt.Sub(pr.CreatedAt).Hours()
+		day := pr.CreatedAt.In(primaryLocation).Weekday()
+		sums[day] += h
+		counts[day]++
+		total += h
+	}
+	if len(prs) >= 20 {
+		overall := total / float64(len(prs))
+		fmt.Printf("   By weekday opened (mean merge time %s):\n", formatDuration(time.Duration(overall*float64(time.Hour))))
+		for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+			if counts[day] == 0 {
+				continue
+			}
+			effect := sums[day]/float64(counts[day]) - overall
+			fmt.Printf("   %-10s %4d PRs   %s\n", day, counts[day], signedHours(effect))
+		}
+	}
+
+	d, ok := decomposeMonths(months)
+	if !ok {
+		fmt.Println("\n   Yearly seasonality needs 24 complete months. Run `bottleneck backfill --months 24` first.")
+		return
+	}
+
+	fmt.Println("\n   Seasonal effect by month of the year:")
+	var parts []string
+	for m, effect := range d.ByMonth {
+		parts = append(parts, fmt.Sprintf("%s %s", time.Month(m + 1).String()[:3], signedHours(effect)))
+		if m%4 == 3 {
+			fmt.Printf("   %s\n", strings.Join(parts, "   "))
+			parts = nil
+		}
+	}
+
+	fmt.Printf("\n   %-9s %-12s %-12s %-12s %-12s %s\n", "Month", "Observed", "Adjusted", "Trend", "Seasonal", "Residual")
+	show := func(v float64, signed bool) string {
+		switch {
+		case math.IsNaN(v):
+			return "-"
+		case signed:
+			return signedHours(v)
+		}
+		return formatDuration(time.Duration(v * float64(time.Hour)))
+	}
+	from := max(0, len(d.Months)-12)
+	var residuals []float64
+	for _, r := range d.Residual {
+		if !math.IsNaN(r) {
+			residuals = append(residuals, r)
+		}
+	}
+	sd := stddev(residuals)
+	for i := from; i < len(d.Months); i++ {
+		flag := ""
+		if !math.IsNaN(d.Residual[i]) && sd > 0 && math.Abs(d.Residual[i]) > 2*sd {
+			flag = " ⚠️"
+		}
+		fmt.Printf("   %-9s %-12s %-12s %-12s %-12s %s%s\n", formatMonthKey(d.Months[i]), show(d.Observed[i], false), show(d.Adjusted(i), false),
+			show(d.Trend[i], false), show(d.Seasonal[i], true), show(d.Residual[i], true), flag)
+	}
+
+	// The trend component stops six months short; the adjusted series
+	// covers the latest months
+	var recent, before []float64
+	for i := len(d.Months) - 1; i >= 0 && len(before) < 3; i-- {
+		if v := d.Adjusted(i); !math.IsNaN(v) {
+			if len(recent) < 3 {
+				recent = append(recent, v)
+			} else {
+				before = append(before, v)
+			}
+		}
+	}
+	if len(before) == 3 {
+		r, b := mean(recent), mean(before)
+		fmt.Printf("\n   Seasonally adjusted, last 3 months vs the 3 before: %s → %s.", formatDuration(time.Duration(b*float64(time.Hour))), formatDuration(time.Duration(r*float64(time.Hour))))
+		switch {
+		case r > b*1.1:
+			fmt.Println(" 🐢 Slowing down beyond the season.")
+		case r < b*0.9:
+			fmt.Println(" 🚀 Speeding up beyond the season.")
+		default:
+			fmt.Println(" ➖ No change beyond the season.")
+		}
+	}
+	fmt.Println("   (⚠️ Residual over two standard deviations: something other than trend and season happened that month.)")
+}
+
+func mean(vs []float64) float64 {
+	if len(vs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range vs {
+		sum += v
+	}
+	return sum / float64(len(vs))
+}
+
+func stddev(vs []float64) float64 {
+	if len(vs) < 2 {
+		return 0
+	}
+	m := mean(vs)
+	sum := 0.0
+	for _, v := range vs {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(vs)-1))
+}
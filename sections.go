@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SectionConfig selects one report section. In YAML it is either a plain
+// section name or a mapping with parameters:
+//
+//	sections:
+//	  - general
+//	  - name: hotspots
+//	    depth: 3
+type SectionConfig struct {
+	Name  string `yaml:"name"`
+	Depth int    `yaml:"depth"` // Directory depth for hotspots and ownership_drift (default: root directory or service)
+	By    string `yaml:"by"`    // Cohort grouping: quarter or release
+	Top   int    `yaml:"top"`   // PRs listed by slowest (default: --slowest)
+}
+
+func (s *SectionConfig) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		s.Name = n.Value
+		return nil
+	}
+	type plain SectionConfig
+	return n.Decode((*plain)(s))
+}
+
+// reportRun is the data of one repository report, shared by its sections.
+// Data only some sections need is fetched on first use.
+type reportRun struct {
+	ctx     context.Context
+	repo    string
+	owner   string
+	name    string
+	o       reportOptions
+	errs    *fetchErrors
+	merged  []PullRequest
+	fetched int // Merged PRs fetched, before docs and outliers were left out
+	open    []PullRequest
+	sample  *SamplePlan
+	docs    []PullRequest // Docs-only PRs left out of merged by --exclude-docs
+	history []Snapshot    // Earlier health snapshots, oldest first
+
+	generated  *GeneratedMatcher
+	violations int
+
+	reviewersLoaded bool
+	owners          *Codeowners
+	avail           *Availability
+
+	checksLoaded bool
+	checks       map[int][]CheckRun
+}
+
+// reviewers loads CODEOWNERS and the availability of requested reviewers.
+func (r *reportRun) reviewers() (*Codeowners, *Availability) {
+	if r.reviewersLoaded {
+		return r.owners, r.availability()
+	}
+	r.reviewersLoaded = true
+	owners, err := fetchCodeowners(r.ctx, r.owner, r.name, r.o.Timeout)
+	if err != nil {
+		slog.Warn("could not fetch CODEOWNERS", "repo", r.repo, "err", err)
+	}
+	r.owners = owners
+	return owners, r.availability()
+}
+
+// availability loads who is away, with the GitHub status of requested
+// reviewers.
+func (r *reportRun) availability() *Availability {
+	if r.avail != nil {
+		return r.avail
+	}
+	var requested []string
+	for _, pr := range r.open {
+		requested = append(requested, pr.Requested...)
+	}
+	avail, warnings := loadAvailability(r.ctx, cfg.Availability, requested, r.o.Timeout)
+	for _, w := range warnings {
+		slog.Warn("availability", "err", w)
+	}
+	r.avail = avail
+	return avail
+}
+
+// checkRuns fetches the CI check runs of the merged PRs.
+func (r *reportRun) checkRuns() map[int][]CheckRun {
+	if r.checksLoaded {
+		return r.checks
+	}
+	r.checksLoaded = true
+	slog.Info("fetching check runs", "repo", r.repo, "prs", len(r.merged))
+	runs, err := fetchCheckRuns(r.ctx, r.owner, r.name, r.merged, r.o.Timeout, r.o.Delay)
+	if err != nil {
+		r.errs.add(r.repo, "check runs", len(runs), err)
+	}
+	r.checks = runs
+	return runs
+}
+
+// months returns the monthly trend data of the merged PRs.
+func (r *reportRun) months() []monthStat {
+	stored, err := r.o.Store.LoadMonths(r.repo)
+	if err != nil {
+		slog.Warn("could not read backfilled months", "repo", r.repo, "err", err)
+	}
+	months := monthlyStats(r.merged, stored, clock(), r.sample == nil && r.fetched >= r.o.Limit)
+	if r.o.ExcludePartial {
+		months = completeMonths(months)
+	}
+	return months
+}
+
+// reportSection is one section of the repository report.
+type reportSection struct {
+	Name string
+	// Needs is the data the section reads: "merged", "open" or "" for
+	// either. The section is skipped when that data is empty.
+	Needs string
+	// Default reports whether the section is in the default report; nil
+	// means always. Sections listed in config run regardless.
+	Default func(o reportOptions) bool
+	// Params lists the SectionConfig parameters the section accepts.
+	Params []string
+	// Fields are the optional pull request fields the section reads. Those
+	// no planned section reads aren't fetched.
+	Fields prField
+	// Personal sections are about named individuals and don't run when
+	// --privacy hides names.
+	Personal bool
+	// Run prints the section. It returns false when it printed nothing.
+	Run func(r *reportRun, s SectionConfig) bool
+}
+
+// reportSections lists every section in default order.
+var reportSections = []reportSection{
+	{Name: "general", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printGeneralStats(r.merged); return true }},
+	{Name: "review", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printReviewStats(r.merged); return true }},
+	{Name: "rounds", Fields: fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printReviewRounds(r.merged); return true }},
+	{Name: "size", Fields: fieldFiles, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printSizeAnalysis(r.merged); return true }},
+	{Name: "generated", Fields: fieldFiles, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		printGeneratedNoise(r.merged, r.generated, !r.o.IncludeGenerated)
+		return true
+	}},
+	{Name: "description", Fields: fieldBody | fieldLabels | fieldFiles | fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printDescriptionQuality(r.merged); return true }},
+	{Name: "template", Fields: fieldBody, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		template, err := fetchPRTemplate(r.ctx, r.owner, r.name, r.o.Timeout)
+		if err != nil {
+			slog.Warn("could not fetch the PR template", "repo", r.repo, "err", err)
+		}
+		printTemplateCompliance(r.merged, template)
+		return true
+	}},
+	{Name: "docs", Fields: fieldFiles, Run: func(r *reportRun, _ SectionConfig) bool {
+		if len(r.merged)+len(r.docs) == 0 {
+			return false
+		}
+		printDocsPRs(append(append([]PullRequest(nil), r.merged...), r.docs...), r.o.ExcludeDocs)
+		return true
+	}},
+	{Name: "test_coverage", Fields: fieldBody | fieldFiles | fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printTestCoverage(r.merged); return true }},
+	{Name: "hotspots", Fields: fieldFiles, Needs: "merged", Params: []string{"depth"}, Run: func(r *reportRun, s SectionConfig) bool {
+		printHotspots(r.merged, s.Depth)
+		return true
+	}},
+	{Name: "coupling", Fields: fieldFiles, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printFileCoupling(r.merged, r.generated); return true }},
+	{Name: "ownership_drift", Fields: fieldFiles, Needs: "merged", Params: []string{"depth"}, Run: func(r *reportRun, s SectionConfig) bool {
+		printOwnershipDrift(r.merged, s.Depth)
+		return true
+	}},
+	{Name: "file_types", Fields: fieldFiles | fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printFileTypeAnalysis(r.merged); return true }},
+	{Name: "change_types", Fields: fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printChangeTypes(r.merged); return true }},
+	{Name: "branch_naming", Fields: fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printBranchNaming(r.merged); return true }},
+	{Name: "long_tail", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		printLongTailAuthors(r.merged, r.o.LongTailMinPRs)
+		return true
+	}},
+	{Name: "trends", Fields: fieldFiles | fieldLabels, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printTrends(r.months()); return true }},
+	{Name: "seasonality", Fields: fieldFiles | fieldLabels, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printSeasonality(r.merged, r.months()); return true }},
+	{Name: "forecast", Fields: fieldFiles | fieldLabels, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printForecast(r.months(), r.merged, r.open); return true }},
+	{Name: "capacity", Fields: fieldTimeline, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printCapacityPlan(r.merged, r.open); return true }},
+	{Name: "cohorts", Fields: fieldFiles | fieldCommits, Needs: "merged", Params: []string{"by"},
+		Default: func(o reportOptions) bool { return o.Cohorts != "" },
+		Run: func(r *reportRun, s SectionConfig) bool {
+			by := s.By
+			if by == "" {
+				by = r.o.Cohorts
+			}
+			if by == "release" {
+				releases, err := fetchReleases(r.ctx, r.owner, r.name, 500, r.o.Timeout, r.o.Delay)
+				if err != nil {
+					slog.Warn("could not fetch release tags", "repo", r.repo, "fetched", len(releases), "err", err)
+				}
+				printCohorts("RELEASE", cohortsByRelease(r.merged, releases, r.o.TagPattern))
+				return true
+			}
+			printCohorts("QUARTER", cohortsByQuarter(r.merged))
+			return true
+		}},
+	{Name: "releases", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		releases, source, err := fetchReleaseDates(r.ctx, r.owner, r.name, r.o.TagPattern, r.o.Timeout, r.o.Delay)
+		if err != nil {
+			slog.Warn("could not fetch releases", "repo", r.repo, "err", err)
+		}
+		printReleaseCadence(r.merged, releases, source)
+		return true
+	}},
+	{Name: "deployments", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		deployments, err := fetchDeployments(r.ctx, r.owner, r.name, deploymentLimit, r.o.Timeout, r.o.Delay)
+		if err != nil {
+			slog.Warn("could not fetch deployments", "repo", r.repo, "fetched", len(deployments), "err", err)
+		}
+		printDeployLeadTime(r.merged, deployments)
+		return true
+	}},
+	{Name: "flaky_checks", Needs: "merged",
+		Default: func(o reportOptions) bool { return o.CI },
+		Run:     func(r *reportRun, _ SectionConfig) bool { printFlakyChecks(r.checkRuns()); return true }},
+	{Name: "ci_timing", Needs: "merged",
+		Default: func(o reportOptions) bool { return o.CI },
+		Run:     func(r *reportRun, _ SectionConfig) bool { printCheckTiming(r.checkRuns()); return true }},
+	{Name: "slowest", Fields: fieldBody | fieldCommits | fieldLabels | fieldTimeline, Needs: "merged", Params: []string{"top"}, Run: func(r *reportRun, s SectionConfig) bool {
+		n := r.o.Slowest
+		if s.Top > 0 {
+			n = s.Top
+		}
+		var checks map[int][]CheckRun
+		if r.o.CI || r.checksLoaded {
+			checks = r.checkRuns()
+		}
+		printSlowestPRs(r.merged, n, checks)
+		return true
+	}},
+	{Name: "delay_causes", Fields: fieldBody | fieldCommits | fieldLabels | fieldTimeline, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool {
+		var checks map[int][]CheckRun
+		if r.o.CI || r.checksLoaded {
+			checks = r.checkRuns()
+		}
+		printDelayCauses(r.merged, checks)
+		return true
+	}},
+	{Name: "histogram", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printHistogram(r.merged); return true }},
+	{Name: "first_review_histogram", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printFirstReviewHistogram(r.merged); return true }},
+	{Name: "review_hours", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printReviewHours(r.merged); return true }},
+	{Name: "follow_the_sun", Fields: fieldCommits, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printFollowTheSun(r.merged, r.open); return true }},
+	{Name: "heroes", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printHeroAnalysis(r.merged); return true }},
+	{Name: "team_latency", Fields: fieldTimeline, Needs: "merged",
+		Default: func(reportOptions) bool { return len(cfg.Teams) > 1 },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			if len(cfg.Teams) < 2 {
+				slog.Warn("skipping the team_latency section; it needs two or more teams under teams in config", "repo", r.repo)
+				return false
+			}
+			printTeamLatencyMatrix(r.merged, r.availability())
+			return true
+		}},
+	{Name: "reviewer_diversity", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printReviewerDiversity(r.merged); return true }},
+	{Name: "burnout", Fields: fieldCommits | fieldTimeline, Needs: "merged", Default: func(o reportOptions) bool { return o.Burnout },
+		Run: func(r *reportRun, _ SectionConfig) bool { printBurnoutRisk(r.merged); return true }},
+	{Name: "merge_authority", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printMergeAuthority(r.merged); return true }},
+	{Name: "merge_methods", Fields: fieldMergeCommit, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printMergeMethods(r.merged); return true }},
+	{Name: "auto_merge", Fields: fieldTimeline, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printAutoMerge(r.merged); return true }},
+	{Name: "approvals", Fields: fieldTimeline, Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printApprovalOrder(r.merged); return true }},
+	{Name: "assignees", Fields: fieldAssignees, Run: func(r *reportRun, _ SectionConfig) bool { printAssignees(r.merged, r.open, clock()); return true }},
+	{Name: "project_boards", Fields: fieldProjects, Run: func(r *reportRun, _ SectionConfig) bool { printProjectDwell(r.merged, r.open, clock()); return true }},
+	{Name: "leaderboard", Fields: fieldTimeline, Needs: "merged", Personal: true,
+		Default: func(o reportOptions) bool { return o.Leaderboard },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			printReviewerLeaderboard(r.merged, r.availability(), r.o.ResponseSLA, r.o.Anonymize)
+			return true
+		}},
+	{Name: "splits", Fields: fieldFiles, Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool { printSplitSuggestions(r.open, r.generated); return true }},
+	{Name: "stale", Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool { printStaleAnalysis(r.open); return true }},
+	{Name: "stale_branches",
+		Default: func(o reportOptions) bool { return o.StaleBranches },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			base, err := fetchDefaultBranch(r.ctx, r.owner, r.name, r.o.Timeout)
+			if err != nil {
+				slog.Warn("skipping the stale_branches section; could not read the default branch", "repo", r.repo, "err", err)
+				r.errs.add(r.repo, "branches", 0, err)
+				return false
+			}
+			slog.Info("fetching stale branches", "repo", r.repo, "limit", r.o.Limit)
+			branches, total, err := fetchStaleBranches(r.ctx, r.owner, r.name, base, clock().Add(-r.o.StaleBranchAge), r.o.Limit, r.o.Timeout, r.o.Delay)
+			if err != nil {
+				r.errs.add(r.repo, "branches", len(branches), err)
+			}
+			printStaleBranches(branches, total, r.o.Limit, r.o.StaleBranchAge)
+			return true
+		}},
+	{Name: "resurrections", Fields: fieldCommits | fieldTimeline, Run: func(r *reportRun, _ SectionConfig) bool { return printResurrections(r.history, r.merged, r.open) }},
+	{Name: "aging", Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool { printOpenAging(r.open); return true }},
+	{Name: "ghosts", Fields: fieldFiles | fieldTimeline, Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool {
+		owners, avail := r.reviewers()
+		printGhostAnalysis(r.open, owners, avail)
+		return true
+	}},
+	{Name: "my_queue", Fields: fieldCommits | fieldTimeline, Default: viewerOnly, Run: func(r *reportRun, _ SectionConfig) bool {
+		if r.o.Viewer == "" {
+			return false
+		}
+		printPersonalStats(r.o.Viewer, r.merged, r.open, r.o.ResponseSLA, clock())
+		return true
+	}},
+	{Name: "my_ghosts", Fields: fieldTimeline, Needs: "open", Default: viewerOnly, Run: func(r *reportRun, _ SectionConfig) bool {
+		return r.o.Viewer != "" && printMyGhosts(r.o.Viewer, r.open, clock())
+	}},
+	{Name: "burndown", Fields: fieldTimeline, Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool {
+		printReviewBurnDown(r.open, r.availability(), r.o.ResponseSLA)
+		return true
+	}},
+	{Name: "dependencies", Fields: fieldBody, Needs: "open", Run: func(r *reportRun, _ SectionConfig) bool { printDependencies(r.open, r.merged); return true }},
+	{Name: "ghost_digest", Fields: fieldBody | fieldFiles | fieldTimeline, Needs: "open", Personal: true,
+		Default: func(o reportOptions) bool { return o.GhostDigest },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			owners, avail := r.reviewers()
+			printGhostDigests(r.ctx, r.owner, r.name, ghostDigests(r.open, owners, avail, clock()), r.o.NotifyGhosts, r.o.Timeout)
+			return true
+		}},
+	{Name: "suggestions", Fields: fieldFiles, Needs: "open", Personal: true, Run: func(r *reportRun, _ SectionConfig) bool {
+		_, avail := r.reviewers()
+		printReviewerSuggestions(r.ctx, r.owner, r.name, r.open, r.merged, avail, r.o.AssignReviewers, r.o.Timeout)
+		return true
+	}},
+	{Name: "stacks", Fields: fieldBody, Run: func(r *reportRun, _ SectionConfig) bool { printStackAnalysis(r.repo, r.merged, r.open); return true }},
+	{Name: "queue", Run: func(r *reportRun, _ SectionConfig) bool { printQueueTheory(r.merged, r.open); return true }},
+	{Name: "littles_law", Needs: "merged", Run: func(r *reportRun, _ SectionConfig) bool { printLittlesLaw(r.merged, r.open); return true }},
+	// Sends metrics to an external service, so it always needs the flag
+	{Name: "ai_insights", Fields: allFields, Needs: "merged",
+		Default: func(o reportOptions) bool { return o.AIInsights },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			if !r.o.AIInsights {
+				slog.Warn("skipping the ai_insights section; it needs --ai-insights", "repo", r.repo)
+				return false
+			}
+			printAIInsights(r.ctx, r.repo, r.merged, r.open)
+			return true
+		}},
+	{Name: "issues",
+		Default: func(o reportOptions) bool { return o.Issues },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			slog.Info("fetching issues", "repo", r.repo, "limit", r.o.Limit)
+			issues, err := fetchIssues(r.ctx, r.owner, r.name, r.o.Limit, r.o.Timeout, r.o.Delay)
+			if err != nil {
+				r.errs.add(r.repo, "issues", len(issues), err)
+			}
+			printIssueTriage(issues)
+			return true
+		}},
+	{Name: "sla", Fields: fieldLabels | fieldFiles,
+		Default: func(reportOptions) bool { return len(cfg.Policies) > 0 },
+		Run: func(r *reportRun, _ SectionConfig) bool {
+			if len(cfg.Policies) == 0 {
+				slog.Warn("skipping the sla section; no policies are configured", "repo", r.repo)
+				return false
+			}
+			r.violations += printSLAPolicies(r.merged, r.open)
+			return true
+		}},
+}
+
+func findSection(name string) *reportSection {
+	for i := range reportSections {
+		if reportSections[i].Name == name {
+			return &reportSections[i]
+		}
+	}
+	return nil
+}
+
+// validateSections checks section names and their parameters.
+func validateSections(sections []SectionConfig) error {
+	for i, s := range sections {
+		sec := findSection(s.Name)
+		if sec == nil {
+			var names []string
+			for _, r := range reportSections {
+				names = append(names, r.Name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("sections[%d]: unknown section %q (known: %s)", i, s.Name, strings.Join(names, ", "))
+		}
+		accepts := func(p string) bool {
+			for _, a := range sec.Params {
+				if a == p {
+					return true
+				}
+			}
+			return false
+		}
+		switch {
+		case s.Depth < 0:
+			return fmt.Errorf("sections[%d] (%s): depth must be positive", i, s.Name)
+		case s.Depth > 0 && !accepts("depth"):
+			return fmt.Errorf("sections[%d] (%s): does not take a depth", i, s.Name)
+		case s.Top < 0:
+			return fmt.Errorf("sections[%d] (%s): top must be positive", i, s.Name)
+		case s.Top > 0 && !accepts("top"):
+			return fmt.Errorf("sections[%d] (%s): does not take a top", i, s.Name)
+		case s.By != "" && !accepts("by"):
+			return fmt.Errorf("sections[%d] (%s): does not take by", i, s.Name)
+		case s.By != "" && s.By != "quarter" && s.By != "release":
+			return fmt.Errorf("sections[%d] (%s): by must be quarter or release", i, s.Name)
+		}
+	}
+	return nil
+}
+
+// plannedSections returns the sections of the report: those in config, in
+// their order, or the default ones for o.
+func plannedSections(o reportOptions) []SectionConfig {
+	if len(cfg.Sections) > 0 {
+		return cfg.Sections
+	}
+	var out []SectionConfig
+	for _, s := range reportSections {
+		if s.Personal && !namesShown() {
+			continue
+		}
+		if s.Default == nil || s.Default(o) {
+			out = append(out, SectionConfig{Name: s.Name})
+		}
+	}
+	return out
+}
+
+// reportFields returns the optional pull request fields the report reads:
+// those of the planned sections and of the parts every report runs. The
+// health score, goals, the scorecard and snapshots lead every report, so
+// their fields are always fetched and the score doesn't depend on the
+// sections picked.
+func reportFields(o reportOptions) prField {
+	f := healthFields
+	for _, s := range plannedSections(o) {
+		if sec := findSection(s.Name); sec != nil {
+			f |= sec.Fields
+		}
+	}
+	return f | configFields()
+}
+
+// plansSection reports whether the report runs the named section.
+func plansSection(o reportOptions, name string) bool {
+	for _, s := range plannedSections(o) {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runSections prints the planned sections, each followed by a separator.
+func runSections(r *reportRun) {
+	for _, s := range plannedSections(r.o) {
+		sec := findSection(s.Name)
+		if sec == nil {
+			continue // Rejected by validateSections
+		}
+		if sec.Personal && !namesShown() {
+			slog.Warn("skipping a section that names individuals", "repo", r.repo, "section", s.Name, "privacy", privacy)
+			continue
+		}
+		if (sec.Needs == "merged" && len(r.merged) == 0) || (sec.Needs == "open" && len(r.open) == 0) {
+			continue
+		}
+		if sec.Run(r, s) {
+			fmt.Println(strings.Repeat("-", 60))
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job states of an on-demand analysis.
+const (
+	jobQueued  = "queued"
+	jobRunning = "running"
+	jobDone    = "done"
+	jobFailed  = "failed"
+)
+
+// analysisJob is one POST /analyze request and, once done, its report.
+type analysisJob struct {
+	ID       string      `json:"id"`
+	Repo     string      `json:"repo"`
+	Window   string      `json:"window,omitempty"`
+	Limit    int         `json:"limit,omitempty"`
+	Status   string      `json:"status"`
+	Created  time.Time   `json:"created"`
+	Started  *time.Time  `json:"started,omitempty"`
+	Finished *time.Time  `json:"finished,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Report   *JSONReport `json:"report,omitempty"`
+
+	window *Period
+	tenant *tenant
+	round  *scheduleRound // Set on scheduled jobs
+}
+
+// analyzeRequest is the body of POST /analyze.
+type analyzeRequest struct {
+	Repo   string `json:"repo"`
+	Window string `json:"window"` // "Q3-2024", "2024-07" or "30d"; default the latest --limit merged PRs
+	Limit  int    `json:"limit"`
+}
+
+var daysWindow = regexp.MustCompile(`^(\d+)d$`)
+
+// parseWindow accepts the periods of parsePeriod and trailing windows of
+// whole days ("30d", ending today).
+func parseWindow(s string, now time.Time) (Period, error) {
+	if m := daysWindow.FindStringSubmatch(s); m != nil {
+		days, _ := strconv.Atoi(m[1])
+		if days < 1 {
+			return Period{}, fmt.Errorf("window %q: need at least 1 day", s)
+		}
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+		return Period{Label: s, Start: end.AddDate(0, 0, -days), End: end}, nil
+	}
+	p, err := parsePeriod(s)
+	if err != nil {
+		return Period{}, fmt.Errorf("window %q: use a quarter (Q3-2024), a month (2024-07) or days (30d)", s)
+	}
+	return p, nil
+}
+
+// analysisServer runs analyses one at a time in the background: each fetch
+// already paces itself against the rate limit, and the report code keeps
+// global state, which each job switches to its tenant's.
+type analysisServer struct {
+	o          reportOptions
+	configPath string // The server's --config, for tenants without their own
+	maxLimit   int
+	ttl        time.Duration // How long finished jobs are kept
+	// tenants by name. Without --tenants there is one, named "", serving
+	// the routes without a /tenants/{tenant} prefix.
+	tenants map[string]*tenant
+
+	mu    sync.Mutex
+	jobs  map[string]*analysisJob
+	queue chan *analysisJob
+}
+
+func (s *analysisServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	prefix := ""
+	if s.tenants[""] == nil {
+		prefix = "/tenants/{tenant}"
+	}
+	mux.HandleFunc("POST "+prefix+"/analyze", s.handleAnalyze)
+	mux.HandleFunc("GET "+prefix+"/jobs/{id}", s.handleJob)
+	mux.HandleFunc("GET "+prefix+"/reports", s.handleReports)
+	mux.HandleFunc("GET "+prefix+"/dashboard", s.handleDashboard)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "schema_version": reportSchemaVersion})
+	})
+	return mux
+}
+
+// bearerMatches reports whether r carries token as its bearer token, or
+// whether no token is required.
+func bearerMatches(r *http.Request, token string) bool {
+	if token == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) == 1
+}
+
+func (s *analysisServer) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	t := s.tenantFor(w, r)
+	if t == nil {
+		return
+	}
+	var req analyzeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %w", err))
+		return
+	}
+	if _, _, err := parseRepo(req.Repo); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(t.repos) > 0 && !t.repos[req.Repo] {
+		writeError(w, http.StatusForbidden, fmt.Errorf("%s is not served here", req.Repo))
+		return
+	}
+	if req.Limit < 0 || req.Limit > s.maxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d, or 0 for the server's default", s.maxLimit))
+		return
+	}
+	job := &analysisJob{ID: newJobID(), Repo: req.Repo, Window: req.Window, Limit: req.Limit, Status: jobQueued, Created: clock(), tenant: t}
+	if req.Window != "" {
+		p, err := parseWindow(req.Window, clock())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		job.window = &p
+	}
+
+	if err := s.enqueue(job); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	s.mu.Lock()
+	view := *job
+	s.mu.Unlock()
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/analyze")+"/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, view)
+}
+
+// enqueue queues a job, refusing it when the queue is full.
+func (s *analysisServer) enqueue(job *analysisJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+	select {
+	case s.queue <- job:
+		s.jobs[job.ID] = job
+	default:
+		return errors.New("too many analyses queued; try again later")
+	}
+	slog.Info("queued analysis", "tenant", job.tenant.label(), "job", job.ID, "repo", job.Repo, "window", job.Window, "scheduled", job.round != nil)
+	return nil
+}
+
+func (s *analysisServer) handleJob(w http.ResponseWriter, r *http.Request) {
+	t := s.tenantFor(w, r)
+	if t == nil {
+		return
+	}
+	s.mu.Lock()
+	job, ok := s.jobs[r.PathValue("id")]
+	ok = ok && job.tenant == t
+	var view analysisJob
+	if ok {
+		view = *job
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("no such job (finished jobs are kept for --job-ttl)"))
+		return
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+// prune drops jobs finished more than ttl ago. The caller holds s.mu.
+func (s *analysisServer) prune() {
+	for id, job := range s.jobs {
+		if job.Finished != nil && clock().Sub(*job.Finished) > s.ttl {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// work runs queued jobs until ctx is done.
+func (s *analysisServer) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.run(ctx, job)
+		}
+	}
+}
+
+func (s *analysisServer) run(ctx context.Context, job *analysisJob) {
+	started := clock()
+	s.mu.Lock()
+	job.Status, job.Started = jobRunning, &started
+	s.mu.Unlock()
+	t := job.tenant
+	slog.Info("running analysis", "tenant", t.label(), "job", job.ID, "repo", job.Repo, "window", job.Window)
+
+	o := s.o
+	o.Store = t.store
+	o.Snapshot = job.round != nil // Scheduled runs build the tenant's history
+	if t.Limit > 0 {
+		o.Limit = t.Limit
+	}
+	if job.Limit > 0 {
+		o.Limit = job.Limit
+	}
+	var errs fetchErrors
+	var rep RepoReport
+	var ok bool
+	if err := t.activate(s.configPath); err != nil {
+		errs.add(job.Repo, "tenant config", 0, err)
+	} else if job.window != nil {
+		rep, ok = windowRepoReport(ctx, job.Repo, *job.window, o, &errs)
+	} else {
+		rep, ok = jsonRepoReport(ctx, job.Repo, o, &errs)
+	}
+
+	finished := clock()
+	s.mu.Lock()
+	job.Finished = &finished
+	var repos []RepoReport
+	if ok {
+		repos = append(repos, rep)
+	}
+	report := newJSONReport(repos, errs)
+	job.Report = &report
+	switch {
+	case ok:
+		job.Status = jobDone
+		if job.window == nil {
+			t.record(rep, finished)
+		}
+	case len(errs) > 0:
+		job.Status, job.Error = jobFailed, errs[0].Err.Error()
+	default:
+		job.Status, job.Error = jobFailed, "no PRs found"
+	}
+	s.mu.Unlock()
+	slog.Info("finished analysis", "tenant", t.label(), "job", job.ID, "repo", job.Repo, "status", job.Status, "duration", finished.Sub(started))
+	if job.round != nil {
+		s.finishRound(ctx, job)
+	}
+}
+
+// windowRepoReport is jsonRepoReport for the PRs merged in a window instead
+// of the latest ones. GitHub search returns at most searchCap of them.
+func windowRepoReport(ctx context.Context, repo string, w Period, o reportOptions, errs *fetchErrors) (RepoReport, bool) {
+	owner, name, _ := parseRepo(repo)
+	merged, err := searchPRs(ctx, owner, name, "is:merged merged:"+w.searchRange(), searchCap, o.Timeout, o.Delay)
+	if err != nil {
+		slog.Error("fetching merged PRs", "repo", repo, "window", w.Label, "fetched", len(merged), "err", err)
+		errs.add(repo, "merged PRs", len(merged), err)
+	}
+	open, err := fetchPRFields(ctx, owner, name, 100, "OPEN", jsonFields|configFields(), o.Timeout, o.Delay)
+	if err != nil {
+		slog.Error("fetching open PRs", "repo", repo, "fetched", len(open), "err", err)
+		errs.add(repo, "open PRs", len(open), err)
+	}
+	fieldErrors.takeInto(errs, repo)
+	return buildRepoReport(ctx, repo, o, merged, open, len(merged) >= searchCap, errs)
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Debug("writing response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:8080", "Address to serve the API on")
+	tenantsPath := fs.String("tenants", "", "Host several teams, each with its own config, tokens, repos, schedule and Slack channel, from this YAML file")
+	limit := fs.Int("limit", 300, "Merged PRs to analyze when a request sets neither window nor limit")
+	maxLimit := fs.Int("max-limit", 1000, "Largest limit a request may ask for")
+	queueSize := fs.Int("queue", 20, "Analyses that may wait in the queue; more are refused with 503")
+	jobTTL := fs.Duration("job-ttl", 24*time.Hour, "How long finished jobs and their reports stay available")
+	schedule := fs.Duration("schedule", 0, "Without --tenants: also analyze the listed repositories this often (0: only on demand)")
+	tokenEnv := fs.String("token-env", "BOTTLENECK_API_TOKEN", "Without --tenants: env var holding the bearer token clients must send; no auth when it's unset")
+	includeGenerated := fs.Bool("include-generated", false, "Count generated, vendored and lock files in PR size")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	storeDir := fs.String("store", defaultStoreDir, "Directory of the local snapshot store (backfilled months; with --tenants, one under tenants/<name>)")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck serve [flags] [owner/repo...]")
+		fmt.Println("       bottleneck serve --tenants tenants.yml [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if *limit < 1 || *maxLimit < *limit || *queueSize < 1 {
+		slog.Error("--limit must be at least 1 and at most --max-limit, and --queue at least 1")
+		os.Exit(1)
+	}
+
+	var tenants []Tenant
+	if *tenantsPath != "" {
+		if fs.NArg() > 0 || *schedule != 0 {
+			slog.Error("with --tenants, list repositories and schedules in the tenants file")
+			os.Exit(1)
+		}
+		var err error
+		if tenants, err = loadTenants(*tenantsPath); err != nil {
+			slog.Error("loading tenants", "err", err)
+			os.Exit(1)
+		}
+	} else {
+		for _, repo := range fs.Args() {
+			if _, _, err := parseRepo(repo); err != nil {
+				slog.Error("invalid repository", "repo", repo, "err", err)
+				os.Exit(1)
+			}
+		}
+		if *schedule != 0 && fs.NArg() == 0 {
+			slog.Error("--schedule needs the repositories to analyze")
+			os.Exit(1)
+		}
+		tenants = []Tenant{{Repos: fs.Args(), Schedule: Duration(*schedule), APITokenEnv: *tokenEnv}}
+	}
+
+	s := &analysisServer{
+		o: reportOptions{
+			Limit:            *limit,
+			Timeout:          *reqTimeout,
+			Delay:            *reqDelay,
+			IncludeGenerated: *includeGenerated,
+			JSON:             true,
+		},
+		configPath: *configPath,
+		maxLimit:   *maxLimit,
+		ttl:        *jobTTL,
+		tenants:    make(map[string]*tenant),
+		jobs:       make(map[string]*analysisJob),
+	}
+	scheduled := 0
+	for _, t := range tenants {
+		tn, err := newTenant(t, *storeDir)
+		if err != nil {
+			slog.Error("loading tenants", "err", err)
+			os.Exit(1)
+		}
+		s.tenants[t.Name] = tn
+		if t.Schedule > 0 {
+			scheduled += len(t.Repos)
+		}
+		if tn.apiToken == "" {
+			slog.Warn("no bearer token set; anyone who can reach the server can start analyses", "tenant", tn.label(), "env", t.APITokenEnv)
+		}
+	}
+	// Room for a whole scheduled round on top of on-demand requests
+	s.queue = make(chan *analysisJob, *queueSize+scheduled)
+
+	ctx, cancel := rootContext()
+	defer cancel()
+	go s.work(ctx)
+	for _, t := range s.tenants {
+		if t.Schedule > 0 {
+			go s.schedule(ctx, t)
+		}
+	}
+
+	srv := &http.Server{Addr: *listen, Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		srv.Shutdown(shutdown)
+	}()
+	slog.Info("serving", "listen", *listen, "tenants", len(tenants))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("serving", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("server stopped")
+}
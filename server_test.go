@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer is an analysisServer as runServe sets one up, with a queue
+// of queueSize and the tenants given (one unnamed without --tenants).
+func newTestServer(t *testing.T, queueSize int, tenants ...Tenant) (*analysisServer, *httptest.Server) {
+	t.Helper()
+	s := &analysisServer{
+		o:        reportOptions{Limit: 50, Timeout: time.Second, JSON: true},
+		maxLimit: 100,
+		ttl:      time.Hour,
+		tenants:  make(map[string]*tenant),
+		jobs:     make(map[string]*analysisJob),
+		queue:    make(chan *analysisJob, queueSize),
+	}
+	for _, tn := range tenants {
+		hosted, err := newTenant(tn, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.tenants[tn.Name] = hosted
+	}
+	srv := httptest.NewServer(s.routes())
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+// postAnalyze sends body to POST {url}/analyze and decodes the job.
+func postAnalyze(t *testing.T, url, token, body string) (*http.Response, analysisJob) {
+	t.Helper()
+	req, _ := http.NewRequest("POST", url+"/analyze", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var job analysisJob
+	json.NewDecoder(resp.Body).Decode(&job)
+	return resp, job
+}
+
+// pollJob fetches the job at location until it is done or failed.
+func pollJob(t *testing.T, url string) analysisJob {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var job analysisJob
+		json.NewDecoder(resp.Body).Decode(&job)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: %s", url, resp.Status)
+		}
+		if job.Status == jobDone || job.Status == jobFailed {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job at %s didn't finish", url)
+	return analysisJob{}
+}
+
+func TestServeAnalyze(t *testing.T) {
+	newFakeGitHub(t, fakeScenario{Merged: 30, Open: 3})
+	s, srv := newTestServer(t, 5, Tenant{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.work(ctx)
+
+	resp, job := postAnalyze(t, srv.URL, "", `{"repo": "acme/widgets", "limit": 20}`)
+	if resp.StatusCode != http.StatusAccepted || job.Status != jobQueued {
+		t.Fatalf("POST /analyze: %s, status %q; want 202 and queued", resp.Status, job.Status)
+	}
+	location := resp.Header.Get("Location")
+	if location != "/jobs/"+job.ID {
+		t.Fatalf("Location = %q, want /jobs/%s", location, job.ID)
+	}
+	done := pollJob(t, srv.URL+location)
+	if done.Status != jobDone || done.Report == nil || len(done.Report.Repositories) != 1 || done.Report.Repositories[0].Merged.Count != 20 {
+		t.Fatalf("job = %+v, want done with a report of 20 merged PRs", done)
+	}
+
+	// A repository with no PRs at all fails the job
+	newFakeGitHub(t, fakeScenario{})
+	resp, _ = postAnalyze(t, srv.URL, "", `{"repo": "acme/empty"}`)
+	if failed := pollJob(t, srv.URL+resp.Header.Get("Location")); failed.Status != jobFailed || failed.Error != "no PRs found" {
+		t.Errorf("job = %+v, want failed with no PRs found", failed)
+	}
+}
+
+func TestServeAnalyzeValidates(t *testing.T) {
+	_, srv := newTestServer(t, 5, Tenant{Repos: []string{"acme/widgets"}})
+	for _, tc := range []struct {
+		body string
+		want int
+	}{
+		{`{"repo": "acme/widgets"}`, http.StatusAccepted},
+		{`{"repo": "acme/widgets", "limit": 0}`, http.StatusAccepted}, // The server's default
+		{`{"repo": "acme/widgets", "limit": 100, "window": "30d"}`, http.StatusAccepted},
+		{`{"repo": "acme/widgets", "window": "Q3-2024"}`, http.StatusAccepted},
+		{`{"repo": "acme/widgets", "limit": -1}`, http.StatusBadRequest},
+		{`{"repo": "acme/widgets", "limit": 101}`, http.StatusBadRequest},
+		{`{"repo": "acme/widgets", "window": "0d"}`, http.StatusBadRequest},
+		{`{"repo": "acme/widgets", "window": "last week"}`, http.StatusBadRequest},
+		{`{"repo": "widgets"}`, http.StatusBadRequest},
+		{`{"repo": "acme/other"}`, http.StatusForbidden},
+		{`not json`, http.StatusBadRequest},
+	} {
+		if resp, _ := postAnalyze(t, srv.URL, "", tc.body); resp.StatusCode != tc.want {
+			t.Errorf("POST %s: %s, want %d", tc.body, resp.Status, tc.want)
+		}
+	}
+}
+
+func TestServeQueueFull(t *testing.T) {
+	_, srv := newTestServer(t, 1, Tenant{}) // No worker drains the queue
+	if resp, _ := postAnalyze(t, srv.URL, "", `{"repo": "acme/widgets"}`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("first POST: %s, want 202", resp.Status)
+	}
+	if resp, _ := postAnalyze(t, srv.URL, "", `{"repo": "acme/widgets"}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("POST to a full queue: %s, want 503", resp.Status)
+	}
+}
+
+func TestServeJobTTL(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+	t.Cleanup(func() { clock = time.Now })
+	s, srv := newTestServer(t, 5, Tenant{})
+	old, recent := now.Add(-2*time.Hour), now.Add(-30*time.Minute)
+	s.jobs["old"] = &analysisJob{ID: "old", Status: jobDone, Finished: &old, tenant: s.tenants[""]}
+	s.jobs["recent"] = &analysisJob{ID: "recent", Status: jobDone, Finished: &recent, tenant: s.tenants[""]}
+	s.jobs["running"] = &analysisJob{ID: "running", Status: jobRunning, tenant: s.tenants[""]}
+
+	// Queuing a job prunes those finished over --job-ttl ago
+	postAnalyze(t, srv.URL, "", `{"repo": "acme/widgets"}`)
+	for id, want := range map[string]int{"old": http.StatusNotFound, "recent": http.StatusOK, "running": http.StatusOK} {
+		resp, err := http.Get(srv.URL + "/jobs/" + id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET /jobs/%s: %s, want %d", id, resp.Status, want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReviewPolicy is a hypothetical review policy to replay history under.
+type ReviewPolicy struct {
+	Approvals    int           // Required approvals (0: as it happened)
+	AutoMerge    bool          // Merge as soon as the required approvals are in
+	AssignWithin time.Duration // A reviewer is requested at most this long after opening (0: off)
+}
+
+func (p ReviewPolicy) String() string {
+	var parts []string
+	if p.Approvals > 0 {
+		s := "s"
+		if p.Approvals == 1 {
+			s = ""
+		}
+		parts = append(parts, fmt.Sprintf("%d approval%s", p.Approvals, s))
+	}
+	if p.AutoMerge {
+		parts = append(parts, "auto-merge")
+	}
+	if p.AssignWithin > 0 {
+		parts = append(parts, "assign within "+formatDuration(p.AssignWithin))
+	}
+	if len(parts) == 0 {
+		return "As it happened"
+	}
+	return strings.Join(parts, " + ")
+}
+
+// firstEngagement returns when a reviewer was first requested or first
+// reviewed pr, whichever came first.
+func firstEngagement(pr PullRequest) (time.Time, bool) {
+	var first time.Time
+	for _, r := range pr.ReviewRequests {
+		if first.IsZero() || r.At.Before(first) {
+			first = r.At
+		}
+	}
+	for _, r := range pr.Reviews {
+		if r.Author != pr.Author && !isBot(r.Author) && (first.IsZero() || r.CreatedAt.Before(first)) {
+			first = r.CreatedAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// simulateMerge replays one merged PR under p and returns its simulated
+// merge time. applied is false when p can't be replayed on the PR, e.g. it
+// requires more approvals than the PR ever got; the actual merge is
+// returned then.
+//
+// The model shifts events, it doesn't invent them: an earlier reviewer
+// assignment moves everything after it earlier, a lower approval bar lets
+// the PR merge at an earlier approval with the delay it actually had after
+// its last one, and auto-merge drops that delay but waits for the last
+// commit (CI is assumed green by then).
+func simulateMerge(pr PullRequest, p ReviewPolicy) (merged time.Time, applied bool) {
+	merged, applied = pr.MergedAt, true
+
+	if p.Approvals > 0 || p.AutoMerge {
+		approvals := approvalOrder(pr)
+		need := p.Approvals
+		if need == 0 {
+			need = len(approvals)
+		}
+		if len(approvals) == 0 || len(approvals) < need {
+			applied = false
+		} else {
+			gate := approvals[need-1].At
+			if p.AutoMerge {
+				merged = gate
+				for _, c := range pr.CommitTimes {
+					if c.After(merged) && c.Before(pr.MergedAt) {
+						merged = c
+					}
+				}
+			} else {
+				delay, _ := approvalToMerge(pr)
+				merged = gate.Add(delay)
+			}
+			if merged.After(pr.MergedAt) {
+				merged = pr.MergedAt
+			}
+		}
+	}
+
+	if p.AssignWithin > 0 {
+		if first, ok := firstEngagement(pr); ok {
+			if idle := first.Sub(pr.CreatedAt) - p.AssignWithin; idle > 0 {
+				merged = merged.Add(-idle)
+			}
+		}
+	}
+	if merged.Before(pr.CreatedAt) {
+		merged = pr.CreatedAt
+	}
+	return merged, applied
+}
+
+// defaultPolicies are the scenarios simulated when no policy flag is given.
+var defaultPolicies = []ReviewPolicy{
+	{Approvals: 1},
+	{Approvals: 2},
+	{AutoMerge: true},
+	{AssignWithin: time.Hour},
+	{Approvals: 1, AutoMerge: true, AssignWithin: time.Hour},
+}
+
+func printSimulation(repo string, prs []PullRequest, policies []ReviewPolicy) {
+	fmt.Printf("🧪 POLICY SIMULATION: %s\n", repo)
+	printExplanation("Replays the merged PRs under hypothetical review policies and estimates the cycle time each would have produced.",
+		"Turns history into a decision tool: see what changing the approval rule, enabling auto-merge or assigning reviewers faster would be worth before you do it.")
+
+	if len(prs) == 0 {
+		fmt.Println("   No merged PRs to replay.")
+		return
+	}
+	var actual []time.Duration
+	for _, pr := range prs {
+		actual = append(actual, pr.MergedAt.Sub(pr.CreatedAt))
+	}
+	baseMedian, baseP90 := percentile(actual, 50), percentile(actual, 90)
+
+	fmt.Printf("   %-48s %-12s %-8s %-12s %-8s %s\n", "Policy", "Median", "Change", "P90", "Change", "Replayed")
+	fmt.Printf("   %-48s %-12s %-8s %-12s %-8s %d\n", ReviewPolicy{}, formatDuration(baseMedian), "", formatDuration(baseP90), "", len(prs))
+	for _, p := range policies {
+		var cycle []time.Duration
+		applied := 0
+		for _, pr := range prs {
+			merged, ok := simulateMerge(pr, p)
+			if ok {
+				applied++
+			}
+			cycle = append(cycle, merged.Sub(pr.CreatedAt))
+		}
+		median, p90 := percentile(cycle, 50), percentile(cycle, 90)
+		fmt.Printf("   %-48s %-12s %-8s %-12s %-8s %d\n", limitString(p.String(), 48),
+			formatDuration(median), fmt.Sprintf("%+.0f%%", pctChange(float64(baseMedian), float64(median))),
+			formatDuration(p90), fmt.Sprintf("%+.0f%%", pctChange(float64(baseP90), float64(p90))), applied)
+	}
+
+	fmt.Println()
+	fmt.Println("   Replayed: PRs the policy could be applied to. The others keep their actual merge time,")
+	fmt.Println("   so a stricter policy (more approvals than PRs got) shows no change rather than a guess.")
+	fmt.Println("   Estimates shift recorded events; they can't tell what a skipped review would have caught.")
+}
+
+func runSimulate(args []string) {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	limit := fs.Int("limit", 100, "Max number of merged PRs to replay")
+	approvals := fs.Int("approvals", 0, "Required approvals to simulate (0: keep as it happened)")
+	autoMerge := fs.Bool("auto-merge", false, "Simulate merging as soon as the required approvals are in")
+	assignWithin := fs.Duration("assign-within", 0, "Simulate a reviewer being assigned within this long of opening (e.g. 1h)")
+	excludeOutliers := fs.Bool("exclude-outliers", false, "Exclude top and bottom 5% of outliers")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck simulate [flags] <owner/repo>")
+		fmt.Println("Without policy flags, a standard set of scenarios is simulated.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 || *approvals < 0 || *assignWithin < 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	policies := defaultPolicies
+	if custom := (ReviewPolicy{Approvals: *approvals, AutoMerge: *autoMerge, AssignWithin: *assignWithin}); custom != (ReviewPolicy{}) {
+		policies = []ReviewPolicy{custom}
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+	prs, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+	if err != nil && len(prs) == 0 {
+		slog.Error("fetching merged PRs", "repo", repo, "err", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Warn("continuing with partial data", "repo", repo, "fetched", len(prs), "err", err)
+	}
+	if *excludeOutliers {
+		prs = filterOutliers(prs)
+	}
+
+	printSimulation(repo, prs, policies)
+}
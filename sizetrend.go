@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sizeTrendMinPRs is how many merged PRs a month needs to count toward the
+// size trend verdict.
+const sizeTrendMinPRs = 5
+
+// sizeMonth is the PR size distribution of one month.
+type sizeMonth struct {
+	Month  string
+	Count  int
+	Median float64
+	P90    float64
+}
+
+// monthlySizes groups the sizes of merged PRs by month of merge.
+func monthlySizes(prs []PullRequest) []sizeMonth {
+	byMonth := make(map[string][]float64)
+	for _, pr := range prs {
+		key := pr.MergedAt.In(primaryLocation).Format("2006-01")
+		byMonth[key] = append(byMonth[key], float64(pr.Size))
+	}
+	var out []sizeMonth
+	for key, sizes := range byMonth {
+		sort.Float64s(sizes)
+		out = append(out, sizeMonth{Month: key, Count: len(sizes), Median: quantile(sizes, 50), P90: quantile(sizes, 90)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Month < out[j].Month })
+	return out
+}
+
+// printSizeTrend prints median and P90 PR size by month, and whether PRs
+// are shrinking or growing: the last three complete months against the
+// three before.
+func printSizeTrend(prs []PullRequest) {
+	months := monthlySizes(prs)
+	if len(months) < 2 {
+		return
+	}
+	current := clock().In(primaryLocation).Format("2006-01")
+
+	fmt.Println("\n   Size discipline (lines changed per merged PR):")
+	fmt.Printf("   %-9s %5s %8s %8s\n", "Month", "PRs", "Median", "P90")
+	peak := 0.0
+	for _, m := range months {
+		peak = max(peak, m.Median)
+	}
+	var complete []sizeMonth
+	for _, m := range months {
+		note := ""
+		if m.Month == current {
+			note = " ◐ in progress"
+		} else if m.Count >= sizeTrendMinPRs {
+			complete = append(complete, m)
+		}
+		bar := ""
+		if peak > 0 {
+			bar = strings.Repeat("█", int(m.Median/peak*20))
+		}
+		line := fmt.Sprintf("   %-9s %5d %8s %8s  %-20s%s", formatMonthKey(m.Month), m.Count, formatInt(int(m.Median)), formatInt(int(m.P90)), bar, note)
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	window := min(3, len(complete)/2)
+	if window == 0 {
+		fmt.Println("   (Not enough complete months to call a trend.)")
+		return
+	}
+	avg := func(ms []sizeMonth, f func(sizeMonth) float64) float64 {
+		total, n := 0.0, 0
+		for _, m := range ms {
+			total += f(m) * float64(m.Count)
+			n += m.Count
+		}
+		return total / float64(n)
+	}
+	median := func(m sizeMonth) float64 { return m.Median }
+	p90 := func(m sizeMonth) float64 { return m.P90 }
+	before, after := complete[len(complete)-2*window:len(complete)-window], complete[len(complete)-window:]
+	medBefore, medAfter := avg(before, median), avg(after, median)
+	p90Before, p90After := avg(before, p90), avg(after, p90)
+	change := pctChange(medBefore, medAfter)
+
+	fmt.Println()
+	span := fmt.Sprintf("the last %d complete months against the %d before", window, window)
+	switch {
+	case change <= -10:
+		fmt.Printf("   📉 PRs are shrinking: median %s → %s lines (%.0f%%), P90 %s → %s, %s.\n",
+			formatInt(int(medBefore)), formatInt(int(medAfter)), change, formatInt(int(p90Before)), formatInt(int(p90After)), span)
+	case change >= 10:
+		fmt.Printf("   📈 PRs are growing: median %s → %s lines (+%.0f%%), P90 %s → %s, %s.\n",
+			formatInt(int(medBefore)), formatInt(int(medAfter)), change, formatInt(int(p90Before)), formatInt(int(p90After)), span)
+	default:
+		fmt.Printf("   ➖ PR size is steady: median %s → %s lines (%+.0f%%), P90 %s → %s, %s.\n",
+			formatInt(int(medBefore)), formatInt(int(medAfter)), change, formatInt(int(p90Before)), formatInt(int(p90After)), span)
+	}
+}
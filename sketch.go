@@ -0,0 +1,167 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// tdigest is a merging t-digest: an approximate quantile sketch that keeps
+// at most a few hundred centroids however many values are added. Accuracy
+// is best at the tails, where P90 and P99 live.
+type tdigest struct {
+	compression float64
+	centroids   []centroid // Sorted by mean after compress
+	buffer      []float64
+	count       float64
+	min, max    float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+func newTDigest() *tdigest { return &tdigest{compression: 100} }
+
+// Add adds one value.
+func (t *tdigest) Add(x float64) {
+	if t.count == 0 || x < t.min {
+		t.min = x
+	}
+	if t.count == 0 || x > t.max {
+		t.max = x
+	}
+	t.count++
+	t.buffer = append(t.buffer, x)
+	if len(t.buffer) >= 500 {
+		t.compress()
+	}
+}
+
+// compress merges the buffered values into the centroids. A centroid may
+// grow to 4·n·q(1-q)/compression, so those near the median absorb many
+// values and those at the tails stay small.
+func (t *tdigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := t.centroids
+	for _, x := range t.buffer {
+		all = append(all, centroid{x, 1})
+	}
+	t.buffer = t.buffer[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	var out []centroid
+	cur, before := all[0], 0.0
+	for _, c := range all[1:] {
+		q := (before + (cur.weight+c.weight)/2) / t.count
+		if cur.weight+c.weight <= math.Max(1, 4*t.count*q*(1-q)/t.compression) {
+			cur.mean += (c.mean - cur.mean) * c.weight / (cur.weight + c.weight)
+			cur.weight += c.weight
+			continue
+		}
+		before += cur.weight
+		out = append(out, cur)
+		cur = c
+	}
+	t.centroids = append(out, cur)
+}
+
+// Quantile estimates the p-th quantile (0-100), interpolating between
+// centroid centers.
+func (t *tdigest) Quantile(p float64) float64 {
+	t.compress()
+	if t.count == 0 {
+		return 0
+	}
+	target := p / 100 * t.count
+	prevCenter, prevMean := 0.0, t.min
+	cum := 0.0
+	for _, c := range t.centroids {
+		center := cum + c.weight/2
+		if target < center {
+			if center == prevCenter {
+				return c.mean
+			}
+			return prevMean + (c.mean-prevMean)*(target-prevCenter)/(center-prevCenter)
+		}
+		prevCenter, prevMean = center, c.mean
+		cum += c.weight
+	}
+	if t.count == prevCenter {
+		return t.max
+	}
+	return prevMean + (t.max-prevMean)*(target-prevCenter)/(t.count-prevCenter)
+}
+
+// Count is the number of values added.
+func (t *tdigest) Count() int { return int(t.count) }
+
+// countMin is a count-min sketch with a bounded set of heavy-hitter
+// candidates: approximate counts of arbitrarily many keys in fixed memory.
+// Estimates never undercount, and with high probability overcount by at
+// most about 0.1% of all counts.
+type countMin struct {
+	rows [4][2048]uint32
+	top  map[string]uint32 // Heavy-hitter candidates and their estimates
+	k    int
+}
+
+func newCountMin(k int) *countMin { return &countMin{top: make(map[string]uint32), k: k} }
+
+func (c *countMin) index(row int, key string) int {
+	h := fnv.New64a()
+	h.Write([]byte{byte(row)})
+	h.Write([]byte(key))
+	return int(h.Sum64() % uint64(len(c.rows[row])))
+}
+
+// Add counts key once and keeps it among the candidates when its estimate
+// beats the smallest one tracked.
+func (c *countMin) Add(key string) {
+	est := uint32(math.MaxUint32)
+	for r := range c.rows {
+		i := c.index(r, key)
+		c.rows[r][i]++
+		est = min(est, c.rows[r][i])
+	}
+	if _, ok := c.top[key]; ok || len(c.top) < c.k {
+		c.top[key] = est
+		return
+	}
+	minKey, minEst := "", uint32(math.MaxUint32)
+	for k, v := range c.top {
+		if v < minEst {
+			minKey, minEst = k, v
+		}
+	}
+	if est > minEst {
+		delete(c.top, minKey)
+		c.top[key] = est
+	}
+}
+
+// Estimate returns the approximate count of key.
+func (c *countMin) Estimate(key string) uint32 {
+	est := uint32(math.MaxUint32)
+	for r := range c.rows {
+		est = min(est, c.rows[r][c.index(r, key)])
+	}
+	return est
+}
+
+// Top returns the n candidates with the highest estimates.
+func (c *countMin) Top(n int) []string {
+	keys := make([]string, 0, len(c.top))
+	for k := range c.top {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ei, ej := c.Estimate(keys[i]), c.Estimate(keys[j]); ei != ej {
+			return ei > ej
+		}
+		return keys[i] < keys[j]
+	})
+	return keys[:min(n, len(keys))]
+}
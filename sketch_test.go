@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTDigestQuantile(t *testing.T) {
+	// Merge times are heavily right-skewed: mostly hours, a tail of weeks
+	rng := rand.New(rand.NewSource(1))
+	td := newTDigest()
+	var exact []time.Duration
+	for range 50000 {
+		hours := math.Exp(rng.NormFloat64()*1.5 + 2)
+		td.Add(hours)
+		exact = append(exact, time.Duration(hours*float64(time.Hour)))
+	}
+	for _, p := range []float64{10, 50, 75, 90, 95, 99} {
+		want := percentile(exact, p).Hours()
+		got := td.Quantile(p)
+		if math.Abs(got-want)/want > 0.01 {
+			t.Errorf("P%.0f = %.3fh, exact %.3fh: off by %.2f%%", p, got, want, math.Abs(got-want)/want*100)
+		}
+	}
+	if td.Count() != 50000 {
+		t.Errorf("Count = %d, want 50000", td.Count())
+	}
+}
+
+func TestCountMin(t *testing.T) {
+	// A few heavy directories and a long tail of small ones
+	rng := rand.New(rand.NewSource(2))
+	cm := newCountMin(50)
+	exact := make(map[string]uint32)
+	total := 0
+	for range 100000 {
+		var key string
+		if rng.Intn(2) == 0 {
+			key = fmt.Sprintf("heavy-%d", rng.Intn(5))
+		} else {
+			key = fmt.Sprintf("tail-%d", rng.Intn(20000))
+		}
+		cm.Add(key)
+		exact[key]++
+		total++
+	}
+	for key, n := range exact {
+		est := cm.Estimate(key)
+		if est < n {
+			t.Fatalf("%s: estimate %d undercounts %d", key, est, n)
+		}
+		if over := float64(est-n) / float64(total); over > 0.001 {
+			t.Errorf("%s: estimate %d overcounts %d by %.3f%% of the total", key, est, n, over*100)
+		}
+	}
+	top := cm.Top(5)
+	for i := range 5 {
+		if want := fmt.Sprintf("heavy-%d", i); !slices.Contains(top, want) {
+			t.Errorf("Top(5) = %v, missing %s", top, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Policy sets SLAs for a class of PRs.
+type Policy struct {
+	Name        string      `yaml:"name"`
+	Match       PolicyMatch `yaml:"match"`
+	FirstReview Duration    `yaml:"first_review"`
+	Merge       Duration    `yaml:"merge"`
+}
+
+// PolicyMatch selects PRs. Every non-empty field must match (any of its
+// values); an empty match applies to all PRs.
+type PolicyMatch struct {
+	Labels  []string `yaml:"labels"`
+	Paths   []string `yaml:"paths"`   // Path prefixes of changed files
+	Teams   []string `yaml:"teams"`   // The author's team (see teams:)
+	Authors []string `yaml:"authors"` // Author logins
+}
+
+func (m PolicyMatch) matches(pr PullRequest) bool {
+	if len(m.Labels) > 0 && !anyEqualFold(m.Labels, pr.Labels) {
+		return false
+	}
+	if len(m.Authors) > 0 && !anyEqualFold(m.Authors, []string{pr.Author}) {
+		return false
+	}
+	if len(m.Teams) > 0 && !anyEqualFold(m.Teams, []string{teamOf(pr.Author)}) {
+		return false
+	}
+	if len(m.Paths) > 0 {
+		found := false
+		for _, p := range pr.FilePaths {
+			for _, prefix := range m.Paths {
+				if strings.HasPrefix(p, prefix) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func anyEqualFold(want, have []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(w, h) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// policyFor returns the first configured policy matching the PR, or nil.
+func policyFor(pr PullRequest) *Policy {
+	for i := range cfg.Policies {
+		if cfg.Policies[i].Match.matches(pr) {
+			return &cfg.Policies[i]
+		}
+	}
+	return nil
+}
+
+func (p *Policy) describe() string {
+	var parts []string
+	if p.FirstReview > 0 {
+		parts = append(parts, "first review "+formatDuration(time.Duration(p.FirstReview)))
+	}
+	if p.Merge > 0 {
+		parts = append(parts, "merge "+formatDuration(time.Duration(p.Merge)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printSLAPolicies reports SLA compliance per policy and returns the number
+// of open PRs currently in violation.
+func printSLAPolicies(merged, open []PullRequest) int {
+	fmt.Println("⏱️  SLA POLICIES")
+	printExplanation("Checks each PR against the SLA of its class (labels, paths, author's team).", "A hotfix waiting 4h is an incident; a chore waiting 2 days is fine. One global threshold fits neither.")
+
+	type PolicyStat struct {
+		Merged                   int
+		ReviewedCount, ReviewHit int
+		MergeHit                 int
+		Violations               []string
+	}
+	stats := make([]PolicyStat, len(cfg.Policies))
+	index := func(p *Policy) int {
+		for i := range cfg.Policies {
+			if &cfg.Policies[i] == p {
+				return i
+			}
+		}
+		return -1
+	}
+
+	unmatched := 0
+	for _, pr := range merged {
+		p := policyFor(pr)
+		if p == nil {
+			unmatched++
+			continue
+		}
+		s := &stats[index(p)]
+		s.Merged++
+		if pr.FirstReviewAt != nil {
+			s.ReviewedCount++
+			if p.FirstReview == 0 || pr.FirstReviewAt.Sub(pr.CreatedAt) <= time.Duration(p.FirstReview) {
+				s.ReviewHit++
+			}
+		}
+		if p.Merge == 0 || pr.MergedAt.Sub(pr.CreatedAt) <= time.Duration(p.Merge) {
+			s.MergeHit++
+		}
+	}
+
+	now := clock()
+	violations := 0
+	for _, pr := range open {
+		if pr.IsDraft {
+			continue
+		}
+		p := policyFor(pr)
+		if p == nil {
+			continue
+		}
+		s := &stats[index(p)]
+		age := now.Sub(pr.CreatedAt)
+		if p.FirstReview > 0 && pr.FirstReviewAt == nil && age > time.Duration(p.FirstReview) {
+			s.Violations = append(s.Violations, fmt.Sprintf("#%d (%s) - no review for %s", pr.Number, limitString(pr.Title, 40), formatDuration(age)))
+			violations++
+		} else if p.Merge > 0 && age > time.Duration(p.Merge) {
+			s.Violations = append(s.Violations, fmt.Sprintf("#%d (%s) - open for %s", pr.Number, limitString(pr.Title, 40), formatDuration(age)))
+			violations++
+		}
+	}
+
+	for i, p := range cfg.Policies {
+		s := stats[i]
+		fmt.Printf("   %s (%s)\n", p.Name, p.describe())
+		if s.Merged > 0 {
+			line := fmt.Sprintf("      Merged: %d PRs", s.Merged)
+			if p.FirstReview > 0 && s.ReviewedCount > 0 {
+				line += fmt.Sprintf(" | First review within SLA: %.0f%%", float64(s.ReviewHit)/float64(s.ReviewedCount)*100)
+			}
+			if p.Merge > 0 {
+				line += fmt.Sprintf(" | Merge within SLA: %.0f%%", float64(s.MergeHit)/float64(s.Merged)*100)
+			}
+			fmt.Println(line)
+		} else {
+			fmt.Println("      Merged: 0 PRs")
+		}
+		if len(s.Violations) == 0 {
+			fmt.Println("      ✅ No open violations.")
+			continue
+		}
+		fmt.Printf("      🚨 Open violations: %d\n", len(s.Violations))
+		for _, v := range s.Violations {
+			fmt.Printf("         %s\n", v)
+		}
+	}
+	if unmatched > 0 {
+		fmt.Printf("\n   (%d merged PRs matched no policy.)\n", unmatched)
+	}
+	return violations
+}
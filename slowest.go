@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// hugePRLines is the size from which a PR counts as huge.
+const hugePRLines = 1000
+
+// slowestPRs returns the n merged PRs that took longest to merge, slowest
+// first.
+func slowestPRs(prs []PullRequest, n int) []PullRequest {
+	sorted := append([]PullRequest(nil), prs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MergedAt.Sub(sorted[i].CreatedAt) > sorted[j].MergedAt.Sub(sorted[j].CreatedAt)
+	})
+	return sorted[:min(n, len(sorted))]
+}
+
+// slowCauses tags the probable causes of a slow PR from its phases, size,
+// review rounds and, when fetched, its check runs.
+func slowCauses(pr PullRequest, phases []prPhase, checks []CheckRun) []string {
+	var total time.Duration
+	share := make(map[string]float64)
+	for _, p := range phases {
+		total += p.Duration()
+	}
+	for _, p := range phases {
+		if total > 0 {
+			share[p.Name] = float64(p.Duration()) / float64(total)
+		}
+	}
+
+	var causes []string
+	switch {
+	case share["Unreviewed"] > 0:
+		causes = append(causes, "never reviewed")
+	case share["Awaiting reviewer"] >= 0.25:
+		causes = append(causes, "no reviewer assigned")
+	}
+	if share["Awaiting first review"] >= 0.4 {
+		causes = append(causes, "slow first review")
+	}
+	if pr.Size >= hugePRLines {
+		causes = append(causes, "huge size")
+	}
+	if reviewRounds(pr) >= 3 {
+		causes = append(causes, "many rounds")
+	}
+	for _, c := range checks {
+		if c.failed() {
+			causes = append(causes, "CI failures")
+			break
+		}
+	}
+	if share["Approved, awaiting merge"] >= 0.4 {
+		causes = append(causes, "waiting to merge")
+	}
+	if len(causes) == 0 && share["In review"] >= 0.5 {
+		causes = append(causes, "long review")
+	}
+	return causes
+}
+
+// printSlowestPRs prints case studies of the n slowest merged PRs. checks
+// holds their check runs, or is nil when CI data wasn't fetched.
+func printSlowestPRs(prs []PullRequest, n int, checks map[int][]CheckRun) {
+	slowest := slowestPRs(prs, n)
+	fmt.Printf("🐢 %d SLOWEST PRs: CASE STUDIES\n", len(slowest))
+	printExplanation("The slowest merged PRs with their time per phase (waiting for a reviewer, for the first review, in review, waiting to merge) and the probable cause.",
+		"Aggregates motivate, examples convince. These are the PRs to walk through in a retro.")
+
+	tally := make(map[string]int)
+	for i, pr := range slowest {
+		if i > 0 {
+			fmt.Println()
+		}
+		phases := prPhases(pr, pr.MergedAt)
+		causes := slowCauses(pr, phases, checks[pr.Number])
+		for _, c := range causes {
+			tally[c]++
+		}
+		tag := "no clear cause"
+		if len(causes) > 0 {
+			tag = strings.Join(causes, ", ")
+		}
+		by := ""
+		if privacy != privacyAggregate {
+			by = person(pr.Author) + ", "
+		}
+		fmt.Printf("   #%d %s (%s%s lines, %d rounds, %s)\n", pr.Number, limitString(pr.Title, 50), by, formatInt(pr.Size), reviewRounds(pr), formatDuration(pr.MergedAt.Sub(pr.CreatedAt)))
+		fmt.Printf("     Probable cause: %s\n", tag)
+		if c, share := dominantCause(delayBreakdown(pr, checks[pr.Number])); c != "" {
+			fmt.Printf("     Dominant delay: %s (%.0f%% of its time)\n", c, share*100)
+		}
+		printTimeline(phases, "     ")
+	}
+
+	if len(tally) > 0 {
+		var causes []string
+		for c := range tally {
+			causes = append(causes, c)
+		}
+		sort.Slice(causes, func(i, j int) bool {
+			if tally[causes[i]] != tally[causes[j]] {
+				return tally[causes[i]] > tally[causes[j]]
+			}
+			return causes[i] < causes[j]
+		})
+		var parts []string
+		for _, c := range causes {
+			parts = append(parts, fmt.Sprintf("%s (%d)", c, tally[c]))
+		}
+		fmt.Printf("\n   Causes across these PRs: %s\n", strings.Join(parts, ", "))
+	}
+	note := "   (Huge: %s+ lines. Run with --ci to also flag CI failures. Drill into one with `bottleneck pr <owner/repo> <number>`.)\n"
+	if checks != nil {
+		note = "   (Huge: %s+ lines. Drill into one with `bottleneck pr <owner/repo> <number>`.)\n"
+	}
+	fmt.Printf("\n"+note, formatInt(hugePRLines))
+}
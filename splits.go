@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// splitMinShare is the smallest share of a PR's lines a suggested part
+// gets on its own; smaller groups are folded into the largest part.
+const splitMinShare = 0.1
+
+// splitPart is one suggested PR out of an oversized one.
+type splitPart struct {
+	Label string
+	Files []string
+	Lines int
+}
+
+// suggestSplit proposes how to break an oversized PR along natural
+// boundaries: docs apart from code, then top-level directories (or
+// services), then subdirectories, then tests apart from sources. It returns
+// nil when no boundary leaves two parts of a worthwhile size.
+func suggestSplit(pr PullRequest, generated *GeneratedMatcher) []splitPart {
+	var files []string
+	lines := 0
+	for _, p := range pr.FilePaths {
+		if ok, _ := generated.Match(p); ok {
+			continue
+		}
+		files = append(files, p)
+		lines += pr.FileLines[p]
+	}
+	if lines == 0 {
+		return nil
+	}
+
+	// Documentation reviews apart from the code, whatever else is split,
+	// unless there is next to none of it
+	var docs, code []string
+	for _, p := range files {
+		if fileCategory(p) == "Docs" {
+			docs = append(docs, p)
+		} else {
+			code = append(code, p)
+		}
+	}
+	if len(code) == 0 || float64(sumLines(pr, docs)) < splitMinShare*float64(lines) {
+		code, docs = files, nil
+	}
+
+	var parts []splitPart
+	for depth := 0; depth <= 2 && len(parts) < 2; depth++ {
+		parts = groupParts(pr, code, lines, func(p string) string { return groupAt(p, depth) })
+	}
+	if len(parts) < 2 {
+		parts = groupParts(pr, code, lines, func(p string) string {
+			if isTestFile(p) {
+				return "tests"
+			}
+			return "sources"
+		})
+	}
+	if len(parts) < 2 {
+		parts = []splitPart{{Label: "code", Files: code, Lines: sumLines(pr, code)}}
+	}
+	if len(docs) > 0 {
+		parts = append(parts, splitPart{Label: "docs", Files: docs, Lines: sumLines(pr, docs)})
+	}
+	if len(parts) < 2 {
+		return nil
+	}
+	return parts
+}
+
+// groupParts groups files by key, largest first, folding groups under
+// splitMinShare of total into the largest.
+func groupParts(pr PullRequest, files []string, total int, key func(string) string) []splitPart {
+	byKey := make(map[string]*splitPart)
+	for _, p := range files {
+		k := key(p)
+		if byKey[k] == nil {
+			byKey[k] = &splitPart{Label: k}
+		}
+		byKey[k].Files = append(byKey[k].Files, p)
+		byKey[k].Lines += pr.FileLines[p]
+	}
+	var parts []splitPart
+	for _, part := range byKey {
+		parts = append(parts, *part)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if parts[i].Lines != parts[j].Lines {
+			return parts[i].Lines > parts[j].Lines
+		}
+		return parts[i].Label < parts[j].Label
+	})
+
+	var kept []splitPart
+	var folded []string
+	for i, part := range parts {
+		if i > 0 && float64(part.Lines) < splitMinShare*float64(total) {
+			kept[0].Files = append(kept[0].Files, part.Files...)
+			kept[0].Lines += part.Lines
+			folded = append(folded, part.Label)
+			continue
+		}
+		kept = append(kept, part)
+	}
+	if len(folded) > 0 {
+		kept[0].Label += " (with " + strings.Join(folded, ", ") + ")"
+	}
+	return kept
+}
+
+func sumLines(pr PullRequest, files []string) int {
+	n := 0
+	for _, p := range files {
+		n += pr.FileLines[p]
+	}
+	return n
+}
+
+func printSplitSuggestions(open []PullRequest, generated *GeneratedMatcher) {
+	fmt.Println("✂️  SPLIT SUGGESTIONS")
+	printExplanation(fmt.Sprintf("Open PRs of %s+ lines and where their changed files naturally fall apart: docs, directories or services, tests and sources.", formatInt(hugePRLines)),
+		"\"Ship smaller PRs\" is easy to say. A concrete plan for the PR in front of you is easier to act on.")
+
+	var big []PullRequest
+	for _, pr := range open {
+		if pr.Size >= hugePRLines && !isBot(pr.Author) {
+			big = append(big, pr)
+		}
+	}
+	if len(big) == 0 {
+		fmt.Printf("   ✅ No open PR has %s or more lines.\n", formatInt(hugePRLines))
+		return
+	}
+	sort.Slice(big, func(i, j int) bool {
+		if big[i].Size != big[j].Size {
+			return big[i].Size > big[j].Size
+		}
+		return big[i].Number < big[j].Number
+	})
+
+	unsplittable := 0
+	for _, pr := range big {
+		by := ""
+		if privacy != privacyAggregate {
+			by = " by " + person(pr.Author)
+		}
+		fmt.Printf("   📦 #%d (%s)%s - %s lines in %d files\n", pr.Number, limitString(pr.Title, 40), by, formatInt(pr.Size), pr.ChangedFiles)
+		parts := suggestSplit(pr, generated)
+		if parts == nil {
+			unsplittable++
+			fmt.Println("      No natural boundary: consider a stacked series of PRs, or landing it behind a feature flag in steps.")
+			continue
+		}
+		for i, part := range parts {
+			files := "files"
+			if len(part.Files) == 1 {
+				files = "file"
+			}
+			fmt.Printf("      %d. %-36s %6s lines, %d %s\n", i+1, limitString(part.Label, 36), formatInt(part.Lines), len(part.Files), files)
+		}
+		if unseen := pr.ChangedFiles - len(pr.FilePaths); unseen > 0 {
+			fmt.Printf("      (%d more files weren't fetched and aren't placed.)\n", unseen)
+		}
+	}
+	fmt.Println("\n   Parts under 10% of a PR's lines are folded into its largest part; generated files are left out.")
+	if unsplittable > 0 {
+		fmt.Printf("   (%d PRs change one area only, with no test or docs split worth making.)\n", unsplittable)
+	}
+}
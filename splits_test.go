@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// splitTestPR is an open PR with the given changed lines per file.
+func splitTestPR(number, size int, lines map[string]int) PullRequest {
+	pr := PullRequest{Number: number, Title: "feat(orders): order sync across api and worker", Author: "alice", Size: size, FileLines: lines}
+	for p := range lines {
+		pr.FilePaths = append(pr.FilePaths, p)
+	}
+	pr.ChangedFiles = len(pr.FilePaths)
+	return pr
+}
+
+func TestSplitSuggestions(t *testing.T) {
+	// Spans two services, a shared library, docs and a lockfile
+	big := splitTestPR(262, 1440, map[string]int{
+		"services/api/handlers/orders.go":      480,
+		"services/api/handlers/orders_test.go": 390,
+		"services/worker/jobs/sync.go":         350,
+		"libs/auth/token.go":                   40,
+		"docs/orders.md":                       180,
+		"go.sum":                               52,
+	})
+	// One directory: only tests and sources can be told apart
+	oneDir := splitTestPR(270, 1200, map[string]int{
+		"pkg/cache/lru.go":      700,
+		"pkg/cache/lru_test.go": 500,
+	})
+	small := splitTestPR(271, 300, map[string]int{"pkg/cache/lru.go": 300})
+
+	got := captureStdout(t, func() { printSplitSuggestions([]PullRequest{small, oneDir, big}, newGeneratedMatcher("")) })
+	want := `   📦 #262 (feat(orders): order sync across api and ...) by alice - 1440 lines in 6 files
+      1. services/api (with libs/auth)           910 lines, 3 files
+      2. services/worker                         350 lines, 1 file
+      3. docs                                    180 lines, 1 file
+   📦 #270 (feat(orders): order sync across api and ...) by alice - 1200 lines in 2 files
+      1. sources                                 700 lines, 1 file
+      2. tests                                   500 lines, 1 file
+`
+	if !strings.Contains(got, want) {
+		t.Errorf("output:\n%s\nwant the plans:\n%s", got, want)
+	}
+	if strings.Contains(got, "#271") {
+		t.Errorf("a 300-line PR got a split suggestion:\n%s", got)
+	}
+}
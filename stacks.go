@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Body markers left by stacked-diff tooling. Each tool lists the whole
+// stack as a bullet list of PR references in the description.
+var stackMarkers = []string{
+	"Stack from [ghstack]",
+	"ghstack-source-id",
+	"Current dependencies on/for this PR",
+	"app.graphite.dev",
+}
+
+var stackListEntry = regexp.MustCompile(`(?m)^\s*[*-]\s+(?:__->__\s+)?\**#(\d+)`)
+
+// PRStack is a chain of PRs that were developed on top of each other.
+type PRStack struct {
+	PRs []PullRequest // Ordered by creation time
+}
+
+func (s PRStack) merged() bool {
+	for _, pr := range s.PRs {
+		if pr.MergedAt.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// CycleTime is the end-to-end time from the first PR being opened until the
+// last one merged. For stacks that are still open it is the age so far.
+func (s PRStack) CycleTime(now time.Time) time.Duration {
+	start := s.PRs[0].CreatedAt
+	if !s.merged() {
+		return now.Sub(start)
+	}
+	var end time.Time
+	for _, pr := range s.PRs {
+		if pr.MergedAt.After(end) {
+			end = pr.MergedAt
+		}
+	}
+	return end.Sub(start)
+}
+
+func hasStackMarker(body string) bool {
+	for _, m := range stackMarkers {
+		if strings.Contains(body, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// longLivedBranches are bases PRs target for integration or release, never
+// another PR's work in progress, even when some PR once used the name as
+// its head (e.g. a gitflow develop → main release PR).
+var longLivedBranches = map[string]bool{
+	"main": true, "master": true, "develop": true, "development": true, "dev": true,
+	"trunk": true, "staging": true, "production": true, "next": true,
+}
+
+// longLived reports whether base is a long-lived branch: one of
+// longLivedBranches, a release branch, or defaultBase, the most common
+// base of the dataset.
+func longLived(base, defaultBase string) bool {
+	return base == defaultBase || longLivedBranches[strings.ToLower(base)] ||
+		strings.HasPrefix(base, "release/") || strings.HasPrefix(base, "release-")
+}
+
+// openDuring reports whether a and b were open at the same time.
+func openDuring(a, b PullRequest, now time.Time) bool {
+	end := func(pr PullRequest) time.Time {
+		if pr.MergedAt.IsZero() {
+			return now
+		}
+		return pr.MergedAt
+	}
+	return a.CreatedAt.Before(end(b)) && b.CreatedAt.Before(end(a))
+}
+
+// detectStacks links PRs whose base branch is the head branch of another
+// PR of repo that was open at the same time, or that reference each other
+// in a ghstack/Graphite stack list, and returns every resulting chain of
+// two or more PRs. Heads on forks can't be a base in repo, and long-lived
+// bases never link.
+func detectStacks(repo string, prs []PullRequest, now time.Time) []PRStack {
+	byNumber := make(map[int]PullRequest)
+	byHead := make(map[string][]int) // Head branches in repo
+	bases := make(map[string]int)
+	for _, pr := range prs {
+		byNumber[pr.Number] = pr
+		if pr.HeadRef != "" && strings.EqualFold(pr.HeadRepo, repo) {
+			byHead[pr.HeadRef] = append(byHead[pr.HeadRef], pr.Number)
+		}
+		bases[pr.BaseRef]++
+	}
+	defaultBase := ""
+	for b, n := range bases {
+		if n > bases[defaultBase] || (n == bases[defaultBase] && b < defaultBase) {
+			defaultBase = b
+		}
+	}
+
+	// Union-find over PR numbers
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(n int) int {
+		if p, ok := parent[n]; ok && p != n {
+			parent[n] = find(p)
+			return parent[n]
+		}
+		return n
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[ra] = rb
+		}
+	}
+	for n := range byNumber {
+		parent[n] = n
+	}
+
+	for _, pr := range prs {
+		if !longLived(pr.BaseRef, defaultBase) {
+			for _, base := range byHead[pr.BaseRef] {
+				if base != pr.Number && openDuring(pr, byNumber[base], now) {
+					union(pr.Number, base)
+				}
+			}
+		}
+		if hasStackMarker(pr.Body) {
+			for _, m := range stackListEntry.FindAllStringSubmatch(pr.Body, -1) {
+				n, err := strconv.Atoi(m[1])
+				if err != nil {
+					continue
+				}
+				if _, ok := byNumber[n]; ok {
+					union(pr.Number, n)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]PullRequest)
+	for n, pr := range byNumber {
+		root := find(n)
+		groups[root] = append(groups[root], pr)
+	}
+
+	var stacks []PRStack
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].CreatedAt.Before(members[j].CreatedAt) })
+		stacks = append(stacks, PRStack{PRs: members})
+	}
+	return stacks
+}
+
+func printStackAnalysis(repo string, merged, open []PullRequest) {
+	fmt.Println("🥞 STACKED PR CHAINS")
+	printExplanation("Groups PRs built on top of each other (base branch = another PR's head, or ghstack/Graphite markers).", "Each element of a stack looks fast or slow on its own. What matters is when the whole change lands.")
+
+	all := append(append([]PullRequest{}, merged...), open...)
+	now := clock()
+	stacks := detectStacks(repo, all, now)
+	if len(stacks) == 0 {
+		fmt.Println("   No stacked PRs detected.")
+		return
+	}
+
+	sort.Slice(stacks, func(i, j int) bool { return stacks[i].CycleTime(now) > stacks[j].CycleTime(now) })
+
+	stacked := 0
+	for _, s := range stacks {
+		stacked += len(s.PRs)
+	}
+	fmt.Printf("   Chains: %d (%d PRs, %.0f%% of dataset)\n\n", len(stacks), stacked, float64(stacked)/float64(len(all))*100)
+
+	for i, s := range stacks {
+		if i >= 5 {
+			break
+		}
+		var nums []string
+		var elemTotal time.Duration
+		elemCount := 0
+		for _, pr := range s.PRs {
+			nums = append(nums, fmt.Sprintf("#%d", pr.Number))
+			if !pr.MergedAt.IsZero() {
+				elemTotal += pr.MergedAt.Sub(pr.CreatedAt)
+				elemCount++
+			}
+		}
+
+		status := "Chain cycle time"
+		if !s.merged() {
+			status = "Open for"
+		}
+		fmt.Printf("   %s (%d PRs)\n", strings.Join(nums, " → "), len(s.PRs))
+		fmt.Printf("      %s: %s", status, formatDuration(s.CycleTime(now)))
+		if elemCount > 0 {
+			fmt.Printf(" (avg per element: %s)", formatDuration(elemTotal/time.Duration(elemCount)))
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectStacks(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return now.AddDate(0, 0, d) }
+	pr := func(n int, headRepo, head, base string, created, merged time.Time) PullRequest {
+		return PullRequest{Number: n, HeadRepo: headRepo, HeadRef: head, BaseRef: base, CreatedAt: created, MergedAt: merged}
+	}
+	prs := []PullRequest{
+		// A real stack: #2 builds on #1 while #1 is open
+		pr(1, "acme/widgets", "feat/parser", "main", day(-10), day(-5)),
+		pr(2, "acme/widgets", "feat/parser-errors", "feat/parser", day(-8), day(-4)),
+		// A fork reusing the branch name #4 targets
+		pr(3, "octocat/widgets", "patch-1", "main", day(-6), time.Time{}),
+		pr(4, "acme/widgets", "fix/typo", "patch-1", day(-6), time.Time{}),
+		// A gitflow release PR: develop is some PR's head, but long-lived
+		pr(5, "acme/widgets", "develop", "main", day(-3), time.Time{}),
+		pr(6, "acme/widgets", "feat/ui", "develop", day(-2), time.Time{}),
+		// An old branch name reused long after its PR merged
+		pr(7, "acme/widgets", "feat/cache", "main", day(-40), day(-38)),
+		pr(8, "acme/widgets", "feat/cache-ttl", "feat/cache", day(-1), time.Time{}),
+		pr(9, "acme/widgets", "feat/more", "main", day(-1), time.Time{}),
+	}
+	stacks := detectStacks("acme/widgets", prs, now)
+	if len(stacks) != 1 || len(stacks[0].PRs) != 2 || stacks[0].PRs[0].Number != 1 || stacks[0].PRs[1].Number != 2 {
+		var got [][]int
+		for _, s := range stacks {
+			var nums []int
+			for _, p := range s.PRs {
+				nums = append(nums, p.Number)
+			}
+			got = append(got, nums)
+		}
+		t.Errorf("stacks = %v, want only [1 2]", got)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// StaleConfig tunes stale PR detection.
+type StaleConfig struct {
+	// Exempt keeps intentionally parked PRs out of the stale counts. They
+	// are still counted separately.
+	Exempt StaleExemptions `yaml:"exempt"`
+}
+
+// StaleExemptions select PRs that are never stale.
+type StaleExemptions struct {
+	Labels  []string `yaml:"labels"`  // e.g. on-hold, blocked-external
+	Drafts  bool     `yaml:"drafts"`  // Draft PRs
+	Authors []string `yaml:"authors"` // Logins, e.g. renovate[bot]
+}
+
+// staleExemption returns why pr is exempt from stale detection, e.g.
+// "label on-hold", or "" when it isn't.
+func staleExemption(pr PullRequest) string {
+	ex := cfg.Stale.Exempt
+	for _, l := range pr.Labels {
+		if slices.ContainsFunc(ex.Labels, func(e string) bool { return strings.EqualFold(e, l) }) {
+			return "label " + strings.ToLower(l)
+		}
+	}
+	if ex.Drafts && pr.IsDraft {
+		return "draft"
+	}
+	for _, a := range ex.Authors {
+		if strings.EqualFold(canonicalLogin(a), pr.Author) {
+			return "author"
+		}
+	}
+	return ""
+}
+
+// isStale reports whether open PR pr has had no activity since cutoff and
+// isn't exempt.
+func isStale(pr PullRequest, cutoff time.Time) bool {
+	return pr.UpdatedAt.Before(cutoff) && staleExemption(pr) == ""
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Branch is a head branch of the repository, compared with the default
+// branch.
+type Branch struct {
+	Name       string
+	LastCommit time.Time
+	Author     string // Login of the last commit's author, if a GitHub user
+	Ahead      int    // Commits not on the default branch
+	PRState    string // State of the branch's latest PR: OPEN, MERGED, CLOSED or "" for none
+}
+
+// fetchDefaultBranch returns the name of the repository's default branch.
+func fetchDefaultBranch(ctx context.Context, owner, name string, timeout time.Duration) (string, error) {
+	query := fmt.Sprintf(`query { repository(owner: %q, name: %q) { defaultBranchRef { name } } }`, owner, name)
+	output, err := ghGraphQL(ctx, query, timeout)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				DefaultBranchRef *struct {
+					Name string `json:"name"`
+				} `json:"defaultBranchRef"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return "", err
+	}
+	if resp.Data.Repository.DefaultBranchRef == nil {
+		return "", fmt.Errorf("%s/%s has no default branch", owner, name)
+	}
+	return resp.Data.Repository.DefaultBranchRef.Name, nil
+}
+
+// fetchStaleBranches lists up to limit branches whose last commit is older
+// than cutoff, oldest first, compared with the base branch. It also returns
+// the number of branches in the repository.
+func fetchStaleBranches(ctx context.Context, owner, name, base string, cutoff time.Time, limit int, timeout, delay time.Duration) ([]Branch, int, error) {
+	var branches []Branch
+	var cursor string
+	total := 0
+
+	queryTmpl := `
+query {
+  repository(owner: %q, name: %q) {
+    refs(%s) {
+      totalCount
+      nodes {
+        branchName: name
+        target { ... on Commit { committedDate author { user { login } } } }
+        compare(headRef: %q) { behindBy }
+        associatedPullRequests(first: 1, orderBy: {field: UPDATED_AT, direction: DESC}) { nodes { state } }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+	for len(branches) < limit {
+		if cursor != "" {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return branches, total, err
+			}
+		}
+		args := fmt.Sprintf(`refPrefix: "refs/heads/", first: %d, orderBy: {field: TAG_COMMIT_DATE, direction: ASC}`, min(100, limit-len(branches)))
+		if cursor != "" {
+			args += fmt.Sprintf(`, after: "%s"`, cursor)
+		}
+
+		output, err := ghGraphQL(ctx, fmt.Sprintf(queryTmpl, owner, name, args, base), timeout)
+		if err != nil {
+			return branches, total, err
+		}
+		var resp struct {
+			Data struct {
+				Repository struct {
+					Refs struct {
+						TotalCount int `json:"totalCount"`
+						Nodes      []struct {
+							Name   string `json:"branchName"`
+							Target struct {
+								CommittedDate *time.Time `json:"committedDate"`
+								Author        struct {
+									User *struct {
+										Login string `json:"login"`
+									} `json:"user"`
+								} `json:"author"`
+							} `json:"target"`
+							// Comparing the branch as base with the default
+							// branch as head: the branch's own commits are
+							// what the default branch is behind by
+							Compare *struct {
+								BehindBy int `json:"behindBy"`
+							} `json:"compare"`
+							AssociatedPullRequests struct {
+								Nodes []struct {
+									State string `json:"state"`
+								} `json:"nodes"`
+							} `json:"associatedPullRequests"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"refs"`
+				} `json:"repository"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(output, &resp); err != nil {
+			return branches, total, err
+		}
+
+		refs := resp.Data.Repository.Refs
+		total = refs.TotalCount
+		for _, n := range refs.Nodes {
+			if n.Target.CommittedDate == nil {
+				continue
+			}
+			if !n.Target.CommittedDate.Before(cutoff) {
+				// Oldest first: every branch from here on is recent
+				return branches, total, nil
+			}
+			if n.Name == base {
+				continue
+			}
+			b := Branch{Name: n.Name, LastCommit: *n.Target.CommittedDate}
+			if u := n.Target.Author.User; u != nil {
+				b.Author = u.Login
+			}
+			if n.Compare != nil {
+				b.Ahead = n.Compare.BehindBy
+			}
+			if prs := n.AssociatedPullRequests.Nodes; len(prs) > 0 {
+				b.PRState = prs[0].State
+			}
+			branches = append(branches, b)
+		}
+		if !refs.PageInfo.HasNextPage {
+			break
+		}
+		cursor = refs.PageInfo.EndCursor
+	}
+	return branches, total, nil
+}
+
+func printStaleBranches(branches []Branch, total, limit int, age time.Duration) {
+	fmt.Println("🪦 STALE BRANCHES (The Graveyard, before the PR)")
+	printExplanation(fmt.Sprintf("Branches with commits of their own but no open PR, untouched for more than %d days.", int(age.Hours()/24)),
+		"Work that never reached review is invisible to every other section: a pre-PR bottleneck, or clutter to delete.")
+
+	now := clock()
+	var unopened, closed, merged []Branch
+	landed := 0
+	for _, b := range branches {
+		switch {
+		case b.PRState == "OPEN", branchType(b.Name) == "release":
+			// An open PR is covered by the stale PR detector; release
+			// branches are meant to stay
+		case b.Ahead == 0:
+			landed++
+		case b.PRState == "":
+			unopened = append(unopened, b)
+		case b.PRState == "MERGED":
+			merged = append(merged, b)
+		default:
+			closed = append(closed, b)
+		}
+	}
+
+	stale := len(unopened) + len(closed) + len(merged)
+	if stale == 0 && landed == 0 {
+		fmt.Println("   ✅ No stale branches found.")
+		return
+	}
+	fmt.Printf("   %d of %d branches are stale:\n", stale, total)
+	fmt.Printf("   %-34s %d\n", "Never opened as a PR", len(unopened))
+	fmt.Printf("   %-34s %d\n", "PR closed without merging", len(closed))
+	fmt.Printf("   %-34s %d\n", "PR merged, branch not deleted", len(merged))
+	if len(branches) >= limit {
+		fmt.Printf("   (Only the %d oldest branches were checked; raise --limit to check more.)\n", limit)
+	}
+	if landed > 0 {
+		fmt.Printf("   (%d more stale branches have no commits of their own: fully merged, safe to delete.)\n", landed)
+	}
+
+	if len(unopened) > 0 {
+		buckets := []struct {
+			Label string
+			Max   time.Duration
+		}{
+			{"< 3 months", 90 * 24 * time.Hour},
+			{"3-12 months", 365 * 24 * time.Hour},
+			{"> 1 year", 1<<63 - 1},
+		}
+		counts := make([]int, len(buckets))
+		for _, b := range unopened {
+			for i, bk := range buckets {
+				if now.Sub(b.LastCommit) < bk.Max {
+					counts[i]++
+					break
+				}
+			}
+		}
+		var parts []string
+		for i, bk := range buckets {
+			parts = append(parts, fmt.Sprintf("%s: %d", bk.Label, counts[i]))
+		}
+		fmt.Printf("\n   Never-opened branches by age: %s\n", strings.Join(parts, ", "))
+
+		// Most commits first: the most work waiting outside review
+		sort.Slice(unopened, func(i, j int) bool {
+			if unopened[i].Ahead != unopened[j].Ahead {
+				return unopened[i].Ahead > unopened[j].Ahead
+			}
+			return unopened[i].Name < unopened[j].Name
+		})
+		fmt.Println("\n   Most work never opened for review:")
+		for _, b := range unopened[:min(10, len(unopened))] {
+			by := ""
+			if privacy != privacyAggregate && b.Author != "" {
+				by = " by " + person(b.Author)
+			}
+			days := int(now.Sub(b.LastCommit).Hours() / 24)
+			fmt.Printf("   🌱 %s - %d commits, the last %d days ago%s\n", limitString(b.Name, 40), b.Ahead, days, by)
+		}
+	}
+
+	if len(closed) > 0 || len(merged) > 0 || landed > 0 {
+		fmt.Printf("\n   Action: Open PRs for the work that should land, and delete the %d finished ones.\n", len(closed)+len(merged)+landed)
+	} else {
+		fmt.Println("\n   Action: Open PRs for the work that should land, or delete it.")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const defaultStoreDir = ".bottleneck"
+
+// Snapshot is the headline state of a repository at one point in time,
+// persisted so numbers can be trended across runs.
+type Snapshot struct {
+	Repo    string             `json:"repo"`
+	Taken   time.Time          `json:"taken"`
+	Health  int                `json:"health"`
+	Scores  map[string]float64 `json:"scores"`
+	Metrics Metrics            `json:"metrics"`
+	Open    int                `json:"open"`
+	Stale   int                `json:"stale"`
+	// StalePRs are the open PRs counted in Stale. Snapshots taken before it
+	// was tracked have none.
+	StalePRs []StalePR `json:"stale_prs,omitempty"`
+	// StaleExempt counts inactive open PRs left out of Stale by the
+	// stale.exempt config.
+	StaleExempt int `json:"stale_exempt,omitempty"`
+	// Areas breaks merge times down by directory or service (groupFor).
+	Areas map[string]AreaSnapshot `json:"areas,omitempty"`
+	// Archetype is the kind of repository the scores were thresholded for.
+	Archetype string `json:"archetype,omitempty"`
+}
+
+// AreaSnapshot is the merge speed of the PRs touching one area.
+type AreaSnapshot struct {
+	PRs               int           `json:"prs"`
+	MedianCycleTime   time.Duration `json:"median_cycle_time"`
+	MedianFirstReview time.Duration `json:"median_first_review"`
+}
+
+// StalePR is an open PR without activity for more than 7 days.
+type StalePR struct {
+	Number int       `json:"number"`
+	Since  time.Time `json:"since"` // Last activity
+}
+
+// Store keeps snapshots as one JSON-lines file per repository under Dir.
+type Store struct {
+	Dir string
+}
+
+func (s Store) path(repo string) string {
+	owner, name, _ := parseRepo(repo)
+	return filepath.Join(s.Dir, "snapshots", owner, name+".jsonl")
+}
+
+// Save appends a snapshot to the repository's history.
+func (s Store) Save(snap Snapshot) error {
+	path := s.path(snap.Repo)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load returns the repository's snapshots, oldest first. A repository
+// without history has none.
+func (s Store) Load(repo string) ([]Snapshot, error) {
+	f, err := os.Open(s.path(repo))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return snaps, fmt.Errorf("%s:%d: %w", s.path(repo), line, err)
+		}
+		snaps = append(snaps, snap)
+	}
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].Taken.Before(snaps[j].Taken) })
+	return snaps, scanner.Err()
+}
+
+// MonthSnapshot is the complete record of one calendar month of merged PRs,
+// materialized by `bottleneck backfill`.
+type MonthSnapshot struct {
+	Month      string        `json:"month"` // 2006-01
+	Count      int           `json:"count"`
+	TotalCycle time.Duration `json:"total_cycle"` // Sum of cycle times, for averages
+	Metrics    Metrics       `json:"metrics"`
+	Truncated  bool          `json:"truncated"` // Hit the search cap; not every PR was fetched
+	Taken      time.Time     `json:"taken"`
+
+	Features    int `json:"features,omitempty"`    // PRs by workKind
+	Maintenance int `json:"maintenance,omitempty"` // Zero in months backfilled before it was tracked
+}
+
+func (s Store) monthsPath(repo string) string {
+	owner, name, _ := parseRepo(repo)
+	return filepath.Join(s.Dir, "months", owner, name+".json")
+}
+
+// LoadMonths returns the repository's materialized months by month key.
+func (s Store) LoadMonths(repo string) (map[string]MonthSnapshot, error) {
+	data, err := os.ReadFile(s.monthsPath(repo))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var months []MonthSnapshot
+	if err := json.Unmarshal(data, &months); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.monthsPath(repo), err)
+	}
+	out := make(map[string]MonthSnapshot, len(months))
+	for _, m := range months {
+		out[m.Month] = m
+	}
+	return out, nil
+}
+
+// SaveMonths merges months into the repository's materialized months,
+// replacing any earlier snapshot of the same month.
+func (s Store) SaveMonths(repo string, months []MonthSnapshot) error {
+	existing, err := s.LoadMonths(repo)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		existing = make(map[string]MonthSnapshot)
+	}
+	for _, m := range months {
+		existing[m.Month] = m
+	}
+	var all []MonthSnapshot
+	for _, m := range existing {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Month < all[j].Month })
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.monthsPath(repo)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
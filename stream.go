@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// liveStats are running metrics of the merged PRs fetched so far, updated
+// as each page arrives so a long fetch shows its numbers early. They are
+// rendered in place on a terminal and logged otherwise.
+type liveStats struct {
+	Repo     string
+	Limit    int
+	cycle    []time.Duration
+	first    []time.Duration
+	reviewed int
+	terminal bool
+}
+
+func newLiveStats(repo string, limit int) *liveStats {
+	fi, err := os.Stderr.Stat()
+	return &liveStats{Repo: repo, Limit: limit, terminal: err == nil && fi.Mode()&os.ModeCharDevice != 0}
+}
+
+// add folds in one page of merged PRs and renders the running numbers.
+func (s *liveStats) add(prs []PullRequest) {
+	for _, pr := range prs {
+		s.cycle = append(s.cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if pr.FirstReviewAt != nil {
+			s.first = append(s.first, max(pr.FirstReviewAt.Sub(pr.CreatedAt), 0))
+		}
+		if len(pr.Reviews) > 0 {
+			s.reviewed++
+		}
+	}
+	if len(s.cycle) == 0 {
+		return
+	}
+	if !s.terminal {
+		slog.Info("partial results", "repo", s.Repo, "prs", len(s.cycle), "median_merge", percentile(s.cycle, 50), "p90_merge", percentile(s.cycle, 90))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s", s.line())
+}
+
+// line renders the running numbers on one line.
+func (s *liveStats) line() string {
+	parts := []string{
+		fmt.Sprintf("⏳ %s/%s merged PRs", formatInt(len(s.cycle)), formatInt(s.Limit)),
+		"median merge " + formatDuration(percentile(s.cycle, 50)),
+		"P90 " + formatDuration(percentile(s.cycle, 90)),
+	}
+	if len(s.first) > 0 {
+		parts = append(parts, "median first review "+formatDuration(percentile(s.first, 50)))
+	}
+	parts = append(parts, fmt.Sprintf("%.0f%% reviewed", float64(s.reviewed)/float64(len(s.cycle))*100))
+	return strings.Join(parts, " · ")
+}
+
+// done clears the in-place line before the report prints.
+func (s *liveStats) done() {
+	if s.terminal && len(s.cycle) > 0 {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
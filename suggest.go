@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Suggestion is a ranked reviewer candidate for an open PR.
+type Suggestion struct {
+	Login string
+	Score float64
+}
+
+// isBot reports whether a login belongs to an automation account.
+func isBot(login string) bool {
+	l := strings.ToLower(login)
+	switch l {
+	case "dependabot", "renovate", "github-actions", "copilot":
+		return true
+	}
+	if botLogins[l] {
+		return true
+	}
+	return strings.HasSuffix(l, "[bot]")
+}
+
+// pathAffinity scores how closely two sets of files overlap:
+// 3 for a shared file, 2 for a shared directory, 1 for a shared group.
+func pathAffinity(a, b []string) float64 {
+	best := 0.0
+	for _, pa := range a {
+		for _, pb := range b {
+			switch {
+			case pa == pb:
+				return 3
+			case path.Dir(pa) == path.Dir(pb) && best < 2:
+				best = 2
+			case groupFor(pa) == groupFor(pb) && best < 1:
+				best = 1
+			}
+		}
+	}
+	return best
+}
+
+// suggestReviewers ranks people who reviewed (or, with less weight, authored)
+// merged PRs touching the same paths. The PR author, bots, overloaded heroes
+// and unavailable reviewers are skipped.
+func suggestReviewers(pr PullRequest, history []PullRequest, overloaded map[string]bool, avail *Availability, now time.Time) []Suggestion {
+	scores := make(map[string]float64)
+	for _, m := range history {
+		affinity := pathAffinity(pr.FilePaths, m.FilePaths)
+		if affinity == 0 {
+			continue
+		}
+		for _, r := range m.Reviewers {
+			scores[r] += affinity
+		}
+		scores[m.Author] += affinity / 2
+	}
+
+	var out []Suggestion
+	for login, score := range scores {
+		if login == "" || login == pr.Author || isBot(login) || overloaded[login] {
+			continue
+		}
+		if away, _ := avail.Away(login, now); away {
+			continue
+		}
+		out = append(out, Suggestion{Login: login, Score: score})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Login < out[j].Login
+	})
+	if len(out) > 3 {
+		out = out[:3]
+	}
+	return out
+}
+
+// overloadedReviewers returns reviewers carrying more than 30% of all reviews
+// (the hero detector's "High Load" threshold).
+func overloadedReviewers(prs []PullRequest) map[string]bool {
+	counts, total := countReviews(prs)
+	overloaded := make(map[string]bool)
+	if total == 0 {
+		return overloaded
+	}
+	for name, count := range counts {
+		if float64(count)/float64(total)*100 > 30 {
+			overloaded[name] = true
+		}
+	}
+	return overloaded
+}
+
+// requestReviewers asks GitHub to request reviews from the given users.
+func requestReviewers(ctx context.Context, owner, name string, number int, reviewers []string, timeout time.Duration) error {
+	args := []string{"-X", "POST", fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, name, number)}
+	for _, r := range reviewers {
+		args = append(args, "-f", fmt.Sprintf("reviewers[]=%s", r))
+	}
+	_, err := ghAPI(ctx, timeout, args...)
+	return err
+}
+
+func printReviewerSuggestions(ctx context.Context, owner, name string, open, merged []PullRequest, avail *Availability, assign bool, timeout time.Duration) {
+	fmt.Println("🎯 REVIEWER SUGGESTIONS")
+	printExplanation("Best reviewers for unreviewed open PRs, based on who reviewed and wrote the touched paths.", "Turns analysis into action. Skips overloaded heroes and people who are away.")
+
+	now := clock()
+	overloaded := overloadedReviewers(merged)
+	found := false
+
+	for _, pr := range open {
+		if pr.IsDraft || len(pr.Reviews) > 0 {
+			continue
+		}
+		suggestions := suggestReviewers(pr, merged, overloaded, avail, now)
+		if len(suggestions) == 0 {
+			continue
+		}
+		found = true
+
+		var names []string
+		for _, s := range suggestions {
+			names = append(names, s.Login)
+		}
+		fmt.Printf("   #%d (%s) by %s → %s\n", pr.Number, limitString(pr.Title, 40), pr.Author, strings.Join(names, ", "))
+
+		if len(pr.Requested) > 0 {
+			fmt.Printf("      Already requested: %s\n", strings.Join(pr.Requested, ", "))
+			continue
+		}
+		if assign {
+			if err := requestReviewers(ctx, owner, name, pr.Number, names, timeout); err != nil {
+				fmt.Printf("      ❌ Could not assign reviewers: %v\n", err)
+			} else {
+				fmt.Println("      ✅ Review requested.")
+			}
+		}
+	}
+
+	if !found {
+		fmt.Println("   ✅ No unreviewed open PRs need suggestions.")
+		return
+	}
+	if !assign {
+		fmt.Println("\n   Action: Re-run with --assign-reviewers to request reviews on PRs that have none.")
+	}
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Period is a reporting window. End is exclusive.
+type Period struct {
+	Label      string
+	Start, End time.Time
+}
+
+var (
+	quarterPeriod = regexp.MustCompile(`^(?i)(?:Q([1-4])-(\d{4})|(\d{4})-Q([1-4]))$`)
+	monthPeriod   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+)
+
+// parsePeriod accepts quarters ("Q3-2024" or "2024-Q3") and months ("2024-07").
+func parsePeriod(s string) (Period, error) {
+	if m := quarterPeriod.FindStringSubmatch(s); m != nil {
+		q, y := m[1], m[2]
+		if q == "" {
+			q, y = m[4], m[3]
+		}
+		quarter, _ := strconv.Atoi(q)
+		year, _ := strconv.Atoi(y)
+		start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Label: fmt.Sprintf("Q%d-%d", quarter, year), Start: start, End: start.AddDate(0, 3, 0)}, nil
+	}
+	if m := monthPeriod.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month >= 1 && month <= 12 {
+			start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			return Period{Label: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)}, nil
+		}
+	}
+	return Period{}, fmt.Errorf("invalid period %q (use Q3-2024 or 2024-07)", s)
+}
+
+// Previous returns the period of the same length right before p.
+func (p Period) Previous() Period {
+	if p.End.Sub(p.Start) > 31*24*time.Hour {
+		start := p.Start.AddDate(0, -3, 0)
+		return Period{Label: fmt.Sprintf("Q%d-%d", (int(start.Month())-1)/3+1, start.Year()), Start: start, End: p.Start}
+	}
+	start := p.Start.AddDate(0, -1, 0)
+	return Period{Label: start.Format("2006-01"), Start: start, End: p.Start}
+}
+
+// searchRange renders the period as an inclusive GitHub search date range.
+func (p Period) searchRange() string {
+	return fmt.Sprintf("%s..%s", p.Start.Format("2006-01-02"), p.End.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+type KeyNumber struct {
+	Label    string
+	Value    string
+	Previous string
+	Change   string // e.g. "▼ 18%"
+}
+
+// ExecSummary is the one-page narrative for leadership.
+type ExecSummary struct {
+	Repo         string
+	Period       string
+	PrevPeriod   string
+	Headline     string
+	KeyNumbers   []KeyNumber
+	Improvements []string
+	Regressions  []string
+	Risks        []string
+}
+
+// pctChange returns the relative change from prev to cur in percent.
+func pctChange(prev, cur float64) float64 {
+	if prev == 0 {
+		return 0
+	}
+	return (cur - prev) / prev * 100
+}
+
+func changeLabel(prev, cur float64) string {
+	c := pctChange(prev, cur)
+	switch {
+	case prev == 0:
+		return "-"
+	case c > 0.5:
+		return fmt.Sprintf("▲ %.0f%%", c)
+	case c < -0.5:
+		return fmt.Sprintf("▼ %.0f%%", -c)
+	}
+	return "➖"
+}
+
+func buildExecSummary(repo string, period, prev Period, cur, before []PullRequest) ExecSummary {
+	m, p := computeMetrics(cur), computeMetrics(before)
+	s := ExecSummary{Repo: repo, Period: period.Label, PrevPeriod: prev.Label}
+
+	s.Headline = fmt.Sprintf("In %s, %d PRs were merged with a median cycle time of %s", period.Label, m.Count, formatDuration(m.MedianCycleTime))
+	if p.Count > 0 {
+		s.Headline += fmt.Sprintf(" (%s vs %s)", changeLabel(float64(p.MedianCycleTime), float64(m.MedianCycleTime)), prev.Label)
+	}
+	s.Headline += "."
+
+	dur := func(label string, cur, prev time.Duration) KeyNumber {
+		k := KeyNumber{Label: label, Value: formatDuration(cur), Previous: "-", Change: "-"}
+		if p.Count > 0 {
+			k.Previous = formatDuration(prev)
+			k.Change = changeLabel(float64(prev), float64(cur))
+		}
+		return k
+	}
+	num := func(label string, decimals int, cur, prev float64) KeyNumber {
+		k := KeyNumber{Label: label, Value: formatNumber(cur, decimals), Previous: "-", Change: "-"}
+		if p.Count > 0 {
+			k.Previous = formatNumber(prev, decimals)
+			k.Change = changeLabel(prev, cur)
+		}
+		return k
+	}
+	s.KeyNumbers = []KeyNumber{
+		num("PRs merged", 0, float64(m.Count), float64(p.Count)),
+		dur("Median cycle time", m.MedianCycleTime, p.MedianCycleTime),
+		dur("p90 cycle time", m.P90CycleTime, p.P90CycleTime),
+		dur("Median time to first review", m.MedianFirstReview, p.MedianFirstReview),
+		num("Avg review rounds", 1, m.AvgRounds, p.AvgRounds),
+		num("Median PR size (lines)", 0, float64(m.MedianSize), float64(p.MedianSize)),
+	}
+
+	// Regressions and improvements: headline metrics first, then groups
+	type delta struct {
+		Text   string
+		Change float64
+	}
+	var deltas []delta
+	if p.Count > 0 {
+		for _, c := range []struct {
+			Label     string
+			Prev, Cur time.Duration
+		}{
+			{"Median cycle time", p.MedianCycleTime, m.MedianCycleTime},
+			{"p90 cycle time", p.P90CycleTime, m.P90CycleTime},
+			{"Median time to first review", p.MedianFirstReview, m.MedianFirstReview},
+		} {
+			ch := pctChange(float64(c.Prev), float64(c.Cur))
+			deltas = append(deltas, delta{fmt.Sprintf("%s: %s → %s", c.Label, formatDuration(c.Prev), formatDuration(c.Cur)), ch})
+		}
+
+		curGroups, prevGroups := groupCycleTimes(cur), groupCycleTimes(before)
+		for g, cd := range curGroups {
+			pd, ok := prevGroups[g]
+			if !ok || len(cd) < 3 || len(pd) < 3 {
+				continue
+			}
+			pm, cm := percentile(pd, 50), percentile(cd, 50)
+			deltas = append(deltas, delta{fmt.Sprintf("%s median cycle time: %s → %s", g, formatDuration(pm), formatDuration(cm)), pctChange(float64(pm), float64(cm))})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Change > deltas[j].Change })
+	for _, d := range deltas {
+		if d.Change > 10 && len(s.Regressions) < 3 {
+			s.Regressions = append(s.Regressions, fmt.Sprintf("%s (▲ %.0f%%)", d.Text, d.Change))
+		}
+	}
+	for i := len(deltas) - 1; i >= 0; i-- {
+		if deltas[i].Change < -10 && len(s.Improvements) < 3 {
+			s.Improvements = append(s.Improvements, fmt.Sprintf("%s (▼ %.0f%%)", deltas[i].Text, -deltas[i].Change))
+		}
+	}
+
+	s.Risks = summaryRisks(m)
+	return s
+}
+
+// groupCycleTimes collects merge durations per directory/service.
+func groupCycleTimes(prs []PullRequest) map[string][]time.Duration {
+	out := make(map[string][]time.Duration)
+	for _, pr := range prs {
+		seen := make(map[string]bool)
+		for _, path := range pr.FilePaths {
+			g := groupFor(path)
+			if !seen[g] {
+				out[g] = append(out[g], pr.MergedAt.Sub(pr.CreatedAt))
+				seen[g] = true
+			}
+		}
+	}
+	return out
+}
+
+// summaryRisks turns metrics into the risks leadership should know about.
+func summaryRisks(m Metrics) []string {
+	var risks []string
+	if m.TopReviewerPct > 30 {
+		who := m.TopReviewer
+		if !namesShown() {
+			who = "one reviewer"
+		}
+		risks = append(risks, fmt.Sprintf("Hero dependence: %s did %.0f%% of all reviews. Losing them would stall delivery.", who, m.TopReviewerPct))
+	}
+	if m.MedianFirstReview > 24*time.Hour {
+		risks = append(risks, fmt.Sprintf("Slow triage: the median PR waits %s for its first review.", formatDuration(m.MedianFirstReview)))
+	}
+	if m.MedianCycleTime > 0 && m.P90CycleTime > 4*m.MedianCycleTime {
+		risks = append(risks, fmt.Sprintf("Long tail: 1 in 10 PRs takes %s or more, over 4x the median.", formatDuration(m.P90CycleTime)))
+	}
+	if m.Count > 0 && m.ReviewedPct < 80 {
+		risks = append(risks, fmt.Sprintf("Review coverage: %.0f%% of PRs were merged without any review.", 100-m.ReviewedPct))
+	}
+	if m.SizeCorrelation > 0.5 {
+		risks = append(risks, "Big PRs: larger PRs take significantly longer to merge.")
+	}
+	return risks
+}
+
+const summaryMarkdown = `# Engineering Velocity Summary: {{.Repo}} ({{.Period}})
+
+{{.Headline}}
+
+## Key Numbers
+
+| Metric | {{.Period}} | {{.PrevPeriod}} | Change |
+|--------|------|------|--------|
+{{- range .KeyNumbers}}
+| {{.Label}} | {{.Value}} | {{.Previous}} | {{.Change}} |
+{{- end}}
+
+## Biggest Improvements
+{{range .Improvements}}
+- {{.}}
+{{- else}}
+- No significant improvements vs {{.PrevPeriod}}.
+{{- end}}
+
+## Biggest Regressions
+{{range .Regressions}}
+- {{.}}
+{{- else}}
+- No significant regressions vs {{.PrevPeriod}}.
+{{- end}}
+
+## Top Risks
+{{range .Risks}}
+- {{.}}
+{{- else}}
+- No major risks detected.
+{{- end}}
+`
+
+const summaryHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Engineering Velocity Summary: {{.Repo}} ({{.Period}})</title>
+<style>
+body { font-family: -apple-system, sans-serif; max-width: 800px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; }
+th { background: #f5f5f5; }
+</style>
+</head>
+<body>
+<h1>Engineering Velocity Summary: {{.Repo}} ({{.Period}})</h1>
+<p>{{.Headline}}</p>
+<h2>Key Numbers</h2>
+<table>
+<tr><th>Metric</th><th>{{.Period}}</th><th>{{.PrevPeriod}}</th><th>Change</th></tr>
+{{- range .KeyNumbers}}
+<tr><td>{{.Label}}</td><td>{{.Value}}</td><td>{{.Previous}}</td><td>{{.Change}}</td></tr>
+{{- end}}
+</table>
+<h2>Biggest Improvements</h2>
+<ul>
+{{- range .Improvements}}
+<li>{{.}}</li>
+{{- else}}
+<li>No significant improvements vs {{.PrevPeriod}}.</li>
+{{- end}}
+</ul>
+<h2>Biggest Regressions</h2>
+<ul>
+{{- range .Regressions}}
+<li>{{.}}</li>
+{{- else}}
+<li>No significant regressions vs {{.PrevPeriod}}.</li>
+{{- end}}
+</ul>
+<h2>Top Risks</h2>
+<ul>
+{{- range .Risks}}
+<li>{{.}}</li>
+{{- else}}
+<li>No major risks detected.</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`
+
+func writeExecSummary(w io.Writer, s ExecSummary, format string) error {
+	switch format {
+	case "markdown":
+		return template.Must(template.New("summary").Parse(summaryMarkdown)).Execute(w, s)
+	case "html":
+		return htmltemplate.Must(htmltemplate.New("summary").Parse(summaryHTML)).Execute(w, s)
+	case "pdf":
+		return writeSummaryPDF(w, s)
+	}
+	return fmt.Errorf("unknown format %q (use markdown, html or pdf)", format)
+}
+
+func runSummary(args []string) {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	periodFlag := fs.String("period", "", "Period to summarize: a quarter (Q3-2024) or month (2024-07)")
+	format := fs.String("format", "markdown", "Output format: markdown, html or pdf")
+	output := fs.String("output", "", "Write the summary to this file instead of stdout")
+	limit := fs.Int("limit", 1000, "Max number of merged PRs to fetch per period (GitHub search caps at 1000)")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	privacyMode := fs.String("privacy", "", "Hide individuals: team or aggregate (default: privacy: in config, else off)")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck summary --period Q3-2024 [flags] <owner/repo>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 1 || *periodFlag == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	period, err := parsePeriod(*periodFlag)
+	if err != nil {
+		slog.Error("invalid --period", "err", err)
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+	if *privacyMode != "" {
+		if err := setPrivacy(*privacyMode); err != nil {
+			slog.Error("invalid --privacy", "err", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	prev := period.Previous()
+	slog.Info("fetching merged PRs", "repo", repo, "period", period.Label, "previous", prev.Label)
+	cur, err := searchPRs(ctx, owner, name, "is:merged merged:"+period.searchRange(), *limit, *reqTimeout, *reqDelay)
+	if err != nil {
+		slog.Error("fetching merged PRs", "repo", repo, "period", period.Label, "err", err)
+		os.Exit(1)
+	}
+	before, err := searchPRs(ctx, owner, name, "is:merged merged:"+prev.searchRange(), *limit, *reqTimeout, *reqDelay)
+	if err != nil {
+		slog.Error("fetching merged PRs", "repo", repo, "period", prev.Label, "err", err)
+		os.Exit(1)
+	}
+
+	summary := buildExecSummary(repo, period, prev, cur, before)
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			slog.Error("creating output file", "err", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeExecSummary(out, summary, strings.ToLower(*format)); err != nil {
+		slog.Error("writing summary", "err", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// teamMatrixMinReviews is how many reviews a cell of the team latency
+// matrix needs to show a median.
+const teamMatrixMinReviews = 3
+
+// teamEdge is the review latency of one author team → reviewer team pair.
+type teamEdge struct {
+	From, To string // Author's team, reviewer's team
+	Waits    []time.Duration
+}
+
+// teamReviewLatency collects, per pair of author team and reviewer team,
+// the wait from review request to the reviewer's first review. Reviews by
+// or of people without a team are counted separately. Time reviewers were
+// away isn't counted (see Availability.responseWait).
+func teamReviewLatency(prs []PullRequest, avail *Availability) (map[[2]string]*teamEdge, int) {
+	edges := make(map[[2]string]*teamEdge)
+	unmapped := 0
+	for _, pr := range prs {
+		from := teamOf(pr.Author)
+		seen := make(map[string]bool)
+		for _, r := range pr.Reviews {
+			if r.Author == "" || r.Author == pr.Author || isBot(r.Author) || seen[r.Author] {
+				continue
+			}
+			seen[r.Author] = true
+			to := teamOf(r.Author)
+			if from == "" || to == "" {
+				unmapped++
+				continue
+			}
+			d, ok := avail.responseWait(r.Author, requestedAt(pr, r.Author, r.CreatedAt), r.CreatedAt)
+			if !ok {
+				continue
+			}
+			key := [2]string{from, to}
+			if edges[key] == nil {
+				edges[key] = &teamEdge{From: from, To: to}
+			}
+			edges[key].Waits = append(edges[key].Waits, d)
+		}
+	}
+	return edges, unmapped
+}
+
+func printTeamLatencyMatrix(prs []PullRequest, avail *Availability) {
+	fmt.Println("🧭 CROSS-TEAM REVIEW LATENCY")
+	printExplanation("Median time from review request to first review for each author team (rows) and reviewer team (columns).",
+		"Handoffs between teams are often the slowest edges in the review graph. The matrix shows which ones.")
+
+	edges, unmapped := teamReviewLatency(prs, avail)
+	if len(edges) == 0 {
+		fmt.Println("   No reviews between mapped team members.")
+		return
+	}
+
+	teamSet := make(map[string]bool)
+	var within, across []time.Duration
+	for k, e := range edges {
+		teamSet[k[0]], teamSet[k[1]] = true, true
+		if k[0] == k[1] {
+			within = append(within, e.Waits...)
+		} else {
+			across = append(across, e.Waits...)
+		}
+	}
+	var teams []string
+	for t := range teamSet {
+		teams = append(teams, t)
+	}
+	sort.Strings(teams)
+
+	corner := "Author \\ Reviewer"
+	width, label := 12, len(corner)+2
+	for _, t := range teams {
+		width = max(width, min(len(t), 20)+2)
+		label = max(label, min(len(t), 20)+2)
+	}
+	fmt.Printf("   %-*s", label, corner)
+	for _, t := range teams {
+		fmt.Printf("%*s", width, limitString(t, 20))
+	}
+	fmt.Println()
+	for _, from := range teams {
+		fmt.Printf("   %-*s", label, limitString(from, 20))
+		for _, to := range teams {
+			cell := "-"
+			if e := edges[[2]string{from, to}]; e != nil && len(e.Waits) >= teamMatrixMinReviews {
+				cell = formatDuration(percentile(e.Waits, 50))
+			}
+			fmt.Printf("%*s", width, cell)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("   (\"-\": fewer than %d reviews.)\n", teamMatrixMinReviews)
+
+	if len(within) == 0 || len(across) == 0 {
+		return
+	}
+	base := percentile(within, 50)
+	fmt.Printf("\n   Median within teams: %s (%d reviews)   Across teams: %s (%d reviews)\n",
+		formatDuration(base), len(within), formatDuration(percentile(across, 50)), len(across))
+
+	// Cross-team edges at least half again as slow as reviews within teams
+	floor := max(base, time.Minute)
+	var slow []*teamEdge
+	for k, e := range edges {
+		if k[0] != k[1] && len(e.Waits) >= teamMatrixMinReviews && float64(percentile(e.Waits, 50)) >= 1.5*float64(floor) {
+			slow = append(slow, e)
+		}
+	}
+	sort.Slice(slow, func(i, j int) bool {
+		if mi, mj := percentile(slow[i].Waits, 50), percentile(slow[j].Waits, 50); mi != mj {
+			return mi > mj
+		}
+		return slow[i].From+"\x00"+slow[i].To < slow[j].From+"\x00"+slow[j].To
+	})
+	if len(slow) == 0 {
+		fmt.Println("   ✅ No cross-team edge is much slower than reviews within a team.")
+	} else {
+		fmt.Println("\n   Slow cross-team edges:")
+		for _, e := range slow {
+			m := percentile(e.Waits, 50)
+			fmt.Printf("   ⚠️  %s → %s: median %s over %d reviews, %sx the within-team median\n",
+				e.From, e.To, formatDuration(m), len(e.Waits), formatNumber(float64(m)/float64(floor), 1))
+		}
+	}
+	if unmapped > 0 {
+		fmt.Printf("   (%d reviews by or of people without a team left out; add them under teams in config.)\n", unmapped)
+	}
+}
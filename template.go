@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+	checklistLine   = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s*(.*)$`)
+	revertReference = regexp.MustCompile(`(?i)\breverts?\s+(?:[\w.-]+/[\w.-]+)?#(\d+)`)
+	revertTitle     = regexp.MustCompile(`^Revert "(.+)"$`)
+)
+
+// TemplateConfig narrows template compliance to some sections.
+type TemplateConfig struct {
+	// Required lists the headings a PR must fill in (default: every
+	// heading of the template).
+	Required []string `yaml:"required"`
+}
+
+// PRTemplate is the parsed pull request template of a repository.
+type PRTemplate struct {
+	Sections  []templateSection
+	Checklist []string // Item texts
+}
+
+type templateSection struct {
+	Heading     string
+	Placeholder string // Text the template puts under the heading
+}
+
+// fetchPRTemplate looks up the pull request template in the locations GitHub
+// honours. It returns nil when the repository has none.
+func fetchPRTemplate(ctx context.Context, owner, name string, timeout time.Duration) (*PRTemplate, error) {
+	var locations []string
+	for _, dir := range []string{".github/", "", "docs/"} {
+		locations = append(locations, dir+"pull_request_template.md", dir+"PULL_REQUEST_TEMPLATE.md")
+	}
+	files, err := fetchRepoFiles(ctx, owner, name, locations, timeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, loc := range locations {
+		if text := files[loc]; text != "" {
+			return parsePRTemplate(text), nil
+		}
+	}
+	return nil, nil
+}
+
+// markdownSections splits markdown into its headings and the text under
+// each, with HTML comments removed. Text before the first heading is
+// ignored.
+func markdownSections(text string) map[string]string {
+	sections := make(map[string]string)
+	current := ""
+	var body strings.Builder
+	flush := func() {
+		if current != "" {
+			sections[current] = strings.TrimSpace(body.String())
+		}
+		body.Reset()
+	}
+	for _, line := range strings.Split(htmlComment.ReplaceAllString(text, ""), "\n") {
+		if m := markdownHeading.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			flush()
+			current = strings.ToLower(m[1])
+			continue
+		}
+		body.WriteString(line + "\n")
+	}
+	flush()
+	return sections
+}
+
+func parsePRTemplate(text string) *PRTemplate {
+	t := &PRTemplate{}
+	for _, line := range strings.Split(htmlComment.ReplaceAllString(text, ""), "\n") {
+		if m := markdownHeading.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			t.Sections = append(t.Sections, templateSection{Heading: m[1]})
+		}
+		if m := checklistLine.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[2]) != "" {
+			t.Checklist = append(t.Checklist, strings.TrimSpace(m[2]))
+		}
+	}
+	sections := markdownSections(text)
+	for i := range t.Sections {
+		t.Sections[i].Placeholder = sections[strings.ToLower(t.Sections[i].Heading)]
+	}
+	return t
+}
+
+// required returns the sections a PR must fill: those named in config, or
+// every section of the template.
+func (t *PRTemplate) required() []templateSection {
+	if len(cfg.Template.Required) == 0 {
+		return t.Sections
+	}
+	var out []templateSection
+	for _, s := range t.Sections {
+		for _, r := range cfg.Template.Required {
+			if strings.EqualFold(s.Heading, r) {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// templateCompliance is how far a PR body follows the template.
+type templateCompliance struct {
+	Filled    map[string]bool // Required heading -> has content of its own
+	Checked   int             // Template checklist items ticked
+	Compliant bool
+}
+
+func checkTemplate(t *PRTemplate, body string) templateCompliance {
+	c := templateCompliance{Filled: make(map[string]bool)}
+	sections := markdownSections(body)
+	c.Compliant = true
+	for _, s := range t.required() {
+		content := sections[strings.ToLower(s.Heading)]
+		// A section left as the template wrote it doesn't count
+		if s.Placeholder != "" {
+			content = strings.TrimSpace(strings.Replace(content, s.Placeholder, "", 1))
+		}
+		// Neither does a section holding only unticked checklist items
+		filled := false
+		for _, line := range strings.Split(content, "\n") {
+			if m := checklistLine.FindStringSubmatch(line); m != nil && m[1] == " " {
+				continue
+			}
+			if strings.TrimSpace(line) != "" {
+				filled = true
+				break
+			}
+		}
+		c.Filled[s.Heading] = filled
+		c.Compliant = c.Compliant && filled
+	}
+
+	ticked := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		if m := checklistLine.FindStringSubmatch(line); m != nil && m[1] != " " {
+			ticked[strings.ToLower(strings.TrimSpace(m[2]))] = true
+		}
+	}
+	for _, item := range t.Checklist {
+		if ticked[strings.ToLower(item)] {
+			c.Checked++
+		}
+	}
+	c.Compliant = c.Compliant && c.Checked == len(t.Checklist)
+	return c
+}
+
+// revertedPRs returns the numbers of PRs that a later PR in prs reverted,
+// found by GitHub's "Reverts owner/repo#N" body or a `Revert "<title>"` title.
+func revertedPRs(prs []PullRequest) map[int]bool {
+	byTitle := make(map[string]int)
+	for _, pr := range prs {
+		byTitle[pr.Title] = pr.Number
+	}
+	reverted := make(map[int]bool)
+	for _, pr := range prs {
+		for _, m := range revertReference.FindAllStringSubmatch(pr.Body, -1) {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				reverted[n] = true
+			}
+		}
+		if m := revertTitle.FindStringSubmatch(pr.Title); m != nil {
+			if n, ok := byTitle[m[1]]; ok {
+				reverted[n] = true
+			}
+		}
+	}
+	return reverted
+}
+
+func printTemplateCompliance(prs []PullRequest, t *PRTemplate) {
+	fmt.Println("📋 PR TEMPLATE COMPLIANCE")
+	printExplanation("Whether merged PRs filled in the template's sections and ticked its checklist, and how compliant PRs fare.",
+		"Templates cost authors time. This shows whether filling them in pays off in review speed or fewer reverts.")
+
+	if t == nil {
+		fmt.Println("   No pull request template found in this repository.")
+		return
+	}
+	required := t.required()
+	if len(required) == 0 && len(t.Checklist) == 0 {
+		fmt.Println("   The template has no sections or checklist items to check.")
+		return
+	}
+
+	reverted := revertedPRs(prs)
+	type group struct {
+		Cycle, FirstReview []time.Duration
+		Count, Reverted    int
+	}
+	var compliant, other group
+	filled := make(map[string]int)
+	checked := 0
+	for _, pr := range prs {
+		c := checkTemplate(t, pr.Body)
+		for h, ok := range c.Filled {
+			if ok {
+				filled[h]++
+			}
+		}
+		checked += c.Checked
+		g := &other
+		if c.Compliant {
+			g = &compliant
+		}
+		g.Count++
+		g.Cycle = append(g.Cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if pr.FirstReviewAt != nil && pr.FirstReviewAt.After(pr.CreatedAt) {
+			g.FirstReview = append(g.FirstReview, pr.FirstReviewAt.Sub(pr.CreatedAt))
+		}
+		if reverted[pr.Number] {
+			g.Reverted++
+		}
+	}
+
+	pct := func(n, of int) float64 {
+		if of == 0 {
+			return 0
+		}
+		return float64(n) / float64(of) * 100
+	}
+	for _, s := range required {
+		fmt.Printf("   %-30s filled in %3.0f%% of PRs\n", limitString(s.Heading, 27), pct(filled[s.Heading], len(prs)))
+	}
+	if len(t.Checklist) > 0 {
+		fmt.Printf("   %-30s %3.0f%% of items ticked\n", fmt.Sprintf("Checklist (%d items)", len(t.Checklist)), pct(checked, len(prs)*len(t.Checklist)))
+	}
+
+	medianOrDash := func(d []time.Duration) string {
+		if len(d) == 0 {
+			return "-"
+		}
+		return formatDuration(percentile(d, 50))
+	}
+	fmt.Printf("\n   %-15s %5s   %-14s %-17s %s\n", "", "PRs", "Median Merge", "Median 1st Review", "Reverted")
+	for _, row := range []struct {
+		Label string
+		G     group
+	}{{"Compliant", compliant}, {"Not compliant", other}} {
+		fmt.Printf("   %-15s %5d   %-14s %-17s %d (%s%%)\n", row.Label, row.G.Count, medianOrDash(row.G.Cycle), medianOrDash(row.G.FirstReview), row.G.Reverted, formatNumber(pct(row.G.Reverted, row.G.Count), 1))
+	}
+
+	if compliant.Count < 5 || other.Count < 5 {
+		fmt.Println("\n   (Not enough PRs on both sides for a reliable comparison.)")
+		return
+	}
+	fmt.Println()
+	if change := pctChange(float64(percentile(other.Cycle, 50)), float64(percentile(compliant.Cycle, 50))); change < 0 {
+		fmt.Printf("   💡 Compliant PRs merge %.0f%% faster.\n", -change)
+	} else {
+		fmt.Printf("   💡 Compliant PRs don't merge faster (%.0f%% slower).\n", change)
+	}
+	if pct(compliant.Reverted, compliant.Count) < pct(other.Reverted, other.Count) {
+		fmt.Println("   💡 Compliant PRs are reverted less often.")
+	}
+	fmt.Println("   (Reverts are only found among the fetched PRs.)")
+}
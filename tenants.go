@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TenantsFile lists the teams a multi-tenant server hosts (serve --tenants).
+type TenantsFile struct {
+	Tenants []Tenant `yaml:"tenants"`
+}
+
+// Tenant is one team hosted by the server, with its own config, tokens,
+// repositories, schedule and notification target.
+type Tenant struct {
+	Name   string   `yaml:"name"`
+	Config string   `yaml:"config"` // Bottleneck config file; default the server's --config
+	Repos  []string `yaml:"repos"`  // The only repositories the tenant may analyze
+	// TokenEnv names the env var with the GitHub token the tenant's fetches
+	// run with; default gh's own login. When it's named, it must be set:
+	// falling back to the operator's login would let the tenant read
+	// whatever the operator can.
+	TokenEnv string `yaml:"token_env"`
+	// APITokenEnv names the env var with the bearer token the tenant's
+	// endpoints require; no auth when unset.
+	APITokenEnv string   `yaml:"api_token_env"`
+	Schedule    Duration `yaml:"schedule"` // Analyze every repository this often; 0 only on demand
+	Limit       int      `yaml:"limit"`    // Merged PRs per analysis; default serve --limit
+	// SlackChannel receives a summary after each scheduled round, posted
+	// with the Slack token of the tenant's config (notify.slack_token_env).
+	SlackChannel string `yaml:"slack_channel"`
+}
+
+var tenantName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// loadTenants reads and checks a tenants file.
+func loadTenants(path string) ([]Tenant, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f TenantsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(f.Tenants) == 0 {
+		return nil, fmt.Errorf("%s lists no tenants", path)
+	}
+	seen := make(map[string]bool)
+	for _, t := range f.Tenants {
+		switch {
+		case !tenantName.MatchString(t.Name):
+			return nil, fmt.Errorf("tenant %q: names are lowercase letters, digits, - and _", t.Name)
+		case seen[t.Name]:
+			return nil, fmt.Errorf("tenant %q is listed twice", t.Name)
+		case len(t.Repos) == 0:
+			return nil, fmt.Errorf("tenant %q lists no repos", t.Name)
+		case t.Limit < 0:
+			return nil, fmt.Errorf("tenant %q: limit must be positive", t.Name)
+		case t.Schedule < 0:
+			return nil, fmt.Errorf("tenant %q: schedule must be positive", t.Name)
+		}
+		seen[t.Name] = true
+		for _, repo := range t.Repos {
+			if _, _, err := parseRepo(repo); err != nil {
+				return nil, fmt.Errorf("tenant %q: %s: %w", t.Name, repo, err)
+			}
+		}
+		if t.Config != "" {
+			if _, err := loadConfig(t.Config); err != nil {
+				return nil, fmt.Errorf("tenant %q: %w", t.Name, err)
+			}
+		}
+	}
+	return f.Tenants, nil
+}
+
+// tenant is a hosted team and the latest report of each of its
+// repositories.
+type tenant struct {
+	Tenant
+	repos    map[string]bool
+	ghToken  string
+	apiToken string
+	store    Store
+	latest   map[string]tenantReport // Guarded by analysisServer.mu
+}
+
+// tenantReport is the latest report of one repository.
+type tenantReport struct {
+	Report     RepoReport
+	Updated    time.Time
+	PrevHealth int
+	HasPrev    bool
+}
+
+// newTenant sets up a tenant with its tokens from the environment. It fails
+// when the tenant names a GitHub token env var that isn't set.
+func newTenant(t Tenant, storeDir string) (*tenant, error) {
+	tn := &tenant{Tenant: t, repos: make(map[string]bool), latest: make(map[string]tenantReport), store: Store{Dir: storeDir}}
+	if t.Name != "" {
+		tn.store.Dir = filepath.Join(storeDir, "tenants", t.Name)
+	}
+	for _, repo := range t.Repos {
+		tn.repos[repo] = true
+	}
+	if t.TokenEnv != "" {
+		tn.ghToken = os.Getenv(t.TokenEnv)
+		if tn.ghToken == "" {
+			return nil, fmt.Errorf("tenant %q: GitHub token env var %s is not set", t.Name, t.TokenEnv)
+		}
+	}
+	if t.APITokenEnv != "" {
+		tn.apiToken = os.Getenv(t.APITokenEnv)
+	}
+	return tn, nil
+}
+
+// label is how the tenant appears in logs and pages.
+func (t *tenant) label() string {
+	if t.Name == "" {
+		return "default"
+	}
+	return t.Name
+}
+
+// activate switches the global config and GitHub token to the tenant's for
+// the job about to run. Jobs run one at a time, so nothing else reads them
+// meanwhile.
+func (t *tenant) activate(serverConfig string) error {
+	path := t.Config
+	if path == "" {
+		path = serverConfig
+	}
+	privacy = privacyOff // A tenant without privacy: must not inherit another's
+	ghToken = t.ghToken
+	return applyConfig(path)
+}
+
+// record keeps a finished report as the repository's latest. The caller
+// holds analysisServer.mu.
+func (t *tenant) record(rep RepoReport, at time.Time) {
+	prev, ok := t.latest[rep.Repo]
+	t.latest[rep.Repo] = tenantReport{Report: rep, Updated: at, PrevHealth: prev.Report.Health, HasPrev: ok}
+}
+
+// scheduleRound is one scheduled analysis of every repository of a tenant.
+type scheduleRound struct {
+	pending int
+	lines   []string
+}
+
+// schedule queues an analysis of every repository of t each t.Schedule
+// until ctx is done, starting now.
+func (s *analysisServer) schedule(ctx context.Context, t *tenant) {
+	ticker := time.NewTicker(time.Duration(t.Schedule))
+	defer ticker.Stop()
+	for {
+		round := &scheduleRound{pending: len(t.Repos)}
+		for _, repo := range t.Repos {
+			job := &analysisJob{ID: newJobID(), Repo: repo, Status: jobQueued, Created: clock(), tenant: t, round: round}
+			if err := s.enqueue(job); err != nil {
+				slog.Warn("skipping scheduled analysis", "tenant", t.label(), "repo", repo, "err", err)
+				s.mu.Lock()
+				round.pending--
+				s.mu.Unlock()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finishRound notes a scheduled job as done and, after the last of its
+// round, posts the round's summary to the tenant's Slack channel. It runs
+// on the worker, with the tenant's config active.
+func (s *analysisServer) finishRound(ctx context.Context, job *analysisJob) {
+	s.mu.Lock()
+	round := job.round
+	if job.Status == jobDone && len(job.Report.Repositories) > 0 {
+		round.lines = append(round.lines, roundLine(job.tenant.latest[job.Repo]))
+	} else {
+		round.lines = append(round.lines, fmt.Sprintf("• %s: failed (%s)", job.Repo, job.Error))
+	}
+	round.pending--
+	last := round.pending == 0
+	lines := append([]string(nil), round.lines...)
+	s.mu.Unlock()
+
+	if !last || job.tenant.SlackChannel == "" {
+		return
+	}
+	sort.Strings(lines)
+	text := fmt.Sprintf("*Bottleneck: %s*\n%s", job.tenant.label(), strings.Join(lines, "\n"))
+	if err := sendSlackDM(ctx, job.tenant.SlackChannel, text, s.o.Timeout); err != nil {
+		slog.Error("posting scheduled summary", "tenant", job.tenant.label(), "channel", job.tenant.SlackChannel, "err", err)
+		return
+	}
+	slog.Info("posted scheduled summary", "tenant", job.tenant.label(), "channel", job.tenant.SlackChannel, "repos", len(lines))
+}
+
+// roundLine summarizes one repository for a scheduled round's message.
+func roundLine(r tenantReport) string {
+	health := fmt.Sprint(r.Report.Health)
+	if r.HasPrev && r.Report.Health != r.PrevHealth {
+		arrow := "▲"
+		if r.Report.Health < r.PrevHealth {
+			arrow = "▼"
+		}
+		health += fmt.Sprintf(" (%s%d)", arrow, max(r.Report.Health-r.PrevHealth, r.PrevHealth-r.Report.Health))
+	}
+	return fmt.Sprintf("• %s: health %s, median merge %s, %d of %d open PRs stale", r.Report.Repo, health,
+		formatDuration(time.Duration(r.Report.Merged.MedianCycleHours*float64(time.Hour))), r.Report.Open.Stale, r.Report.Open.Count)
+}
+
+// dashboardRow is one repository on a tenant's dashboard.
+type dashboardRow struct {
+	Repo, Health, Change, MedianMerge, FirstReview, Reviewed, Open, Updated string
+	Partial                                                                 bool
+}
+
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Bottleneck: {{.Tenant}}</title>
+<style>
+body { font-family: -apple-system, sans-serif; max-width: 1000px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; }
+th { background: #f5f5f5; }
+</style>
+</head>
+<body>
+<h1>Bottleneck: {{.Tenant}}</h1>
+<table>
+<tr><th>Repository</th><th>Health</th><th>Change</th><th>Median Merge</th><th>Median First Review</th><th>Reviewed</th><th>Open (Stale)</th><th>Updated</th></tr>
+{{- range .Rows}}
+<tr><td>{{.Repo}}{{if .Partial}} ⚠️{{end}}</td><td>{{.Health}}</td><td>{{.Change}}</td><td>{{.MedianMerge}}</td><td>{{.FirstReview}}</td><td>{{.Reviewed}}</td><td>{{.Open}}</td><td>{{.Updated}}</td></tr>
+{{- end}}
+</table>
+<p>⚠️: some data couldn't be fetched. Repositories not analyzed since the server started show dashes.</p>
+</body>
+</html>
+`
+
+var dashboardTemplate = htmltemplate.Must(htmltemplate.New("dashboard").Parse(dashboardHTML))
+
+func (s *analysisServer) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	t := s.tenantFor(w, r)
+	if t == nil {
+		return
+	}
+	s.mu.Lock()
+	var rows []dashboardRow
+	for _, repo := range t.Repos {
+		row := dashboardRow{Repo: repo, Health: "-", Change: "-", MedianMerge: "-", FirstReview: "-", Reviewed: "-", Open: "-", Updated: "-"}
+		if l, ok := t.latest[repo]; ok {
+			m := l.Report.Merged
+			row.Health = fmt.Sprint(l.Report.Health)
+			if l.HasPrev {
+				row.Change = fmt.Sprintf("%+d", l.Report.Health-l.PrevHealth)
+			}
+			row.MedianMerge = formatDuration(time.Duration(m.MedianCycleHours * float64(time.Hour)))
+			row.FirstReview = formatDuration(time.Duration(m.MedianFirstReviewHours * float64(time.Hour)))
+			row.Reviewed = fmt.Sprintf("%.0f%%", m.ReviewedPct)
+			row.Open = fmt.Sprintf("%d (%d)", l.Report.Open.Count, l.Report.Open.Stale)
+			row.Updated = l.Updated.UTC().Format("2006-01-02 15:04 MST")
+			row.Partial = l.Report.Partial
+		}
+		rows = append(rows, row)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, map[string]any{"Tenant": t.label(), "Rows": rows}); err != nil {
+		slog.Debug("writing dashboard", "err", err)
+	}
+}
+
+func (s *analysisServer) handleReports(w http.ResponseWriter, r *http.Request) {
+	t := s.tenantFor(w, r)
+	if t == nil {
+		return
+	}
+	s.mu.Lock()
+	var repos []RepoReport
+	for _, repo := range t.Repos {
+		if l, ok := t.latest[repo]; ok {
+			repos = append(repos, l.Report)
+		}
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, newJSONReport(repos, nil))
+}
+
+// tenantFor returns the tenant of a request, checking its bearer token. It
+// writes the error response and returns nil when there is none or the token
+// is wrong.
+func (s *analysisServer) tenantFor(w http.ResponseWriter, r *http.Request) *tenant {
+	t := s.tenants[r.PathValue("tenant")]
+	if t == nil {
+		writeError(w, http.StatusNotFound, errors.New("no such tenant"))
+		return nil
+	}
+	if !bearerMatches(r, t.apiToken) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or wrong bearer token"))
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewTenantNeedsItsToken(t *testing.T) {
+	t.Setenv("PAYMENTS_GH_TOKEN", "")
+	if _, err := newTenant(Tenant{Name: "payments", TokenEnv: "PAYMENTS_GH_TOKEN"}, t.TempDir()); err == nil {
+		t.Error("a tenant whose GitHub token isn't set would run with the operator's login")
+	}
+	t.Setenv("PAYMENTS_GH_TOKEN", "ghp_payments")
+	tn, err := newTenant(Tenant{Name: "payments", TokenEnv: "PAYMENTS_GH_TOKEN"}, t.TempDir())
+	if err != nil || tn.ghToken != "ghp_payments" {
+		t.Errorf("tenant = %+v, %v; want its token", tn, err)
+	}
+}
+
+func TestTenantRouting(t *testing.T) {
+	t.Setenv("PAYMENTS_API_TOKEN", "secret")
+	s, srv := newTestServer(t, 5,
+		Tenant{Name: "payments", Repos: []string{"acme/payments"}, APITokenEnv: "PAYMENTS_API_TOKEN"},
+		Tenant{Name: "data", Repos: []string{"acme/pipelines"}})
+
+	get := func(path, token string) int {
+		req, _ := http.NewRequest("GET", srv.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, tc := range []struct {
+		path, token string
+		want        int
+	}{
+		{"/tenants/payments/reports", "secret", http.StatusOK},
+		{"/tenants/payments/reports", "", http.StatusUnauthorized},
+		{"/tenants/payments/reports", "wrong", http.StatusUnauthorized},
+		{"/tenants/data/reports", "", http.StatusOK},
+		{"/tenants/ops/reports", "", http.StatusNotFound},
+		{"/reports", "", http.StatusNotFound}, // No default tenant with --tenants
+	} {
+		if got := get(tc.path, tc.token); got != tc.want {
+			t.Errorf("GET %s with token %q: %d, want %d", tc.path, tc.token, got, tc.want)
+		}
+	}
+
+	// Each tenant may only analyze its repositories, and only sees its jobs
+	if resp, _ := postAnalyze(t, srv.URL+"/tenants/data", "", `{"repo": "acme/payments"}`); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("data analyzing acme/payments: %s, want 403", resp.Status)
+	}
+	resp, job := postAnalyze(t, srv.URL+"/tenants/payments", "secret", `{"repo": "acme/payments"}`)
+	if loc := resp.Header.Get("Location"); resp.StatusCode != http.StatusAccepted || loc != "/tenants/payments/jobs/"+job.ID {
+		t.Fatalf("POST: %s, Location %q", resp.Status, loc)
+	}
+	if s.jobs[job.ID].tenant != s.tenants["payments"] {
+		t.Error("job queued for the wrong tenant")
+	}
+	if got := get("/tenants/payments/jobs/"+job.ID, "secret"); got != http.StatusOK {
+		t.Errorf("payments polling its job: %d, want 200", got)
+	}
+	if got := get("/tenants/data/jobs/"+job.ID, ""); got != http.StatusNotFound {
+		t.Errorf("data polling a payments job: %d, want 404", got)
+	}
+}
+
+func TestFinishRound(t *testing.T) {
+	s, _ := newTestServer(t, 5, Tenant{Name: "payments", Repos: []string{"acme/payments", "acme/ledger"}})
+	tn := s.tenants["payments"]
+	round := &scheduleRound{pending: 2}
+	rep := RepoReport{Repo: "acme/payments", Health: 72}
+	tn.record(RepoReport{Repo: "acme/payments", Health: 80}, clock())
+	tn.record(rep, clock())
+
+	done := &analysisJob{Repo: "acme/payments", Status: jobDone, tenant: tn, round: round,
+		Report: &JSONReport{Repositories: []RepoReport{rep}}}
+	s.finishRound(context.Background(), done)
+	if round.pending != 1 || len(round.lines) != 1 || !strings.Contains(round.lines[0], "health 72 (▼8)") {
+		t.Fatalf("round after the first job = %+v", round)
+	}
+	failed := &analysisJob{Repo: "acme/ledger", Status: jobFailed, Error: "gh: HTTP 502", tenant: tn, round: round}
+	s.finishRound(context.Background(), failed)
+	if round.pending != 0 || len(round.lines) != 2 || round.lines[1] != "• acme/ledger: failed (gh: HTTP 502)" {
+		t.Errorf("round after the last job = %+v", round)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sourceExtensions are the programming language files whose changes
+// should come with tests.
+var sourceExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".rs": true,
+	".java": true, ".kt": true, ".scala": true, ".rb": true, ".swift": true, ".c": true, ".cc": true,
+	".cpp": true, ".h": true, ".cs": true, ".php": true,
+}
+
+// isSourceFile reports whether p is non-test program code.
+func isSourceFile(p string) bool {
+	return sourceExtensions[strings.ToLower(path.Ext(p))] && !isTestFile(p)
+}
+
+// testedShare counts PRs and how many of them touch a test file.
+type testedShare struct {
+	PRs, Tested int
+}
+
+func (t testedShare) Pct() float64 {
+	if t.PRs == 0 {
+		return 0
+	}
+	return float64(t.Tested) / float64(t.PRs) * 100
+}
+
+func printTestCoverage(prs []PullRequest) {
+	fmt.Println("🧪 TEST COVERAGE OF PRs")
+	printExplanation("The share of PRs changing source code that also change a test file, by directory and author, and how those PRs fare in review and after merge.",
+		"A cheap quality signal from file paths alone: untested changes tend to draw more review rounds and more reverts.")
+
+	reverted := revertedPRs(prs)
+	type group struct {
+		Count, Rounds, Reverted int
+		Cycle                   []time.Duration
+	}
+	var tested, untested group
+	dirs := make(map[string]*testedShare)
+	authors := make(map[string]*testedShare)
+	count := func(m map[string]*testedShare, key string, has bool) {
+		if m[key] == nil {
+			m[key] = &testedShare{}
+		}
+		m[key].PRs++
+		if has {
+			m[key].Tested++
+		}
+	}
+	for _, pr := range prs {
+		source := make(map[string]bool)
+		has := false
+		for _, p := range pr.FilePaths {
+			switch {
+			case isTestFile(p):
+				has = true
+			case isSourceFile(p):
+				source[groupFor(p)] = true
+			}
+		}
+		if len(source) == 0 {
+			continue
+		}
+		g := &untested
+		if has {
+			g = &tested
+		}
+		g.Count++
+		g.Rounds += reviewRounds(pr)
+		g.Cycle = append(g.Cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if reverted[pr.Number] {
+			g.Reverted++
+		}
+		for d := range source {
+			count(dirs, d, has)
+		}
+		if pr.Author != "" && !isBot(pr.Author) {
+			name := pr.Author
+			if privacy == privacyTeam {
+				name = person(pr.Author)
+			}
+			count(authors, name, has)
+		}
+	}
+
+	total := tested.Count + untested.Count
+	if total == 0 {
+		fmt.Println("   No PRs changed source code.")
+		return
+	}
+	fmt.Printf("   PRs changing source code: %d   With test changes: %.0f%%\n\n", total, float64(tested.Count)/float64(total)*100)
+
+	fmt.Printf("   %-12s %5s   %-10s %-14s %s\n", "Has tests", "PRs", "Rounds/PR", "Median Merge", "Reverted")
+	for _, row := range []struct {
+		Label string
+		G     group
+	}{{"Yes", tested}, {"No", untested}} {
+		if row.G.Count == 0 {
+			fmt.Printf("   %-12s %5d   %-10s %-14s %s\n", row.Label, 0, "-", "-", "-")
+			continue
+		}
+		n := float64(row.G.Count)
+		fmt.Printf("   %-12s %5d   %-10s %-14s %d (%s%%)\n", row.Label, row.G.Count, formatNumber(float64(row.G.Rounds)/n, 1),
+			formatDuration(percentile(row.G.Cycle, 50)), row.G.Reverted, formatNumber(float64(row.G.Reverted)/n*100, 1))
+	}
+
+	// Least tested first, among entries with enough PRs to mean something
+	lowest := func(m map[string]*testedShare, minPRs int) []string {
+		var keys []string
+		for k, s := range m {
+			if s.PRs >= minPRs {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if m[keys[i]].Pct() != m[keys[j]].Pct() {
+				return m[keys[i]].Pct() < m[keys[j]].Pct()
+			}
+			return keys[i] < keys[j]
+		})
+		return keys
+	}
+	fmt.Printf("\n   By %s (least tested first, 3+ PRs):\n", strings.ToLower(groupLabel()))
+	for i, d := range lowest(dirs, 3) {
+		if i == 8 {
+			break
+		}
+		fmt.Printf("   %-25s %5d PRs   %3.0f%% with tests\n", limitString(d, 22), dirs[d].PRs, dirs[d].Pct())
+	}
+
+	rated := lowest(authors, 5)
+	if privacy == privacyAggregate {
+		below := 0
+		for _, a := range rated {
+			if authors[a].Pct() < 50 {
+				below++
+			}
+		}
+		fmt.Printf("\n   Authors with 5+ PRs: %d   Adding tests to fewer than half their PRs: %d\n", len(rated), below)
+	} else if len(rated) > 0 {
+		fmt.Println("\n   By author (least tested first, 5+ PRs):")
+		for i, a := range rated {
+			if i == 8 {
+				break
+			}
+			fmt.Printf("   %-25s %5d PRs   %3.0f%% with tests\n", limitString(a, 22), authors[a].PRs, authors[a].Pct())
+		}
+	}
+
+	if tested.Count < 5 || untested.Count < 5 {
+		fmt.Println("\n   (Not enough PRs on both sides for a reliable comparison.)")
+		return
+	}
+	fmt.Println()
+	rate := func(g group) float64 { return float64(g.Reverted) / float64(g.Count) }
+	switch {
+	case rate(untested) > 0 && rate(tested) == 0:
+		fmt.Println("   💡 Only PRs without tests were reverted.")
+	case rate(tested) > 0 && rate(untested) >= 2*rate(tested):
+		fmt.Printf("   💡 PRs without tests are reverted %sx as often.\n", formatNumber(rate(untested)/rate(tested), 1))
+	}
+	if r := float64(untested.Rounds)/float64(untested.Count) - float64(tested.Rounds)/float64(tested.Count); r > 0.2 {
+		fmt.Printf("   💡 PRs without tests take %s more review rounds on average.\n", formatNumber(r, 1))
+	}
+	fmt.Println("   (Tests are recognized by path: test directories, _test, .test., .spec., test_*.py, *Test.java. Reverts are only found among the fetched PRs.)")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// throughputRates are PRs per week, opened and merged, averaged over weeks.
+type throughputRates struct {
+	Weeks            int
+	Arrivals, Merges float64
+}
+
+// Growth is the open backlog's net change per week.
+func (r throughputRates) Growth() float64 { return r.Arrivals - r.Merges }
+
+func weeklyRates(weeks []queueWeek) throughputRates {
+	r := throughputRates{Weeks: len(weeks)}
+	for _, w := range weeks {
+		r.Arrivals += float64(w.Arrivals)
+		r.Merges += float64(w.Merged)
+	}
+	if len(weeks) > 0 {
+		r.Arrivals /= float64(len(weeks))
+		r.Merges /= float64(len(weeks))
+	}
+	return r
+}
+
+// printThroughputForecast projects merges and open backlog over the next 30
+// days from the last four complete weeks, and flags a backlog that grows
+// because PRs arrive faster than they merge.
+func printThroughputForecast(merged, open []PullRequest) {
+	weeks := queueWeeks(merged, open, clock())
+	if len(weeks) < 4 {
+		fmt.Println("\n   (Not enough complete weeks for a throughput forecast. Need 4+.)")
+		return
+	}
+	recent := weeklyRates(weeks[len(weeks)-4:])
+	const perMonth = 30.0 / 7
+	backlog := 0
+	for _, pr := range open {
+		if !pr.IsDraft {
+			backlog++
+		}
+	}
+
+	fmt.Printf("\n   Throughput, last 4 weeks: %s PRs merged/week, %s opened/week\n", formatNumber(recent.Merges, 1), formatNumber(recent.Arrivals, 1))
+	if len(weeks) >= 8 {
+		earlier := weeklyRates(weeks[len(weeks)-8 : len(weeks)-4])
+		fmt.Printf("   Four weeks before:        %s PRs merged/week, %s opened/week\n", formatNumber(earlier.Merges, 1), formatNumber(earlier.Arrivals, 1))
+	}
+	projected := math.Max(float64(backlog)+recent.Growth()*perMonth, 0)
+	fmt.Printf("\n   🎯 PREDICTION: ~%.0f PRs merged in the next 30 days\n", recent.Merges*perMonth)
+	growth := formatNumber(recent.Growth(), 1)
+	if recent.Growth() >= 0 {
+		growth = "+" + growth
+	}
+	fmt.Printf("   📥 BACKLOG:    %d open PRs now, ~%.0f in 30 days (%s/week)\n", backlog, projected, growth)
+
+	switch {
+	case recent.Merges == 0 && recent.Arrivals > 0:
+		fmt.Println("\n   🚨 Nothing merged in the last 4 weeks while PRs kept arriving. The backlog only grows.")
+	case recent.Arrivals > recent.Merges*1.1:
+		fmt.Printf("\n   🚨 PRs arrive %.0f%% faster than they merge. The backlog grows without bound until that flips,\n", (recent.Arrivals/recent.Merges-1)*100)
+		fmt.Println("      and every queued PR waits longer, whatever the merge time of the ones that do get through.")
+	}
+	fmt.Println("   (Opened counts exclude drafts and PRs closed without merging, which aren't fetched.)")
+}
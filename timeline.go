@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timelineWidth is the width of the terminal timeline bars.
+const timelineWidth = 40
+
+// prPhase is one stretch of a PR's life between two milestones.
+type prPhase struct {
+	Name       string
+	Start, End time.Time
+}
+
+func (p prPhase) Duration() time.Duration { return p.End.Sub(p.Start) }
+
+// prPhases splits a PR's life from creation to merge, or to end for open
+// PRs, into consecutive phases: waiting for a reviewer to be assigned,
+// waiting for the first review, review until the last approval, and
+// approved until merge. Phases that didn't happen are left out.
+func prPhases(pr PullRequest, end time.Time) []prPhase {
+	if !pr.MergedAt.IsZero() {
+		end = pr.MergedAt
+	}
+	var requested, reviewed, approved time.Time
+	for _, r := range pr.ReviewRequests {
+		if requested.IsZero() || r.At.Before(requested) {
+			requested = r.At
+		}
+	}
+	for _, r := range pr.Reviews {
+		if r.Author == pr.Author || isBot(r.Author) || r.CreatedAt.After(end) {
+			continue
+		}
+		if reviewed.IsZero() || r.CreatedAt.Before(reviewed) {
+			reviewed = r.CreatedAt
+		}
+		if r.State == "APPROVED" && r.CreatedAt.After(approved) {
+			approved = r.CreatedAt
+		}
+	}
+
+	var phases []prPhase
+	at := pr.CreatedAt
+	add := func(name string, until time.Time) {
+		if until.After(at) {
+			phases = append(phases, prPhase{Name: name, Start: at, End: until})
+			at = until
+		}
+	}
+	if reviewed.IsZero() {
+		if !requested.IsZero() && requested.Before(end) {
+			add("Awaiting reviewer", requested)
+			add("Awaiting first review", end)
+		} else if pr.MergedAt.IsZero() {
+			add("Awaiting reviewer", end)
+		} else {
+			add("Unreviewed", end)
+		}
+		return phases
+	}
+	if !requested.IsZero() && requested.Before(reviewed) {
+		add("Awaiting reviewer", requested)
+	}
+	add("Awaiting first review", reviewed)
+	if approved.IsZero() {
+		add("In review", end)
+		return phases
+	}
+	add("In review", approved)
+	if pr.MergedAt.IsZero() {
+		add("Approved, not merged", end)
+	} else {
+		add("Approved, awaiting merge", end)
+	}
+	return phases
+}
+
+// printTimeline draws the phases as bars on a shared time axis.
+func printTimeline(phases []prPhase, indent string) {
+	if len(phases) == 0 {
+		fmt.Println(indent + "(no recorded activity)")
+		return
+	}
+	start, end := phases[0].Start, phases[len(phases)-1].End
+	total := end.Sub(start)
+	for _, p := range phases {
+		from, width, pct := 0, timelineWidth, 100.0
+		if total > 0 {
+			pos := func(t time.Time) int { return int(float64(t.Sub(start))/float64(total)*timelineWidth + 0.5) }
+			from = min(pos(p.Start), timelineWidth-1)
+			width = max(pos(p.End)-from, 1)
+			pct = float64(p.Duration()) / float64(total) * 100
+		}
+		fmt.Printf("%s%-25s %s%s%s %-10s %3.0f%%\n", indent, p.Name,
+			strings.Repeat("·", from), strings.Repeat("█", width), strings.Repeat("·", timelineWidth-from-width),
+			formatDuration(p.Duration()), pct)
+	}
+}
+
+// mermaidGantt renders the phases of one PR as a Mermaid gantt chart.
+func mermaidGantt(pr PullRequest, phases []prPhase) string {
+	// Colons and semicolons separate task fields
+	clean := strings.NewReplacer(":", " ", ";", " ", "#", "")
+	var b strings.Builder
+	b.WriteString("```mermaid\ngantt\n")
+	fmt.Fprintf(&b, "    title PR %d %s\n", pr.Number, clean.Replace(limitString(pr.Title, 60)))
+	b.WriteString("    dateFormat YYYY-MM-DD HH:mm\n    axisFormat %m-%d %H:%M\n")
+	fmt.Fprintf(&b, "    section PR %d\n", pr.Number)
+	for i, p := range phases {
+		tag := ""
+		if i == len(phases)-1 && pr.MergedAt.IsZero() {
+			tag = "active, "
+		}
+		fmt.Fprintf(&b, "    %s (%s) :%sp%d, %s, %s\n", p.Name, clean.Replace(formatDuration(p.Duration())), tag, i+1,
+			p.Start.UTC().Format("2006-01-02 15:04"), p.End.UTC().Format("2006-01-02 15:04"))
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
+
+// prEvent is one dated event in a PR's history.
+type prEvent struct {
+	At   time.Time
+	Text string
+}
+
+// prEvents lists what happened on a PR, oldest first.
+func prEvents(pr PullRequest) []prEvent {
+	events := []prEvent{{pr.CreatedAt, fmt.Sprintf("Opened by %s", pr.Author)}}
+	for _, r := range pr.ReviewRequests {
+		events = append(events, prEvent{r.At, "Review requested from " + r.Reviewer})
+	}
+	for _, r := range pr.Reviews {
+		state := strings.ReplaceAll(strings.ToLower(r.State), "_", " ")
+		if state != "" {
+			state = strings.ToUpper(state[:1]) + state[1:]
+		}
+		events = append(events, prEvent{r.CreatedAt, state + " by " + r.Author})
+	}
+	for _, t := range pr.CommitTimes {
+		events = append(events, prEvent{t, "Commit pushed"})
+	}
+	if pr.AutoMergeAt != nil {
+		events = append(events, prEvent{*pr.AutoMergeAt, "Auto-merge enabled"})
+	}
+	if !pr.MergedAt.IsZero() {
+		events = append(events, prEvent{pr.MergedAt, "Merged by " + pr.MergedBy})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
+
+// fetchPR fetches a single pull request and its state (OPEN, CLOSED or
+// MERGED).
+func fetchPR(ctx context.Context, owner, name string, number int, timeout time.Duration) (PullRequest, string, error) {
+	query := fmt.Sprintf(`
+query {
+  repository(owner: %q, name: %q) {
+    pullRequest(number: %d) {
+state
+`+prFields+`
+    }
+  }
+}`, owner, name, number)
+	output, err := ghGraphQL(ctx, query, timeout)
+	if err != nil {
+		return PullRequest{}, "", err
+	}
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					GRPCPullRequest
+					State string `json:"state"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return PullRequest{}, "", err
+	}
+	node := resp.Data.Repository.PullRequest
+	if node == nil {
+		return PullRequest{}, "", fmt.Errorf("pull request #%d not found", number)
+	}
+	return toPullRequest(node.GRPCPullRequest), node.State, nil
+}
+
+func printPRForensics(repo string, pr PullRequest, state string, now time.Time) {
+	fmt.Printf("🔬 PR #%d: %s\n", pr.Number, pr.Title)
+	printExplanation("Where this PR's time went, phase by phase, with every review, request and push.",
+		"One PR's story makes a bottleneck concrete in a way an average can't.")
+
+	end := now
+	if !pr.MergedAt.IsZero() {
+		end = pr.MergedAt
+	}
+	fmt.Printf("   Repository: %s, author: %s, state: %s\n", repo, pr.Author, strings.ToLower(state))
+	fmt.Printf("   Size: %s lines in %d files, %d commits, %d reviews\n", formatInt(pr.Size), pr.ChangedFiles, len(pr.CommitTimes), len(pr.Reviews))
+	if pr.MergedAt.IsZero() {
+		fmt.Printf("   Opened %s, %s ago\n", formatDate(pr.CreatedAt), formatDuration(end.Sub(pr.CreatedAt)))
+	} else {
+		fmt.Printf("   Opened %s, merged %s later\n", formatDate(pr.CreatedAt), formatDuration(end.Sub(pr.CreatedAt)))
+	}
+	if state == "CLOSED" {
+		fmt.Println("   (Closed without merging. Phases run until now.)")
+	}
+	fmt.Println()
+
+	printTimeline(prPhases(pr, end), "   ")
+
+	fmt.Println("\n   Events:")
+	for _, e := range prEvents(pr) {
+		fmt.Printf("   %s %s  %s\n", formatDate(e.At), e.At.In(primaryLocation).Format("15:04"), e.Text)
+	}
+}
+
+func runPR(args []string) {
+	fs := flag.NewFlagSet("pr", flag.ExitOnError)
+	format := fs.String("format", "text", "Output format: text (terminal timeline) or mermaid (Gantt chart for Markdown)")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck pr [flags] <owner/repo> <number>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if fs.NArg() < 2 || (*format != "text" && *format != "mermaid") {
+		fs.Usage()
+		os.Exit(1)
+	}
+	repo := fs.Arg(0)
+	owner, name, err := parseRepo(repo)
+	if err != nil {
+		slog.Error("invalid repository", "err", err)
+		os.Exit(1)
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(fs.Arg(1), "#"))
+	if err != nil || number < 1 {
+		slog.Error("invalid pull request number", "number", fs.Arg(1))
+		os.Exit(1)
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	pr, state, err := fetchPR(ctx, owner, name, number, *reqTimeout)
+	if err != nil {
+		slog.Error("fetching pull request", "repo", repo, "number", number, "err", err)
+		os.Exit(1)
+	}
+	now := clock()
+	if *format == "mermaid" {
+		fmt.Print(mermaidGantt(pr, prPhases(pr, now)))
+		return
+	}
+	printPRForensics(repo, pr, state, now)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimezoneConfig sets where the team works. Dates, time-of-day analyses and
+// stale cutoffs are presented in Primary; Teams and People place reviewers
+// in their own timezone for follow-the-sun coverage.
+type TimezoneConfig struct {
+	Primary   string            `yaml:"primary"`    // IANA name, e.g. Europe/Berlin (default: local time)
+	WorkHours string            `yaml:"work_hours"` // e.g. "09:00-17:00" (default)
+	Teams     map[string]string `yaml:"teams"`      // Team (from teams:) to IANA name
+	People    map[string]string `yaml:"people"`     // Login to IANA name, overriding the team's
+}
+
+// Loaded timezone state, set by applyTimezones.
+var (
+	primaryLocation = time.Local
+	workStart       = 9 * time.Hour
+	workEnd         = 17 * time.Hour
+	teamLocations   = map[string]*time.Location{}
+	peopleLocations = map[string]*time.Location{}
+)
+
+// applyTimezones validates and loads the timezone config.
+func applyTimezones(tz TimezoneConfig) error {
+	primary := time.Local
+	if tz.Primary != "" {
+		l, err := time.LoadLocation(tz.Primary)
+		if err != nil {
+			return fmt.Errorf("timezones.primary: %w", err)
+		}
+		primary = l
+	}
+	start, end := 9*time.Hour, 17*time.Hour
+	if tz.WorkHours != "" {
+		var err error
+		if start, end, err = parseWorkHours(tz.WorkHours); err != nil {
+			return fmt.Errorf("timezones.work_hours: %w", err)
+		}
+	}
+	load := func(field string, names map[string]string) (map[string]*time.Location, error) {
+		out := make(map[string]*time.Location, len(names))
+		for k, name := range names {
+			l, err := time.LoadLocation(name)
+			if err != nil {
+				return nil, fmt.Errorf("timezones.%s.%s: %w", field, k, err)
+			}
+			out[strings.ToLower(k)] = l
+		}
+		return out, nil
+	}
+	teams, err := load("teams", tz.Teams)
+	if err != nil {
+		return err
+	}
+	people, err := load("people", tz.People)
+	if err != nil {
+		return err
+	}
+	// Other accounts of a person share their timezone
+	byPerson := make(map[string]*time.Location, len(people))
+	for k, l := range people {
+		byPerson[strings.ToLower(canonicalLogin(k))] = l
+	}
+	primaryLocation, workStart, workEnd, teamLocations, peopleLocations = primary, start, end, teams, byPerson
+	return nil
+}
+
+// parseWorkHours parses "09:00-17:00".
+func parseWorkHours(s string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q is not a range like 09:00-17:00", s)
+	}
+	var bounds [2]time.Duration
+	for i, part := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("%q is not a range like 09:00-17:00", s)
+		}
+		bounds[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if bounds[0] >= bounds[1] {
+		return 0, 0, fmt.Errorf("%q ends before it starts", s)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// locationOf returns a reviewer's timezone: their own, their team's, or nil
+// when neither is configured.
+func locationOf(login string) *time.Location {
+	if l, ok := peopleLocations[strings.ToLower(canonicalLogin(login))]; ok {
+		return l
+	}
+	if l, ok := teamLocations[strings.ToLower(teamOf(login))]; ok {
+		return l
+	}
+	return nil
+}
+
+// atWork reports whether t falls within work hours on a weekday in loc.
+func atWork(t time.Time, loc *time.Location) bool {
+	local := t.In(loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	return offset >= workStart && offset < workEnd
+}
+
+// staleCutoff returns the start of the day, in the primary timezone, seven
+// days before now. PRs last updated before it are stale.
+func staleCutoff(now time.Time) time.Time {
+	local := now.In(primaryLocation)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, primaryLocation).AddDate(0, 0, -7)
+}
+
+func printReviewHours(prs []PullRequest) {
+	fmt.Printf("🕐 REVIEW HOURS (%s)\n", primaryLocation)
+	printExplanation("When reviews are submitted by hour of day, and which hours have no reviewer within their working hours.",
+		"PRs opened outside everyone's working hours wait for the next day. Follow-the-sun gaps show where a hand-off is missing.")
+
+	var hours [24]int
+	total := 0
+	for _, pr := range prs {
+		for _, r := range pr.Reviews {
+			if r.Author == "" || r.Author == pr.Author || isBot(r.Author) {
+				continue
+			}
+			hours[r.CreatedAt.In(primaryLocation).Hour()]++
+			total++
+		}
+	}
+	if total == 0 {
+		fmt.Println("   No reviews found in this dataset.")
+		return
+	}
+	peak := 0
+	for _, n := range hours {
+		peak = max(peak, n)
+	}
+
+	// The same reviewers follow-the-sun coverage places, so both sections
+	// agree on the gaps. Coverage is checked on the coming Wednesday, with
+	// today's DST offsets and no weekend in the way
+	placed, _ := placeReviewers(prs)
+	day := nextMonday(clock().In(primaryLocation)).AddDate(0, 0, 2)
+	var gaps []int
+	for h := 0; h < 24; h++ {
+		covered := len(onShift(placed, day.Add(time.Duration(h)*time.Hour)))
+		cov := ""
+		if len(placed) > 0 {
+			cov = fmt.Sprintf("  %2d on shift", covered)
+			if covered == 0 {
+				cov = "  ⚠️  nobody on shift"
+				gaps = append(gaps, h)
+			}
+		}
+		fmt.Printf("   %02d:00 %-20s %4d%s\n", h, strings.Repeat("█", hours[h]*20/peak), hours[h], cov)
+	}
+
+	if len(placed) == 0 {
+		fmt.Println("\n   (Configure timezones.teams or timezones.people to see follow-the-sun coverage.)")
+		return
+	}
+	perZone := make(map[string]int)
+	inferred := make(map[string]bool)
+	for _, r := range placed {
+		perZone[r.Location.String()]++
+		inferred[r.Location.String()] = inferred[r.Location.String()] || r.Inferred
+	}
+	var zones []string
+	for l, n := range perZone {
+		zone := fmt.Sprintf("%s (%d)", l, n)
+		if inferred[l] {
+			zone += "*"
+		}
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	fmt.Printf("\n   Reviewer timezones (%d+ reviews; *: includes inferred): %s\n", sunMinReviews, strings.Join(zones, ", "))
+	if len(gaps) == 0 {
+		fmt.Println("   ✅ Someone is on shift every hour of the working week.")
+		return
+	}
+	fmt.Printf("   🌙 Coverage gaps: %s (%d hours with no reviewer at work).\n", hourRanges(gaps), len(gaps))
+}
+
+// hourRanges renders sorted hours as ranges, e.g. "02:00-05:00, 22:00-24:00".
+func hourRanges(hours []int) string {
+	var parts []string
+	for i := 0; i < len(hours); {
+		j := i
+		for j+1 < len(hours) && hours[j+1] == hours[j]+1 {
+			j++
+		}
+		parts = append(parts, fmt.Sprintf("%02d:00-%02d:00", hours[i], hours[j]+1))
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// builtinViews are the report variants for each audience, picked with
+// --view. A view is a preset over the same fetched data; the IC view adds
+// sections scoped to the authenticated user.
+var builtinViews = map[string]Preset{
+	"ic": {
+		Description: "Your review queue and the reviewers your PRs wait on",
+		Sections: []SectionConfig{
+			{Name: "my_queue"}, {Name: "my_ghosts"}, {Name: "burndown"},
+		},
+		Flags: map[string]string{"explain": "false"},
+	},
+	"lead": {
+		Description: "Team health: who is stuck, who carries the reviews",
+		Sections: []SectionConfig{
+			{Name: "general"}, {Name: "review"}, {Name: "ghosts"}, {Name: "heroes"}, {Name: "stale"},
+			{Name: "aging"}, {Name: "team_latency"}, {Name: "reviewer_diversity"}, {Name: "sla"},
+		},
+	},
+	"exec": {
+		Description: "Trends and the forecast behind the health score",
+		Sections: []SectionConfig{
+			{Name: "general"}, {Name: "trends"}, {Name: "forecast"}, {Name: "cohorts", By: "quarter"},
+		},
+		Flags: map[string]string{"limit": "500", "exclude-partial-months": "true"},
+	},
+}
+
+// viewNames lists the views, sorted.
+func viewNames() []string {
+	var names []string
+	for n := range builtinViews {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyView sets the view's flags on fs, skipping those given on the
+// command line, and makes its sections the report's.
+func applyView(fs *flag.FlagSet, name string) error {
+	p, ok := builtinViews[name]
+	if !ok {
+		return fmt.Errorf("unknown view %q (available: %s)", name, strings.Join(viewNames(), ", "))
+	}
+	return setPreset(fs, "view "+name, p)
+}
+
+// plansViewer reports whether a planned section is scoped to the
+// authenticated user, who must then be looked up before the run.
+func plansViewer(o reportOptions) bool {
+	return plansSection(o, "my_queue") || plansSection(o, "my_ghosts")
+}
+
+// viewerOnly keeps the sections scoped to the authenticated user out of the
+// default report; --view ic or config sections pick them.
+func viewerOnly(reportOptions) bool { return false }
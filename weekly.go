@@ -0,0 +1,252 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+)
+
+// resolvedGhosts counts review requests that had waited more than 48 hours
+// and got their review between from and to, and the requests on open PRs
+// still waiting that long at to.
+func resolvedGhosts(merged, open []PullRequest, from, to time.Time) (resolved, waiting int) {
+	const ghostAge = 48 * time.Hour
+	for _, pr := range append(append([]PullRequest(nil), merged...), open...) {
+		seen := make(map[string]bool)
+		for _, req := range pr.ReviewRequests {
+			if seen[req.Reviewer] || isBot(req.Reviewer) {
+				continue
+			}
+			for _, r := range pr.Reviews {
+				if r.Author == req.Reviewer && r.CreatedAt.After(req.At) {
+					if !r.CreatedAt.Before(from) && r.CreatedAt.Before(to) && r.CreatedAt.Sub(req.At) > ghostAge {
+						seen[req.Reviewer] = true
+						resolved++
+					}
+					break
+				}
+			}
+		}
+	}
+	for _, pr := range open {
+		if pr.IsDraft {
+			continue
+		}
+		for _, login := range pr.Requested {
+			if !isBot(login) && to.Sub(requestedAt(pr, login, to)) > ghostAge {
+				waiting++
+			}
+		}
+	}
+	return resolved, waiting
+}
+
+// periodStats are the medians of the PRs merged in one period.
+type periodStats struct {
+	Merged           []PullRequest
+	Cycle, FirstWait time.Duration
+}
+
+func statsBetween(prs []PullRequest, from, to time.Time) periodStats {
+	var s periodStats
+	var cycle, first []time.Duration
+	for _, pr := range prs {
+		if pr.MergedAt.Before(from) || !pr.MergedAt.Before(to) {
+			continue
+		}
+		s.Merged = append(s.Merged, pr)
+		cycle = append(cycle, pr.MergedAt.Sub(pr.CreatedAt))
+		if pr.FirstReviewAt != nil {
+			first = append(first, max(pr.FirstReviewAt.Sub(pr.CreatedAt), 0))
+		}
+	}
+	if len(cycle) > 0 {
+		s.Cycle = percentile(cycle, 50)
+	}
+	if len(first) > 0 {
+		s.FirstWait = percentile(first, 50)
+	}
+	return s
+}
+
+// versus formats a current duration against the previous period's.
+func versus(now, before time.Duration) string {
+	if before == 0 {
+		return formatDuration(now)
+	}
+	return fmt.Sprintf("%s (was %s, %+.0f%%)", formatDuration(now), formatDuration(before), pctChange(float64(before), float64(now)))
+}
+
+// printWeeklyDigest prints what changed between now-since and now, compared
+// with the period before.
+func printWeeklyDigest(repo string, merged, open []PullRequest, since time.Duration, top int, now time.Time) {
+	from := now.Add(-since)
+	cur, prev := statsBetween(merged, from, now), statsBetween(merged, from.Add(-since), from)
+	fmt.Printf("📰 %s: what changed, %s - %s\n\n", repo, formatDate(from), formatDate(now))
+
+	by := func(pr PullRequest) string {
+		if privacy == privacyAggregate {
+			return ""
+		}
+		return person(pr.Author) + ", "
+	}
+
+	fmt.Printf("✅ Merged: %d PRs (previous period: %d)\n", len(cur.Merged), len(prev.Merged))
+	if len(cur.Merged) > 0 {
+		fmt.Printf("   Median merge time:   %s\n", versus(cur.Cycle, prev.Cycle))
+		if cur.FirstWait > 0 {
+			fmt.Printf("   Median first review: %s\n", versus(cur.FirstWait, prev.FirstWait))
+		}
+		sort.Slice(cur.Merged, func(i, j int) bool { return cur.Merged[i].MergedAt.After(cur.Merged[j].MergedAt) })
+		for i, pr := range cur.Merged {
+			if i == top {
+				fmt.Printf("   ... and %d more\n", len(cur.Merged)-top)
+				break
+			}
+			fmt.Printf("   - #%d %s (%s%s)\n", pr.Number, limitString(pr.Title, 60), by(pr), formatDuration(pr.MergedAt.Sub(pr.CreatedAt)))
+		}
+	}
+
+	opened := 0
+	for _, pr := range append(append([]PullRequest(nil), merged...), open...) {
+		if !pr.CreatedAt.Before(from) {
+			opened++
+		}
+	}
+	fmt.Printf("\n📬 Opened: %d   Open now: %d\n", opened, len(open))
+
+	// A PR goes stale seven days after its last activity; new ones crossed
+	// that line during the period
+	var stale []PullRequest
+	for _, pr := range open {
+		if wentStale := pr.UpdatedAt.AddDate(0, 0, 7); !wentStale.Before(from) && wentStale.Before(now) && staleExemption(pr) == "" {
+			stale = append(stale, pr)
+		}
+	}
+	fmt.Printf("\n💀 New stale PRs: %d\n", len(stale))
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+	for i, pr := range stale {
+		if i == top {
+			fmt.Printf("   ... and %d more\n", len(stale)-top)
+			break
+		}
+		fmt.Printf("   - #%d %s (%squiet since %s)\n", pr.Number, limitString(pr.Title, 60), by(pr), formatDate(pr.UpdatedAt))
+	}
+
+	resolved, waiting := resolvedGhosts(merged, open, from, now)
+	fmt.Printf("\n👻 Ghost requests resolved: %d   Still waiting over 48h: %d\n", resolved, waiting)
+
+	// Outliers: this period's merges slower than 90% of all fetched PRs, and
+	// its biggest PR
+	if len(cur.Merged) > 0 {
+		var all []time.Duration
+		for _, pr := range merged {
+			all = append(all, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+		p90 := percentile(all, 90)
+		var notes []string
+		biggest := cur.Merged[0]
+		for _, pr := range cur.Merged {
+			if d := pr.MergedAt.Sub(pr.CreatedAt); d > p90 {
+				notes = append(notes, fmt.Sprintf("#%d %s merged after %s", pr.Number, limitString(pr.Title, 50), formatDuration(d)))
+			}
+			if pr.Size > biggest.Size {
+				biggest = pr
+			}
+		}
+		if len(notes) > top {
+			notes = append(notes[:top], fmt.Sprintf("... and %d more", len(notes)-top))
+		}
+		notes = append(notes, fmt.Sprintf("#%d %s is the biggest: %s lines", biggest.Number, limitString(biggest.Title, 50), formatInt(biggest.Size)))
+		fmt.Printf("\n🔎 Notable (slower than 90%% of the last %d merged PRs):\n", len(merged))
+		for _, n := range notes {
+			fmt.Printf("   - %s\n", n)
+		}
+	}
+}
+
+func runDigest(args []string) {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	sinceFlag := fs.String("since", "7d", "Period to digest, e.g. 7d, 2w or 36h; compared with the period before")
+	limit := fs.Int("limit", 300, "Max number of merged PRs to fetch; must reach back two periods")
+	top := fs.Int("top", 10, "PRs listed per heading")
+	reqTimeout := fs.Duration("timeout", 30*time.Second, "Timeout for each API request")
+	reqDelay := fs.Duration("delay", 200*time.Millisecond, "Delay between API requests to avoid rate limits")
+	configPath := fs.String("config", "", "Path to config file (default: .bottleneck.yml if present)")
+	privacyMode := fs.String("privacy", "", "Hide names: team or aggregate. Overrides privacy: in config")
+	locale := fs.String("locale", "iso", "Date and number format: iso, en-US, en-GB, de-DE, fr-FR, es-ES or ja-JP")
+	durFormat := fs.String("duration-format", "humanized", "Duration format: humanized, hours or iso8601")
+	logLevel, logFormat := addLogFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage: bottleneck digest [flags] <owner/repo> [owner/repo...]")
+		fmt.Println("A compact what-changed digest for standups and weekly syncs.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := setupLogging(*logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	since, err := parseDuration(*sinceFlag)
+	if err != nil || since <= 0 || fs.NArg() < 1 || *limit < 1 || *top < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	for _, repo := range fs.Args() {
+		if _, _, err := parseRepo(repo); err != nil {
+			slog.Error("invalid repository", "repo", repo, "err", err)
+			os.Exit(1)
+		}
+	}
+	if err := applyConfig(*configPath); err != nil {
+		slog.Error("loading config", "err", err)
+		os.Exit(1)
+	}
+	if *privacyMode != "" {
+		if err := setPrivacy(*privacyMode); err != nil {
+			slog.Error("invalid --privacy", "err", err)
+			os.Exit(1)
+		}
+	}
+	if err := setFormatting(*locale, *durFormat); err != nil {
+		slog.Error("invalid formatting flags", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := rootContext()
+	defer cancel()
+
+	now := clock()
+	for i, repo := range fs.Args() {
+		owner, name, _ := parseRepo(repo)
+		slog.Info("fetching merged PRs", "repo", repo, "limit", *limit)
+		merged, err := fetchPRs(ctx, owner, name, *limit, "MERGED", *reqTimeout, *reqDelay)
+		if err != nil {
+			slog.Warn("continuing with partial data", "repo", repo, "fetched", len(merged), "err", err)
+		}
+		slog.Info("fetching open PRs", "repo", repo, "limit", 100)
+		open, err := fetchPRs(ctx, owner, name, 100, "OPEN", *reqTimeout, *reqDelay)
+		if err != nil {
+			slog.Warn("continuing with partial data", "repo", repo, "fetched", len(open), "err", err)
+		}
+		if len(merged) == *limit {
+			oldest := merged[0].MergedAt
+			for _, pr := range merged {
+				if pr.MergedAt.Before(oldest) {
+					oldest = pr.MergedAt
+				}
+			}
+			if oldest.After(now.Add(-2 * since)) {
+				slog.Warn("--limit doesn't reach back to the previous period; its numbers are incomplete", "repo", repo, "oldest", oldest)
+			}
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		printWeeklyDigest(repo, merged, open, since, *top, now)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Kinds of work for the maintenance-vs-feature trend.
+const (
+	workFeature     = "feature"
+	workMaintenance = "maintenance"
+)
+
+var (
+	featureLabel     = regexp.MustCompile(`(?i)feature|enhancement`)
+	maintenanceLabel = regexp.MustCompile(`(?i)refactor|tech[\s_-]?debt|maintenance|chore|cleanup|dependenc`)
+	// Titles without a conventional prefix, e.g. "Refactor the client" or
+	// "Bump golang.org/x/net from 0.1 to 0.2"
+	maintenanceTitle = regexp.MustCompile(`(?i)^\s*(refactor|clean ?up|bump|upgrade|remove unused|rename)\b`)
+)
+
+// maintenanceTypes are the conventional-commit types that invest in the
+// codebase rather than add to it.
+var maintenanceTypes = map[string]bool{"refactor": true, "chore": true, "build": true, "ci": true, "style": true, "test": true, "perf": true}
+
+// isMaintenancePath reports whether p is build, CI, dependency or test
+// plumbing.
+func isMaintenancePath(p string) bool {
+	switch path.Base(p) {
+	case "go.mod", "go.sum", "package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.toml", "Cargo.lock",
+		"requirements.txt", "poetry.lock", "Gemfile", "Gemfile.lock", "Makefile", "Dockerfile":
+		return true
+	}
+	return strings.HasPrefix(p, ".github/") || isTestFile(p)
+}
+
+// workKind classifies a PR as feature or maintenance work (refactoring,
+// dependencies, build and test plumbing), or "" for neither, such as bug
+// fixes and docs. The title prefix decides first, then labels, then the
+// title wording and changed paths.
+func workKind(pr PullRequest) string {
+	switch t := changeType(pr.Title); {
+	case t == "feat":
+		return workFeature
+	case maintenanceTypes[t]:
+		return workMaintenance
+	case t != "untyped" && t != "other":
+		return ""
+	}
+	for _, l := range pr.Labels {
+		switch {
+		case maintenanceLabel.MatchString(l):
+			return workMaintenance
+		case featureLabel.MatchString(l):
+			return workFeature
+		}
+	}
+	if maintenanceTitle.MatchString(pr.Title) {
+		return workMaintenance
+	}
+	if len(pr.FilePaths) == 0 {
+		return ""
+	}
+	for _, p := range pr.FilePaths {
+		if !isMaintenancePath(p) {
+			return ""
+		}
+	}
+	return workMaintenance
+}
+
+// maintenanceRatio formats maintenance PRs per feature PR.
+func maintenanceRatio(maintenance, features int) string {
+	switch {
+	case features > 0:
+		return formatNumber(float64(maintenance)/float64(features), 1)
+	case maintenance > 0:
+		return "all"
+	}
+	return "-"
+}
+
+// printMaintenanceTrend compares the maintenance-to-feature ratio of the
+// earlier and later half of the months, a signal of tech-debt investment.
+func printMaintenanceTrend(months []monthStat) {
+	var classified []monthStat
+	for _, m := range months {
+		if m.Features+m.Maintenance > 0 {
+			classified = append(classified, m)
+		}
+	}
+	fmt.Println("\n   (M:F: maintenance PRs (refactor, chore, dependencies, build, CI, tests) per feature PR, by title prefix, label and paths.)")
+	if len(classified) < 2 {
+		return
+	}
+	half := len(classified) / 2
+	sum := func(ms []monthStat) (maintenance, features int) {
+		for _, m := range ms {
+			maintenance += m.Maintenance
+			features += m.Features
+		}
+		return maintenance, features
+	}
+	em, ef := sum(classified[:half])
+	lm, lf := sum(classified[half:])
+	if ef == 0 || lf == 0 {
+		return
+	}
+	early, late := float64(em)/float64(ef), float64(lm)/float64(lf)
+	fmt.Printf("   🔧 Maintenance per feature PR: %s (%s - %s) -> %s (%s - %s).", formatNumber(early, 1),
+		formatMonthKey(classified[0].Month), formatMonthKey(classified[half-1].Month), formatNumber(late, 1),
+		formatMonthKey(classified[half].Month), formatMonthKey(classified[len(classified)-1].Month))
+	switch {
+	case late > early*1.25:
+		fmt.Println(" Rising: more investment in paying down tech debt.")
+	case late < early*0.8:
+		fmt.Println(" Falling: feature work is crowding out maintenance.")
+	default:
+		fmt.Println(" Steady.")
+	}
+}